@@ -18,17 +18,21 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"io/ioutil"
+	"strings"
 
 	"github.com/GoogleContainerTools/skaffold/cmd/skaffold/app/flags"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
 var (
-	quietFlag       bool
+	pushFlag        bool
+	fileOutputFlag  string
 	buildFormatFlag = flags.NewTemplateFlag("{{range .Builds}}{{.ImageName}} -> {{.Tag}}\n{{end}}", BuildOutput{})
 )
 
@@ -39,24 +43,30 @@ func NewCmdBuild(out io.Writer) *cobra.Command {
 		Short: "Builds the artifacts",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().Changed("push") {
+				opts.Push = &pushFlag
+			}
 			return runBuild(out)
 		},
 	}
 	AddRunDevFlags(cmd)
-	cmd.Flags().BoolVarP(&quietFlag, "quiet", "q", false, "Suppress the build output and print image built on success")
+	cmd.Flags().BoolVarP(&opts.Quiet, "quiet", "q", false, "Suppress the build output and print image built on success")
 	cmd.Flags().VarP(buildFormatFlag, "output", "o", buildFormatFlag.Usage())
+	cmd.Flags().StringArrayVarP(&opts.TargetImages, "build-image", "b", nil, "Only build the artifacts whose image name contains this expression. Set multiple times for multiple expressions.")
+	cmd.Flags().BoolVar(&pushFlag, "push", false, "Push the built images, or not when set to false (overrides build.local.push)")
+	cmd.Flags().StringVar(&fileOutputFlag, "file-output", "", "Write the built images to this file, as JSON")
 	return cmd
 }
 
 // BuildOutput is the output of `skaffold build`.
 type BuildOutput struct {
-	Builds []build.Artifact
+	Builds []build.Artifact `json:"builds"`
 }
 
 func runBuild(out io.Writer) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	catchCtrlC(cancel)
+	catchCtrlC(cancel, opts.ShutdownGracePeriod)
 
 	runner, config, err := newRunner(opts)
 	if err != nil {
@@ -64,18 +74,61 @@ func runBuild(out io.Writer) error {
 	}
 
 	buildOut := out
-	if quietFlag {
+	if opts.Quiet {
 		buildOut = ioutil.Discard
 	}
 
-	bRes, err := runner.Build(ctx, buildOut, runner.Tagger, config.Build.Artifacts)
+	artifacts, err := targetArtifacts(config.Build.Artifacts, opts.TargetImages)
 	if err != nil {
+		return err
+	}
+
+	bRes, err := runner.Build(ctx, buildOut, runner.Tagger, artifacts)
+	if err != nil {
+		build.PrintFailure(out, err)
 		return errors.Wrap(err, "build step")
 	}
 
 	cmdOut := BuildOutput{Builds: bRes}
+	if fileOutputFlag != "" {
+		if err := writeBuildOutput(fileOutputFlag, cmdOut); err != nil {
+			return err
+		}
+	}
 	if err := buildFormatFlag.Template().Execute(out, cmdOut); err != nil {
 		return errors.Wrap(err, "executing template")
 	}
 	return nil
 }
+
+// targetArtifacts keeps the artifacts whose image name contains one of the
+// expressions. All the artifacts are kept when there's no expression.
+func targetArtifacts(artifacts []*latest.Artifact, expressions []string) ([]*latest.Artifact, error) {
+	if len(expressions) == 0 {
+		return artifacts, nil
+	}
+
+	var targets []*latest.Artifact
+	for _, artifact := range artifacts {
+		for _, expression := range expressions {
+			if strings.Contains(artifact.ImageName, expression) {
+				targets = append(targets, artifact)
+				break
+			}
+		}
+	}
+
+	if len(targets) == 0 {
+		return nil, errors.Errorf("no artifact matches %s", strings.Join(expressions, ", "))
+	}
+	return targets, nil
+}
+
+func writeBuildOutput(path string, output BuildOutput) error {
+	buf, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshalling build output")
+	}
+
+	return errors.Wrap(ioutil.WriteFile(path, buf, 0644), "writing build output")
+}
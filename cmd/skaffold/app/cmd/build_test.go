@@ -0,0 +1,95 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestTargetArtifacts(t *testing.T) {
+	frontend := &latest.Artifact{ImageName: "gcr.io/project/frontend"}
+	backend := &latest.Artifact{ImageName: "gcr.io/project/backend"}
+	worker := &latest.Artifact{ImageName: "gcr.io/project/backend-worker"}
+	artifacts := []*latest.Artifact{frontend, backend, worker}
+
+	var tests = []struct {
+		description string
+		expressions []string
+		expected    []*latest.Artifact
+		shouldErr   bool
+	}{
+		{
+			description: "no filter",
+			expected:    artifacts,
+		},
+		{
+			description: "single expression",
+			expressions: []string{"frontend"},
+			expected:    []*latest.Artifact{frontend},
+		},
+		{
+			description: "partial match",
+			expressions: []string{"backend"},
+			expected:    []*latest.Artifact{backend, worker},
+		},
+		{
+			description: "multiple expressions",
+			expressions: []string{"worker", "frontend"},
+			expected:    []*latest.Artifact{frontend, worker},
+		},
+		{
+			description: "no match",
+			expressions: []string{"unknown"},
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			targets, err := targetArtifacts(artifacts, test.expressions)
+
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, targets)
+		})
+	}
+}
+
+func TestWriteBuildOutput(t *testing.T) {
+	tmpDir, teardown := testutil.NewTempDir(t)
+	defer teardown()
+
+	path := filepath.Join(tmpDir.Root(), "build.json")
+	err := writeBuildOutput(path, BuildOutput{
+		Builds: []build.Artifact{{ImageName: "image", Tag: "image:tag"}},
+	})
+	testutil.CheckError(t, false, err)
+
+	buf, err := ioutil.ReadFile(path)
+	testutil.CheckErrorAndDeepEqual(t, false, err, `{
+  "builds": [
+    {
+      "imageName": "image",
+      "tag": "image:tag"
+    }
+  ]
+}`, string(buf))
+}
@@ -23,9 +23,14 @@ import (
 	"os"
 	"strings"
 
+	configutil "github.com/GoogleContainerTools/skaffold/cmd/skaffold/app/cmd/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/update"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/version"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -34,9 +39,11 @@ import (
 )
 
 var (
-	opts      = &config.SkaffoldOptions{}
-	v         string
-	overwrite bool
+	opts       = &config.SkaffoldOptions{}
+	v          string
+	colorMode  string
+	overwrite  bool
+	timestamps bool
 
 	updateMsg = make(chan string)
 )
@@ -47,17 +54,37 @@ var rootCmd = &cobra.Command{
 }
 
 func NewSkaffoldCommand(out, err io.Writer) *cobra.Command {
+	timestampedOut := color.NewTimestampWriter(out)
+	out = timestampedOut
+
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := color.SetMode(colorMode); err != nil {
+			return err
+		}
+		if timestamps {
+			timestampedOut.Enable()
+		}
 		if err := SetUpLogs(err, v); err != nil {
 			return err
 		}
+		if err := startProfiling(); err != nil {
+			return err
+		}
 		rootCmd.SilenceUsage = true
 		logrus.Infof("Skaffold %+v", version.Get())
-		go func() {
-			if err := updateCheck(updateMsg); err != nil {
-				logrus.Infof("update check failed: %s", err)
-			}
-		}()
+
+		// Label everything deployed by this invocation with a unique run ID.
+		opts.RunID = util.RandomID()
+		deploy.AddManifestTransform(deploy.LabelRunID(opts.RunID))
+		logrus.Debugf("Run ID: %s", opts.RunID)
+
+		if isUpdateCheckEnabled() {
+			go func() {
+				if err := updateCheck(updateMsg); err != nil {
+					logrus.Infof("update check failed: %s", err)
+				}
+			}()
+		}
 		return nil
 	}
 
@@ -74,30 +101,59 @@ func NewSkaffoldCommand(out, err io.Writer) *cobra.Command {
 	rootCmd.AddCommand(NewCmdVersion(out))
 	rootCmd.AddCommand(NewCmdRun(out))
 	rootCmd.AddCommand(NewCmdDev(out))
+	rootCmd.AddCommand(NewCmdDebug(out))
 	rootCmd.AddCommand(NewCmdBuild(out))
 	rootCmd.AddCommand(NewCmdDeploy(out))
 	rootCmd.AddCommand(NewCmdDelete(out))
+	rootCmd.AddCommand(NewCmdExec(out))
 	rootCmd.AddCommand(NewCmdFix(out))
 	rootCmd.AddCommand(NewCmdConfig(out))
 	rootCmd.AddCommand(NewCmdInit(out))
 	rootCmd.AddCommand(NewCmdDiagnose(out))
+	rootCmd.AddCommand(NewCmdDaemon(out))
 
-	rootCmd.PersistentFlags().StringVarP(&v, "verbosity", "v", constants.DefaultLogLevel.String(), "Log level (debug, info, warn, error, fatal, panic)")
+	rootCmd.PersistentFlags().StringVar(&colorMode, "color", color.AutoMode, "When to color the output: 'auto' colors it on terminals unless NO_COLOR is set, 'always' or 'never'")
+	rootCmd.PersistentFlags().BoolVar(&timestamps, "timestamps", false, "Prefix the output and the logs of deployed containers with RFC3339 timestamps")
+	AddProfilingFlags(rootCmd)
+	rootCmd.PersistentFlags().StringVarP(&v, "verbosity", "v", constants.DefaultLogLevel.String(), "Log level (debug, info, warn, error, fatal, panic). At error level and below, the builders and deployers only print errors. At debug level, they print more details, including the progress of each docker layer pulled or pushed")
 
 	setFlagsFromEnvVariables(rootCmd.Commands())
 
 	return rootCmd
 }
 
-func updateCheck(ch chan string) error {
-	if quietFlag {
+// isUpdateCheckEnabled says if skaffold should check for a newer release.
+// The check can be disabled with the SKAFFOLD_UPDATE_CHECK env variable
+// or the update-check key of the global config.
+func isUpdateCheckEnabled() bool {
+	if opts.Quiet {
 		logrus.Debugf("Update check is disabled because of quiet mode")
-		return nil
+		return false
 	}
 	if !update.IsUpdateCheckEnabled() {
 		logrus.Debugf("Update check not enabled, skipping.")
-		return nil
+		return false
+	}
+	enabled, err := configutil.IsUpdateCheckEnabled()
+	if err != nil {
+		logrus.Infof("update check failed: reading global config: %s", err)
+		return false
 	}
+	if !enabled {
+		logrus.Debugf("Update check disabled in the global config, skipping.")
+	}
+	return enabled
+}
+
+// exitCodesHelp documents the exit codes of run and dev.
+const exitCodesHelp = `Exit codes:
+  1  any other failure
+  2  the skaffold configuration is invalid
+  3  an artifact failed to build
+  4  the deployment failed
+  5  deployments were not rolled out within the status check timeout`
+
+func updateCheck(ch chan string) error {
 	current, err := version.ParseVersion(version.Get().Version)
 	if err != nil {
 		return errors.Wrap(err, "parsing current semver, skipping update check")
@@ -134,19 +190,58 @@ func setFlagsFromEnvVariables(commands []*cobra.Command) {
 
 func AddRunDeployFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&opts.Tail, "tail", false, "Stream logs from deployed objects")
-	cmd.Flags().StringArrayVarP(&opts.CustomLabels, "label", "l", nil, "Add custom labels to deployed objects. Set multiple times for multiple labels.")
+	cmd.Flags().StringArrayVarP(&opts.CustomLabels, "label", "l", nil, "Add custom labels to deployed objects, as key=value. Set multiple times for multiple labels. They are merged with the deploy.labels of skaffold.yaml.")
+}
+
+// AddFilenameFlag adds the flag to choose the pipeline files.
+// Several files can be given, to be merged into one pipeline.
+func AddFilenameFlag(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVarP(&opts.ConfigurationFiles, "filename", "f", []string{"skaffold.yaml"}, "Filename or URL to the pipeline file. Set multiple times to merge multiple pipelines.")
 }
 
 func AddRunDevFlags(cmd *cobra.Command) {
-	cmd.Flags().StringVarP(&opts.ConfigurationFile, "filename", "f", "skaffold.yaml", "Filename or URL to the pipeline file")
+	AddFilenameFlag(cmd)
 	cmd.Flags().BoolVar(&opts.Notification, "toot", false, "Emit a terminal beep after the deploy is complete")
 	cmd.Flags().StringArrayVarP(&opts.Profiles, "profile", "p", nil, "Activate profiles by name")
-	cmd.Flags().StringVarP(&opts.Namespace, "namespace", "n", "", "Run deployments in the specified namespace")
+	cmd.Flags().StringVarP(&opts.Namespace, "namespace", "n", "", "Run deployments in the specified namespace. Logs, port-forwarding, file sync, status checks and verifications are then limited to that namespace and the ones the manifests declare")
+	cmd.Flags().StringArrayVar(&opts.Overrides, "set", nil, "Override a field of skaffold.yaml, e.g. --set build.artifacts[0].docker.dockerfile=Dockerfile.dev. Set multiple times for multiple fields. Relative paths are relative to the current directory.")
+	cmd.Flags().StringArrayVar(&opts.AllowedEnv, "allow-env", nil, "Environment variables that can be expanded in skaffold.yaml with ${VAR} or {{ env \"VAR\" }}. Set multiple times for multiple variables.")
 	cmd.Flags().StringVarP(&opts.DefaultRepo, "default-repo", "d", "", "Default repository value (overrides global config)")
+	cmd.Flags().BoolVar(&opts.StatusCheck, "status-check", false, "Wait for deployments to be rolled out after each deploy")
+	cmd.Flags().StringArrayVar(&opts.InsecureRegistries, "insecure-registry", nil, "Target registries for built images which are accessed over plain HTTP. Set multiple times for multiple registries.")
+	cmd.Flags().StringVar(&opts.RegistryCABundle, "registry-ca-bundle", "", "Path to a PEM bundle of CA certificates used to verify self-hosted registries")
+	cmd.Flags().StringArrayVar(&opts.RegistryMirrors, "registry-mirror", nil, "Mirror used to pull Docker Hub base images during builds. Set multiple times for multiple mirrors.")
+	cmd.Flags().StringVar(&opts.KubeConfig, "kubeconfig", "", "Path to the kubeconfig file to use (overrides KUBECONFIG)")
+	cmd.Flags().StringVar(&opts.KubeContext, "kube-context", "", "Kubernetes context to use instead of the current context of the kubeconfig")
+	cmd.Flags().StringVar(&opts.KubectlBinary, "kubectl", "", "Path to the kubectl binary to run")
+	cmd.Flags().StringVar(&opts.KubectlWrapper, "kubectl-wrapper", "", "Command kubectl is run through, such as 'tsh'")
+	cmd.Flags().BoolVar(&opts.CheckPermissions, "check-permissions", false, "Check that the current user is allowed to create the resources needed by the builders and deployers before starting")
+	cmd.Flags().StringVar(&opts.DockerOutput, "docker-output", docker.RawBuildOutput, "How to print the output of docker builds: 'raw' streams it all, 'summary' prints one line per step with its duration and the output of failing steps")
+	cmd.Flags().StringVar(&opts.EventOutput, "event-output", "", "Format of the events reported for each phase and artifact of the pipeline: 'json' writes one event per line, 'github' writes GitHub Actions groups and error annotations, 'junit' writes a JUnit XML report to --event-file")
+	cmd.Flags().StringVar(&opts.EventFile, "event-file", "", "File the events are written to, or 'fd:N' for an open file descriptor. Defaults to stderr")
+	cmd.Flags().BoolVar(&opts.Strict, "strict", false, "Fail on warnings that usually signal a drift in the configuration, such as built images not used by the deployment. Useful on CI")
+	cmd.Flags().DurationVar(&opts.BuildTimeout, "build-timeout", 0, "Give up on builds that take longer (overrides build.timeout)")
+	cmd.Flags().DurationVar(&opts.DeployTimeout, "deploy-timeout", 0, "Give up on deploys that take longer (overrides deploy.timeout)")
+	cmd.Flags().DurationVar(&opts.StatusCheckTimeout, "status-check-timeout", 0, "How long to wait for deployments to be rolled out (overrides deploy.statusCheckTimeout)")
+	cmd.Flags().DurationVar(&opts.ShutdownGracePeriod, "shutdown-grace-period", constants.DefaultShutdownGracePeriod, "How long to wait for in-flight builds, deploys and cleanup to stop when interrupted, before exiting anyway. Zero means no limit")
+	cmd.Flags().StringVar(&opts.BuildCache, "build-cache", "", "Share the images built from the same sources with other machines, through a GCS bucket (gs://bucket/path) or by tagging them in their registry ('registry')")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Print the images that would be built, with their tags, the rendered manifests and the resources that would be applied, without calling docker or the cluster")
 }
 
 func SetUpLogs(out io.Writer, level string) error {
 	logrus.SetOutput(out)
+	formatter := &logrus.TextFormatter{}
+	switch {
+	case color.Mode() == color.AlwaysMode:
+		formatter.ForceColors = true
+	case !color.Enabled(out):
+		formatter.DisableColors = true
+	}
+	if timestamps {
+		formatter.FullTimestamp = true
+		formatter.TimestampFormat = color.TimestampFormat
+	}
+	logrus.SetFormatter(formatter)
 	lvl, err := logrus.ParseLevel(v)
 	if err != nil {
 		return errors.Wrap(err, "parsing log level")
@@ -21,11 +21,35 @@ package config
 type Config struct {
 	Global         *ContextConfig   `yaml:"global,omitempty"`
 	ContextConfigs []*ContextConfig `yaml:"kubeContexts"`
+	LocalClusters  []*LocalCluster  `yaml:"local-clusters,omitempty"`
+}
+
+// LocalCluster makes skaffold treat the clusters of the kube-contexts
+// matching a glob pattern as local. Their images aren't pushed unless
+// push is true. When given, the load command, followed by the image name,
+// is run to load each image into the cluster.
+type LocalCluster struct {
+	Pattern string `yaml:"pattern"`
+	Push    *bool  `yaml:"push,omitempty"`
+	Load    string `yaml:"load,omitempty"`
 }
 
 // ContextConfig is the context-specific config information provided in
 // the global Skaffold config.
 type ContextConfig struct {
-	Kubecontext string `yaml:"kube-context,omitempty"`
-	DefaultRepo string `yaml:"default-repo,omitempty"`
+	Kubecontext        string   `yaml:"kube-context,omitempty"`
+	DefaultRepo        string   `yaml:"default-repo,omitempty"`
+	Namespace          string   `yaml:"namespace,omitempty"`
+	LocalCluster       *bool    `yaml:"local-cluster,omitempty"`
+	GCBProject         string   `yaml:"gcb-project,omitempty"`
+	InsecureRegistries []string `yaml:"insecure-registries,omitempty"`
+	RegistryCABundle   string   `yaml:"registry-ca-bundle,omitempty"`
+	RegistryMirrors    []string `yaml:"registry-mirrors,omitempty"`
+	KubectlBinary      string   `yaml:"kubectl,omitempty"`
+	KubectlWrapper     string   `yaml:"kubectl-wrapper,omitempty"`
+	UpdateCheck        *bool    `yaml:"update-check,omitempty"`
+
+	GCSCompressionLevel  *int `yaml:"gcs-compression-level,omitempty"`
+	GCSChunkSizeMB       *int `yaml:"gcs-chunk-size-mb,omitempty"`
+	GCSUploadParallelism *int `yaml:"gcs-upload-parallelism,omitempty"`
 }
@@ -21,6 +21,8 @@ import (
 
 	"gopkg.in/yaml.v2"
 
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/cluster"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/GoogleContainerTools/skaffold/testutil"
 )
 
@@ -99,6 +101,118 @@ func TestSetAndUnsetConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:        "set local cluster",
+			key:         "local-cluster",
+			value:       "true",
+			kubecontext: "this_is_a_context",
+			expectedSetCfg: &Config{
+				ContextConfigs: []*ContextConfig{
+					{
+						Kubecontext:  "this_is_a_context",
+						LocalCluster: util.BoolPtr(true),
+					},
+				},
+			},
+			expectedUnsetCfg: &Config{
+				ContextConfigs: []*ContextConfig{
+					{
+						Kubecontext: "this_is_a_context",
+					},
+				},
+			},
+		},
+		{
+			name:        "set insecure registries",
+			key:         "insecure-registries",
+			value:       "localhost:5000,registry.local:5000",
+			kubecontext: "this_is_a_context",
+			expectedSetCfg: &Config{
+				ContextConfigs: []*ContextConfig{
+					{
+						Kubecontext:        "this_is_a_context",
+						InsecureRegistries: []string{"localhost:5000", "registry.local:5000"},
+					},
+				},
+			},
+			expectedUnsetCfg: &Config{
+				ContextConfigs: []*ContextConfig{
+					{
+						Kubecontext: "this_is_a_context",
+					},
+				},
+			},
+		},
+		{
+			name:        "set registry mirrors",
+			key:         "registry-mirrors",
+			value:       "mirror.gcr.io",
+			kubecontext: "this_is_a_context",
+			expectedSetCfg: &Config{
+				ContextConfigs: []*ContextConfig{
+					{
+						Kubecontext:     "this_is_a_context",
+						RegistryMirrors: []string{"mirror.gcr.io"},
+					},
+				},
+			},
+			expectedUnsetCfg: &Config{
+				ContextConfigs: []*ContextConfig{
+					{
+						Kubecontext: "this_is_a_context",
+					},
+				},
+			},
+		},
+		{
+			name:        "set gcs upload parallelism",
+			key:         "gcs-upload-parallelism",
+			value:       "8",
+			kubecontext: "this_is_a_context",
+			expectedSetCfg: &Config{
+				ContextConfigs: []*ContextConfig{
+					{
+						Kubecontext:          "this_is_a_context",
+						GCSUploadParallelism: intPtr(8),
+					},
+				},
+			},
+			expectedUnsetCfg: &Config{
+				ContextConfigs: []*ContextConfig{
+					{
+						Kubecontext: "this_is_a_context",
+					},
+				},
+			},
+		},
+		{
+			name:         "set invalid gcs chunk size",
+			key:          "gcs-chunk-size-mb",
+			value:        "big",
+			kubecontext:  "this_is_a_context",
+			shouldErrSet: true,
+			expectedSetCfg: &Config{
+				ContextConfigs: []*ContextConfig{
+					{
+						Kubecontext: "this_is_a_context",
+					},
+				},
+			},
+		},
+		{
+			name:         "set invalid local cluster",
+			key:          "local-cluster",
+			value:        "maybe",
+			kubecontext:  "this_is_a_context",
+			shouldErrSet: true,
+			expectedSetCfg: &Config{
+				ContextConfigs: []*ContextConfig{
+					{
+						Kubecontext: "this_is_a_context",
+					},
+				},
+			},
+		},
 		{
 			name:         "set fake value",
 			key:          "not_a_real_value",
@@ -173,3 +287,63 @@ func TestSetAndUnsetConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestGetConfigValues(t *testing.T) {
+	c, _ := yaml.Marshal(Config{
+		Global: &ContextConfig{
+			Namespace:      "global-namespace",
+			GCBProject:     "global-project",
+			KubectlWrapper: "tsh",
+			UpdateCheck:    util.BoolPtr(false),
+			GCSChunkSizeMB: intPtr(8),
+		},
+		ContextConfigs: []*ContextConfig{
+			{
+				Kubecontext:         "test-context",
+				Namespace:           "context-namespace",
+				LocalCluster:        util.BoolPtr(false),
+				GCSCompressionLevel: intPtr(1),
+			},
+		},
+		LocalClusters: []*LocalCluster{
+			{Pattern: "lab-*", Load: "lab load"},
+		},
+	})
+	cfg, teardown := testutil.TempFile(t, "config", c)
+	defer func() {
+		teardown()
+		kubecontext = ""
+		configFile = ""
+	}()
+
+	configFile = cfg
+	kubecontext = "test-context"
+
+	namespace, err := GetNamespace("")
+	testutil.CheckErrorAndDeepEqual(t, false, err, "context-namespace", namespace)
+
+	namespace, err = GetNamespace("cli-namespace")
+	testutil.CheckErrorAndDeepEqual(t, false, err, "cli-namespace", namespace)
+
+	project, err := GetGCBProject()
+	testutil.CheckErrorAndDeepEqual(t, false, err, "global-project", project)
+
+	localCluster, err := GetLocalCluster()
+	testutil.CheckErrorAndDeepEqual(t, false, err, util.BoolPtr(false), localCluster)
+
+	rules, err := GetLocalClusterRules()
+	testutil.CheckErrorAndDeepEqual(t, false, err, []cluster.Rule{{Pattern: "lab-*", Load: "lab load"}}, rules)
+
+	kubectl, wrapper, err := GetKubectl("/opt/kubectl", "")
+	testutil.CheckErrorAndDeepEqual(t, false, err, []string{"/opt/kubectl", "tsh"}, []string{kubectl, wrapper})
+
+	updateCheck, err := IsUpdateCheckEnabled()
+	testutil.CheckErrorAndDeepEqual(t, false, err, false, updateCheck)
+
+	level, chunkSizeMB, parallelism, err := GetGCSUploadOptions()
+	testutil.CheckErrorAndDeepEqual(t, false, err, []int{1, 8, 0}, []int{level, chunkSizeMB, parallelism})
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -21,6 +21,7 @@ import (
 	"io"
 	"io/ioutil"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -46,7 +47,7 @@ func NewCmdSet(out io.Writer) *cobra.Command {
 	return cmd
 }
 
-func setConfigValue(name string, value interface{}) error {
+func setConfigValue(name string, value string) error {
 	cfg, err := getOrCreateConfigForKubectx()
 	if err != nil {
 		return err
@@ -67,17 +68,44 @@ func setConfigValue(name string, value interface{}) error {
 	}
 	fieldValue := cfgValue.FieldByName(fieldName)
 
-	fieldType := fieldValue.Type()
-	val := reflect.ValueOf(value)
-
-	if fieldType != val.Type() {
+	val, err := parseConfigValue(fieldValue.Type(), value)
+	if err != nil {
 		return fmt.Errorf("%s is not a valid value for field %s", value, fieldName)
 	}
-	reflect.ValueOf(cfg).Elem().FieldByName(fieldName).Set(val)
+	fieldValue.Set(val)
 
 	return writeConfig(cfg)
 }
 
+// parseConfigValue converts a value given on the command line to the type of
+// a config field. An empty value unsets the field.
+func parseConfigValue(fieldType reflect.Type, value string) (reflect.Value, error) {
+	if value == "" {
+		return reflect.Zero(fieldType), nil
+	}
+
+	switch fieldType {
+	case reflect.TypeOf(""):
+		return reflect.ValueOf(value), nil
+	case reflect.TypeOf((*bool)(nil)):
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(&b), nil
+	case reflect.TypeOf((*int)(nil)):
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(&i), nil
+	case reflect.TypeOf([]string{}):
+		return reflect.ValueOf(strings.Split(value, ",")), nil
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported type %s", fieldType)
+	}
+}
+
 func writeConfig(cfg *ContextConfig) error {
 	fullConfig, err := readConfig()
 	if err != nil {
@@ -20,6 +20,7 @@ import (
 	"io/ioutil"
 	"path/filepath"
 
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/cluster"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 
@@ -144,31 +145,211 @@ func getOrCreateConfigForKubectx() (*ContextConfig, error) {
 	return newCfg, nil
 }
 
+// GetDefaultRepo returns the default repo, either given on the command line,
+// set for the current kube-context or set globally.
 func GetDefaultRepo(cliValue string) (string, error) {
 	// CLI flag takes precedence. If no default-repo specified from a flag,
 	// retrieve the value from the global config.
 	if cliValue != "" {
 		return cliValue, nil
 	}
-	cfg, err := GetConfigForKubectx()
+	return getStringValue(func(cfg *ContextConfig) string { return cfg.DefaultRepo })
+}
+
+// GetNamespace returns the namespace to deploy to, either given on the
+// command line, set for the current kube-context or set globally.
+func GetNamespace(cliValue string) (string, error) {
+	if cliValue != "" {
+		return cliValue, nil
+	}
+	return getStringValue(func(cfg *ContextConfig) string { return cfg.Namespace })
+}
+
+// GetGCBProject returns the Google Cloud Build project
+// set for the current kube-context or set globally.
+func GetGCBProject() (string, error) {
+	return getStringValue(func(cfg *ContextConfig) string { return cfg.GCBProject })
+}
+
+// GetLocalCluster says if the current kube-context was configured
+// as a local cluster. It returns nil if it wasn't configured.
+func GetLocalCluster() (*bool, error) {
+	configs, err := getConfigsForKubectx()
+	if err != nil {
+		return nil, err
+	}
+	for _, cfg := range configs {
+		if cfg.LocalCluster != nil {
+			return cfg.LocalCluster, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetLocalClusterRules returns the user's rules to detect additional local clusters.
+func GetLocalClusterRules() ([]cluster.Rule, error) {
+	cfg, err := readConfig()
 	if err != nil {
-		return "", errors.Wrap(err, "retrieving global config")
+		return nil, err
 	}
-	var defaultRepo string
-	if cfg != nil {
-		defaultRepo = cfg.DefaultRepo
+
+	var rules []cluster.Rule
+	for _, c := range cfg.LocalClusters {
+		rules = append(rules, cluster.Rule{
+			Pattern: c.Pattern,
+			Push:    c.Push,
+			Load:    c.Load,
+		})
 	}
-	if defaultRepo == "" {
-		// if we don't have a defaultRepo value set for the current context,
-		// retrieve the global config and use this value as a fallback
-		cfg, err := GetGlobalConfig()
+	return rules, nil
+}
+
+// IsUpdateCheckEnabled says if skaffold should check for newer releases.
+// The check can only be disabled globally, with `skaffold config set --global update-check false`.
+func IsUpdateCheckEnabled() (bool, error) {
+	cfg, err := GetGlobalConfig()
+	if err != nil {
+		return false, err
+	}
+	if cfg == nil || cfg.UpdateCheck == nil {
+		return true, nil
+	}
+	return *cfg.UpdateCheck, nil
+}
+
+// GetInsecureRegistries returns the registries that are accessed over plain HTTP,
+// either given on the command line, set for the current kube-context or set globally.
+func GetInsecureRegistries(cliValues []string) ([]string, error) {
+	configs, err := getConfigsForKubectx()
+	if err != nil {
+		return nil, err
+	}
+
+	registries := cliValues
+	for _, cfg := range configs {
+		registries = append(registries, cfg.InsecureRegistries...)
+	}
+	return registries, nil
+}
+
+// GetRegistryMirrors returns the mirrors used to pull Docker Hub images during builds,
+// either given on the command line, set for the current kube-context or set globally.
+func GetRegistryMirrors(cliValues []string) ([]string, error) {
+	if len(cliValues) > 0 {
+		return cliValues, nil
+	}
+
+	configs, err := getConfigsForKubectx()
+	if err != nil {
+		return nil, err
+	}
+	for _, cfg := range configs {
+		if len(cfg.RegistryMirrors) > 0 {
+			return cfg.RegistryMirrors, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetRegistryCABundle returns the CA bundle used to verify the certificates of
+// registries, either given on the command line, set for the current kube-context or set globally.
+func GetRegistryCABundle(cliValue string) (string, error) {
+	if cliValue != "" {
+		return cliValue, nil
+	}
+	return getStringValue(func(cfg *ContextConfig) string { return cfg.RegistryCABundle })
+}
+
+// GetKubectl returns the kubectl binary and the command it's run through,
+// either given on the command line, set for the current kube-context or set globally.
+func GetKubectl(cliBinary, cliWrapper string) (string, string, error) {
+	binary := cliBinary
+	if binary == "" {
+		value, err := getStringValue(func(cfg *ContextConfig) string { return cfg.KubectlBinary })
 		if err != nil {
-			return "", errors.Wrap(err, "retrieving global config")
+			return "", "", err
 		}
-		if cfg != nil {
-			defaultRepo = cfg.DefaultRepo
+		binary = value
+	}
+
+	wrapper := cliWrapper
+	if wrapper == "" {
+		value, err := getStringValue(func(cfg *ContextConfig) string { return cfg.KubectlWrapper })
+		if err != nil {
+			return "", "", err
 		}
+		wrapper = value
+	}
+
+	return binary, wrapper, nil
+}
+
+// GetGCSUploadOptions returns the compression level, the chunk size and the parallelism
+// of the uploads of build contexts to GCS, set for the current kube-context or set globally.
+// Unset values are zero.
+func GetGCSUploadOptions() (int, int, int, error) {
+	level, err := getIntValue(func(cfg *ContextConfig) *int { return cfg.GCSCompressionLevel })
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	chunkSizeMB, err := getIntValue(func(cfg *ContextConfig) *int { return cfg.GCSChunkSizeMB })
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	parallelism, err := getIntValue(func(cfg *ContextConfig) *int { return cfg.GCSUploadParallelism })
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return level, chunkSizeMB, parallelism, nil
+}
+
+func getIntValue(get func(*ContextConfig) *int) (int, error) {
+	configs, err := getConfigsForKubectx()
+	if err != nil {
+		return 0, err
+	}
+	for _, cfg := range configs {
+		if value := get(cfg); value != nil {
+			return *value, nil
+		}
+	}
+	return 0, nil
+}
+
+func getStringValue(get func(*ContextConfig) string) (string, error) {
+	configs, err := getConfigsForKubectx()
+	if err != nil {
+		return "", err
+	}
+	for _, cfg := range configs {
+		if value := get(cfg); value != "" {
+			return value, nil
+		}
+	}
+	return "", nil
+}
+
+// getConfigsForKubectx returns the configs to look values up in, by order of
+// precedence: the config of the current kube-context, then the global config.
+func getConfigsForKubectx() ([]*ContextConfig, error) {
+	var configs []*ContextConfig
+
+	cfg, err := GetConfigForKubectx()
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieving global config")
+	}
+	if cfg != nil {
+		configs = append(configs, cfg)
+	}
+
+	// use the global values as a fallback
+	globalCfg, err := GetGlobalConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieving global config")
+	}
+	if globalCfg != nil {
+		configs = append(configs, globalCfg)
 	}
 
-	return defaultRepo, nil
+	return configs, nil
 }
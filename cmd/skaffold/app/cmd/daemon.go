@@ -0,0 +1,265 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/server"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var daemonPort int
+
+// NewCmdDaemon describes the CLI command to serve builds and deploys to IDEs.
+func NewCmdDaemon(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Serves builds, deploys and dev loops to IDEs",
+		Long: `Keeps the pipeline loaded and the dependencies of the artifacts resolved,
+and serves builds, deploys and dev loops through an HTTP API on localhost, so that
+IDEs don't pay the startup cost on every invocation.
+
+  POST /v1/build    builds the artifacts
+  POST /v1/deploy   deploys the images given as {"builds": [...]}, or the last ones built
+  POST /v1/run      builds, tests and deploys once
+  POST /v1/dev      runs a dev loop until the client disconnects
+
+Responses are newline delimited JSON: the output of the operation, then a message
+marked as done with the error or the built images. One operation runs at a time.
+The pipeline is reloaded when skaffold.yaml changes. Requests sent by web pages,
+with an Origin header or a Host that isn't local, are refused.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDaemon(out)
+		},
+	}
+	AddRunDevFlags(cmd)
+	AddDevDebugFlags(cmd)
+	cmd.Flags().IntVar(&daemonPort, "port", constants.DefaultDaemonPort, "Port on which the daemon API listens")
+	return cmd
+}
+
+func runDaemon(out io.Writer) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	catchCtrlC(cancel, opts.ShutdownGracePeriod)
+
+	d := &daemon{
+		opts: opts,
+		out:  out,
+	}
+	if err := d.refresh(ctx); err != nil {
+		return err
+	}
+
+	shutdown, err := server.InitializeDaemon(daemonPort, d)
+	if err != nil {
+		return errors.Wrap(err, "starting daemon API")
+	}
+	defer shutdown()
+	color.Default.Fprintf(out, "Daemon listening on localhost:%d\n", daemonPort)
+
+	// Keep the pipeline and the dependencies up to date between operations.
+	ticker := time.NewTicker(time.Duration(opts.WatchPollInterval) * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := d.refresh(ctx); err != nil {
+				logrus.Warnln("Unable to reload the pipeline:", err)
+			}
+		}
+	}
+}
+
+// daemon keeps a runner warm between the operations asked through the daemon API.
+type daemon struct {
+	opts *config.SkaffoldOptions
+	out  io.Writer
+
+	lock    sync.Mutex
+	running bool
+	runner  *runner.SkaffoldRunner
+	config  *latest.SkaffoldPipeline
+	stamp   string
+	builds  []build.Artifact
+}
+
+// refresh reloads the pipeline if it changed and lists the dependencies of the
+// artifacts again if they changed. Nothing is done while an operation runs.
+func (d *daemon) refresh(ctx context.Context) error {
+	d.lock.Lock()
+	if d.running {
+		d.lock.Unlock()
+		return nil
+	}
+	err := d.reloadIfChanged()
+	config := d.config
+	d.lock.Unlock()
+
+	if err != nil {
+		return err
+	}
+	runner.PrefetchDependencies(ctx, config.Build.Artifacts)
+	return nil
+}
+
+// reloadIfChanged must be called with the lock held.
+func (d *daemon) reloadIfChanged() error {
+	stamp := configStamp(d.opts.ConfigurationFiles)
+	if d.runner != nil && stamp == d.stamp {
+		return nil
+	}
+
+	r, config, err := newRunner(d.opts)
+	if err != nil {
+		return errors.Wrap(err, "creating runner")
+	}
+
+	if d.runner != nil {
+		color.Default.Fprintln(d.out, "Pipeline reloaded")
+	}
+	d.runner, d.config, d.stamp = r, config, stamp
+	return nil
+}
+
+// configStamp identifies the version of the pipeline files. Remote
+// files are only loaded once.
+func configStamp(files []string) string {
+	var stamp strings.Builder
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			fmt.Fprintf(&stamp, "%s:%d:%d;", file, info.ModTime().UnixNano(), info.Size())
+		}
+	}
+	return stamp.String()
+}
+
+// start marks the beginning of an operation. It returns the up to date
+// runner and pipeline, and the function that marks the end of the operation.
+func (d *daemon) start() (*runner.SkaffoldRunner, *latest.SkaffoldPipeline, func(), error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if err := d.reloadIfChanged(); err != nil {
+		return nil, nil, nil, err
+	}
+	d.running = true
+	d.runner.ResetPods()
+
+	return d.runner, d.config, func() {
+		d.lock.Lock()
+		d.running = false
+		d.lock.Unlock()
+	}, nil
+}
+
+func (d *daemon) Build(ctx context.Context, out io.Writer) ([]build.Artifact, error) {
+	r, config, done, err := d.start()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	bRes, err := r.Build(ctx, out, r.Tagger, config.Build.Artifacts)
+	if err != nil {
+		return nil, errors.Wrap(err, "build step")
+	}
+
+	d.builds = bRes
+	return bRes, nil
+}
+
+func (d *daemon) Deploy(ctx context.Context, out io.Writer, builds []build.Artifact) error {
+	r, config, done, err := d.start()
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	if len(builds) == 0 {
+		builds = d.builds
+	}
+	if len(builds) == 0 {
+		return errors.New("no image to deploy: none was given and nothing was built yet")
+	}
+
+	if err := checkDeployTools(config); err != nil {
+		return err
+	}
+	return r.DeployAndCheck(ctx, out, builds)
+}
+
+func (d *daemon) Run(ctx context.Context, out io.Writer) error {
+	r, config, done, err := d.start()
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	if err := checkDeployTools(config); err != nil {
+		return err
+	}
+	return r.Run(ctx, out, config.Build.Artifacts)
+}
+
+func (d *daemon) Dev(ctx context.Context, out io.Writer) error {
+	r, config, done, err := d.start()
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	if err := checkDeployTools(config); err != nil {
+		return err
+	}
+
+	bRes, err := r.Dev(ctx, out, config.Build.Artifacts)
+	if len(bRes) > 0 {
+		d.builds = bRes
+	}
+
+	// The client is gone by then.
+	if d.opts.Cleanup {
+		if err := r.Cleanup(context.Background(), d.out); err != nil {
+			logrus.Warnln("cleanup:", err)
+		}
+	}
+	if err := r.Prune(context.Background(), d.out); err != nil {
+		logrus.Warnln("pruning images:", err)
+	}
+
+	return err
+}
@@ -29,6 +29,7 @@ func NewCmdDelete(out io.Writer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "delete",
 		Short: "Delete the deployed resources",
+		Long:  "Deletes everything the pipeline deploys: the kubectl and kustomize manifests and the helm releases. Use it to clean up after `skaffold run` or `skaffold deploy`.",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return delete(out)
@@ -41,12 +42,16 @@ func NewCmdDelete(out io.Writer) *cobra.Command {
 func delete(out io.Writer) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	catchCtrlC(cancel)
+	catchCtrlC(cancel, opts.ShutdownGracePeriod)
 
-	runner, _, err := newRunner(opts)
+	runner, config, err := newRunner(opts)
 	if err != nil {
 		return errors.Wrap(err, "creating runner")
 	}
 
+	if err := checkDeployTools(config); err != nil {
+		return err
+	}
+
 	return runner.Cleanup(ctx, out)
 }
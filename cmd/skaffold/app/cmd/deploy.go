@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"io/ioutil"
 
@@ -28,7 +29,8 @@ import (
 )
 
 var (
-	images []string
+	images             []string
+	buildArtifactsFlag string
 )
 
 // NewCmdDeploy describes the CLI command to deploy artifacts.
@@ -44,40 +46,87 @@ func NewCmdDeploy(out io.Writer) *cobra.Command {
 	AddRunDevFlags(cmd)
 	AddRunDeployFlags(cmd)
 	cmd.Flags().StringSliceVar(&images, "images", nil, "A list of images to deploy")
-	cmd.Flags().BoolVarP(&quietFlag, "quiet", "q", false, "Suppress the deploy output")
+	cmd.Flags().StringVarP(&buildArtifactsFlag, "build-artifacts", "a", "", "File containing the images to deploy, as written by `skaffold build --file-output`. Images given with --images take precedence")
+	cmd.Flags().BoolVarP(&opts.Quiet, "quiet", "q", false, "Suppress the deploy output and only print the deployed resources")
 	return cmd
 }
 
 func runDeploy(out io.Writer) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	catchCtrlC(cancel)
+	catchCtrlC(cancel, opts.ShutdownGracePeriod)
 
 	r, config, err := newRunner(opts)
 	if err != nil {
 		return errors.Wrap(err, "creating runner")
 	}
 
+	if err := checkDeployTools(config); err != nil {
+		return err
+	}
+
 	deployOut := out
-	if quietFlag {
+	if opts.Quiet {
 		deployOut = ioutil.Discard
 	}
 
+	builds, err := deployedArtifacts(buildArtifactsFlag, images)
+	if err != nil {
+		return err
+	}
+
+	if err := r.DeployAndCheck(ctx, out, builds); err != nil {
+		return err
+	}
+
+	if err := r.Verify(ctx, deployOut, builds); err != nil {
+		return errors.Wrap(err, "verify step")
+	}
+
+	return r.TailLogs(ctx, out, config.Build.Artifacts, builds)
+}
+
+// deployedArtifacts lists the images to deploy, read from a build output file
+// and from the command line. An image given on the command line replaces the
+// one with the same name in the file.
+func deployedArtifacts(buildArtifactsFile string, images []string) ([]build.Artifact, error) {
 	var builds []build.Artifact
+
+	if buildArtifactsFile != "" {
+		buf, err := ioutil.ReadFile(buildArtifactsFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading build artifacts")
+		}
+
+		var output BuildOutput
+		if err := json.Unmarshal(buf, &output); err != nil {
+			return nil, errors.Wrapf(err, "parsing build artifacts %s", buildArtifactsFile)
+		}
+		builds = output.Builds
+	}
+
 	for _, image := range images {
 		parsed, err := docker.ParseReference(image)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		builds = append(builds, build.Artifact{
+
+		artifact := build.Artifact{
 			ImageName: parsed.BaseName,
 			Tag:       image,
-		})
-	}
+		}
 
-	if _, err := r.Deploy(ctx, deployOut, builds); err != nil {
-		return err
+		replaced := false
+		for i := range builds {
+			if builds[i].ImageName == artifact.ImageName {
+				builds[i] = artifact
+				replaced = true
+			}
+		}
+		if !replaced {
+			builds = append(builds, artifact)
+		}
 	}
 
-	return r.TailLogs(ctx, out, config.Build.Artifacts, builds)
+	return builds, nil
 }
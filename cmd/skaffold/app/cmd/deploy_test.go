@@ -0,0 +1,87 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestDeployedArtifacts(t *testing.T) {
+	file, teardown := testutil.TempFile(t, "build.json", []byte(`{
+  "builds": [
+    {"imageName": "gcr.io/project/frontend", "tag": "gcr.io/project/frontend:v1"},
+    {"imageName": "gcr.io/project/backend", "tag": "gcr.io/project/backend:v1"}
+  ]
+}`))
+	defer teardown()
+
+	invalidFile, teardownInvalid := testutil.TempFile(t, "build.json", []byte("not json"))
+	defer teardownInvalid()
+
+	var tests = []struct {
+		description string
+		file        string
+		images      []string
+		expected    []build.Artifact
+		shouldErr   bool
+	}{
+		{
+			description: "images",
+			images:      []string{"gcr.io/project/frontend:v2"},
+			expected:    []build.Artifact{{ImageName: "gcr.io/project/frontend", Tag: "gcr.io/project/frontend:v2"}},
+		},
+		{
+			description: "build artifacts file",
+			file:        file,
+			expected: []build.Artifact{
+				{ImageName: "gcr.io/project/frontend", Tag: "gcr.io/project/frontend:v1"},
+				{ImageName: "gcr.io/project/backend", Tag: "gcr.io/project/backend:v1"},
+			},
+		},
+		{
+			description: "images override the file",
+			file:        file,
+			images:      []string{"gcr.io/project/backend:v2", "gcr.io/project/worker:v1"},
+			expected: []build.Artifact{
+				{ImageName: "gcr.io/project/frontend", Tag: "gcr.io/project/frontend:v1"},
+				{ImageName: "gcr.io/project/backend", Tag: "gcr.io/project/backend:v2"},
+				{ImageName: "gcr.io/project/worker", Tag: "gcr.io/project/worker:v1"},
+			},
+		},
+		{
+			description: "missing file",
+			file:        "does-not-exist.json",
+			shouldErr:   true,
+		},
+		{
+			description: "invalid file",
+			file:        invalidFile,
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			builds, err := deployedArtifacts(test.file, test.images)
+
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, builds)
+		})
+	}
+}
@@ -20,6 +20,8 @@ import (
 	"context"
 	"io"
 
+	"github.com/GoogleContainerTools/skaffold/cmd/skaffold/app/flags"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -31,50 +33,81 @@ func NewCmdDev(out io.Writer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "dev",
 		Short: "Runs a pipeline file in development mode",
+		Long:  "Builds, tests and deploys the pipeline, then watches the sources and redeploys on changes.\n\n" + exitCodesHelp,
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return dev(out)
 		},
 	}
 	AddRunDevFlags(cmd)
+	AddDevDebugFlags(cmd)
+	cmd.Flags().StringArrayVar(&opts.Intercept, "intercept", nil, "Run an artifact, chosen by image name or glob pattern, as a local process that receives the traffic of its workload through telepresence. The artifact needs an intercept configuration. Set multiple times for multiple artifacts.")
+	return cmd
+}
+
+// AddDevDebugFlags adds the flags shared by `dev` and `debug`.
+func AddDevDebugFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&opts.TailDev, "tail", true, "Stream logs from deployed objects")
 	cmd.Flags().StringVar(&opts.Trigger, "trigger", "polling", "How are changes detected? (polling or manual)")
 	cmd.Flags().BoolVar(&opts.Cleanup, "cleanup", true, "Delete deployments after dev mode is interrupted")
-	cmd.Flags().StringArrayVarP(&opts.Watch, "watch-image", "w", nil, "Choose which artifacts to watch. Artifacts with image names that contain the expression will be watched only. Default is to watch sources for all artifacts.")
+	cmd.Flags().StringArrayVarP(&opts.Watch, "watch-image", "w", nil, "Choose which artifacts to watch, by image name or glob pattern, matched against the full name or its last component (e.g. api, gcr.io/project/api or api-*). Set multiple times for multiple artifacts. Default is to watch sources for all artifacts.")
 	cmd.Flags().IntVarP(&opts.WatchPollInterval, "watch-poll-interval", "i", 1000, "Interval (in ms) between two checks for file changes.")
 	cmd.Flags().BoolVar(&opts.PortForward, "port-forward", true, "Port-forward exposed container ports within pods")
-	cmd.Flags().StringArrayVarP(&opts.CustomLabels, "label", "l", nil, "Add custom labels to deployed objects. Set multiple times for multiple labels.")
-	return cmd
+	cmd.Flags().StringArrayVarP(&opts.CustomLabels, "label", "l", nil, "Add custom labels to deployed objects, as key=value. Set multiple times for multiple labels. They are merged with the deploy.labels of skaffold.yaml.")
+	cmd.Flags().BoolVar(&opts.EnableRPC, "enable-rpc", false, "Enable the control API to trigger builds, syncs and deploys externally")
+	cmd.Flags().IntVar(&opts.RPCPort, "rpc-port", constants.DefaultRPCPort, "Port on which the control API listens")
+	addNegatedBoolFlag(cmd, &opts.DisableAutoBuild, "auto-build", "Build automatically when source files change")
+	addNegatedBoolFlag(cmd, &opts.DisableAutoSync, "auto-sync", "Sync automatically when synced files change")
+	addNegatedBoolFlag(cmd, &opts.DisableAutoDeploy, "auto-deploy", "Deploy automatically after a build or when manifests change")
+	cmd.Flags().BoolVar(&opts.PipelineDev, "pipeline", false, "Build new changes while the previous deploy and status check are still running")
+	cmd.Flags().StringVar(&opts.ConfigChange, "on-config-change", runner.ConfigChangePrompt, "What to do when skaffold.yaml changes: 'prompt' asks when possible and reloads otherwise, 'reload', 'ignore' or 'exit'")
+	cmd.Flags().BoolVar(&opts.Rollback, "rollback", false, "Roll back to the previously deployed manifests when a deployment fails to roll out. Implies --status-check")
+}
+
+// addNegatedBoolFlag adds a boolean flag, true by default, that sets
+// an option meaning the opposite.
+func addNegatedBoolFlag(cmd *cobra.Command, disabled *bool, name, usage string) {
+	f := cmd.Flags().VarPF(flags.NewNegatedBoolFlag(disabled), name, "", usage)
+	f.NoOptDefVal = "true"
 }
 
 func dev(out io.Writer) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	catchCtrlC(cancel)
+	catchCtrlC(cancel, opts.ShutdownGracePeriod)
+
+	if err := runner.CheckConfigChange(opts.ConfigChange); err != nil {
+		return err
+	}
+
+	if opts.DryRun {
+		return dryRun(ctx, out, true)
+	}
+
+	r, config, err := newRunner(opts)
+	if err != nil {
+		return errors.Wrap(err, "creating runner")
+	}
+
+	if err := checkDeployTools(config); err != nil {
+		return err
+	}
 
 	if opts.Cleanup {
+		// The runner that deployed knows the run ID of what it deployed.
 		defer func() {
-			if err := delete(out); err != nil {
+			if err := r.Cleanup(context.Background(), out); err != nil {
 				logrus.Warnln("cleanup:", err)
 			}
 		}()
 	}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			r, config, err := newRunner(opts)
-			if err != nil {
-				return errors.Wrap(err, "creating runner")
-			}
+	_, err = r.Dev(ctx, out, config.Build.Artifacts)
 
-			if _, err := r.Dev(ctx, out, config.Build.Artifacts); err != nil {
-				if errors.Cause(err) != runner.ErrorConfigurationChanged {
-					return err
-				}
-			}
-		}
+	// The context is cancelled by then.
+	if err := r.Prune(context.Background(), out); err != nil {
+		logrus.Warnln("pruning images:", err)
 	}
+
+	return err
 }
@@ -24,6 +24,8 @@ import (
 	"time"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
@@ -32,6 +34,12 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var (
+	diagnoseYaml         bool
+	diagnoseDependencies bool
 )
 
 // NewCmdDiagnose describes the CLI command to diagnose skaffold.
@@ -44,24 +52,43 @@ func NewCmdDiagnose(out io.Writer) *cobra.Command {
 			return doDiagnose(out)
 		},
 	}
-	cmd.Flags().StringVarP(&opts.ConfigurationFile, "filename", "f", "skaffold.yaml", "Filename or URL to the pipeline file")
+	AddFilenameFlag(cmd)
+	cmd.Flags().StringArrayVarP(&opts.Profiles, "profile", "p", nil, "Activate profiles by name")
+	cmd.Flags().BoolVar(&diagnoseYaml, "yaml", false, "Only print the fully resolved pipeline, with profiles, overrides and defaults applied")
+	cmd.Flags().BoolVar(&diagnoseDependencies, "dependencies", false, "List the files each artifact depends on and the expanded manifests")
 	return cmd
 }
 
 func doDiagnose(out io.Writer) error {
-	_, config, err := newRunner(opts)
+	r, config, err := newRunner(opts)
 	if err != nil {
 		return errors.Wrap(err, "creating runner")
 	}
 
+	if diagnoseYaml {
+		buf, err := yaml.Marshal(config)
+		if err != nil {
+			return errors.Wrap(err, "marshalling pipeline")
+		}
+		out.Write(buf)
+		return nil
+	}
+
 	fmt.Fprintln(out, "Skaffold version:", version.Get().GitCommit)
 	fmt.Fprintln(out, "Configuration version:", config.APIVersion)
+	fmt.Fprintln(out, "Builder:", r.Builder.Labels()[constants.Labels.Builder])
+	fmt.Fprintln(out, "Tagger:", r.Tagger.Labels()[constants.Labels.TagPolicy])
+	fmt.Fprintln(out, "Deployer:", r.Deployer.Labels()[constants.Labels.Deployer])
 	fmt.Fprintln(out, "Number of artifacts:", len(config.Build.Artifacts))
 
 	if err := diagnoseArtifacts(out, config.Build.Artifacts); err != nil {
 		return errors.Wrap(err, "running diagnostic on artifacts")
 	}
 
+	if err := diagnoseDeployer(out, r.Deployer); err != nil {
+		return errors.Wrap(err, "running diagnostic on deployer")
+	}
+
 	return nil
 }
 
@@ -102,11 +129,40 @@ func diagnoseArtifacts(out io.Writer, artifacts []*latest.Artifact) error {
 		}
 
 		fmt.Fprintf(out, " - Time to compute mTimes on dependencies: %v (2nd time: %v)\n", timeMTimes1, timeMTimes2)
+
+		if diagnoseDependencies {
+			listFiles(out, deps)
+		}
+	}
+
+	return nil
+}
+
+func diagnoseDeployer(out io.Writer, deployer deploy.Deployer) error {
+	color.Default.Fprintf(out, "\nDeployer\n")
+
+	start := time.Now()
+	deps, err := deployer.Dependencies()
+	if err != nil {
+		return errors.Wrap(err, "listing deployer dependencies")
+	}
+
+	fmt.Fprintln(out, " - Dependencies:", len(deps), "files")
+	fmt.Fprintf(out, " - Time to list dependencies: %v\n", time.Since(start))
+
+	if diagnoseDependencies {
+		listFiles(out, deps)
 	}
 
 	return nil
 }
 
+func listFiles(out io.Writer, files []string) {
+	for _, file := range files {
+		fmt.Fprintln(out, "   ", file)
+	}
+}
+
 func timeToListDependencies(ctx context.Context, a *latest.Artifact) (time.Duration, []string, error) {
 	start := time.Now()
 
@@ -152,6 +208,8 @@ func typeOfArtifact(a *latest.Artifact) string {
 		return "Jib Gradle artifact"
 	case a.JibMavenArtifact != nil:
 		return "Jib Maven artifact"
+	case a.TarballArtifact != nil:
+		return "Tarball artifact"
 	default:
 		return "Unknown artifact"
 	}
@@ -0,0 +1,59 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdExec describes the CLI command to run a shell in a running container.
+func NewCmdExec(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exec IMAGE [-- COMMAND [ARG...]]",
+		Short: "Open a shell in a running container of an artifact",
+		Long:  "Runs a command, `sh` by default, in the most recent running container of an artifact's image, the same containers files are synced to. No need to look up pod names with kubectl.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execInContainer(os.Stdin, out, args[0], args[1:])
+		},
+	}
+	AddFilenameFlag(cmd)
+	cmd.Flags().StringArrayVarP(&opts.Profiles, "profile", "p", nil, "Activate profiles by name")
+	cmd.Flags().StringVarP(&opts.Namespace, "namespace", "n", "", "Only look for containers in the specified namespace")
+	cmd.Flags().StringVarP(&opts.DefaultRepo, "default-repo", "d", "", "Default repository value (overrides global config)")
+	cmd.Flags().StringVar(&opts.KubeConfig, "kubeconfig", "", "Path to the kubeconfig file to use (overrides KUBECONFIG)")
+	cmd.Flags().StringVar(&opts.KubeContext, "kube-context", "", "Kubernetes context to use instead of the current context of the kubeconfig")
+	cmd.Flags().StringVar(&opts.KubectlBinary, "kubectl", "", "Path to the kubectl binary to run")
+	return cmd
+}
+
+func execInContainer(in io.Reader, out io.Writer, imageName string, command []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runner, _, err := newRunner(opts)
+	if err != nil {
+		return errors.Wrap(err, "creating runner")
+	}
+
+	return runner.Exec(ctx, in, out, imageName, command)
+}
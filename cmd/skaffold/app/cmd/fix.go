@@ -26,7 +26,6 @@ import (
 	schemautil "github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/util"
 
 	"github.com/pkg/errors"
-	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	yaml "gopkg.in/yaml.v2"
 )
@@ -35,21 +34,18 @@ func NewCmdFix(out io.Writer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "fix",
 		Short: "Converts old skaffold.yaml to newest schema version",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg, err := schema.ParseConfig(opts.ConfigurationFile, false)
 			if err != nil {
-				logrus.Error(err)
-				return
+				return errors.Wrap(err, "parsing skaffold config")
 			}
 
 			if cfg.GetVersion() == latest.Version {
 				color.Default.Fprintln(out, "config is already latest version")
-				return
+				return nil
 			}
 
-			if err := runFix(out, cfg); err != nil {
-				logrus.Errorf("fix: %s", err)
-			}
+			return errors.Wrap(runFix(out, cfg), "fix")
 		},
 		Args: cobra.NoArgs,
 	}
@@ -23,6 +23,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
@@ -70,7 +71,7 @@ func NewCmdInit(out io.Writer) *cobra.Command {
 func doInit(out io.Writer) error {
 	rootDir := "."
 
-	var potentialConfigs, k8sConfigs, dockerfiles, images []string
+	var potentialConfigs, k8sConfigs, dockerfiles, images, chartDirs []string
 	err := filepath.Walk(rootDir, func(path string, f os.FileInfo, e error) error {
 		if f.IsDir() {
 			return nil
@@ -78,6 +79,15 @@ func doInit(out io.Writer) error {
 		if strings.HasPrefix(path, ".") {
 			return nil
 		}
+		if filepath.Base(path) == helmChartFile {
+			if isInHelmChart(path, chartDirs) {
+				// subcharts are deployed with their parent
+				return nil
+			}
+			logrus.Infof("existing helm chart found: %s", filepath.Dir(path))
+			chartDirs = append(chartDirs, filepath.Dir(path))
+			return nil
+		}
 		if util.IsSupportedKubernetesFormat(path) {
 			potentialConfigs = append(potentialConfigs, path)
 		}
@@ -92,7 +102,27 @@ func doInit(out io.Writer) error {
 		return err
 	}
 
+	var charts []helmChart
+	for _, dir := range chartDirs {
+		chart := parseHelmChart(dir)
+		charts = append(charts, chart)
+
+		var keys []string
+		for key := range chart.Images {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			images = append(images, chart.Images[key])
+		}
+	}
+
 	for _, file := range potentialConfigs {
+		if isInHelmChart(file, chartDirs) {
+			// chart templates are not valid kubernetes yaml
+			continue
+		}
+
 		if !force {
 			config, err := schema.ParseConfig(file, true)
 			if err == nil && config != nil {
@@ -118,8 +148,8 @@ func doInit(out io.Writer) error {
 			return errors.New("one or more valid Dockerfiles must be present to run skaffold; please provide at least one Dockerfile and try again")
 		}
 
-		if len(k8sConfigs) == 0 {
-			return errors.New("one or more valid kubernetes manifests is required to run skaffold")
+		if len(k8sConfigs) == 0 && len(charts) == 0 {
+			return errors.New("one or more valid kubernetes manifests or helm charts is required to run skaffold")
 		}
 
 		if cliArtifacts != nil {
@@ -132,7 +162,7 @@ func doInit(out io.Writer) error {
 		}
 	}
 
-	pipeline, err := generateSkaffoldPipeline(k8sConfigs, pairs)
+	pipeline, err := generateSkaffoldPipeline(k8sConfigs, charts, pairs)
 	if err != nil {
 		return err
 	}
@@ -261,7 +291,7 @@ func processBuildArtifacts(pairs []dockerfilePair) latest.BuildConfig {
 	return config
 }
 
-func generateSkaffoldPipeline(k8sConfigs []string, dockerfilePairs []dockerfilePair) ([]byte, error) {
+func generateSkaffoldPipeline(k8sConfigs []string, charts []helmChart, dockerfilePairs []dockerfilePair) ([]byte, error) {
 	// if we're here, the user has no skaffold yaml so we need to generate one
 	// if the user doesn't have any k8s yamls, generate one for each dockerfile
 	logrus.Info("generating skaffold config")
@@ -275,12 +305,17 @@ func generateSkaffoldPipeline(k8sConfigs []string, dockerfilePairs []dockerfileP
 	}
 
 	pipeline.Build = processBuildArtifacts(dockerfilePairs)
-	pipeline.Deploy = latest.DeployConfig{
-		DeployType: latest.DeployType{
-			KubectlDeploy: &latest.KubectlDeploy{
-				Manifests: k8sConfigs,
-			},
-		},
+	pipeline.Deploy = latest.DeployConfig{}
+	// A pipeline has a single deployer: helm charts take precedence over plain manifests.
+	if len(charts) > 0 {
+		if len(k8sConfigs) > 0 {
+			logrus.Warnf("helm charts found, the kubernetes manifests won't be deployed: %v", k8sConfigs)
+		}
+		pipeline.Deploy.HelmDeploy = processHelmCharts(charts, dockerfilePairs)
+	} else {
+		pipeline.Deploy.KubectlDeploy = &latest.KubectlDeploy{
+			Manifests: k8sConfigs,
+		}
 	}
 
 	pipelineStr, err := yaml.Marshal(pipeline)
@@ -342,17 +377,154 @@ func parseImagesFromYaml(doc interface{}) []string {
 		}
 	case map[interface{}]interface{}:
 		for k, v := range t {
-			if k.(string) != "image" {
-				images = append(images, parseImagesFromYaml(v)...)
+			if image, ok := v.(string); ok && k == "image" {
+				images = append(images, image)
 				continue
 			}
 
-			images = append(images, v.(string))
+			images = append(images, parseImagesFromYaml(v)...)
 		}
 	}
 	return images
 }
 
+const helmChartFile = "Chart.yaml"
+
+// helmChart is a chart found in the repository, with the images
+// set in its values, keyed by their path in the values.
+// Repositories are the keys of the images set with the
+// `image: {repository: ..., tag: ...}` layout.
+type helmChart struct {
+	Name         string
+	Path         string
+	Images       map[string]string
+	Repositories map[string]bool
+}
+
+func isInHelmChart(path string, chartDirs []string) bool {
+	for _, dir := range chartDirs {
+		// a chart at the root of the repository contains every file
+		if dir == "." || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseHelmChart finds the name of a chart and the images set in its default values.
+func parseHelmChart(dir string) helmChart {
+	chart := helmChart{
+		Name:         helmChartName(dir),
+		Path:         dir,
+		Images:       map[string]string{},
+		Repositories: map[string]bool{},
+	}
+
+	buf, err := ioutil.ReadFile(filepath.Join(dir, "values.yaml"))
+	if err != nil {
+		logrus.Infof("no default values for helm chart %s: %s", dir, err)
+		return chart
+	}
+
+	var values map[interface{}]interface{}
+	if err := yaml.Unmarshal(buf, &values); err != nil {
+		logrus.Infof("invalid values for helm chart %s: %s", dir, err)
+		return chart
+	}
+
+	parseImageValues(values, "", &chart)
+	return chart
+}
+
+// helmChartName is the name given to a chart in its Chart.yaml or, if it
+// has none, the name of its directory.
+func helmChartName(dir string) string {
+	var metadata struct {
+		Name string `yaml:"name"`
+	}
+	if buf, err := ioutil.ReadFile(filepath.Join(dir, helmChartFile)); err == nil {
+		if err := yaml.Unmarshal(buf, &metadata); err != nil {
+			logrus.Infof("invalid %s for helm chart %s: %s", helmChartFile, dir, err)
+		}
+	}
+	if metadata.Name != "" {
+		return metadata.Name
+	}
+
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	return filepath.Base(dir)
+}
+
+// parseImageValues finds the images set in the values of a chart, either as
+// `image: name:tag` or as `image: {repository: name, tag: tag}`. A separate
+// registry value, as some charts have, isn't taken into account.
+func parseImageValues(values map[interface{}]interface{}, prefix string, chart *helmChart) {
+	for k, v := range values {
+		key := fmt.Sprintf("%s%v", prefix, k)
+
+		switch t := v.(type) {
+		case string:
+			if k == "image" {
+				chart.Images[key] = t
+			}
+		case map[interface{}]interface{}:
+			if repository, ok := t["repository"].(string); ok && k == "image" {
+				chart.Images[key] = repository
+				chart.Repositories[key] = true
+				continue
+			}
+			parseImageValues(t, key+".", chart)
+		}
+	}
+}
+
+// processHelmCharts generates a release for each chart. The chart values
+// that reference built images are set to the freshly built images.
+// A release sets all its images with the same layout: if a chart mixes
+// both, the images set with a repository and a tag are left out.
+func processHelmCharts(charts []helmChart, pairs []dockerfilePair) *latest.HelmDeploy {
+	built := map[string]bool{}
+	for _, pair := range pairs {
+		built[pair.ImageName] = true
+	}
+
+	helm := &latest.HelmDeploy{}
+	for _, chart := range charts {
+		release := latest.HelmRelease{
+			Name:      strings.ToLower(chart.Name),
+			ChartPath: chart.Path,
+		}
+
+		images, repositories := map[string]string{}, map[string]string{}
+		for key, image := range chart.Images {
+			if !built[image] {
+				continue
+			}
+			if chart.Repositories[key] {
+				repositories[key] = image
+			} else {
+				images[key] = image
+			}
+		}
+
+		switch {
+		case len(images) > 0:
+			release.Values = images
+			if len(repositories) > 0 {
+				logrus.Warnf("helm chart %s sets images both as single values and with a repository and a tag, add these to the configuration manually: %v", chart.Path, repositories)
+			}
+		case len(repositories) > 0:
+			release.Values = repositories
+			release.ImageStrategy.HelmConventionConfig = &latest.HelmConventionConfig{}
+		}
+
+		helm.Releases = append(helm.Releases, release)
+	}
+	return helm
+}
+
 type dockerfilePair struct {
 	Dockerfile string
 	ImageName  string
@@ -18,6 +18,8 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
@@ -31,7 +33,7 @@ deploy:
   kubectl: {}
 `, latest.Version)
 
-	buf, err := generateSkaffoldPipeline(nil, nil)
+	buf, err := generateSkaffoldPipeline(nil, nil, nil)
 
 	testutil.CheckErrorAndDeepEqual(t, false, err, expectedYaml, string(buf))
 }
@@ -56,7 +58,187 @@ deploy:
 		ImageName:  "docker/image",
 	}}
 
-	buf, err := generateSkaffoldPipeline(k8sConfigs, dockerfilePairs)
+	buf, err := generateSkaffoldPipeline(k8sConfigs, nil, dockerfilePairs)
 
 	testutil.CheckErrorAndDeepEqual(t, false, err, expectedYaml, string(buf))
 }
+
+func TestGenerateSkaffoldPipelineWithHelmChart(t *testing.T) {
+	expectedYaml := fmt.Sprintf(`apiVersion: %s
+kind: Config
+build:
+  artifacts:
+  - image: docker/image
+    context: app
+deploy:
+  helm:
+    releases:
+    - name: chart
+      chartPath: charts/chart
+      values:
+        app.image: docker/image
+`, latest.Version)
+
+	charts := []helmChart{{
+		Name: "chart",
+		Path: "charts/chart",
+		Images: map[string]string{
+			"app.image":   "docker/image",
+			"redis.image": "redis",
+		},
+	}}
+	dockerfilePairs := []dockerfilePair{{
+		Dockerfile: "app/Dockerfile",
+		ImageName:  "docker/image",
+	}}
+
+	buf, err := generateSkaffoldPipeline(nil, charts, dockerfilePairs)
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, expectedYaml, string(buf))
+}
+
+func TestGenerateSkaffoldPipelineWithHelmChartAndManifests(t *testing.T) {
+	expectedYaml := fmt.Sprintf(`apiVersion: %s
+kind: Config
+deploy:
+  helm:
+    releases:
+    - name: chart
+      chartPath: charts/chart
+`, latest.Version)
+
+	charts := []helmChart{{
+		Name: "chart",
+		Path: "charts/chart",
+	}}
+
+	buf, err := generateSkaffoldPipeline([]string{"pod.yaml"}, charts, nil)
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, expectedYaml, string(buf))
+}
+
+func TestParseImageValues(t *testing.T) {
+	values := map[interface{}]interface{}{
+		"image": "web",
+		"redis": map[interface{}]interface{}{
+			"image": "redis",
+		},
+		"worker": map[interface{}]interface{}{
+			"image": map[interface{}]interface{}{
+				"repository": "worker",
+			},
+		},
+	}
+
+	chart := helmChart{Images: map[string]string{}, Repositories: map[string]bool{}}
+	parseImageValues(values, "", &chart)
+
+	testutil.CheckDeepEqual(t, map[string]string{"image": "web", "redis.image": "redis", "worker.image": "worker"}, chart.Images)
+	testutil.CheckDeepEqual(t, map[string]bool{"worker.image": true}, chart.Repositories)
+}
+
+func TestGenerateSkaffoldPipelineWithRepositoryValues(t *testing.T) {
+	expectedYaml := fmt.Sprintf(`apiVersion: %s
+kind: Config
+build:
+  artifacts:
+  - image: docker/image
+    context: app
+deploy:
+  helm:
+    releases:
+    - name: chart
+      chartPath: .
+      values:
+        app.image: docker/image
+      imageStrategy:
+        helm: {}
+`, latest.Version)
+
+	charts := []helmChart{{
+		Name:         "Chart",
+		Path:         ".",
+		Images:       map[string]string{"app.image": "docker/image", "redis.image": "redis"},
+		Repositories: map[string]bool{"app.image": true, "redis.image": true},
+	}}
+	dockerfilePairs := []dockerfilePair{{
+		Dockerfile: "app/Dockerfile",
+		ImageName:  "docker/image",
+	}}
+
+	buf, err := generateSkaffoldPipeline(nil, charts, dockerfilePairs)
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, expectedYaml, string(buf))
+}
+
+func TestIsInHelmChart(t *testing.T) {
+	var tests = []struct {
+		description string
+		path        string
+		chartDirs   []string
+		expected    bool
+	}{
+		{
+			description: "template",
+			path:        filepath.Join("charts", "web", "templates", "deployment.yaml"),
+			chartDirs:   []string{filepath.Join("charts", "web")},
+			expected:    true,
+		},
+		{
+			description: "outside of the chart",
+			path:        filepath.Join("k8s", "deployment.yaml"),
+			chartDirs:   []string{filepath.Join("charts", "web")},
+		},
+		{
+			description: "chart with a common prefix",
+			path:        filepath.Join("charts", "web-worker", "values.yaml"),
+			chartDirs:   []string{filepath.Join("charts", "web")},
+		},
+		{
+			description: "chart at the root",
+			path:        filepath.Join("templates", "deployment.yaml"),
+			chartDirs:   []string{"."},
+			expected:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			testutil.CheckDeepEqual(t, test.expected, isInHelmChart(test.path, test.chartDirs))
+		})
+	}
+}
+
+func TestParseHelmChartAtTheRoot(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	tmpDir.Write("Chart.yaml", "name: web\nversion: 0.1.0").
+		Write("values.yaml", "image:\n  repository: gcr.io/project/web\n  tag: latest")
+
+	wd, _ := os.Getwd()
+	os.Chdir(tmpDir.Root())
+	defer os.Chdir(wd)
+
+	chart := parseHelmChart(".")
+
+	testutil.CheckDeepEqual(t, helmChart{
+		Name:         "web",
+		Path:         ".",
+		Images:       map[string]string{"image": "gcr.io/project/web"},
+		Repositories: map[string]bool{"image": true},
+	}, chart)
+}
+
+func TestHelmChartNameWithoutMetadata(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	tmpDir.Mkdir("web")
+
+	wd, _ := os.Getwd()
+	os.Chdir(tmpDir.Path("web"))
+	defer os.Chdir(wd)
+
+	testutil.CheckDeepEqual(t, "web", helmChartName("."))
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cpuProfile string
+	memProfile string
+	traceFile  string
+
+	profilingLock sync.Mutex
+	stopProfiling []func() error
+)
+
+// AddProfilingFlags adds the flags that write Go profiles of skaffold itself.
+func AddProfilingFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&cpuProfile, "cpuprofile", "", "Write a CPU profile of skaffold to this file, to be read with 'go tool pprof'")
+	cmd.PersistentFlags().StringVar(&memProfile, "memprofile", "", "Write a heap profile of skaffold to this file when it exits, to be read with 'go tool pprof'")
+	cmd.PersistentFlags().StringVar(&traceFile, "trace", "", "Write an execution trace of skaffold to this file, to be read with 'go tool trace'")
+}
+
+// startProfiling starts the CPU profiling and the tracing asked for on the command line.
+func startProfiling() error {
+	if cpuProfile != "" {
+		f, err := os.Create(cpuProfile)
+		if err != nil {
+			return errors.Wrap(err, "creating cpu profile")
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return errors.Wrap(err, "starting cpu profile")
+		}
+		stopProfiling = append(stopProfiling, func() error {
+			pprof.StopCPUProfile()
+			return f.Close()
+		})
+	}
+
+	if traceFile != "" {
+		f, err := os.Create(traceFile)
+		if err != nil {
+			return errors.Wrap(err, "creating trace")
+		}
+		if err := trace.Start(f); err != nil {
+			f.Close()
+			return errors.Wrap(err, "starting trace")
+		}
+		stopProfiling = append(stopProfiling, func() error {
+			trace.Stop()
+			return f.Close()
+		})
+	}
+
+	if memProfile != "" {
+		stopProfiling = append(stopProfiling, func() error {
+			f, err := os.Create(memProfile)
+			if err != nil {
+				return errors.Wrap(err, "creating memory profile")
+			}
+			defer f.Close()
+
+			runtime.GC()
+			return errors.Wrap(pprof.WriteHeapProfile(f), "writing memory profile")
+		})
+	}
+
+	return nil
+}
+
+// StopProfiling flushes the profiles. It has to be called before skaffold exits.
+func StopProfiling() {
+	profilingLock.Lock()
+	defer profilingLock.Unlock()
+
+	for _, stop := range stopProfiling {
+		if err := stop(); err != nil {
+			logrus.Warnln("Unable to write profile:", err)
+		}
+	}
+	stopProfiling = nil
+}
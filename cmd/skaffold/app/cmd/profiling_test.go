@@ -0,0 +1,48 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestProfiling(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	defer func(c, m, tr string) { cpuProfile, memProfile, traceFile = c, m, tr }(cpuProfile, memProfile, traceFile)
+	cpuProfile = tmpDir.Path("cpu.pprof")
+	memProfile = tmpDir.Path("mem.pprof")
+	traceFile = tmpDir.Path("trace.out")
+
+	err := startProfiling()
+	testutil.CheckError(t, false, err)
+	StopProfiling()
+
+	for _, file := range []string{cpuProfile, memProfile, traceFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() == 0 {
+			t.Errorf("%s is empty", file)
+		}
+	}
+}
@@ -29,6 +29,7 @@ func NewCmdRun(out io.Writer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "run",
 		Short: "Runs a pipeline file",
+		Long:  "Builds, tests and deploys the pipeline once.\n\n" + exitCodesHelp,
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return run(out)
@@ -38,18 +39,27 @@ func NewCmdRun(out io.Writer) *cobra.Command {
 	AddRunDeployFlags(cmd)
 
 	cmd.Flags().StringVarP(&opts.CustomTag, "tag", "t", "", "The optional custom tag to use for images which overrides the current Tagger configuration")
+	cmd.Flags().BoolVarP(&opts.Quiet, "quiet", "q", false, "Suppress the build, test and deploy output and only print the images built, the resources deployed and errors")
 	return cmd
 }
 
 func run(out io.Writer) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	catchCtrlC(cancel)
+	catchCtrlC(cancel, opts.ShutdownGracePeriod)
+
+	if opts.DryRun {
+		return dryRun(ctx, out, false)
+	}
 
 	runner, config, err := newRunner(opts)
 	if err != nil {
 		return errors.Wrap(err, "creating runner")
 	}
 
+	if err := checkDeployTools(config); err != nil {
+		return err
+	}
+
 	return runner.Run(ctx, out, config.Build.Artifacts)
 }
@@ -17,47 +17,247 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
+	"io"
+
 	configutil "github.com/GoogleContainerTools/skaffold/cmd/skaffold/app/cmd/config"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/exitcode"
+	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 // newRunner creates a SkaffoldRunner and returns the SkaffoldPipeline associated with it.
 func newRunner(opts *config.SkaffoldOptions) (*runner.SkaffoldRunner, *latest.SkaffoldPipeline, error) {
-	parsed, err := schema.ParseConfig(opts.ConfigurationFile, true)
+	config, err := setUpAndLoadConfig(opts)
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "parsing skaffold config")
+		return nil, nil, err
 	}
 
-	if err := schema.CheckVersionIsLatest(parsed.GetVersion()); err != nil {
-		return nil, nil, errors.Wrap(err, "invalid config")
+	runner, err := runner.NewForConfig(opts, config)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "creating runner")
+	}
+	runner.LoadConfig = func() (*latest.SkaffoldPipeline, error) {
+		return loadConfig(opts)
 	}
 
-	config := parsed.(*latest.SkaffoldPipeline)
-	err = schema.ApplyProfiles(config, opts.Profiles)
+	return runner, config, nil
+}
+
+// setUpAndLoadConfig applies the options that configure skaffold globally,
+// then loads the pipeline.
+func setUpAndLoadConfig(opts *config.SkaffoldOptions) (*latest.SkaffoldPipeline, error) {
+	kubectx.SetKubeConfig(opts.KubeConfig, opts.KubeContext)
+	if err := applyGlobalConfig(opts); err != nil {
+		return nil, errors.Wrap(err, "reading global config")
+	}
+	kubectx.SetKubectl(opts.KubectlBinary, opts.KubectlWrapper)
+	docker.SetLocalCluster(opts.LocalCluster, opts.LocalClusterRules)
+	warnings.SetStrict(opts.Strict)
+	if err := docker.ConfigureRegistries(opts.InsecureRegistries, opts.RegistryCABundle); err != nil {
+		return nil, errors.Wrap(err, "configuring registries")
+	}
+	docker.SetRegistryMirrors(opts.RegistryMirrors)
+	if err := setGCSUploadOptions(); err != nil {
+		return nil, err
+	}
+	if err := docker.SetBuildOutput(opts.DockerOutput); err != nil {
+		return nil, err
+	}
+	if err := event.SetOutput(opts.EventOutput, opts.EventFile); err != nil {
+		return nil, err
+	}
+
+	config, err := loadConfig(opts)
+	if err != nil {
+		return nil, exitcode.Wrap(err, exitcode.Config)
+	}
+	return config, nil
+}
+
+// setGCSUploadOptions configures the uploads of build contexts to GCS with the global config.
+func setGCSUploadOptions() error {
+	level, chunkSizeMB, parallelism, err := configutil.GetGCSUploadOptions()
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "applying profiles")
+		return errors.Wrap(err, "getting gcs upload options")
+	}
+	return errors.Wrap(docker.SetGCSUploadOptions(docker.GCSUploadOptions{
+		CompressionLevel: level,
+		ChunkSizeMB:      chunkSizeMB,
+		Parallelism:      parallelism,
+	}), "configuring gcs uploads")
+}
+
+// dryRun prints what a run or dev session would do, without calling docker or the cluster.
+func dryRun(ctx context.Context, out io.Writer, reuse bool) error {
+	config, err := setUpAndLoadConfig(opts)
+	if err != nil {
+		return err
+	}
+	return runner.DryRun(ctx, out, opts, config, reuse)
+}
+
+// checkDeployTools fails early if the CLIs needed by the deployer are missing.
+func checkDeployTools(config *latest.SkaffoldPipeline) error {
+	return deploy.CheckTools(context.Background(), &config.Deploy)
+}
+
+// loadConfig parses and merges the skaffold configurations and applies the profiles,
+// the command line overrides, the environment variables, the default repo and the debug settings.
+// The paths of the configurations are resolved before the overrides are applied:
+// a relative path given with --set is relative to the current directory.
+func loadConfig(opts *config.SkaffoldOptions) (*latest.SkaffoldPipeline, error) {
+	config, err := parseConfigs(opts.ConfigurationFiles, opts.Profiles)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = schema.ApplyOverrides(config, opts.Overrides); err != nil {
+		return nil, errors.Wrap(err, "applying overrides")
+	}
+
+	if err = schema.ExpandEnv(config, opts.AllowedEnv); err != nil {
+		return nil, errors.Wrap(err, "expanding environment variables")
 	}
 
 	defaultRepo, err := configutil.GetDefaultRepo(opts.DefaultRepo)
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "getting default repo")
+		return nil, errors.Wrap(err, "getting default repo")
 	}
 
 	if err = applyDefaultRepoSubstitution(config, defaultRepo); err != nil {
-		return nil, nil, errors.Wrap(err, "substituting default repos")
+		return nil, errors.Wrap(err, "substituting default repos")
 	}
 
-	runner, err := runner.NewForConfig(opts, config)
+	gcbProject, err := configutil.GetGCBProject()
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "creating runner")
+		return nil, errors.Wrap(err, "getting gcb project")
 	}
+	applyGCBProject(config, gcbProject)
 
-	return runner, config, nil
+	if opts.DebugMode {
+		applyDebugBuildArgs(config)
+	}
+
+	if err := schema.Validate(config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// parseConfigs parses the pipeline files, applies their profiles and merges them.
+// When multiple files are given, each profile has to be defined in at least one of them.
+func parseConfigs(files []string, profiles []string) (*latest.SkaffoldPipeline, error) {
+	var configs []*latest.SkaffoldPipeline
+	defined := map[string]bool{}
+
+	for _, file := range files {
+		config, err := schema.ParseAndUpgradeConfig(file, true)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing skaffold config %s", file)
+		}
+
+		activated := profiles
+		if len(files) > 1 {
+			activated = nil
+			for _, name := range profiles {
+				for _, profile := range config.Profiles {
+					if profile.Name == name {
+						activated = append(activated, name)
+						defined[name] = true
+					}
+				}
+			}
+		}
+
+		if err := schema.ApplyProfiles(config, activated); err != nil {
+			return nil, errors.Wrapf(err, "applying profiles to %s", file)
+		}
+		schema.ResolvePaths(config, file)
+		configs = append(configs, config)
+	}
+
+	if len(files) == 1 {
+		return configs[0], nil
+	}
+
+	for _, name := range profiles {
+		if !defined[name] {
+			return nil, errors.Errorf("couldn't find profile %s", name)
+		}
+	}
+	config, err := schema.MergeConfigs(configs, files)
+	return config, errors.Wrap(err, "merging skaffold configs")
+}
+
+// applyGlobalConfig fills the options that were not given on the
+// command line with the values of the global skaffold config.
+func applyGlobalConfig(opts *config.SkaffoldOptions) error {
+	namespace, err := configutil.GetNamespace(opts.Namespace)
+	if err != nil {
+		return errors.Wrap(err, "getting namespace")
+	}
+	opts.Namespace = namespace
+
+	localCluster, err := configutil.GetLocalCluster()
+	if err != nil {
+		return errors.Wrap(err, "getting local-cluster")
+	}
+	opts.LocalCluster = localCluster
+
+	localClusterRules, err := configutil.GetLocalClusterRules()
+	if err != nil {
+		return errors.Wrap(err, "getting local-clusters")
+	}
+	opts.LocalClusterRules = localClusterRules
+
+	insecureRegistries, err := configutil.GetInsecureRegistries(opts.InsecureRegistries)
+	if err != nil {
+		return errors.Wrap(err, "getting insecure-registries")
+	}
+	opts.InsecureRegistries = insecureRegistries
+
+	caBundle, err := configutil.GetRegistryCABundle(opts.RegistryCABundle)
+	if err != nil {
+		return errors.Wrap(err, "getting registry-ca-bundle")
+	}
+	opts.RegistryCABundle = caBundle
+
+	mirrors, err := configutil.GetRegistryMirrors(opts.RegistryMirrors)
+	if err != nil {
+		return errors.Wrap(err, "getting registry-mirrors")
+	}
+	opts.RegistryMirrors = mirrors
+
+	kubectlBinary, kubectlWrapper, err := configutil.GetKubectl(opts.KubectlBinary, opts.KubectlWrapper)
+	if err != nil {
+		return errors.Wrap(err, "getting kubectl")
+	}
+	opts.KubectlBinary = kubectlBinary
+	opts.KubectlWrapper = kubectlWrapper
+
+	return nil
+}
+
+// applyGCBProject sets the project used by Google Cloud Build,
+// if it's not already in the skaffold config.
+func applyGCBProject(config *latest.SkaffoldPipeline, project string) {
+	gcb := config.Build.GoogleCloudBuild
+	if gcb != nil && gcb.ProjectID == "" {
+		gcb.ProjectID = project
+	}
 }
 
 func applyDefaultRepoSubstitution(config *latest.SkaffoldPipeline, defaultRepo string) error {
@@ -73,3 +273,30 @@ func applyDefaultRepoSubstitution(config *latest.SkaffoldPipeline, defaultRepo s
 	}
 	return nil
 }
+
+// applyDebugBuildArgs passes the Go compiler flags that disable optimizations
+// to the Docker builds of Go code. Dockerfiles can use them with
+// `ARG SKAFFOLD_GO_GCFLAGS`. Other runtimes don't need to be rebuilt to be debugged.
+func applyDebugBuildArgs(config *latest.SkaffoldPipeline) {
+	gcflags := constants.DebugGoGcflags
+
+	for _, artifact := range config.Build.Artifacts {
+		if artifact.DockerArtifact == nil {
+			continue
+		}
+		buildsGo, err := docker.BuildsGo(artifact.Workspace, artifact.DockerArtifact)
+		if err != nil {
+			logrus.Warnf("Unable to tell if %s is written in Go, not disabling compiler optimizations: %s", artifact.ImageName, err)
+			continue
+		}
+		if !buildsGo {
+			continue
+		}
+		if artifact.DockerArtifact.BuildArgs == nil {
+			artifact.DockerArtifact.BuildArgs = map[string]*string{}
+		}
+		if _, present := artifact.DockerArtifact.BuildArgs["SKAFFOLD_GO_GCFLAGS"]; !present {
+			artifact.DockerArtifact.BuildArgs["SKAFFOLD_GO_GCFLAGS"] = &gcflags
+		}
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestApplyDebugBuildArgs(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	tmpDir.Write("go/Dockerfile", "FROM golang:1.11\nARG SKAFFOLD_GO_GCFLAGS\nRUN go build -gcflags=\"${SKAFFOLD_GO_GCFLAGS}\" -o /app .").
+		Write("node/Dockerfile", "FROM node:10\nRUN npm install")
+
+	config := &latest.SkaffoldPipeline{
+		Build: latest.BuildConfig{
+			Artifacts: []*latest.Artifact{
+				{ImageName: "go", Workspace: tmpDir.Path("go"), ArtifactType: latest.ArtifactType{DockerArtifact: &latest.DockerArtifact{DockerfilePath: "Dockerfile"}}},
+				{ImageName: "node", Workspace: tmpDir.Path("node"), ArtifactType: latest.ArtifactType{DockerArtifact: &latest.DockerArtifact{DockerfilePath: "Dockerfile"}}},
+			},
+		},
+	}
+
+	applyDebugBuildArgs(config)
+
+	gcflags := constants.DebugGoGcflags
+	testutil.CheckDeepEqual(t, map[string]*string{"SKAFFOLD_GO_GCFLAGS": &gcflags}, config.Build.Artifacts[0].DockerArtifact.BuildArgs)
+	testutil.CheckDeepEqual(t, map[string]*string(nil), config.Build.Artifacts[1].DockerArtifact.BuildArgs)
+}
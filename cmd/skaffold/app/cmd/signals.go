@@ -21,10 +21,20 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
 )
 
-func catchCtrlC(cancel context.CancelFunc) {
-	signals := make(chan os.Signal, 1)
+// for testing
+var exit = os.Exit
+
+// catchCtrlC cancels the context on the first signal, giving in-flight
+// builds and deploys the chance to stop and clean up after themselves.
+// Skaffold exits anyway if the grace period is exceeded or on a second signal.
+// A grace period of zero means no limit.
+func catchCtrlC(cancel context.CancelFunc, gracePeriod time.Duration) {
+	signals := make(chan os.Signal, 2)
 	signal.Notify(signals,
 		syscall.SIGTERM,
 		syscall.SIGINT,
@@ -34,5 +44,19 @@ func catchCtrlC(cancel context.CancelFunc) {
 	go func() {
 		<-signals
 		cancel()
+
+		var timeout <-chan time.Time
+		if gracePeriod > 0 {
+			timeout = time.After(gracePeriod)
+		}
+
+		select {
+		case <-signals:
+			logrus.Warnln("Interrupted again, exiting without waiting for cleanup")
+		case <-timeout:
+			logrus.Warnf("Cleanup didn't complete within %v, exiting", gracePeriod)
+		}
+		StopProfiling()
+		exit(1)
 	}()
 }
@@ -23,14 +23,20 @@ import (
 	"sync"
 	"syscall"
 	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
 )
 
 func TestCatchCtrlC(t *testing.T) {
+	exitCodes := make(chan int, 1)
+	defer func(e func(int)) { exit = e }(exit)
+	exit = func(code int) { exitCodes <- code }
+
 	var wg sync.WaitGroup
 	wg.Add(1)
 
 	ctx, cancel := context.WithCancel(context.Background())
-	catchCtrlC(cancel)
+	catchCtrlC(cancel, 0)
 
 	go func() {
 		<-ctx.Done()
@@ -40,4 +46,9 @@ func TestCatchCtrlC(t *testing.T) {
 	syscall.Kill(syscall.Getpid(), syscall.SIGINT)
 
 	wg.Wait()
+
+	// A second signal exits without waiting.
+	syscall.Kill(syscall.Getpid(), syscall.SIGINT)
+
+	testutil.CheckDeepEqual(t, 1, <-exitCodes)
 }
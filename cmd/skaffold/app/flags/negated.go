@@ -0,0 +1,47 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flags
+
+import "strconv"
+
+// NegatedBoolFlag is a boolean flag that stores the opposite of its value.
+// It lets options whose zero value means "enabled" be set with positive flags.
+type NegatedBoolFlag struct {
+	value *bool
+}
+
+// NewNegatedBoolFlag returns a flag that sets *value to the opposite of what's given.
+func NewNegatedBoolFlag(value *bool) *NegatedBoolFlag {
+	return &NegatedBoolFlag{value: value}
+}
+
+func (f *NegatedBoolFlag) String() string {
+	return strconv.FormatBool(!*f.value)
+}
+
+func (f *NegatedBoolFlag) Set(value string) error {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	*f.value = !b
+	return nil
+}
+
+func (f *NegatedBoolFlag) Type() string {
+	return "bool"
+}
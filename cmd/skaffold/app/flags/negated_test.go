@@ -0,0 +1,39 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flags
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestNegatedBoolFlag(t *testing.T) {
+	var disabled bool
+	flag := NewNegatedBoolFlag(&disabled)
+	testutil.CheckDeepEqual(t, "true", flag.String())
+
+	err := flag.Set("false")
+	testutil.CheckErrorAndDeepEqual(t, false, err, true, disabled)
+	testutil.CheckDeepEqual(t, "false", flag.String())
+
+	err = flag.Set("true")
+	testutil.CheckErrorAndDeepEqual(t, false, err, false, disabled)
+
+	err = flag.Set("maybe")
+	testutil.CheckError(t, true, err)
+}
@@ -37,8 +37,11 @@ func (t *TemplateFlag) String() string {
 	return t.rawTemplate
 }
 
+// jsonTemplate is used when the flag is set to `json`.
+const jsonTemplate = "{{json .}}\n"
+
 func (t *TemplateFlag) Usage() string {
-	defaultUsage := "Format output with go-template."
+	defaultUsage := "Format output with go-template, or `json`."
 	if t.context != nil {
 		goType := reflect.TypeOf(t.context)
 		url := fmt.Sprintf("https://godoc.org/%s#%s", goType.PkgPath(), goType.Name())
@@ -48,7 +51,12 @@ func (t *TemplateFlag) Usage() string {
 }
 
 func (t *TemplateFlag) Set(value string) error {
-	tmpl, err := parseTemplate(value)
+	rawTemplate := value
+	if value == "json" {
+		rawTemplate = jsonTemplate
+	}
+
+	tmpl, err := parseTemplate(rawTemplate)
 	if err != nil {
 		return errors.Wrap(err, "setting template flag")
 	}
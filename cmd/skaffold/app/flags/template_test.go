@@ -60,6 +60,23 @@ func TestTemplateSet(t *testing.T) {
 	}
 }
 
+func TestTemplateSetJSON(t *testing.T) {
+	flag := &TemplateFlag{}
+	if err := flag.Set("json"); err != nil {
+		t.Errorf("Error setting flag value: %s", err)
+	}
+
+	actual := &bytes.Buffer{}
+	if err := flag.Template().Execute(actual, &data); err != nil {
+		t.Errorf("Error executing template: %s", err)
+	}
+
+	expected := "{\"Field\":\"test\"}\n"
+	if actual.String() != expected {
+		t.Errorf("Template output did not match. Expected %s, Actual %s", expected, actual.String())
+	}
+}
+
 func TestTemplateString(t *testing.T) {
 	flag := NewTemplateFlag(rawTemplate, nil)
 	if rawTemplate != flag.String() {
@@ -24,5 +24,6 @@ import (
 
 func Run() error {
 	c := cmd.NewSkaffoldCommand(os.Stdout, os.Stderr)
+	defer cmd.StopProfiling()
 	return c.Execute()
 }
@@ -18,19 +18,28 @@ package main
 
 import (
 	"context"
+	"os"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
 	"github.com/GoogleContainerTools/skaffold/cmd/skaffold/app"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/exitcode"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
 )
 
 func main() {
 	if err := app.Run(); err != nil {
 		if errors.Cause(err) == context.Canceled {
 			logrus.Debugln(errors.Wrap(err, "ignore error since context is cancelled"))
+		} else if remediable, ok := errors.Cause(err).(kubernetes.Remediable); ok {
+			// The full chain of errors is only useful to debug skaffold itself.
+			logrus.Debugln(err)
+			logrus.Errorf("%s\n%s", remediable, remediable.Remediation())
+			os.Exit(exitcode.Of(err))
 		} else {
-			logrus.Fatal(err)
+			logrus.Error(err)
+			os.Exit(exitcode.Of(err))
 		}
 	}
 }
@@ -0,0 +1,126 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+)
+
+var ecrRegistry = regexp.MustCompile(`^(\d+)\.dkr\.ecr(-fips)?\.([a-z0-9-]+)\.amazonaws\.com(\.cn)?$`)
+
+// IsECR says if a registry is an Amazon Elastic Container Registry,
+// whose credentials are given by the `ecr-login` credential helper.
+func IsECR(registry string) bool {
+	return ecrRegistry.MatchString(registry)
+}
+
+// ECRRepositories creates the missing repositories of the images pushed to ECR.
+// Repositories are checked once per session, with the aws CLI.
+type ECRRepositories struct {
+	cfg *latest.ECRConfig
+
+	lock  sync.Mutex
+	known map[string]bool
+}
+
+// NewECRRepositories creates the repositories with the given settings.
+func NewECRRepositories(cfg *latest.ECRConfig) *ECRRepositories {
+	return &ECRRepositories{
+		cfg:   cfg,
+		known: map[string]bool{},
+	}
+}
+
+// Ensure creates the repository of an image if it's pushed to ECR and the repository doesn't exist.
+// Other images are ignored.
+func (r *ECRRepositories) Ensure(ctx context.Context, out io.Writer, image string) error {
+	ref, err := name.ParseReference(image, name.WeakValidation)
+	if err != nil {
+		return errors.Wrapf(err, "parsing image %s", image)
+	}
+
+	registry := ref.Context().RegistryStr()
+	match := ecrRegistry.FindStringSubmatch(registry)
+	if match == nil {
+		return nil
+	}
+	account, region := match[1], match[3]
+	repository := ref.Context().RepositoryStr()
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	key := registry + "/" + repository
+	if r.known[key] {
+		return nil
+	}
+
+	describe := exec.CommandContext(ctx, "aws", "ecr", "describe-repositories", "--registry-id", account, "--region", region, "--repository-names", repository)
+	if _, err := util.RunCmdOut(describe); err == nil {
+		r.known[key] = true
+		return nil
+	} else if !strings.Contains(err.Error(), "RepositoryNotFoundException") {
+		return errors.Wrapf(err, "checking repository %s", key)
+	}
+
+	color.Default.Fprintf(out, "Creating ECR repository %s...\n", key)
+
+	create := exec.CommandContext(ctx, "aws", r.createArgs(account, region, repository)...)
+	if _, err := util.RunCmdOut(create); err != nil && !strings.Contains(err.Error(), "RepositoryAlreadyExistsException") {
+		return errors.Wrapf(err, "creating repository %s", key)
+	}
+
+	r.known[key] = true
+	return nil
+}
+
+func (r *ECRRepositories) createArgs(account, region, repository string) []string {
+	args := []string{"ecr", "create-repository", "--registry-id", account, "--region", region, "--repository-name", repository}
+
+	args = append(args, "--image-scanning-configuration", fmt.Sprintf("scanOnPush=%t", r.cfg.ScanOnPush))
+	if r.cfg.ImageTagMutability != "" {
+		args = append(args, "--image-tag-mutability", r.cfg.ImageTagMutability)
+	}
+
+	if len(r.cfg.Tags) > 0 {
+		var keys []string
+		for k := range r.cfg.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		args = append(args, "--tags")
+		for _, k := range keys {
+			args = append(args, fmt.Sprintf("Key=%s,Value=%s", k, r.cfg.Tags[k]))
+		}
+	}
+
+	return args
+}
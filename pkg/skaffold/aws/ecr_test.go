@@ -0,0 +1,119 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+// fakeAWS records the aws commands and fails the ones listed in errors.
+type fakeAWS struct {
+	commands []string
+	errors   map[string]error
+}
+
+func (f *fakeAWS) RunCmdOut(cmd *exec.Cmd) ([]byte, error) {
+	command := strings.Join(cmd.Args, " ")
+	f.commands = append(f.commands, command)
+	return nil, f.errors[cmd.Args[2]]
+}
+
+func (f *fakeAWS) RunCmd(cmd *exec.Cmd) error {
+	_, err := f.RunCmdOut(cmd)
+	return err
+}
+
+func TestIsECR(t *testing.T) {
+	testutil.CheckDeepEqual(t, true, IsECR("123456789.dkr.ecr.us-east-1.amazonaws.com"))
+	testutil.CheckDeepEqual(t, true, IsECR("123456789.dkr.ecr.cn-north-1.amazonaws.com.cn"))
+	testutil.CheckDeepEqual(t, false, IsECR("registry.amazonaws.com.example.com"))
+	testutil.CheckDeepEqual(t, false, IsECR("gcr.io"))
+}
+
+func TestEnsureRepository(t *testing.T) {
+	cfg := &latest.ECRConfig{
+		CreateRepositories: true,
+		ScanOnPush:         true,
+		ImageTagMutability: "IMMUTABLE",
+		Tags:               map[string]string{"team": "payments", "env": "dev"},
+	}
+	describe := "aws ecr describe-repositories --registry-id 123456789 --region us-east-1 --repository-names team/app"
+	create := "aws ecr create-repository --registry-id 123456789 --region us-east-1 --repository-name team/app --image-scanning-configuration scanOnPush=true --image-tag-mutability IMMUTABLE --tags Key=env,Value=dev Key=team,Value=payments"
+
+	var tests = []struct {
+		description      string
+		image            string
+		errors           map[string]error
+		shouldErr        bool
+		expectedCommands []string
+	}{
+		{
+			description: "not ecr",
+			image:       "gcr.io/project/app",
+		},
+		{
+			description:      "existing repository",
+			image:            "123456789.dkr.ecr.us-east-1.amazonaws.com/team/app:v1",
+			expectedCommands: []string{describe},
+		},
+		{
+			description:      "missing repository",
+			image:            "123456789.dkr.ecr.us-east-1.amazonaws.com/team/app:v1",
+			errors:           map[string]error{"describe-repositories": fmt.Errorf("An error occurred (RepositoryNotFoundException)")},
+			expectedCommands: []string{describe, create},
+		},
+		{
+			description:      "repository created concurrently",
+			image:            "123456789.dkr.ecr.us-east-1.amazonaws.com/team/app:v1",
+			errors:           map[string]error{"describe-repositories": fmt.Errorf("RepositoryNotFoundException"), "create-repository": fmt.Errorf("RepositoryAlreadyExistsException")},
+			expectedCommands: []string{describe, create},
+		},
+		{
+			description:      "access denied",
+			image:            "123456789.dkr.ecr.us-east-1.amazonaws.com/team/app:v1",
+			errors:           map[string]error{"describe-repositories": fmt.Errorf("AccessDeniedException")},
+			shouldErr:        true,
+			expectedCommands: []string{describe},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			fake := &fakeAWS{errors: test.errors}
+			defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+			util.DefaultExecCommand = fake
+
+			repositories := NewECRRepositories(cfg)
+			err := repositories.Ensure(context.Background(), &bytes.Buffer{}, test.image)
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expectedCommands, fake.commands)
+
+			if !test.shouldErr && test.expectedCommands != nil {
+				// The repository is only checked once per session.
+				repositories.Ensure(context.Background(), &bytes.Buffer{}, test.image)
+				testutil.CheckDeepEqual(t, test.expectedCommands, fake.commands)
+			}
+		})
+	}
+}
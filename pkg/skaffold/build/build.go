@@ -26,8 +26,23 @@ import (
 
 // Artifact is the result corresponding to each successful build.
 type Artifact struct {
-	ImageName string
-	Tag       string
+	ImageName string `json:"imageName"`
+	Tag       string `json:"tag"`
+
+	// Signature is the reference of the image's cosign signature, when images are signed.
+	Signature string `json:"signature,omitempty"`
+
+	// SBOM is the software bill of materials of the image, when one is generated.
+	SBOM *SBOM `json:"sbom,omitempty"`
+}
+
+// SBOM is the software bill of materials of a built image.
+type SBOM struct {
+	Format string `json:"format"`
+	File   string `json:"file"`
+
+	// Reference is where the SBOM is attached to the image, if it is.
+	Reference string `json:"reference,omitempty"`
 }
 
 // Builder is an interface to the Build API of Skaffold.
@@ -39,3 +54,21 @@ type Builder interface {
 
 	Build(ctx context.Context, out io.Writer, tagger tag.Tagger, artifacts []*latest.Artifact) ([]Artifact, error)
 }
+
+// Pruner is implemented by builders that can remove older images they built.
+type Pruner interface {
+	Prune(ctx context.Context, out io.Writer, artifacts []*latest.Artifact) error
+}
+
+// PlatformChecker is implemented by builders that can warn when the images
+// they built can't run on the cluster's nodes. It's only worth checking
+// before the images are deployed.
+type PlatformChecker interface {
+	CheckPlatforms(ctx context.Context, out io.Writer, builds []Artifact)
+}
+
+// Pusher is implemented by builders that push images only if configured to.
+// The other builders always push the images they build.
+type Pusher interface {
+	Pushes() bool
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package build
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+)
+
+// Failure is the error returned when an artifact fails to build.
+// It keeps the step that failed and the last lines of its output.
+type Failure struct {
+	ImageName string
+	Step      string
+	Output    []string
+	Err       error
+}
+
+func (f *Failure) Error() string {
+	return f.Err.Error()
+}
+
+// Cause returns the underlying error.
+func (f *Failure) Cause() error {
+	return f.Err
+}
+
+// PrintFailure summarizes the build failure found in the chain of wrapped
+// errors, if any, so that users don't have to scroll through the whole build output.
+func PrintFailure(out io.Writer, err error) {
+	if f := failureOf(err); f != nil {
+		f.print(out)
+	}
+}
+
+// failureOf finds the build failure in a chain of wrapped errors.
+func failureOf(err error) *Failure {
+	for err != nil {
+		if f, ok := err.(*Failure); ok {
+			return f
+		}
+		cause, ok := err.(interface{ Cause() error })
+		if !ok {
+			return nil
+		}
+		err = cause.Cause()
+	}
+	return nil
+}
+
+func (f *Failure) print(out io.Writer) {
+	if f.Step != "" {
+		color.Red.Fprintf(out, "Build of [%s] failed at: %s\n", f.ImageName, f.Step)
+	} else {
+		color.Red.Fprintf(out, "Build of [%s] failed\n", f.ImageName)
+	}
+	for _, line := range f.Output {
+		fmt.Fprintf(out, "  %s\n", line)
+	}
+	color.Red.Fprintln(out, f.Err.Error())
+}
+
+// withFailureSummary wraps an artifactBuilder so that a failed build
+// returns a Failure with the step that failed and the end of its output.
+func withFailureSummary(buildArtifact artifactBuilder) artifactBuilder {
+	return func(ctx context.Context, out io.Writer, tagger tag.Tagger, artifact *latest.Artifact) (string, error) {
+		recorder := &outputRecorder{out: out}
+
+		tag, err := buildArtifact(ctx, recorder, tagger, artifact)
+		if err != nil {
+			step, output := recorder.summary()
+			return "", &Failure{
+				ImageName: artifact.ImageName,
+				Step:      step,
+				Output:    output,
+				Err:       err,
+			}
+		}
+
+		return tag, nil
+	}
+}
+
+// outputRecorder forwards the output of a build while it keeps track
+// of the current step and of the last lines of output.
+type outputRecorder struct {
+	out   io.Writer
+	lock  sync.Mutex
+	steps util.BuildSteps
+}
+
+func (r *outputRecorder) Write(p []byte) (int, error) {
+	r.lock.Lock()
+	r.steps.Write(string(p), nil)
+	r.lock.Unlock()
+
+	return r.out.Write(p)
+}
+
+// summary returns the last step and the lines printed since it started.
+func (r *outputRecorder) summary() (string, []string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.steps.Flush(nil)
+	return r.steps.Step(), r.steps.Lines()
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package build
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"github.com/pkg/errors"
+)
+
+func TestFailureSummary(t *testing.T) {
+	var manyLines []string
+	for i := 0; i < 30; i++ {
+		manyLines = append(manyLines, fmt.Sprintf("line %d", i))
+	}
+
+	var tests = []struct {
+		description    string
+		output         string
+		expectedStep   string
+		expectedOutput []string
+	}{
+		{
+			description:    "docker",
+			output:         "Step 1/3 : FROM golang\n ---> 1234\nStep 2/3 : RUN make\nmake: *** No rule to make target.\n\nStop.",
+			expectedStep:   "Step 2/3 : RUN make",
+			expectedOutput: []string{"make: *** No rule to make target.", "Stop."},
+		},
+		{
+			description:    "cloud build",
+			output:         "Step #0: Step 1/2 : FROM golang\nStep #0: Step 2/2 : RUN make\nStep #0: make: failed\n",
+			expectedStep:   "Step 2/2 : RUN make",
+			expectedOutput: []string{"Step #0: make: failed"},
+		},
+		{
+			description:    "buildkit",
+			output:         "#5 [1/2] FROM golang\n#6 [2/2] RUN make\n#6 0.345 make: failed\n#6 ERROR: executor failed\n",
+			expectedStep:   "#6 [2/2] RUN make",
+			expectedOutput: []string{"#6 0.345 make: failed", "#6 ERROR: executor failed"},
+		},
+		{
+			description:    "kaniko",
+			output:         "INFO[0000] Unpacking rootfs\nINFO[0003] RUN make\nmake: failed\n",
+			expectedStep:   "RUN make",
+			expectedOutput: []string{"make: failed"},
+		},
+		{
+			description:    "failure after the build",
+			output:         "Step 1/1 : FROM golang\n ---> 1234\nSuccessfully built 1234\nThe push refers to repository [gcr.io/project/image]\n",
+			expectedOutput: []string{"The push refers to repository [gcr.io/project/image]"},
+		},
+		{
+			description:    "last lines without a step",
+			output:         strings.Join(manyLines, "\n"),
+			expectedOutput: manyLines[10:],
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			buildArtifact := withFailureSummary(func(ctx context.Context, out io.Writer, tagger tag.Tagger, artifact *latest.Artifact) (string, error) {
+				io.WriteString(out, test.output)
+				return "", errors.New("build failed")
+			})
+
+			var out bytes.Buffer
+			_, err := buildArtifact(context.Background(), &out, nil, &latest.Artifact{ImageName: "image"})
+
+			failure, ok := err.(*Failure)
+			if !ok {
+				t.Fatalf("expected a build failure, got %v", err)
+			}
+			testutil.CheckDeepEqual(t, test.output, out.String())
+			testutil.CheckDeepEqual(t, test.expectedStep, failure.Step)
+			testutil.CheckDeepEqual(t, test.expectedOutput, failure.Output)
+		})
+	}
+}
+
+func TestPrintFailure(t *testing.T) {
+	err := errors.Wrap(&Failure{
+		ImageName: "image",
+		Step:      "Step 2/3 : RUN make",
+		Output:    []string{"make: failed"},
+		Err:       errors.New("exit status 2"),
+	}, "building [image]")
+
+	var out bytes.Buffer
+	PrintFailure(&out, err)
+
+	testutil.CheckDeepEqual(t, "Build of [image] failed at: Step 2/3 : RUN make\n  make: failed\nexit status 2\n", out.String())
+}
+
+func TestPrintFailureIgnoresOtherErrors(t *testing.T) {
+	var out bytes.Buffer
+	PrintFailure(&out, errors.New("not a build failure"))
+
+	testutil.CheckDeepEqual(t, "", out.String())
+}
+
+func TestInSequenceReturnsFailure(t *testing.T) {
+	buildArtifact := func(ctx context.Context, out io.Writer, tagger tag.Tagger, artifact *latest.Artifact) (string, error) {
+		io.WriteString(out, "Step 1/1 : RUN false\n")
+		return "", errors.New("exit status 1")
+	}
+
+	_, err := InSequence(context.Background(), ioutil.Discard, nil, []*latest.Artifact{{ImageName: "image"}}, buildArtifact)
+
+	var out bytes.Buffer
+	PrintFailure(&out, err)
+	testutil.CheckDeepEqual(t, "Build of [image] failed at: Step 1/1 : RUN false\nexit status 1\n", out.String())
+}
@@ -33,10 +33,8 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/gcp"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
-	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/version"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
-	"golang.org/x/oauth2/google"
 	cloudbuild "google.golang.org/api/cloudbuild/v1"
 	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iterator"
@@ -48,22 +46,19 @@ func (b *Builder) Build(ctx context.Context, out io.Writer, tagger tag.Tagger, a
 }
 
 func (b *Builder) buildArtifact(ctx context.Context, out io.Writer, tagger tag.Tagger, artifact *latest.Artifact) (string, error) {
-	client, err := google.DefaultClient(ctx, cloudbuild.CloudPlatformScope)
-	if err != nil {
-		return "", errors.Wrap(err, "getting google client")
+	if err := docker.CheckDockerfile(artifact.Workspace, artifact.DockerArtifact); err != nil {
+		return "", err
 	}
 
-	cbclient, err := cloudbuild.New(client)
+	cbclient, err := gcp.CloudBuildClient()
 	if err != nil {
-		return "", errors.Wrap(err, "getting builder")
+		return "", err
 	}
-	cbclient.UserAgent = version.UserAgent()
 
-	c, err := cstorage.NewClient(ctx)
+	c, err := gcp.StorageClient()
 	if err != nil {
-		return "", errors.Wrap(err, "getting cloud storage client")
+		return "", err
 	}
-	defer c.Close()
 
 	projectID := b.ProjectID
 	if projectID == "" {
@@ -77,17 +72,28 @@ func (b *Builder) buildArtifact(ctx context.Context, out io.Writer, tagger tag.T
 
 	cbBucket := fmt.Sprintf("%s%s", projectID, constants.GCSBucketSuffix)
 	buildObject := fmt.Sprintf("source/%s-%s.tar.gz", projectID, util.RandomID())
+	digest, err := docker.ContextDigest(ctx, artifact.Workspace, artifact.DockerArtifact)
+	if err != nil {
+		logrus.Debugf("Unable to compute the digest of the sources: %s", err)
+	} else {
+		// Unchanged sources are uploaded to the same object, that can be reused.
+		buildObject = fmt.Sprintf("source/%s-%s.tar.gz", projectID, digest)
+	}
 
-	if err := b.createBucketIfNotExists(ctx, projectID, cbBucket); err != nil {
+	if err := createBucketIfNotExists(ctx, c, projectID, cbBucket); err != nil {
 		return "", errors.Wrap(err, "creating bucket if not exists")
 	}
-	if err := b.checkBucketProjectCorrect(ctx, projectID, cbBucket); err != nil {
+	if err := checkBucketProjectCorrect(ctx, c, projectID, cbBucket); err != nil {
 		return "", errors.Wrap(err, "checking bucket is in correct project")
 	}
 
-	color.Default.Fprintf(out, "Pushing code to gs://%s/%s\n", cbBucket, buildObject)
-	if err := docker.UploadContextToGCS(ctx, artifact.Workspace, artifact.DockerArtifact, cbBucket, buildObject); err != nil {
-		return "", errors.Wrap(err, "uploading source tarball")
+	if _, err := c.Bucket(cbBucket).Object(buildObject).Attrs(ctx); err == nil {
+		color.Default.Fprintf(out, "Sources are unchanged, reusing gs://%s/%s\n", cbBucket, buildObject)
+	} else {
+		color.Default.Fprintf(out, "Pushing code to gs://%s/%s\n", cbBucket, buildObject)
+		if err := docker.UploadContextToGCS(ctx, artifact.Workspace, artifact.DockerArtifact, cbBucket, buildObject); err != nil {
+			return "", errors.Wrap(err, "uploading source tarball")
+		}
 	}
 
 	desc := b.buildDescription(artifact, cbBucket, buildObject)
@@ -113,7 +119,7 @@ watch:
 			return "", errors.Wrap(err, "getting build status")
 		}
 
-		r, err := b.getLogs(ctx, offset, cbBucket, logsObject)
+		r, err := getLogs(ctx, c, offset, cbBucket, logsObject)
 		if err != nil {
 			return "", errors.Wrap(err, "getting logs")
 		}
@@ -186,13 +192,7 @@ func getImageID(b *cloudbuild.Build) (string, error) {
 	return b.Results.Images[0].Digest, nil
 }
 
-func (b *Builder) getLogs(ctx context.Context, offset int64, bucket, objectName string) (io.ReadCloser, error) {
-	c, err := cstorage.NewClient(ctx)
-	if err != nil {
-		return nil, errors.Wrap(err, "getting storage client")
-	}
-	defer c.Close()
-
+func getLogs(ctx context.Context, c *cstorage.Client, offset int64, bucket, objectName string) (io.ReadCloser, error) {
 	r, err := c.Bucket(bucket).Object(objectName).NewRangeReader(ctx, offset, -1)
 	if err != nil {
 		if gerr, ok := err.(*googleapi.Error); ok {
@@ -212,11 +212,7 @@ func (b *Builder) getLogs(ctx context.Context, offset int64, bucket, objectName
 	return r, nil
 }
 
-func (b *Builder) checkBucketProjectCorrect(ctx context.Context, projectID, bucket string) error {
-	c, err := cstorage.NewClient(ctx)
-	if err != nil {
-		return errors.Wrap(err, "getting storage client")
-	}
+func checkBucketProjectCorrect(ctx context.Context, c *cstorage.Client, projectID, bucket string) error {
 	it := c.Buckets(ctx, projectID)
 	// Set the prefix to the bucket we're looking for to only return that bucket and buckets with that prefix
 	// that we'll filter further later on
@@ -236,14 +232,8 @@ func (b *Builder) checkBucketProjectCorrect(ctx context.Context, projectID, buck
 	}
 }
 
-func (b *Builder) createBucketIfNotExists(ctx context.Context, projectID, bucket string) error {
-	c, err := cstorage.NewClient(ctx)
-	if err != nil {
-		return errors.Wrap(err, "getting storage client")
-	}
-	defer c.Close()
-
-	_, err = c.Bucket(bucket).Attrs(ctx)
+func createBucketIfNotExists(ctx context.Context, c *cstorage.Client, projectID, bucket string) error {
+	_, err := c.Bucket(bucket).Attrs(ctx)
 
 	if err == nil {
 		// Bucket exists
@@ -17,14 +17,19 @@ limitations under the License.
 package gcb
 
 import (
+	"fmt"
+
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/sirupsen/logrus"
 	cloudbuild "google.golang.org/api/cloudbuild/v1"
 )
 
 func (b *Builder) buildDescription(artifact *latest.Artifact, bucket, object string) *cloudbuild.Build {
 	var steps []*cloudbuild.BuildStep
 
+	steps = append(steps, mirrorSteps(b.DockerImage, artifact)...)
+
 	for _, cacheFrom := range artifact.DockerArtifact.CacheFrom {
 		steps = append(steps, &cloudbuild.BuildStep{
 			Name: b.DockerImage,
@@ -32,7 +37,7 @@ func (b *Builder) buildDescription(artifact *latest.Artifact, bucket, object str
 		})
 	}
 
-	args := append([]string{"build", "--tag", artifact.ImageName, "-f", artifact.DockerArtifact.DockerfilePath})
+	args := []string{"build", "--tag", artifact.ImageName, "-f", artifact.DockerArtifact.DockerfilePath}
 	args = append(args, docker.GetBuildArgs(artifact.DockerArtifact)...)
 	args = append(args, ".")
 
@@ -58,3 +63,34 @@ func (b *Builder) buildDescription(artifact *latest.Artifact, bucket, object str
 		Timeout: b.Timeout,
 	}
 }
+
+// mirrorSteps pull the Docker Hub base images through the first registry mirror.
+// Failures are ignored since the build can still pull the images from Docker Hub.
+func mirrorSteps(dockerImage string, artifact *latest.Artifact) []*cloudbuild.BuildStep {
+	mirrors := docker.RegistryMirrors()
+	if len(mirrors) == 0 {
+		return nil
+	}
+
+	images, err := docker.BaseImages(artifact.Workspace, artifact.DockerArtifact)
+	if err != nil {
+		logrus.Debugf("Unable to list base images: %s", err)
+		return nil
+	}
+
+	var steps []*cloudbuild.BuildStep
+	for _, image := range images {
+		mirrored, ok := docker.MirroredImage(image, mirrors[0])
+		if !ok {
+			continue
+		}
+
+		steps = append(steps, &cloudbuild.BuildStep{
+			Name:       dockerImage,
+			Entrypoint: "sh",
+			Args:       []string{"-c", fmt.Sprintf("docker pull %s && docker tag %s %s || true", mirrored, mirrored, image)},
+		})
+	}
+
+	return steps
+}
@@ -19,6 +19,7 @@ package gcb
 import (
 	"testing"
 
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/GoogleContainerTools/skaffold/testutil"
@@ -103,3 +104,40 @@ func TestPullCacheFrom(t *testing.T) {
 
 	testutil.CheckDeepEqual(t, expected, desc.Steps)
 }
+
+func TestPullFromMirror(t *testing.T) {
+	defer docker.SetRegistryMirrors(nil)
+	docker.SetRegistryMirrors([]string{"mirror.gcr.io"})
+
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+	tmpDir.Write("Dockerfile", "FROM golang:1.11\nFROM gcr.io/distroless/base\nCOPY --from=0 /app /app")
+
+	artifact := &latest.Artifact{
+		ImageName: "nginx",
+		Workspace: tmpDir.Root(),
+		ArtifactType: latest.ArtifactType{
+			DockerArtifact: &latest.DockerArtifact{
+				DockerfilePath: "Dockerfile",
+			},
+		},
+	}
+
+	builder := Builder{
+		GoogleCloudBuild: &latest.GoogleCloudBuild{
+			DockerImage: "docker/docker",
+		},
+	}
+	desc := builder.buildDescription(artifact, "bucket", "object")
+
+	expected := []*cloudbuild.BuildStep{{
+		Name:       "docker/docker",
+		Entrypoint: "sh",
+		Args:       []string{"-c", "docker pull mirror.gcr.io/library/golang:1.11 && docker tag mirror.gcr.io/library/golang:1.11 golang:1.11 || true"},
+	}, {
+		Name: "docker/docker",
+		Args: []string{"build", "--tag", "nginx", "-f", "Dockerfile", "."},
+	}}
+
+	testutil.CheckDeepEqual(t, expected, desc.Steps)
+}
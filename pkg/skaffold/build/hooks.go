@@ -0,0 +1,152 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package build
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+)
+
+// withHooks wraps an artifactBuilder so that the artifact's
+// before and after hooks are run around the build.
+func withHooks(buildArtifact artifactBuilder) artifactBuilder {
+	return func(ctx context.Context, out io.Writer, tagger tag.Tagger, artifact *latest.Artifact) (string, error) {
+		if artifact.Hooks == nil {
+			return buildArtifact(ctx, out, tagger, artifact)
+		}
+
+		if err := runHooks(ctx, out, artifact.Hooks.Before, artifact.Workspace, hookEnv(artifact, "")); err != nil {
+			return "", errors.Wrap(err, "running pre-build hook")
+		}
+
+		tag, err := buildArtifact(ctx, out, tagger, artifact)
+		if err != nil {
+			return "", err
+		}
+
+		if err := runHooks(ctx, out, artifact.Hooks.After, artifact.Workspace, hookEnv(artifact, tag)); err != nil {
+			return "", errors.Wrap(err, "running post-build hook")
+		}
+
+		return tag, nil
+	}
+}
+
+// withEvents wraps an artifactBuilder so that the start
+// and the end of the build are reported as events.
+func withEvents(buildArtifact artifactBuilder) artifactBuilder {
+	return func(ctx context.Context, out io.Writer, tagger tag.Tagger, artifact *latest.Artifact) (string, error) {
+		end := event.Start(event.Build, artifact.ImageName)
+
+		tag, err := buildArtifact(ctx, out, tagger, artifact)
+		end(tag, blameDefinitionFile(err, artifact))
+
+		return tag, err
+	}
+}
+
+// definitionErrors match the errors reported when the definition file of an artifact can't be parsed.
+var definitionErrors = regexp.MustCompile(`(?i)(dockerfile parse error|parsing dockerfile|unknown instruction|non-parseable pom|could not compile build file)`)
+
+// networkErrors match the errors caused by an unreachable registry or daemon,
+// even when they're reported by a build step pulling a base image.
+var networkErrors = regexp.MustCompile(`(?i)(timeout|timed out|connection refused|connection reset|no such host|cannot connect to the docker daemon)`)
+
+// blameDefinitionFile attributes a build error to the definition file of the artifact,
+// but only if that file can't be parsed or one of its steps failed. Push or network
+// errors are not caused by the file.
+func blameDefinitionFile(err error, artifact *latest.Artifact) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := errors.Cause(err).(net.Error); ok || networkErrors.MatchString(err.Error()) {
+		return err
+	}
+
+	if f := failureOf(err); (f == nil || f.Step == "") && !definitionErrors.MatchString(err.Error()) {
+		return err
+	}
+	return event.WithFile(err, definitionFile(artifact))
+}
+
+// definitionFile is the file that describes how an artifact is built.
+// It's blamed when the build fails.
+func definitionFile(artifact *latest.Artifact) string {
+	var file string
+	switch {
+	case artifact.DockerArtifact != nil:
+		file = artifact.DockerArtifact.DockerfilePath
+	case artifact.JibMavenArtifact != nil:
+		file = "pom.xml"
+	case artifact.JibGradleArtifact != nil:
+		file = "build.gradle"
+	default:
+		return ""
+	}
+
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(artifact.Workspace, file)
+}
+
+// hookEnv lists the environment variables describing the artifact being built.
+func hookEnv(artifact *latest.Artifact, tag string) []string {
+	env := []string{
+		"SKAFFOLD_IMAGE=" + artifact.ImageName,
+		"SKAFFOLD_WORKSPACE=" + artifact.Workspace,
+	}
+	if tag != "" {
+		env = append(env, "SKAFFOLD_TAG="+tag)
+	}
+	return env
+}
+
+func runHooks(ctx context.Context, out io.Writer, hooks []latest.HostHook, workspace string, env []string) error {
+	for _, hook := range hooks {
+		if len(hook.Command) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(out, "Running hook: %s\n", strings.Join(hook.Command, " "))
+
+		cmd := exec.CommandContext(ctx, hook.Command[0], hook.Command[1:]...)
+		cmd.Dir = workspace
+		cmd.Env = append(os.Environ(), env...)
+		cmd.Stdout = out
+		cmd.Stderr = out
+
+		if err := util.RunCmd(cmd); err != nil {
+			return errors.Wrapf(err, "running %s", hook.Command)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package build
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+type recordingCmd struct {
+	commands []string
+	err      error
+}
+
+func (r *recordingCmd) RunCmdOut(cmd *exec.Cmd) ([]byte, error) {
+	return nil, r.RunCmd(cmd)
+}
+
+func (r *recordingCmd) RunCmd(cmd *exec.Cmd) error {
+	var env []string
+	for _, e := range cmd.Env {
+		if strings.HasPrefix(e, "SKAFFOLD_") {
+			env = append(env, e)
+		}
+	}
+
+	r.commands = append(r.commands, cmd.Dir+": "+strings.Join(cmd.Args, " ")+" "+strings.Join(env, " "))
+	return r.err
+}
+
+func TestWithHooks(t *testing.T) {
+	var tests = []struct {
+		description      string
+		hooks            *latest.BuildHooks
+		hookErr          error
+		buildErr         error
+		shouldErr        bool
+		expectedCommands []string
+	}{
+		{
+			description: "no hooks",
+		},
+		{
+			description: "before and after",
+			hooks: &latest.BuildHooks{
+				Before: []latest.HostHook{{Command: []string{"make", "gen"}}},
+				After:  []latest.HostHook{{Command: []string{"./notify.sh"}}},
+			},
+			expectedCommands: []string{
+				"app: make gen SKAFFOLD_IMAGE=image SKAFFOLD_WORKSPACE=app",
+				"app: ./notify.sh SKAFFOLD_IMAGE=image SKAFFOLD_WORKSPACE=app SKAFFOLD_TAG=image:tag",
+			},
+		},
+		{
+			description: "failing hook",
+			hooks: &latest.BuildHooks{
+				Before: []latest.HostHook{{Command: []string{"make", "gen"}}},
+			},
+			hookErr:          errors.New("BUG"),
+			shouldErr:        true,
+			expectedCommands: []string{"app: make gen SKAFFOLD_IMAGE=image SKAFFOLD_WORKSPACE=app"},
+		},
+		{
+			description: "no after hook on failed build",
+			hooks: &latest.BuildHooks{
+				After: []latest.HostHook{{Command: []string{"./notify.sh"}}},
+			},
+			buildErr:  errors.New("BUG"),
+			shouldErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			recorder := &recordingCmd{err: test.hookErr}
+			defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+			util.DefaultExecCommand = recorder
+
+			buildArtifact := withHooks(func(context.Context, io.Writer, tag.Tagger, *latest.Artifact) (string, error) {
+				return "image:tag", test.buildErr
+			})
+
+			artifact := &latest.Artifact{
+				ImageName: "image",
+				Workspace: "app",
+				Hooks:     test.hooks,
+			}
+			_, err := buildArtifact(context.Background(), &bytes.Buffer{}, nil, artifact)
+
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expectedCommands, recorder.commands)
+		})
+	}
+}
+
+func TestDefinitionFile(t *testing.T) {
+	var tests = []struct {
+		description string
+		artifact    *latest.Artifact
+		expected    string
+	}{
+		{
+			description: "dockerfile",
+			artifact: &latest.Artifact{
+				Workspace: "app",
+				ArtifactType: latest.ArtifactType{
+					DockerArtifact: &latest.DockerArtifact{DockerfilePath: "Dockerfile.dev"},
+				},
+			},
+			expected: filepath.Join("app", "Dockerfile.dev"),
+		},
+		{
+			description: "jib maven",
+			artifact: &latest.Artifact{
+				Workspace: "app",
+				ArtifactType: latest.ArtifactType{
+					JibMavenArtifact: &latest.JibMavenArtifact{},
+				},
+			},
+			expected: filepath.Join("app", "pom.xml"),
+		},
+		{
+			description: "bazel",
+			artifact: &latest.Artifact{
+				Workspace: "app",
+				ArtifactType: latest.ArtifactType{
+					BazelArtifact: &latest.BazelArtifact{},
+				},
+			},
+			expected: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			testutil.CheckDeepEqual(t, test.expected, definitionFile(test.artifact))
+		})
+	}
+}
+
+func TestBlameDefinitionFile(t *testing.T) {
+	artifact := &latest.Artifact{
+		Workspace: "app",
+		ArtifactType: latest.ArtifactType{
+			DockerArtifact: &latest.DockerArtifact{DockerfilePath: "Dockerfile"},
+		},
+	}
+
+	var tests = []struct {
+		description  string
+		err          error
+		expectedFile string
+	}{
+		{
+			description:  "failed step",
+			err:          &Failure{Step: "Step 2/3 : RUN make", Err: errors.New("exit status 2")},
+			expectedFile: filepath.Join("app", "Dockerfile"),
+		},
+		{
+			description:  "parse error",
+			err:          errors.New("docker build: Dockerfile parse error line 3: unknown instruction: RUNN"),
+			expectedFile: filepath.Join("app", "Dockerfile"),
+		},
+		{
+			description: "push error",
+			err:         &Failure{Err: errors.New("pushing: denied: requested access to the resource is denied")},
+		},
+		{
+			description: "network error during a step",
+			err:         &Failure{Step: "Step 1/3 : FROM golang", Err: errors.New("Get https://registry-1.docker.io/v2/: net/http: TLS handshake timeout")},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := blameDefinitionFile(test.err, artifact)
+
+			var file string
+			if fileErr, ok := err.(*event.FileError); ok {
+				file = fileErr.File
+			}
+			testutil.CheckDeepEqual(t, test.expectedFile, file)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kaniko
+
+import (
+	"io"
+	"io/ioutil"
+	"path"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// caBundleName is the name of the config map holding the registry CA bundle.
+	caBundleName = "kaniko-registry-ca"
+	caBundleKey  = "registry-ca.crt"
+
+	// kanikoCertsDir is where kaniko looks for the certificates it trusts.
+	kanikoCertsDir = "/kaniko/ssl/certs"
+)
+
+// setupCABundle copies the registry CA bundle, if one is configured, to a config map
+// that is mounted in the kaniko pods. It says if there is such a bundle.
+func (b *Builder) setupCABundle(out io.Writer) (func(), bool, error) {
+	caBundle := docker.RegistryCABundle()
+	if caBundle == "" {
+		return func() {}, false, nil
+	}
+
+	color.Default.Fprintf(out, "Creating kaniko CA bundle [%s]...\n", caBundleName)
+
+	pem, err := ioutil.ReadFile(caBundle)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "reading CA bundle")
+	}
+
+	client, err := kubernetes.Client()
+	if err != nil {
+		return nil, false, errors.Wrap(err, "getting kubernetes client")
+	}
+
+	configMaps := client.CoreV1().ConfigMaps(b.Namespace)
+
+	// An interrupted session might have left the config map behind.
+	if err := configMaps.Delete(caBundleName, &metav1.DeleteOptions{}); err != nil && !apierrs.IsNotFound(err) {
+		return nil, false, errors.Wrap(err, "deleting previous CA bundle")
+	}
+
+	if _, err := configMaps.Create(&v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   caBundleName,
+			Labels: map[string]string{"skaffold-kaniko": "skaffold-kaniko"},
+		},
+		Data: map[string]string{caBundleKey: string(pem)},
+	}); err != nil {
+		return nil, false, errors.Wrap(err, "creating CA bundle")
+	}
+
+	return func() {
+		if err := configMaps.Delete(caBundleName, &metav1.DeleteOptions{}); err != nil {
+			logrus.Warnf("deleting CA bundle: %s", err)
+		}
+	}, true, nil
+}
+
+// mountCABundle adds the registry CA bundle to the certificates trusted by kaniko.
+// The system certificates found in the same directory are still trusted.
+func mountCABundle(pod *v1.Pod) {
+	pod.Spec.Volumes = append(pod.Spec.Volumes, v1.Volume{
+		Name: caBundleName,
+		VolumeSource: v1.VolumeSource{
+			ConfigMap: &v1.ConfigMapVolumeSource{
+				LocalObjectReference: v1.LocalObjectReference{Name: caBundleName},
+			},
+		},
+	})
+
+	for i := range pod.Spec.Containers {
+		c := &pod.Spec.Containers[i]
+		if c.Name == constants.DefaultKanikoContainerName {
+			c.VolumeMounts = append(c.VolumeMounts, v1.VolumeMount{
+				Name:      caBundleName,
+				MountPath: path.Join(kanikoCertsDir, caBundleKey),
+				SubPath:   caBundleKey,
+				ReadOnly:  true,
+			})
+		}
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kaniko
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgo "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testCABundle = `-----BEGIN CERTIFICATE-----
+MIIBhTCCASugAwIBAgIQIRi6zePL6mKjOipn+dNuaTAKBggqhkjOPQQDAjASMRAw
+DgYDVQQKEwdBY21lIENvMB4XDTE3MTAyMDE5NDMwNloXDTE4MTAyMDE5NDMwNlow
+EjEQMA4GA1UEChMHQWNtZSBDbzBZMBMGByqGSM49AgEGCCqGSM49AwEHA0IABD0d
+7VNhbWvZLWPuj/RtHFjvtJBEwOkhbN/BnnE8rnZR8+sbwnc/KhCk3FhnpHZnQz7B
+5aETbbIgmuvewdjvSBSjYzBhMA4GA1UdDwEB/wQEAwICpDATBgNVHSUEDDAKBggr
+BgEFBQcDATAPBgNVHRMBAf8EBTADAQH/MCkGA1UdEQQiMCCCDmxvY2FsaG9zdDo1
+NDUzgg4xMjcuMC4wLjE6NTQ1MzAKBggqhkjOPQQDAgNIADBFAiEA2zpJEPQyz6/l
+Wf86aX6PepsntZv2GYlA5UpabfT2EZICICpJ5h/iI+i341gBmLiAFQOyTDT+/wQc
+6MF9+Yw1Yy0t
+-----END CERTIFICATE-----
+`
+
+func TestSetupCABundle(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+	tmpDir.Write("ca.pem", testCABundle)
+
+	defer func(c func() (clientgo.Interface, error)) { kubernetes.Client = c }(kubernetes.Client)
+	client := fake.NewSimpleClientset()
+	kubernetes.Client = func() (clientgo.Interface, error) { return client, nil }
+
+	defer docker.ConfigureRegistries(nil, "")
+	err := docker.ConfigureRegistries(nil, tmpDir.Path("ca.pem"))
+	testutil.CheckError(t, false, err)
+
+	b := &Builder{KanikoBuild: &latest.KanikoBuild{Namespace: "ns"}}
+	teardown, caBundle, err := b.setupCABundle(ioutil.Discard)
+	testutil.CheckErrorAndDeepEqual(t, false, err, true, caBundle)
+
+	configMap, err := client.CoreV1().ConfigMaps("ns").Get(caBundleName, metav1.GetOptions{})
+	testutil.CheckErrorAndDeepEqual(t, false, err, testCABundle, configMap.Data[caBundleKey])
+
+	teardown()
+	_, err = client.CoreV1().ConfigMaps("ns").Get(caBundleName, metav1.GetOptions{})
+	testutil.CheckError(t, true, err)
+}
+
+func TestSetupNoCABundle(t *testing.T) {
+	b := &Builder{KanikoBuild: &latest.KanikoBuild{}}
+	_, caBundle, err := b.setupCABundle(ioutil.Discard)
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, false, caBundle)
+}
+
+func TestMountCABundle(t *testing.T) {
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{Name: constants.DefaultKanikoContainerName}},
+		},
+	}
+	mountCABundle(pod)
+
+	testutil.CheckDeepEqual(t, v1.VolumeMount{
+		Name:      caBundleName,
+		MountPath: "/kaniko/ssl/certs/registry-ca.crt",
+		SubPath:   caBundleKey,
+		ReadOnly:  true,
+	}, pod.Spec.Containers[0].VolumeMounts[0])
+	testutil.CheckDeepEqual(t, caBundleName, pod.Spec.Volumes[0].ConfigMap.Name)
+}
@@ -29,17 +29,25 @@ import (
 
 // Build builds a list of artifacts with Kaniko.
 func (b *Builder) Build(ctx context.Context, out io.Writer, tagger tag.Tagger, artifacts []*latest.Artifact) ([]build.Artifact, error) {
-	teardown, err := b.setupSecret(out)
+	teardown, dockerConfig, err := b.setupSecret(out, artifacts)
 	if err != nil {
 		return nil, errors.Wrap(err, "setting up secret")
 	}
 	defer teardown()
 
-	return build.InParallel(ctx, out, tagger, artifacts, b.buildArtifact)
+	teardownCABundle, caBundle, err := b.setupCABundle(out)
+	if err != nil {
+		return nil, errors.Wrap(err, "setting up CA bundle")
+	}
+	defer teardownCABundle()
+
+	return build.InParallel(ctx, out, tagger, artifacts, func(ctx context.Context, out io.Writer, tagger tag.Tagger, artifact *latest.Artifact) (string, error) {
+		return b.buildArtifact(ctx, out, tagger, artifact, dockerConfig, caBundle)
+	})
 }
 
-func (b *Builder) buildArtifact(ctx context.Context, out io.Writer, tagger tag.Tagger, artifact *latest.Artifact) (string, error) {
-	initialTag, err := b.run(ctx, out, artifact, b.KanikoBuild)
+func (b *Builder) buildArtifact(ctx context.Context, out io.Writer, tagger tag.Tagger, artifact *latest.Artifact, dockerConfig, caBundle bool) (string, error) {
+	initialTag, err := b.run(ctx, out, artifact, b.KanikoBuild, dockerConfig, caBundle)
 	if err != nil {
 		return "", errors.Wrapf(err, "kaniko build for [%s]", artifact.ImageName)
 	}
@@ -26,20 +26,26 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func (b *Builder) run(ctx context.Context, out io.Writer, artifact *latest.Artifact, cfg *latest.KanikoBuild) (string, error) {
+func (b *Builder) run(ctx context.Context, out io.Writer, artifact *latest.Artifact, cfg *latest.KanikoBuild, dockerConfig, caBundle bool) (string, error) {
+	if err := docker.CheckDockerfile(artifact.Workspace, artifact.DockerArtifact); err != nil {
+		return "", err
+	}
+
 	initialTag := util.RandomID()
 
 	s := sources.Retrieve(cfg)
-	context, err := s.Setup(ctx, out, artifact, initialTag)
+	buildContext, err := s.Setup(ctx, out, artifact, initialTag)
 	if err != nil {
 		return "", errors.Wrap(err, "setting up build context")
 	}
-	defer s.Cleanup(ctx)
+	// Clean up even if the build was cancelled.
+	defer s.Cleanup(context.Background())
 
 	client, err := kubernetes.GetClientset()
 	if err != nil {
@@ -49,14 +55,28 @@ func (b *Builder) run(ctx context.Context, out io.Writer, artifact *latest.Artif
 	imageDst := fmt.Sprintf("%s:%s", artifact.ImageName, initialTag)
 	args := []string{
 		fmt.Sprintf("--dockerfile=%s", artifact.DockerArtifact.DockerfilePath),
-		fmt.Sprintf("--context=%s", context),
+		fmt.Sprintf("--context=%s", buildContext),
 		fmt.Sprintf("--destination=%s", imageDst),
 		fmt.Sprintf("-v=%s", logLevel().String()),
 	}
 	args = append(args, docker.GetBuildArgs(artifact.DockerArtifact)...)
+	for _, mirror := range docker.RegistryMirrors() {
+		args = append(args, fmt.Sprintf("--registry-mirror=%s", mirror))
+	}
+	if insecureDestination(imageDst) {
+		args = append(args, "--insecure", "--skip-tls-verify")
+	}
 
 	pods := client.CoreV1().Pods(cfg.Namespace)
-	p, err := pods.Create(s.Pod(args))
+	pod := s.Pod(args)
+	if dockerConfig {
+		useDockerConfig(pod)
+	}
+	if caBundle {
+		mountCABundle(pod)
+	}
+
+	p, err := pods.Create(pod)
 	if err != nil {
 		return "", errors.Wrap(err, "creating kaniko pod")
 	}
@@ -64,7 +84,7 @@ func (b *Builder) run(ctx context.Context, out io.Writer, artifact *latest.Artif
 		if err := pods.Delete(p.Name, &metav1.DeleteOptions{
 			GracePeriodSeconds: new(int64),
 		}); err != nil {
-			logrus.Fatalf("deleting pod: %s", err)
+			logrus.Warnf("deleting pod: %s", err)
 		}
 	}()
 
@@ -74,7 +94,7 @@ func (b *Builder) run(ctx context.Context, out io.Writer, artifact *latest.Artif
 
 	waitForLogs := streamLogs(out, p.Name, pods)
 
-	if err := kubernetes.WaitForPodComplete(ctx, pods, p.Name, b.timeout); err != nil {
+	if err := kubernetes.WaitForPodComplete(ctx, out, pods, client.CoreV1().Events(cfg.Namespace), p.Name, b.timeout); err != nil {
 		return "", errors.Wrap(err, "waiting for pod to complete")
 	}
 
@@ -82,3 +102,12 @@ func (b *Builder) run(ctx context.Context, out io.Writer, artifact *latest.Artif
 
 	return imageDst, nil
 }
+
+// insecureDestination says if kaniko should push to a registry over plain HTTP.
+func insecureDestination(image string) bool {
+	ref, err := name.ParseReference(image, name.WeakValidation)
+	if err != nil {
+		return false
+	}
+	return docker.IsInsecureRegistry(ref.Context().RegistryStr())
+}
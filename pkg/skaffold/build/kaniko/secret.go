@@ -22,36 +22,61 @@ import (
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func (b *Builder) setupSecret(out io.Writer) (func(), error) {
+// dockerConfigKey is the key of the generated docker config.json in the kaniko secret.
+const dockerConfigKey = "config.json"
+
+// setupSecret creates the secret mounted in the kaniko pods. On top of the pull secret,
+// it holds a docker config.json with the credentials that skaffold resolves for
+// the artifacts' registries. It says if the secret holds such a config.
+func (b *Builder) setupSecret(out io.Writer, artifacts []*latest.Artifact) (func(), bool, error) {
 	color.Default.Fprintf(out, "Creating kaniko secret [%s]...\n", b.PullSecretName)
 
 	client, err := kubernetes.GetClientset()
 	if err != nil {
-		return nil, errors.Wrap(err, "getting kubernetes client")
+		return nil, false, errors.Wrap(err, "getting kubernetes client")
 	}
 
 	secrets := client.CoreV1().Secrets(b.Namespace)
 
+	dockerConfig, err := docker.DefaultKeychain.ConfigJSON(registries(artifacts))
+	if err != nil {
+		return nil, false, errors.Wrap(err, "generating docker config")
+	}
+
+	data := map[string][]byte{}
+	if dockerConfig != nil {
+		data[dockerConfigKey] = dockerConfig
+	}
+
 	if b.PullSecret == "" {
 		logrus.Debug("No pull secret specified. Checking for one in the cluster.")
 
-		if _, err := secrets.Get(b.PullSecretName, metav1.GetOptions{}); err != nil {
-			return nil, errors.Wrap(err, "checking for existing kaniko secret")
+		_, err := secrets.Get(b.PullSecretName, metav1.GetOptions{})
+		if err == nil {
+			return func() {}, false, nil
+		}
+		if !apierrs.IsNotFound(err) || dockerConfig == nil {
+			return nil, false, errors.Wrap(err, "checking for existing kaniko secret")
 		}
 
-		return func() {}, nil
-	}
-
-	secretData, err := ioutil.ReadFile(b.PullSecret)
-	if err != nil {
-		return nil, errors.Wrap(err, "reading secret")
+		logrus.Debug("No secret in the cluster. Using the local registry credentials.")
+	} else {
+		secretData, err := ioutil.ReadFile(b.PullSecret)
+		if err != nil {
+			return nil, false, errors.Wrap(err, "reading secret")
+		}
+		data[constants.DefaultKanikoSecretName] = secretData
 	}
 
 	secret := &v1.Secret{
@@ -59,18 +84,51 @@ func (b *Builder) setupSecret(out io.Writer) (func(), error) {
 			Name:   b.PullSecretName,
 			Labels: map[string]string{"skaffold-kaniko": "skaffold-kaniko"},
 		},
-		Data: map[string][]byte{
-			constants.DefaultKanikoSecretName: secretData,
-		},
+		Data: data,
 	}
 
 	if _, err := secrets.Create(secret); err != nil {
-		return nil, errors.Wrapf(err, "creating secret: %s", err)
+		return nil, false, errors.Wrapf(err, "creating secret: %s", err)
 	}
 
 	return func() {
 		if err := secrets.Delete(b.PullSecretName, &metav1.DeleteOptions{}); err != nil {
 			logrus.Warnf("deleting secret")
 		}
-	}, nil
+	}, dockerConfig != nil, nil
+}
+
+// registries lists the registries the artifacts are pushed to.
+func registries(artifacts []*latest.Artifact) []string {
+	var registries []string
+	seen := map[string]bool{}
+
+	for _, artifact := range artifacts {
+		ref, err := name.ParseReference(artifact.ImageName, name.WeakValidation)
+		if err != nil {
+			continue
+		}
+
+		registry := ref.Context().RegistryStr()
+		if !seen[registry] {
+			seen[registry] = true
+			registries = append(registries, registry)
+		}
+	}
+
+	return registries
+}
+
+// useDockerConfig makes kaniko read the registry credentials from the docker config.json
+// of the mounted secret, instead of the one bundled in its image.
+func useDockerConfig(pod *v1.Pod) {
+	for i := range pod.Spec.Containers {
+		c := &pod.Spec.Containers[i]
+		if c.Name == constants.DefaultKanikoContainerName {
+			c.Env = append(c.Env, v1.EnvVar{
+				Name:  "DOCKER_CONFIG",
+				Value: "/secret",
+			})
+		}
+	}
 }
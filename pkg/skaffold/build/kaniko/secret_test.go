@@ -0,0 +1,35 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kaniko
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestRegistries(t *testing.T) {
+	artifacts := []*latest.Artifact{
+		{ImageName: "gcr.io/project/image1"},
+		{ImageName: "gcr.io/project/image2"},
+		{ImageName: "123456789.dkr.ecr.us-east-1.amazonaws.com/image"},
+		{ImageName: "busybox"},
+	}
+
+	testutil.CheckDeepEqual(t, []string{"gcr.io", "123456789.dkr.ecr.us-east-1.amazonaws.com", "index.docker.io"}, registries(artifacts))
+}
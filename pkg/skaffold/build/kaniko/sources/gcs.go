@@ -21,7 +21,6 @@ import (
 	"fmt"
 	"io"
 
-	cstorage "cloud.google.com/go/storage"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/gcp"
@@ -35,7 +34,8 @@ type GCSBucket struct {
 	tarName string
 }
 
-// Setup uploads the context to the provided GCS bucket
+// Setup uploads the context to the provided GCS bucket.
+// Unlike Google Cloud Build sources, the context is uploaded for every build since Cleanup deletes it.
 func (g *GCSBucket) Setup(ctx context.Context, out io.Writer, artifact *latest.Artifact, initialTag string) (string, error) {
 	bucket := g.cfg.BuildContext.GCSBucket
 	if bucket == "" {
@@ -70,11 +70,10 @@ func (g *GCSBucket) ModifyPod(ctx context.Context, p *v1.Pod) error {
 
 // Cleanup deletes the tarball from the GCS bucket
 func (g *GCSBucket) Cleanup(ctx context.Context) error {
-	c, err := cstorage.NewClient(ctx)
+	c, err := gcp.StorageClient()
 	if err != nil {
 		return err
 	}
-	defer c.Close()
 
 	return c.Bucket(g.cfg.BuildContext.GCSBucket).Object(g.tarName).Delete(ctx)
 }
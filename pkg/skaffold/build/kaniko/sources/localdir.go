@@ -21,7 +21,6 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
 
 	"github.com/pkg/errors"
@@ -31,6 +30,7 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 )
@@ -105,17 +105,17 @@ func (g *LocalDir) ModifyPod(ctx context.Context, p *v1.Pod) error {
 		return errors.Wrap(err, "waiting for pod to initialize")
 	}
 	// Copy over the buildcontext tarball into the init container
-	copy := exec.CommandContext(ctx, "kubectl", "cp", g.tarPath, fmt.Sprintf("%s:/%s", p.Name, g.tarPath), "-c", initContainer, "-n", p.Namespace)
+	copy := kubectx.KubectlCommand(ctx, append(kubectx.KubectlArgs(), "cp", g.tarPath, fmt.Sprintf("%s:/%s", p.Name, g.tarPath), "-c", initContainer, "-n", p.Namespace)...)
 	if err := util.RunCmd(copy); err != nil {
 		return errors.Wrap(err, "copying buildcontext into init container")
 	}
 	// Next, extract the buildcontext to the empty dir
-	extract := exec.CommandContext(ctx, "kubectl", "exec", p.Name, "-c", initContainer, "-n", p.Namespace, "--", "tar", "-xzf", g.tarPath, "-C", constants.DefaultKanikoEmptyDirMountPath)
+	extract := kubectx.KubectlCommand(ctx, append(kubectx.KubectlArgs(), "exec", p.Name, "-c", initContainer, "-n", p.Namespace, "--", "tar", "-xzf", g.tarPath, "-C", constants.DefaultKanikoEmptyDirMountPath)...)
 	if err := util.RunCmd(extract); err != nil {
 		return errors.Wrap(err, "extracting buildcontext to empty dir")
 	}
 	// Generate a file to successfully terminate the init container
-	file := exec.CommandContext(ctx, "kubectl", "exec", p.Name, "-c", initContainer, "-n", p.Namespace, "--", "touch", "/tmp/complete")
+	file := kubectx.KubectlCommand(ctx, append(kubectx.KubectlArgs(), "exec", p.Name, "-c", initContainer, "-n", p.Namespace, "--", "touch", "/tmp/complete")...)
 	return util.RunCmd(file)
 }
 
@@ -27,11 +27,17 @@ import (
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/pkg/errors"
 )
 
 func (b *Builder) buildBazel(ctx context.Context, out io.Writer, workspace string, a *latest.BazelArtifact) (string, error) {
-	cmd := exec.CommandContext(ctx, "bazel", "build", a.BuildTarget)
+	args := []string{"build", a.BuildTarget}
+	if util.ToolVerbosity() == util.QuietOutput {
+		args = append(args, "--noshow_progress")
+	}
+
+	cmd := exec.CommandContext(ctx, "bazel", args...)
 	cmd.Dir = workspace
 	cmd.Stdout = out
 	cmd.Stderr = out
@@ -48,7 +54,12 @@ func (b *Builder) buildBazel(ctx context.Context, out io.Writer, workspace strin
 	}
 	defer imageTar.Close()
 
-	resp, err := b.api.ImageLoad(ctx, imageTar, false)
+	api, err := b.dockerAPI()
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := api.ImageLoad(ctx, imageTar, false)
 	if err != nil {
 		return "", errors.Wrap(err, "loading image into docker daemon")
 	}
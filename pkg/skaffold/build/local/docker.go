@@ -30,8 +30,24 @@ import (
 )
 
 func (b *Builder) buildDocker(ctx context.Context, out io.Writer, workspace string, a *latest.DockerArtifact) (string, error) {
+	if err := docker.CheckDockerfile(workspace, a); err != nil {
+		return "", err
+	}
+
+	api, err := b.dockerAPI()
+	if err != nil {
+		return "", err
+	}
+
+	platform, err := b.targetPlatform(out)
+	if err != nil {
+		return "", err
+	}
+
 	initialTag := util.RandomID()
 
+	docker.PullFromMirrors(ctx, out, api, workspace, a)
+
 	if b.cfg.UseDockerCLI || b.cfg.UseBuildkit {
 		dockerfilePath, err := docker.NormalizeDockerfilePath(workspace, a.DockerfilePath)
 		if err != nil {
@@ -40,10 +56,14 @@ func (b *Builder) buildDocker(ctx context.Context, out io.Writer, workspace stri
 
 		args := []string{"build", workspace, "--file", dockerfilePath, "-t", initialTag}
 		args = append(args, docker.GetBuildArgs(a)...)
+		if platform != "" {
+			args = append(args, "--platform", platform)
+		}
 
 		cmd := exec.CommandContext(ctx, "docker", args...)
+		cmd.Env = append(os.Environ(), docker.DaemonEnv()...)
 		if b.cfg.UseBuildkit {
-			cmd.Env = append(os.Environ(), "DOCKER_BUILDKIT=1")
+			cmd.Env = append(cmd.Env, "DOCKER_BUILDKIT=1")
 		}
 		cmd.Stdout = out
 		cmd.Stderr = out
@@ -52,10 +72,13 @@ func (b *Builder) buildDocker(ctx context.Context, out io.Writer, workspace stri
 			return "", errors.Wrap(err, "running build")
 		}
 	} else {
-		if err := docker.BuildArtifact(ctx, out, b.api, workspace, a, initialTag); err != nil {
+		if err := docker.BuildArtifact(ctx, out, api, workspace, a, initialTag, platform); err != nil {
 			return "", errors.Wrap(err, "running build")
 		}
 	}
 
-	return fmt.Sprintf("%s:latest", initialTag), nil
+	image := fmt.Sprintf("%s:latest", initialTag)
+	b.addTemporaryImage(image)
+
+	return image, nil
 }
@@ -65,6 +65,13 @@ func generateGradleArgs(task string, skaffoldImage string, a *latest.JibGradleAr
 }
 
 func runGradleCommand(ctx context.Context, out io.Writer, workspace string, args []string) error {
+	switch util.ToolVerbosity() {
+	case util.QuietOutput:
+		args = append([]string{"--quiet"}, args...)
+	case util.VerboseOutput:
+		args = append([]string{"--info"}, args...)
+	}
+
 	cmd := jib.GradleCommand.CreateCommand(ctx, workspace, args)
 	cmd.Stdout = out
 	cmd.Stderr = out
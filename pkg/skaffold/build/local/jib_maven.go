@@ -111,6 +111,10 @@ func verifyJibPackageGoal(ctx context.Context, requiredGoal string, workspace st
 }
 
 func runMavenCommand(ctx context.Context, out io.Writer, workspace string, args []string) error {
+	if util.ToolVerbosity() == util.QuietOutput {
+		args = append([]string{"--quiet"}, args...)
+	}
+
 	cmd := jib.MavenCommand.CreateCommand(ctx, workspace, args)
 	cmd.Stdout = out
 	cmd.Stderr = out
@@ -0,0 +1,49 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/cluster"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+)
+
+// loadImage makes an image that isn't pushed available to a local cluster.
+func (b *Builder) loadImage(ctx context.Context, out io.Writer, image string) error {
+	if b.cluster.Type == cluster.Minikube && len(docker.DaemonEnv()) > 0 {
+		// The image was built with minikube's docker daemon.
+		return nil
+	}
+
+	cmd := b.cluster.LoadCommand(ctx, image)
+	if cmd == nil {
+		return nil
+	}
+
+	fmt.Fprintf(out, "Loading image %s into the cluster\n", image)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	if err := util.RunCmd(cmd); err != nil {
+		return errors.Wrapf(err, "loading image into %s", b.kubeContext)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/cluster"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestLoadImage(t *testing.T) {
+	var tests = []struct {
+		description string
+		kubeContext string
+		command     *testutil.FakeCmd
+		shouldErr   bool
+	}{
+		{
+			description: "kind",
+			kubeContext: "kind-dev",
+			command:     testutil.NewFakeCmd("kind load docker-image --name dev image:tag", nil),
+		},
+		{
+			description: "minikube with the host daemon",
+			kubeContext: "minikube",
+			command:     testutil.NewFakeCmd("minikube image load image:tag", nil),
+		},
+		{
+			description: "docker for desktop shares the daemon",
+			kubeContext: "docker-for-desktop",
+			command:     testutil.NewFakeCmd("", fmt.Errorf("no command expected")),
+		},
+		{
+			description: "load failure",
+			kubeContext: "kind-dev",
+			command:     testutil.NewFakeCmd("kind load docker-image --name dev image:tag", fmt.Errorf("no such cluster")),
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+			util.DefaultExecCommand = test.command
+
+			b := &Builder{
+				kubeContext: test.kubeContext,
+				cluster:     cluster.Detect(test.kubeContext, nil, nil),
+			}
+			err := b.loadImage(context.Background(), ioutil.Discard, "image:tag")
+
+			testutil.CheckError(t, test.shouldErr, err)
+		})
+	}
+}
+
+func TestNewBuilderLoadsImages(t *testing.T) {
+	var tests = []struct {
+		description string
+		kubeContext string
+		push        *bool
+		expected    []bool
+	}{
+		{
+			description: "kind",
+			kubeContext: "kind-dev",
+			expected:    []bool{false, true},
+		},
+		{
+			description: "kind with push",
+			kubeContext: "kind-dev",
+			push:        util.BoolPtr(true),
+			expected:    []bool{true, false},
+		},
+		{
+			description: "microk8s",
+			kubeContext: "microk8s",
+			expected:    []bool{true, false},
+		},
+		{
+			description: "remote cluster",
+			kubeContext: "gke_project_zone_cluster",
+			expected:    []bool{true, false},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			b, err := NewBuilder(&latest.LocalBuild{Push: test.push}, test.kubeContext, cluster.Detect(test.kubeContext, nil, nil))
+
+			testutil.CheckErrorAndDeepEqual(t, false, err, test.expected, []bool{b.pushImages, b.loadImages})
+		})
+	}
+}
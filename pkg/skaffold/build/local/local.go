@@ -25,6 +25,7 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/pkg/errors"
 )
@@ -32,13 +33,13 @@ import (
 // Build runs a docker build on the host and tags the resulting image with
 // its checksum. It streams build progress to the writer argument.
 func (b *Builder) Build(ctx context.Context, out io.Writer, tagger tag.Tagger, artifacts []*latest.Artifact) ([]build.Artifact, error) {
+	defer b.closeDockerAPI()
+
 	if b.localCluster {
 		if _, err := color.Default.Fprintf(out, "Found [%s] context, using local docker daemon.\n", b.kubeContext); err != nil {
 			return nil, errors.Wrap(err, "writing status")
 		}
 	}
-	defer b.api.Close()
-
 	// TODO(dgageot): parallel builds
 	return build.InSequence(ctx, out, tagger, artifacts, b.buildArtifact)
 }
@@ -101,6 +102,9 @@ func (b *Builder) runBuildForArtifact(ctx context.Context, out io.Writer, artifa
 		}
 		return b.buildJibGradleToDocker(ctx, out, artifact.Workspace, artifact.JibGradleArtifact)
 
+	case artifact.TarballArtifact != nil:
+		return b.loadTarball(ctx, out, artifact.Workspace, artifact.TarballArtifact)
+
 	default:
 		return "", fmt.Errorf("undefined artifact type: %+v", artifact.ArtifactType)
 	}
@@ -110,7 +114,12 @@ func (b *Builder) getDigestForArtifact(ctx context.Context, initialTag string, a
 	if b.pushImages && (artifact.JibMavenArtifact != nil || artifact.JibGradleArtifact != nil) {
 		return docker.RemoteDigest(initialTag)
 	}
-	return docker.Digest(ctx, b.api, initialTag)
+
+	api, err := b.dockerAPI()
+	if err != nil {
+		return "", err
+	}
+	return docker.Digest(ctx, api, initialTag)
 }
 
 func (b *Builder) retagAndPush(ctx context.Context, out io.Writer, initialTag string, newTag string, artifact *latest.Artifact) error {
@@ -121,15 +130,50 @@ func (b *Builder) retagAndPush(ctx context.Context, out io.Writer, initialTag st
 		return nil
 	}
 
-	if err := b.api.ImageTag(ctx, initialTag, newTag); err != nil {
+	api, err := b.dockerAPI()
+	if err != nil {
+		return err
+	}
+
+	if err := api.ImageTag(ctx, initialTag, newTag); err != nil {
 		return err
 	}
 
 	if b.pushImages {
-		if err := docker.RunPush(ctx, b.api, newTag, out); err != nil {
+		end := event.Start(event.Push, artifact.ImageName)
+		err := docker.RunPush(ctx, api, newTag, out)
+		end(newTag, err)
+		if err != nil {
 			return errors.Wrap(err, "pushing")
 		}
 	}
 
+	if b.loadImages {
+		return b.loadImage(ctx, out, newTag)
+	}
+
 	return nil
 }
+
+// Prune removes the older images of the artifacts from the local daemon,
+// if the builder is configured to do so.
+func (b *Builder) Prune(ctx context.Context, out io.Writer, artifacts []*latest.Artifact) error {
+	if b.cfg.Prune == nil {
+		return nil
+	}
+
+	var repos []string
+	for _, artifact := range artifacts {
+		repos = append(repos, artifact.ImageName)
+	}
+
+	api, err := b.dockerAPI()
+	if err != nil {
+		return err
+	}
+	b.temporaryImagesLock.Lock()
+	temporary := append([]string(nil), b.temporaryImages...)
+	b.temporaryImagesLock.Unlock()
+
+	return docker.PruneImages(ctx, out, api, repos, temporary, b.cfg.Prune.KeepLast)
+}
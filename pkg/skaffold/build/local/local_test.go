@@ -54,8 +54,8 @@ func (t testAuthHelper) GetAuthConfig(string) (types.AuthConfig, error) {
 func (t testAuthHelper) GetAllAuthConfigs() (map[string]types.AuthConfig, error) { return nil, nil }
 
 func TestLocalRun(t *testing.T) {
-	defer func(h docker.AuthConfigHelper) { docker.DefaultAuthHelper = h }(docker.DefaultAuthHelper)
-	docker.DefaultAuthHelper = testAuthHelper{}
+	defer func(k *docker.RegistryKeychain) { docker.DefaultKeychain = k }(docker.DefaultKeychain)
+	docker.DefaultKeychain = docker.NewKeychain(testAuthHelper{})
 
 	restore := testutil.SetupFakeKubernetesContext(t, api.Config{CurrentContext: "cluster1"})
 	defer restore()
@@ -63,7 +63,7 @@ func TestLocalRun(t *testing.T) {
 	tmpDir, cleanup := testutil.NewTempDir(t)
 	defer cleanup()
 
-	tmpDir.Write("Dockerfile", "")
+	tmpDir.Write("Dockerfile", "FROM scratch")
 
 	var tests = []struct {
 		description  string
@@ -87,7 +87,7 @@ func TestLocalRun(t *testing.T) {
 					ImageName: "gcr.io/test/image",
 					Workspace: tmpDir.Root(),
 					ArtifactType: latest.ArtifactType{
-						DockerArtifact: &latest.DockerArtifact{},
+						DockerArtifact: &latest.DockerArtifact{DockerfilePath: "Dockerfile"},
 					},
 				},
 			},
@@ -112,7 +112,7 @@ func TestLocalRun(t *testing.T) {
 					ImageName: "gcr.io/test/image",
 					Workspace: tmpDir.Root(),
 					ArtifactType: latest.ArtifactType{
-						DockerArtifact: &latest.DockerArtifact{},
+						DockerArtifact: &latest.DockerArtifact{DockerfilePath: "Dockerfile"},
 					},
 				},
 			},
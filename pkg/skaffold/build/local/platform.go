@@ -0,0 +1,132 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// clusterPlatform is the value of build.local.platform that builds images for the cluster's nodes.
+const clusterPlatform = "cluster"
+
+// nodesTimeout is how long listing the cluster's nodes can take.
+var nodesTimeout = 10 * time.Second // for testing
+
+// targetPlatform is the platform docker images are built for. It's empty to use the daemon's.
+func (b *Builder) targetPlatform(out io.Writer) (string, error) {
+	if b.cfg.Platform != clusterPlatform {
+		return b.cfg.Platform, nil
+	}
+
+	platforms, err := b.nodePlatforms()
+	if err != nil {
+		return "", errors.Wrap(err, "getting the platform of the cluster's nodes")
+	}
+
+	switch len(platforms) {
+	case 0:
+		return "", nil
+	case 1:
+		return platforms[0], nil
+	default:
+		color.Yellow.Fprintf(out, "The cluster's nodes run on %s, building for %s.\n", strings.Join(platforms, ", "), platforms[0])
+		return platforms[0], nil
+	}
+}
+
+// nodePlatforms returns the platforms of the cluster's nodes. They are only listed
+// once, even if that fails, so that an unreachable cluster doesn't slow every build down.
+func (b *Builder) nodePlatforms() ([]string, error) {
+	b.platformsLock.Lock()
+	defer b.platformsLock.Unlock()
+
+	if !b.platformsListed {
+		b.clusterPlatforms, b.clusterErr = listNodePlatforms()
+		b.platformsListed = true
+	}
+	return b.clusterPlatforms, b.clusterErr
+}
+
+// listNodePlatforms lists the platforms of the cluster's nodes, giving up after nodesTimeout.
+func listNodePlatforms() ([]string, error) {
+	client, err := kubernetes.Client()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting kubernetes client")
+	}
+
+	type result struct {
+		platforms []string
+		err       error
+	}
+	listed := make(chan result, 1)
+	go func() {
+		platforms, err := kubernetes.NodePlatforms(client)
+		listed <- result{platforms, err}
+	}()
+
+	select {
+	case r := <-listed:
+		return r.platforms, r.err
+	case <-time.After(nodesTimeout):
+		return nil, fmt.Errorf("listing nodes timed out after %s", nodesTimeout)
+	}
+}
+
+// CheckPlatforms warns when the images built in the local daemon can't run on
+// the cluster's nodes, instead of letting their pods crash with "exec format error".
+// Images that were pushed without being loaded in the daemon, like Jib's, are skipped.
+func (b *Builder) CheckPlatforms(ctx context.Context, out io.Writer, builds []build.Artifact) {
+	for _, artifact := range builds {
+		b.checkPlatform(ctx, out, artifact.ImageName, artifact.Tag)
+	}
+}
+
+func (b *Builder) checkPlatform(ctx context.Context, out io.Writer, imageName, tag string) {
+	api, err := b.dockerAPI()
+	if err != nil {
+		return
+	}
+
+	platform, err := docker.ImagePlatform(ctx, api, tag)
+	if err != nil || platform == "" {
+		return
+	}
+
+	nodes, err := b.nodePlatforms()
+	if err != nil {
+		logrus.Debugln("Unable to check the platform of the cluster's nodes:", err)
+		return
+	}
+	if len(nodes) == 0 || util.StrSliceContains(nodes, platform) {
+		return
+	}
+
+	color.Yellow.Fprintf(out, "WARN: %s is built for %s but the cluster's nodes run on %s: its containers will fail with \"exec format error\".\n", imageName, platform, strings.Join(nodes, ", "))
+	color.Yellow.Fprintln(out, "Set build.local.platform to `cluster` to build images for the cluster's platform.")
+}
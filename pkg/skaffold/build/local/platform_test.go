@@ -0,0 +1,176 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgo "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func fakeNodes(archs ...string) func() (clientgo.Interface, error) {
+	var nodes []runtime.Object
+	for _, arch := range archs {
+		nodes = append(nodes, &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: arch},
+			Status: v1.NodeStatus{
+				NodeInfo: v1.NodeSystemInfo{OperatingSystem: "linux", Architecture: arch},
+			},
+		})
+	}
+	return func() (clientgo.Interface, error) { return fake.NewSimpleClientset(nodes...), nil }
+}
+
+func TestCheckPlatform(t *testing.T) {
+	var tests = []struct {
+		description   string
+		imagePlatform string
+		nodes         []string
+		shouldWarn    bool
+	}{
+		{
+			description:   "same platform",
+			imagePlatform: "linux/amd64",
+			nodes:         []string{"amd64"},
+		},
+		{
+			description:   "mismatch",
+			imagePlatform: "linux/arm64",
+			nodes:         []string{"amd64"},
+			shouldWarn:    true,
+		},
+		{
+			description:   "one of the nodes' platforms",
+			imagePlatform: "linux/arm64",
+			nodes:         []string{"amd64", "arm64"},
+		},
+		{
+			description: "unknown image platform",
+			nodes:       []string{"amd64"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			defer func(c func() (clientgo.Interface, error)) { kubernetes.Client = c }(kubernetes.Client)
+			kubernetes.Client = fakeNodes(test.nodes...)
+
+			b := &Builder{
+				cfg: &latest.LocalBuild{},
+				api: testutil.NewFakeImageAPIClient(map[string]string{"image:latest": "sha256:imageid"}, &testutil.FakeImageAPIOptions{
+					ImagePlatform: test.imagePlatform,
+				}),
+			}
+
+			var out bytes.Buffer
+			b.CheckPlatforms(context.Background(), &out, []build.Artifact{{ImageName: "image", Tag: "image:latest"}})
+
+			testutil.CheckDeepEqual(t, test.shouldWarn, out.Len() > 0)
+		})
+	}
+}
+
+func TestNodePlatformsFailureIsCached(t *testing.T) {
+	defer func(c func() (clientgo.Interface, error)) { kubernetes.Client = c }(kubernetes.Client)
+	calls := 0
+	kubernetes.Client = func() (clientgo.Interface, error) {
+		calls++
+		return nil, errors.New("unreachable")
+	}
+
+	b := &Builder{cfg: &latest.LocalBuild{}}
+	_, err := b.nodePlatforms()
+	testutil.CheckError(t, true, err)
+	_, err = b.nodePlatforms()
+	testutil.CheckError(t, true, err)
+
+	testutil.CheckDeepEqual(t, 1, calls)
+}
+
+func TestNodePlatformsTimeout(t *testing.T) {
+	defer func(d time.Duration) { nodesTimeout = d }(nodesTimeout)
+	nodesTimeout = 10 * time.Millisecond
+
+	defer func(c func() (clientgo.Interface, error)) { kubernetes.Client = c }(kubernetes.Client)
+	client := fake.NewSimpleClientset()
+	unblock := make(chan bool)
+	defer close(unblock)
+	client.PrependReactor("list", "nodes", func(k8stesting.Action) (bool, runtime.Object, error) {
+		<-unblock
+		return true, &v1.NodeList{}, nil
+	})
+	kubernetes.Client = func() (clientgo.Interface, error) { return client, nil }
+
+	b := &Builder{cfg: &latest.LocalBuild{}}
+	_, err := b.nodePlatforms()
+
+	testutil.CheckError(t, true, err)
+}
+
+func TestTargetPlatform(t *testing.T) {
+	var tests = []struct {
+		description string
+		platform    string
+		nodes       []string
+		expected    string
+	}{
+		{
+			description: "daemon platform",
+			nodes:       []string{"amd64"},
+		},
+		{
+			description: "explicit platform",
+			platform:    "linux/arm64",
+			expected:    "linux/arm64",
+		},
+		{
+			description: "cluster platform",
+			platform:    "cluster",
+			nodes:       []string{"amd64"},
+			expected:    "linux/amd64",
+		},
+		{
+			description: "mixed cluster",
+			platform:    "cluster",
+			nodes:       []string{"arm64", "amd64"},
+			expected:    "linux/amd64",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			defer func(c func() (clientgo.Interface, error)) { kubernetes.Client = c }(kubernetes.Client)
+			kubernetes.Client = fakeNodes(test.nodes...)
+
+			b := &Builder{cfg: &latest.LocalBuild{Platform: test.platform}}
+			platform, err := b.targetPlatform(&bytes.Buffer{})
+
+			testutil.CheckErrorAndDeepEqual(t, false, err, test.expected, platform)
+		})
+	}
+}
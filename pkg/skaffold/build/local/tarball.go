@@ -0,0 +1,45 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
+)
+
+// loadTarball imports an image tarball produced by another tool. The image
+// is then tagged and pushed like any other image.
+func (b *Builder) loadTarball(ctx context.Context, out io.Writer, workspace string, a *latest.TarballArtifact) (string, error) {
+	tarball, err := os.Open(filepath.Join(workspace, a.Path))
+	if err != nil {
+		return "", errors.Wrap(err, "opening image tarball")
+	}
+	defer tarball.Close()
+
+	api, err := b.dockerAPI()
+	if err != nil {
+		return "", err
+	}
+
+	return docker.LoadImage(ctx, out, api, tarball)
+}
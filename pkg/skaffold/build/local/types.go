@@ -19,9 +19,11 @@ package local
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/cluster"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -31,26 +33,35 @@ import (
 type Builder struct {
 	cfg *latest.LocalBuild
 
-	api          docker.APIClient
+	apiLock      sync.Mutex
+	api          docker.APIClient // use dockerAPI()
 	localCluster bool
 	pushImages   bool
+	loadImages   bool
 	kubeContext  string
+	cluster      cluster.Cluster
 
 	alreadyTagged map[string]string
+
+	platformsLock    sync.Mutex
+	platformsListed  bool
+	clusterPlatforms []string // use nodePlatforms()
+	clusterErr       error
+
+	temporaryImagesLock sync.Mutex
+	temporaryImages     []string
 }
 
 // NewBuilder returns an new instance of a local Builder.
-func NewBuilder(cfg *latest.LocalBuild, kubeContext string) (*Builder, error) {
-	api, err := docker.NewAPIClient()
-	if err != nil {
-		return nil, errors.Wrap(err, "getting docker client")
-	}
-
-	localCluster := kubeContext == constants.DefaultMinikubeContext || kubeContext == constants.DefaultDockerForDesktopContext
+// Unless the configuration says otherwise, images are pushed to
+// a registry only if the cluster needs it. Images that aren't pushed
+// are loaded into local clusters that don't share the docker daemon, like kind.
+func NewBuilder(cfg *latest.LocalBuild, kubeContext string, c cluster.Cluster) (*Builder, error) {
+	localCluster := c.IsLocal()
 	var pushImages bool
 	if cfg.Push == nil {
-		pushImages = !localCluster
-		logrus.Debugf("push value not present, defaulting to %t because localCluster is %t", pushImages, localCluster)
+		pushImages = c.Push
+		logrus.Debugf("push value not present, defaulting to %t for cluster type %q", pushImages, c.Type)
 	} else {
 		pushImages = *cfg.Push
 	}
@@ -58,22 +69,74 @@ func NewBuilder(cfg *latest.LocalBuild, kubeContext string) (*Builder, error) {
 	return &Builder{
 		cfg:          cfg,
 		kubeContext:  kubeContext,
-		api:          api,
+		cluster:      c,
 		localCluster: localCluster,
 		pushImages:   pushImages,
+		loadImages:   localCluster && !pushImages,
 	}, nil
 }
 
+// Pushes tells whether the images are pushed to a registry or kept in the local daemon.
+func (b *Builder) Pushes() bool {
+	return b.pushImages
+}
+
+// dockerAPI returns the docker client. It's only created when first needed
+// since some artifacts, like Jib ones pushed to a registry, don't use the daemon.
+func (b *Builder) dockerAPI() (docker.APIClient, error) {
+	b.apiLock.Lock()
+	defer b.apiLock.Unlock()
+
+	if b.api != nil {
+		return b.api, nil
+	}
+
+	api, err := docker.NewAPIClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting docker client")
+	}
+	b.api = api
+	return api, nil
+}
+
+// closeDockerAPI releases the connections of the docker client, if it was used.
+// The client can still be used by the next builds.
+func (b *Builder) closeDockerAPI() {
+	b.apiLock.Lock()
+	defer b.apiLock.Unlock()
+
+	if b.api == nil {
+		return
+	}
+	if err := b.api.Close(); err != nil {
+		logrus.Debugln("Unable to close the docker client:", err)
+	}
+}
+
 // Labels are labels specific to local builder.
 func (b *Builder) Labels() map[string]string {
 	labels := map[string]string{
 		constants.Labels.Builder: "local",
 	}
 
-	v, err := b.api.ServerVersion(context.Background())
+	api, err := b.dockerAPI()
+	if err != nil {
+		return labels
+	}
+
+	v, err := api.ServerVersion(context.Background())
 	if err == nil {
 		labels[constants.Labels.DockerAPIVersion] = fmt.Sprintf("%v", v.APIVersion)
 	}
 
 	return labels
 }
+
+// addTemporaryImage remembers a random name given to an image while it's built,
+// so that it can be pruned.
+func (b *Builder) addTemporaryImage(image string) {
+	b.temporaryImagesLock.Lock()
+	defer b.temporaryImagesLock.Unlock()
+
+	b.temporaryImages = append(b.temporaryImages, image)
+}
@@ -41,6 +41,8 @@ func InParallel(ctx context.Context, out io.Writer, tagger tag.Tagger, artifacts
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	buildArtifact = withEvents(withFailureSummary(withHooks(buildArtifact)))
+
 	n := len(artifacts)
 	tags := make([]string, n)
 	errs := make([]error, n)
@@ -80,6 +82,13 @@ func InParallel(ctx context.Context, out io.Writer, tagger tag.Tagger, artifacts
 		}
 
 		if errs[i] != nil {
+			// Cancel the other builds and wait for them to clean up after themselves.
+			cancel()
+			for _, lines := range outputs[i+1:] {
+				for range lines {
+				}
+			}
+
 			return nil, errors.Wrapf(errs[i], "building [%s]", artifact.ImageName)
 		}
 
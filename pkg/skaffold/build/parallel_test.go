@@ -0,0 +1,55 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package build
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+)
+
+func TestInParallelCancelsOtherBuildsOnError(t *testing.T) {
+	artifacts := []*latest.Artifact{
+		{ImageName: "failing"},
+		{ImageName: "slow"},
+	}
+
+	cancelled := false
+	buildArtifact := func(ctx context.Context, out io.Writer, tagger tag.Tagger, artifact *latest.Artifact) (string, error) {
+		if artifact.ImageName == "failing" {
+			return "", errors.New("BUG")
+		}
+
+		<-ctx.Done()
+		cancelled = true
+		return "", ctx.Err()
+	}
+
+	_, err := InParallel(context.Background(), ioutil.Discard, nil, artifacts, buildArtifact)
+
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	if !cancelled {
+		t.Error("Expected the slow build to be cancelled before returning")
+	}
+}
@@ -29,6 +29,48 @@ import (
 // for testing to an arbitrary method.
 var IsTerminal = isTerminal
 
+const (
+	// AutoMode colors the output written to a terminal, unless NO_COLOR is set.
+	AutoMode = "auto"
+	// AlwaysMode colors the output, even when it's not written to a terminal.
+	AlwaysMode = "always"
+	// NeverMode never colors the output.
+	NeverMode = "never"
+)
+
+var mode = AutoMode
+
+// SetMode chooses when the output is colored.
+func SetMode(m string) error {
+	switch m {
+	case "":
+		mode = AutoMode
+	case AutoMode, AlwaysMode, NeverMode:
+		mode = m
+	default:
+		return fmt.Errorf("unknown color mode %q, expected %s, %s or %s", m, AutoMode, AlwaysMode, NeverMode)
+	}
+	return nil
+}
+
+// Mode returns when the output is colored.
+func Mode() string {
+	return mode
+}
+
+// Enabled tells whether the text written to out is colored.
+// See https://no-color.org for NO_COLOR.
+func Enabled(out io.Writer) bool {
+	switch mode {
+	case AlwaysMode:
+		return true
+	case NeverMode:
+		return false
+	default:
+		return os.Getenv("NO_COLOR") == "" && IsTerminal(out)
+	}
+}
+
 // Color can be used to format text using ANSI escape codes so it can be printed to
 // the terminal in color.
 type Color int
@@ -64,20 +106,20 @@ var (
 )
 
 // Fprint wraps the operands in c's ANSI escape codes, and outputs the result to
-// out. If out is not a terminal, the escape codes will not be added.
+// out. The escape codes are only added if colors are enabled for out.
 // It returns the number of bytes written and any errors encountered.
 func (c Color) Fprint(out io.Writer, a ...interface{}) (n int, err error) {
-	if IsTerminal(out) {
+	if Enabled(out) {
 		return fmt.Fprintf(out, "\033[%dm%s\033[0m", c, fmt.Sprint(a...))
 	}
 	return fmt.Fprint(out, a...)
 }
 
 // Fprintln wraps the operands in c's ANSI escape codes, and outputs the result to
-// out, followed by a newline. If out is not a terminal, the escape codes will not be added.
+// out, followed by a newline. The escape codes are only added if colors are enabled for out.
 // It returns the number of bytes written and any errors encountered.
 func (c Color) Fprintln(out io.Writer, a ...interface{}) (n int, err error) {
-	if IsTerminal(out) {
+	if Enabled(out) {
 		return fmt.Fprintf(out, "\033[%dm%s\033[0m\n", c, strings.TrimSuffix(fmt.Sprintln(a...), "\n"))
 	}
 	return fmt.Fprintln(out, a...)
@@ -85,10 +127,10 @@ func (c Color) Fprintln(out io.Writer, a ...interface{}) (n int, err error) {
 
 // Fprintf applies formats according to the format specifier (and the optional interfaces provided),
 // wraps the result in c's ANSI escape codes, and outputs the result to
-// out, followed by a newline. If out is not a terminal, the escape codes will not be added.
+// out, followed by a newline. The escape codes are only added if colors are enabled for out.
 // It returns the number of bytes written and any errors encountered.
 func (c Color) Fprintf(out io.Writer, format string, a ...interface{}) (n int, err error) {
-	if IsTerminal(out) {
+	if Enabled(out) {
 		return fmt.Fprintf(out, "\033[%dm%s\033[0m", c, fmt.Sprintf(format, a...))
 	}
 	return fmt.Fprintf(out, format, a...)
@@ -100,6 +142,8 @@ func isTerminal(w io.Writer) bool {
 	switch v := w.(type) {
 	case *os.File:
 		return terminal.IsTerminal(int(v.Fd()))
+	case *TimestampWriter:
+		return isTerminal(v.out)
 	default:
 		return false
 	}
@@ -19,7 +19,10 @@ package color
 import (
 	"bytes"
 	"io"
+	"os"
 	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
 )
 
 func compareText(t *testing.T, expected, actual string, expectedN int, actualN int, err error) {
@@ -85,3 +88,40 @@ func TestFprintfNoTTY(t *testing.T) {
 	expected := "It's been 1 week"
 	compareText(t, expected, b.String(), 16, n, err)
 }
+
+func TestEnabled(t *testing.T) {
+	defer func(f func(io.Writer) bool) { IsTerminal = f }(IsTerminal)
+	defer SetMode(AutoMode)
+	defer os.Setenv("NO_COLOR", os.Getenv("NO_COLOR"))
+
+	var tests = []struct {
+		description string
+		mode        string
+		terminal    bool
+		noColor     string
+		expected    bool
+	}{
+		{description: "auto on terminal", mode: "auto", terminal: true, expected: true},
+		{description: "auto not on terminal", mode: "auto", terminal: false, expected: false},
+		{description: "auto with NO_COLOR", mode: "auto", terminal: true, noColor: "1", expected: false},
+		{description: "default mode", mode: "", terminal: true, expected: true},
+		{description: "always", mode: "always", terminal: false, noColor: "1", expected: true},
+		{description: "never", mode: "never", terminal: true, expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			IsTerminal = func(io.Writer) bool { return test.terminal }
+			os.Setenv("NO_COLOR", test.noColor)
+			testutil.CheckError(t, false, SetMode(test.mode))
+
+			testutil.CheckDeepEqual(t, test.expected, Enabled(&bytes.Buffer{}))
+		})
+	}
+}
+
+func TestSetMode(t *testing.T) {
+	defer SetMode(AutoMode)
+
+	testutil.CheckError(t, true, SetMode("sometimes"))
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package color
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+)
+
+// TimestampFormat is RFC3339 with milliseconds.
+const TimestampFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// for testing
+var now = time.Now
+
+// TimestampWriter prefixes each line written to the underlying writer with
+// the time it was written at, once enabled.
+type TimestampWriter struct {
+	out     io.Writer
+	enabled bool
+	midLine bool
+	lock    sync.Mutex
+}
+
+// NewTimestampWriter wraps out. Timestamps are only added after Enable is called.
+func NewTimestampWriter(out io.Writer) *TimestampWriter {
+	return &TimestampWriter{out: out}
+}
+
+// Enable starts prefixing the lines with timestamps.
+func (w *TimestampWriter) Enable() {
+	w.lock.Lock()
+	w.enabled = true
+	w.lock.Unlock()
+}
+
+func (w *TimestampWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if !w.enabled {
+		return w.out.Write(p)
+	}
+
+	n := len(p)
+	var buf bytes.Buffer
+	prefix := now().Format(TimestampFormat) + " "
+	for len(p) > 0 {
+		if !w.midLine {
+			buf.WriteString(prefix)
+		}
+
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			buf.Write(p)
+			w.midLine = true
+			break
+		}
+
+		buf.Write(p[:i+1])
+		p = p[i+1:]
+		w.midLine = false
+	}
+
+	if _, err := w.out.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package color
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestTimestampWriter(t *testing.T) {
+	defer func(f func() time.Time) { now = f }(now)
+	now = func() time.Time { return time.Date(2018, 11, 5, 14, 3, 7, 250000000, time.UTC) }
+
+	var tests = []struct {
+		description string
+		enabled     bool
+		writes      []string
+		expected    string
+	}{
+		{
+			description: "disabled",
+			writes:      []string{"line1\n", "line2\n"},
+			expected:    "line1\nline2\n",
+		},
+		{
+			description: "one line per write",
+			enabled:     true,
+			writes:      []string{"line1\n", "line2\n"},
+			expected:    "2018-11-05T14:03:07.250Z line1\n2018-11-05T14:03:07.250Z line2\n",
+		},
+		{
+			description: "several lines per write",
+			enabled:     true,
+			writes:      []string{"line1\nline2\n"},
+			expected:    "2018-11-05T14:03:07.250Z line1\n2018-11-05T14:03:07.250Z line2\n",
+		},
+		{
+			description: "lines split across writes",
+			enabled:     true,
+			writes:      []string{"li", "ne1\nli", "ne2\n"},
+			expected:    "2018-11-05T14:03:07.250Z line1\n2018-11-05T14:03:07.250Z line2\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := NewTimestampWriter(&buf)
+			if test.enabled {
+				w.Enable()
+			}
+
+			for _, s := range test.writes {
+				n, err := fmt.Fprint(w, s)
+				testutil.CheckErrorAndDeepEqual(t, false, err, len(s), n)
+			}
+
+			testutil.CheckDeepEqual(t, test.expected, buf.String())
+		})
+	}
+}
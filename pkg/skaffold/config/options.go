@@ -18,25 +18,79 @@ package config
 
 import (
 	"strings"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/cluster"
 )
 
 // SkaffoldOptions are options that are set by command line arguments not included
 // in the config file itself
 type SkaffoldOptions struct {
+	// ConfigurationFile is used by commands that work on a single file.
 	ConfigurationFile string
-	Cleanup           bool
-	Notification      bool
-	Tail              bool
-	TailDev           bool
-	PortForward       bool
-	Profiles          []string
-	CustomTag         string
-	Namespace         string
-	Watch             []string
-	Trigger           string
-	CustomLabels      []string
-	WatchPollInterval int
-	DefaultRepo       string
+	// ConfigurationFiles are merged into a single pipeline.
+	ConfigurationFiles  []string
+	Cleanup             bool
+	Notification        bool
+	Tail                bool
+	TailDev             bool
+	PortForward         bool
+	Profiles            []string
+	CustomTag           string
+	Namespace           string
+	Watch               []string
+	Intercept           []string
+	Trigger             string
+	ConfigChange        string
+	CustomLabels        []string
+	WatchPollInterval   int
+	DefaultRepo         string
+	EnableRPC           bool
+	RPCPort             int
+	DisableAutoBuild    bool
+	DisableAutoSync     bool
+	DisableAutoDeploy   bool
+	DebugMode           bool
+	StatusCheck         bool
+	Rollback            bool
+	BuildTimeout        time.Duration
+	DeployTimeout       time.Duration
+	StatusCheckTimeout  time.Duration
+	ShutdownGracePeriod time.Duration
+	PipelineDev         bool
+	DryRun              bool
+	BuildCache          string
+	AllowedEnv          []string
+	Overrides           []string
+	Strict              bool
+	InsecureRegistries  []string
+	RegistryCABundle    string
+	RegistryMirrors     []string
+	DockerOutput        string
+	EventOutput         string
+	EventFile           string
+	KubeConfig          string
+	KubeContext         string
+	KubectlBinary       string
+	KubectlWrapper      string
+	CheckPermissions    bool
+	Quiet               bool
+
+	// LocalCluster overrides the detection of local clusters, when not nil.
+	LocalCluster *bool
+
+	// LocalClusterRules declare the kube-contexts of additional local clusters.
+	LocalClusterRules []cluster.Rule
+
+	// Push overrides whether the local builder pushes the images, when not nil.
+	Push *bool
+
+	// TargetImages restricts the build to the artifacts whose image name
+	// contains one of these expressions.
+	TargetImages []string
+
+	// RunID uniquely identifies a skaffold invocation.
+	RunID string
 }
 
 // Labels returns a map of labels to be applied to all deployed
@@ -19,6 +19,7 @@ package constants
 import (
 	"fmt"
 	"runtime"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
@@ -55,10 +56,49 @@ const (
 
 	DefaultAlpineImage = "alpine"
 
+	// DefaultPruneKeepLast is how many images are kept for each artifact when pruning
+	DefaultPruneKeepLast = 1
+
+	// DefaultScanSeverity is the lowest severity of the vulnerabilities reported by image scans
+	DefaultScanSeverity = "HIGH"
+
+	// DefaultSBOMFormat is the format of the generated software bill of materials
+	DefaultSBOMFormat = "spdx-json"
+
+	// DefaultSBOMDir is where the software bills of materials are written
+	DefaultSBOMDir = ".skaffold/sbom"
+
+	// DefaultDelveImage is the image of the sidecar used to debug Go containers.
+	DefaultDelveImage = "gcr.io/k8s-skaffold/skaffold-debug-support/go"
+
+	// DebugGoGcflags are the Go compiler flags that disable optimizations and inlining.
+	DebugGoGcflags = "all=-N -l"
+
 	UpdateCheckEnvironmentVariable = "SKAFFOLD_UPDATE_CHECK"
 
 	DefaultCloudBuildDockerImage = "gcr.io/cloud-builders/docker"
 
+	// DefaultRPCPort is the default port of the control API
+	DefaultRPCPort = 50051
+
+	// DefaultDaemonPort is the default port of the daemon API
+	DefaultDaemonPort = 50052
+
+	// DefaultStatusCheckTimeout is how long to wait for deployments to be rolled out
+	DefaultStatusCheckTimeout = 2 * time.Minute
+
+	// DefaultJobTimeout is how long to wait for the Jobs run during deploys to complete
+	DefaultJobTimeout = 10 * time.Minute
+
+	// DefaultCanaryWeight is the percentage of replicas that run the canary of a Deployment
+	DefaultCanaryWeight = 20
+
+	// DefaultCanaryPause is how long a canary runs before it's promoted
+	DefaultCanaryPause = "30s"
+
+	// DefaultShutdownGracePeriod is how long to wait for in-flight operations and cleanup when interrupted
+	DefaultShutdownGracePeriod = 30 * time.Second
+
 	// A regex matching valid repository names (https://github.com/docker/distribution/blob/master/reference/reference.go)
 	RepositoryComponentRegex string = `^[a-z\d]+(?:(?:[_.]|__|-+)[a-z\d]+)*$`
 )
@@ -72,6 +112,8 @@ var Labels = struct {
 	Deployer         string
 	Builder          string
 	DockerAPIVersion string
+	RunID            string
+	SkaffoldVersion  string
 	DefaultLabels    map[string]string
 }{
 	DefaultLabels: map[string]string{
@@ -81,4 +123,6 @@ var Labels = struct {
 	Deployer:         "skaffold-deployer",
 	Builder:          "skaffold-builder",
 	DockerAPIVersion: "docker-api-version",
+	RunID:            "skaffold-run-id",
+	SkaffoldVersion:  "skaffold-version",
 }
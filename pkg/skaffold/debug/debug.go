@@ -0,0 +1,282 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/kubectl"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/api/core/v1"
+	extv1beta1 "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// ConfigAnnotation is the annotation set on debuggable pods.
+// It lists the debugging runtime and ports for each container.
+const ConfigAnnotation = "debug.cloud.google.com/config"
+
+// ContainerDebugConfiguration captures how a container was configured for debugging.
+type ContainerDebugConfiguration struct {
+	// Runtime is the language runtime: jvm, nodejs or go.
+	Runtime string `json:"runtime,omitempty"`
+	// Ports lists the debug ports, keyed by protocol name.
+	Ports map[string]int32 `json:"ports,omitempty"`
+}
+
+// imageConfiguration captures the information from a container image and its
+// container definition that is relevant to detect the language runtime.
+type imageConfiguration struct {
+	env        map[string]string
+	entrypoint []string
+	arguments  []string
+}
+
+// portAllocator returns a free port, close to the desired one.
+type portAllocator func(desired int32) int32
+
+// containerTransformer configures a container for debugging a specific language runtime.
+type containerTransformer interface {
+	// IsApplicable says if the container uses this runtime.
+	IsApplicable(config imageConfiguration) bool
+
+	// Apply configures the container for debugging and returns the
+	// debug configuration along with the sidecar containers to add to the pod.
+	Apply(container *v1.Container, config imageConfiguration, portAlloc portAllocator) (*ContainerDebugConfiguration, []v1.Container)
+}
+
+var containerTransformers = []containerTransformer{
+	jdwpTransformer{},
+	nodeTransformer{},
+	dlvTransformer{},
+}
+
+// for testing
+var retrieveConfiguration = retrieveImageConfiguration
+
+// ApplyDebuggingTransforms rewrites the pod specs found in a list of manifests
+// so that language debuggers can be attached to the containers built by skaffold.
+func ApplyDebuggingTransforms(l kubectl.ManifestList, builds []build.Artifact) (kubectl.ManifestList, error) {
+	decoder := scheme.Codecs.UniversalDeserializer()
+
+	var updated kubectl.ManifestList
+	for _, manifest := range l {
+		obj, _, err := decoder.Decode(manifest, nil, nil)
+		if err != nil {
+			logrus.Debugf("Not transforming manifest for debugging: %s", err)
+			updated = append(updated, manifest)
+			continue
+		}
+
+		if !transformManifest(obj, builds) {
+			updated = append(updated, manifest)
+			continue
+		}
+
+		out, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, errors.Wrap(err, "marshalling manifest")
+		}
+		updated = append(updated, out)
+	}
+
+	return updated, nil
+}
+
+// transformManifest configures the pod spec of a Kubernetes object.
+// It returns true if the object was changed.
+func transformManifest(obj runtime.Object, builds []build.Artifact) bool {
+	switch o := obj.(type) {
+	case *v1.Pod:
+		return transformPodSpec(&o.ObjectMeta, &o.Spec, builds)
+	case *v1.ReplicationController:
+		if o.Spec.Template != nil {
+			return transformPodSpec(&o.Spec.Template.ObjectMeta, &o.Spec.Template.Spec, builds)
+		}
+	case *appsv1.Deployment:
+		return transformPodSpec(&o.Spec.Template.ObjectMeta, &o.Spec.Template.Spec, builds)
+	case *appsv1.DaemonSet:
+		return transformPodSpec(&o.Spec.Template.ObjectMeta, &o.Spec.Template.Spec, builds)
+	case *appsv1.ReplicaSet:
+		return transformPodSpec(&o.Spec.Template.ObjectMeta, &o.Spec.Template.Spec, builds)
+	case *appsv1.StatefulSet:
+		return transformPodSpec(&o.Spec.Template.ObjectMeta, &o.Spec.Template.Spec, builds)
+	case *extv1beta1.Deployment:
+		return transformPodSpec(&o.Spec.Template.ObjectMeta, &o.Spec.Template.Spec, builds)
+	case *batchv1.Job:
+		return transformPodSpec(&o.Spec.Template.ObjectMeta, &o.Spec.Template.Spec, builds)
+	default:
+		logrus.Debugf("No debug transformation for %T", obj)
+	}
+
+	return false
+}
+
+func transformPodSpec(metadata *metav1.ObjectMeta, podSpec *v1.PodSpec, builds []build.Artifact) bool {
+	portAlloc := newPortAllocator(podSpec)
+
+	configurations := make(map[string]ContainerDebugConfiguration)
+	var sidecars []v1.Container
+
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		if !isBuiltBySkaffold(container.Image, builds) {
+			continue
+		}
+
+		config := retrieveConfiguration(container)
+		for _, transformer := range containerTransformers {
+			if !transformer.IsApplicable(config) {
+				continue
+			}
+
+			configuration, containers := transformer.Apply(container, config, portAlloc)
+			if configuration != nil {
+				logrus.Infof("Configured %q for %s debugging", container.Name, configuration.Runtime)
+				configurations[container.Name] = *configuration
+				sidecars = append(sidecars, containers...)
+			}
+			break
+		}
+	}
+
+	if len(configurations) == 0 {
+		return false
+	}
+
+	if len(sidecars) > 0 {
+		share := true
+		podSpec.ShareProcessNamespace = &share
+		podSpec.Containers = append(podSpec.Containers, sidecars...)
+	}
+
+	annotation, err := json.Marshal(configurations)
+	if err != nil {
+		logrus.Warnf("Unable to marshal debug configuration: %s", err)
+	} else {
+		if metadata.Annotations == nil {
+			metadata.Annotations = make(map[string]string)
+		}
+		metadata.Annotations[ConfigAnnotation] = string(annotation)
+	}
+
+	return true
+}
+
+func isBuiltBySkaffold(image string, builds []build.Artifact) bool {
+	for _, build := range builds {
+		if build.Tag == image {
+			return true
+		}
+	}
+	return false
+}
+
+// retrieveImageConfiguration combines the configuration of the image
+// with the overrides of the container definition.
+func retrieveImageConfiguration(container *v1.Container) imageConfiguration {
+	config := imageConfiguration{
+		env: make(map[string]string),
+	}
+
+	imageConfig, err := docker.RetrieveImage(container.Image)
+	if err != nil {
+		logrus.Debugf("Unable to retrieve image configuration for %s: %s", container.Image, err)
+	} else {
+		for _, env := range imageConfig.Config.Env {
+			kv := strings.SplitN(env, "=", 2)
+			if len(kv) == 2 {
+				config.env[kv[0]] = kv[1]
+			}
+		}
+		config.entrypoint = imageConfig.Config.Entrypoint
+		config.arguments = imageConfig.Config.Cmd
+	}
+
+	return withContainerOverrides(config, container)
+}
+
+// withContainerOverrides applies the Kubernetes rules for overriding an
+// image's entrypoint and arguments.
+func withContainerOverrides(config imageConfiguration, container *v1.Container) imageConfiguration {
+	for _, env := range container.Env {
+		config.env[env.Name] = env.Value
+	}
+
+	if len(container.Command) > 0 {
+		config.entrypoint = container.Command
+		config.arguments = nil
+	}
+	if len(container.Args) > 0 {
+		config.arguments = container.Args
+	}
+
+	return config
+}
+
+// commandLine returns the full command line run by a container.
+func (c imageConfiguration) commandLine() []string {
+	var commandLine []string
+	commandLine = append(commandLine, c.entrypoint...)
+	commandLine = append(commandLine, c.arguments...)
+	return commandLine
+}
+
+func newPortAllocator(podSpec *v1.PodSpec) portAllocator {
+	used := make(map[int32]bool)
+	for _, container := range podSpec.Containers {
+		for _, port := range container.Ports {
+			used[port.ContainerPort] = true
+		}
+	}
+
+	return func(desired int32) int32 {
+		port := desired
+		for used[port] {
+			port++
+		}
+		used[port] = true
+		return port
+	}
+}
+
+// setEnvVar sets an environment variable on a container, replacing any existing value.
+func setEnvVar(container *v1.Container, name, value string) {
+	for i := range container.Env {
+		if container.Env[i].Name == name {
+			container.Env[i].Value = value
+			return
+		}
+	}
+	container.Env = append(container.Env, v1.EnvVar{Name: name, Value: value})
+}
+
+func exposePort(container *v1.Container, name string, port int32) {
+	container.Ports = append(container.Ports, v1.ContainerPort{
+		Name:          name,
+		ContainerPort: port,
+	})
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/kubectl"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"k8s.io/api/core/v1"
+)
+
+func fromContainer(container *v1.Container) imageConfiguration {
+	return withContainerOverrides(imageConfiguration{env: map[string]string{}}, container)
+}
+
+func TestTransformContainers(t *testing.T) {
+	var tests = []struct {
+		description       string
+		container         v1.Container
+		expectedRuntime   string
+		expectedContainer v1.Container
+		expectedSidecars  int
+	}{
+		{
+			description: "java command",
+			container: v1.Container{
+				Name:    "app",
+				Command: []string{"java", "-jar", "app.jar"},
+			},
+			expectedRuntime: "jvm",
+			expectedContainer: v1.Container{
+				Name:    "app",
+				Command: []string{"java", "-jar", "app.jar"},
+				Env:     []v1.EnvVar{{Name: "JAVA_TOOL_OPTIONS", Value: "-agentlib:jdwp=transport=dt_socket,server=y,suspend=n,address=5005"}},
+				Ports:   []v1.ContainerPort{{Name: "jdwp", ContainerPort: 5005}},
+			},
+		},
+		{
+			description: "existing JAVA_TOOL_OPTIONS",
+			container: v1.Container{
+				Name: "app",
+				Env:  []v1.EnvVar{{Name: "JAVA_TOOL_OPTIONS", Value: "-Xmx1g"}},
+			},
+			expectedRuntime: "jvm",
+			expectedContainer: v1.Container{
+				Name:  "app",
+				Env:   []v1.EnvVar{{Name: "JAVA_TOOL_OPTIONS", Value: "-Xmx1g -agentlib:jdwp=transport=dt_socket,server=y,suspend=n,address=5005"}},
+				Ports: []v1.ContainerPort{{Name: "jdwp", ContainerPort: 5005}},
+			},
+		},
+		{
+			description: "node command",
+			container: v1.Container{
+				Name:    "app",
+				Command: []string{"node"},
+				Args:    []string{"index.js"},
+			},
+			expectedRuntime: "nodejs",
+			expectedContainer: v1.Container{
+				Name:    "app",
+				Command: []string{"node", "--inspect=0.0.0.0:9229", "index.js"},
+				Ports:   []v1.ContainerPort{{Name: "devtools", ContainerPort: 9229}},
+			},
+		},
+		{
+			description: "npm command",
+			container: v1.Container{
+				Name:    "app",
+				Command: []string{"npm", "start"},
+			},
+			expectedRuntime: "nodejs",
+			expectedContainer: v1.Container{
+				Name:    "app",
+				Command: []string{"npm", "start"},
+				Env:     []v1.EnvVar{{Name: "NODE_OPTIONS", Value: "--inspect=0.0.0.0:9229"}},
+				Ports:   []v1.ContainerPort{{Name: "devtools", ContainerPort: 9229}},
+			},
+		},
+		{
+			description: "go binary",
+			container: v1.Container{
+				Name:    "app",
+				Command: []string{"/app/server"},
+				Env:     []v1.EnvVar{{Name: "GOTRACEBACK", Value: "all"}},
+			},
+			expectedRuntime: "go",
+			expectedContainer: v1.Container{
+				Name:    "app",
+				Command: []string{"/app/server"},
+				Env:     []v1.EnvVar{{Name: "GOTRACEBACK", Value: "all"}},
+			},
+			expectedSidecars: 1,
+		},
+		{
+			description: "unknown runtime",
+			container: v1.Container{
+				Name:    "app",
+				Command: []string{"/bin/sh"},
+			},
+			expectedContainer: v1.Container{
+				Name:    "app",
+				Command: []string{"/bin/sh"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			container := test.container
+			config := fromContainer(&container)
+
+			var runtime string
+			var sidecars []v1.Container
+			for _, transformer := range containerTransformers {
+				if transformer.IsApplicable(config) {
+					debugConfig, containers := transformer.Apply(&container, config, func(port int32) int32 { return port })
+					runtime = debugConfig.Runtime
+					sidecars = containers
+					break
+				}
+			}
+
+			testutil.CheckDeepEqual(t, test.expectedRuntime, runtime)
+			testutil.CheckDeepEqual(t, test.expectedContainer, container)
+			testutil.CheckDeepEqual(t, test.expectedSidecars, len(sidecars))
+		})
+	}
+}
+
+func TestPortAllocator(t *testing.T) {
+	alloc := newPortAllocator(&v1.PodSpec{
+		Containers: []v1.Container{{
+			Ports: []v1.ContainerPort{{ContainerPort: 5005}},
+		}},
+	})
+
+	testutil.CheckDeepEqual(t, int32(5006), alloc(5005))
+	testutil.CheckDeepEqual(t, int32(5007), alloc(5005))
+	testutil.CheckDeepEqual(t, int32(9229), alloc(9229))
+}
+
+func TestApplyDebuggingTransforms(t *testing.T) {
+	retrieveConfiguration = fromContainer
+	defer func() { retrieveConfiguration = retrieveImageConfiguration }()
+
+	manifests := kubectl.ManifestList{[]byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: gcr.io/project/web:TAG
+        command: ["java", "-jar", "web.jar"]
+      - name: proxy
+        image: nginx
+`), []byte(`apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+  - port: 80
+`)}
+
+	builds := []build.Artifact{{ImageName: "gcr.io/project/web", Tag: "gcr.io/project/web:TAG"}}
+
+	updated, err := ApplyDebuggingTransforms(manifests, builds)
+
+	testutil.CheckError(t, false, err)
+	testutil.CheckDeepEqual(t, 2, len(updated))
+	testutil.CheckDeepEqual(t, string(manifests[1]), string(updated[1]))
+
+	deployment := string(updated[0])
+	for _, expected := range []string{
+		ConfigAnnotation + `: '{"web":{"runtime":"jvm","ports":{"jdwp":5005}}}'`,
+		"name: JAVA_TOOL_OPTIONS",
+		"name: jdwp",
+	} {
+		if !strings.Contains(deployment, expected) {
+			t.Errorf("Expected %q in transformed manifest:\n%s", expected, deployment)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/sirupsen/logrus"
+	"k8s.io/api/core/v1"
+)
+
+const defaultDlvPort = 56268
+
+// dlvTransformer configures Go containers for debugging with a delve sidecar
+// that attaches to the process of the main container.
+type dlvTransformer struct{}
+
+func (dlvTransformer) IsApplicable(config imageConfiguration) bool {
+	// These variables are read by the Go runtime.
+	for _, env := range []string{"GODEBUG", "GOGC", "GOMAXPROCS", "GOTRACEBACK"} {
+		if _, found := config.env[env]; found {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply adds a sidecar that waits for the binary to run and attaches delve to it.
+// The pod has to share its process namespace with the sidecar.
+func (dlvTransformer) Apply(container *v1.Container, config imageConfiguration, portAlloc portAllocator) (*ContainerDebugConfiguration, []v1.Container) {
+	commandLine := config.commandLine()
+	if len(commandLine) == 0 {
+		logrus.Warnf("Unable to find the Go binary run by %q: not configured for debugging", container.Name)
+		return nil, nil
+	}
+
+	port := portAlloc(defaultDlvPort)
+	binary := filepath.Base(commandLine[0])
+	attach := fmt.Sprintf("until pid=$(pidof -s %s); do sleep 1; done; exec dlv attach $pid --headless --continue --accept-multiclient --api-version=2 --listen=:%d", binary, port)
+
+	sidecar := v1.Container{
+		Name:    container.Name + "-dlv",
+		Image:   constants.DefaultDelveImage,
+		Command: []string{"sh", "-c", attach},
+		Ports: []v1.ContainerPort{{
+			Name:          "dlv",
+			ContainerPort: port,
+		}},
+		SecurityContext: &v1.SecurityContext{
+			Capabilities: &v1.Capabilities{
+				Add: []v1.Capability{"SYS_PTRACE"},
+			},
+		},
+	}
+
+	return &ContainerDebugConfiguration{
+		Runtime: "go",
+		Ports:   map[string]int32{"dlv": port},
+	}, []v1.Container{sidecar}
+}
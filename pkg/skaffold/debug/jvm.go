@@ -0,0 +1,67 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/api/core/v1"
+)
+
+const (
+	defaultJdwpPort = 5005
+	javaToolOptions = "JAVA_TOOL_OPTIONS"
+)
+
+// jdwpTransformer configures JVM containers for debugging with JDWP.
+type jdwpTransformer struct{}
+
+func (jdwpTransformer) IsApplicable(config imageConfiguration) bool {
+	if _, found := config.env[javaToolOptions]; found {
+		return true
+	}
+	if _, found := config.env["JAVA_VERSION"]; found {
+		return true
+	}
+
+	commandLine := config.commandLine()
+	return len(commandLine) > 0 && filepath.Base(commandLine[0]) == "java"
+}
+
+// Apply loads the JDWP agent through the JAVA_TOOL_OPTIONS environment variable.
+func (jdwpTransformer) Apply(container *v1.Container, config imageConfiguration, portAlloc portAllocator) (*ContainerDebugConfiguration, []v1.Container) {
+	port := portAlloc(defaultJdwpPort)
+	agent := fmt.Sprintf("-agentlib:jdwp=transport=dt_socket,server=y,suspend=n,address=%d", port)
+
+	options := agent
+	if existing := config.env[javaToolOptions]; existing != "" {
+		if strings.Contains(existing, "-agentlib:jdwp") {
+			return nil, nil
+		}
+		options = existing + " " + agent
+	}
+
+	setEnvVar(container, javaToolOptions, options)
+	exposePort(container, "jdwp", port)
+
+	return &ContainerDebugConfiguration{
+		Runtime: "jvm",
+		Ports:   map[string]int32{"jdwp": port},
+	}, nil
+}
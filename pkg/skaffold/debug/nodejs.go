@@ -0,0 +1,76 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"k8s.io/api/core/v1"
+)
+
+const defaultDevtoolsPort = 9229
+
+// nodeTransformer configures Node.js containers for debugging with the inspector.
+type nodeTransformer struct{}
+
+func (nodeTransformer) IsApplicable(config imageConfiguration) bool {
+	for _, env := range []string{"NODE_VERSION", "NODEJS_VERSION", "NODE_ENV"} {
+		if _, found := config.env[env]; found {
+			return true
+		}
+	}
+
+	commandLine := config.commandLine()
+	if len(commandLine) == 0 {
+		return false
+	}
+
+	switch filepath.Base(commandLine[0]) {
+	case "node", "nodemon", "npm":
+		return true
+	default:
+		return false
+	}
+}
+
+// Apply enables the inspector. When node is launched directly, the --inspect flag
+// is added to its command line. Otherwise, it goes through NODE_OPTIONS.
+func (nodeTransformer) Apply(container *v1.Container, config imageConfiguration, portAlloc portAllocator) (*ContainerDebugConfiguration, []v1.Container) {
+	port := portAlloc(defaultDevtoolsPort)
+	inspect := fmt.Sprintf("--inspect=0.0.0.0:%d", port)
+
+	commandLine := config.commandLine()
+	if len(commandLine) > 0 && filepath.Base(commandLine[0]) == "node" {
+		// Set the whole command line explicitly since the image's entrypoint can't be amended.
+		container.Command = append([]string{commandLine[0], inspect}, commandLine[1:]...)
+		container.Args = nil
+	} else {
+		options := inspect
+		if existing := config.env["NODE_OPTIONS"]; existing != "" {
+			options = existing + " " + inspect
+		}
+		setEnvVar(container, "NODE_OPTIONS", options)
+	}
+
+	exposePort(container, "devtools", port)
+
+	return &ContainerDebugConfiguration{
+		Runtime: "nodejs",
+		Ports:   map[string]int32{"devtools": port},
+	}, nil
+}
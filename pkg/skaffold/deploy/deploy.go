@@ -48,6 +48,13 @@ type Deployer interface {
 	Cleanup(context.Context, io.Writer) error
 }
 
+// Renderer is implemented by the deployers that can show what they would deploy
+// without touching the cluster. Render writes the manifests, with the images
+// replaced by the given builds, and returns the objects they describe when known.
+type Renderer interface {
+	Render(context.Context, io.Writer, []build.Artifact) ([]Artifact, error)
+}
+
 type multiDeployer struct {
 	deployers []Deployer
 }
@@ -82,6 +89,24 @@ func (m *multiDeployer) Deploy(ctx context.Context, out io.Writer, builds []buil
 	return results, nil
 }
 
+func (m *multiDeployer) Render(ctx context.Context, out io.Writer, builds []build.Artifact) ([]Artifact, error) {
+	results := []Artifact{}
+	for _, deployer := range m.deployers {
+		renderer, ok := deployer.(Renderer)
+		if !ok {
+			fmt.Fprintf(out, "# %T can't be rendered\n", deployer)
+			continue
+		}
+		a, err := renderer.Render(ctx, out, builds)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, a...)
+	}
+
+	return results, nil
+}
+
 func (m *multiDeployer) Dependencies() ([]string, error) {
 	allDeps := []string{}
 
@@ -22,6 +22,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -34,6 +35,7 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/pkg/errors"
@@ -45,6 +47,7 @@ type HelmDeployer struct {
 	*latest.HelmDeploy
 
 	kubeContext string
+	kubeConfig  string
 	namespace   string
 	defaultRepo string
 }
@@ -55,6 +58,7 @@ func NewHelmDeployer(cfg *latest.HelmDeploy, kubeContext string, namespace strin
 	return &HelmDeployer{
 		HelmDeploy:  cfg,
 		kubeContext: kubeContext,
+		kubeConfig:  kubectx.KubeConfigFile(),
 		namespace:   namespace,
 		defaultRepo: defaultRepo,
 	}
@@ -83,16 +87,37 @@ func (h *HelmDeployer) Dependencies() ([]string, error) {
 	var deps []string
 	for _, release := range h.Releases {
 		deps = append(deps, release.ValuesFiles...)
-		chartDepsDir := filepath.Join(release.ChartPath, "charts")
-		filepath.Walk(release.ChartPath, func(path string, info os.FileInfo, err error) error {
-			if !info.IsDir() && !strings.HasPrefix(path, chartDepsDir) {
-				deps = append(deps, path)
+		if !isOCIChart(release.ChartPath) {
+			chartDepsDir := filepath.Join(release.ChartPath, "charts")
+			filepath.Walk(release.ChartPath, func(path string, info os.FileInfo, err error) error {
+				if !info.IsDir() && !strings.HasPrefix(path, chartDepsDir) {
+					deps = append(deps, path)
+				}
+				return nil
+			})
+		}
+
+		if postRenderer := release.PostRenderer; postRenderer != nil {
+			files, err := util.ExpandPathsGlob("", postRenderer.Dependencies)
+			if err != nil {
+				return nil, errors.Wrap(err, "listing post-renderer dependencies")
 			}
-			return nil
-		})
+			deps = append(deps, files...)
+			if len(postRenderer.Command) > 0 && filepath.IsAbs(postRenderer.Command[0]) {
+				deps = append(deps, postRenderer.Command[0])
+			}
+		}
 	}
 	sort.Strings(deps)
-	return deps, nil
+
+	// The post-renderer's files can be part of the chart.
+	var unique []string
+	for i, dep := range deps {
+		if i == 0 || dep != deps[i-1] {
+			unique = append(unique, dep)
+		}
+	}
+	return unique, nil
 }
 
 // Cleanup deletes what was deployed by calling Deploy.
@@ -100,20 +125,31 @@ func (h *HelmDeployer) Cleanup(ctx context.Context, out io.Writer) error {
 	for _, r := range h.Releases {
 		if err := h.deleteRelease(ctx, out, r); err != nil {
 			releaseName, _ := evaluateReleaseName(r.Name)
-			return errors.Wrapf(err, "deploying %s", releaseName)
+			return errors.Wrapf(err, "deleting %s", releaseName)
 		}
 	}
 	return nil
 }
 
 func (h *HelmDeployer) helm(ctx context.Context, out io.Writer, arg ...string) error {
-	args := append([]string{"--kube-context", h.kubeContext}, arg...)
+	return util.RunCmd(h.helmCmd(ctx, out, arg...))
+}
+
+func (h *HelmDeployer) helmCmd(ctx context.Context, out io.Writer, arg ...string) *exec.Cmd {
+	var args []string
+	if h.kubeContext != "" {
+		args = append(args, "--kube-context", h.kubeContext)
+	}
+	if h.kubeConfig != "" {
+		args = append(args, "--kubeconfig", h.kubeConfig)
+	}
+	args = append(args, arg...)
 
 	cmd := exec.CommandContext(ctx, "helm", args...)
 	cmd.Stdout = out
 	cmd.Stderr = out
 
-	return util.RunCmd(cmd)
+	return cmd
 }
 
 func (h *HelmDeployer) deployRelease(ctx context.Context, out io.Writer, r latest.HelmRelease, builds []build.Artifact) ([]Artifact, error) {
@@ -147,10 +183,16 @@ func (h *HelmDeployer) deployRelease(ctx context.Context, out io.Writer, r lates
 		}
 	}
 
-	// First build dependencies.
-	logrus.Infof("Building helm dependencies...")
-	if err := h.helm(ctx, out, "dep", "build", r.ChartPath); err != nil {
-		return nil, errors.Wrap(err, "building helm dependencies")
+	// First build dependencies. Charts from an OCI registry come with theirs.
+	if isOCIChart(r.ChartPath) {
+		if err := h.loginChartRegistry(ctx, out, r.ChartPath); err != nil {
+			return nil, errors.Wrap(err, "logging into the chart registry")
+		}
+	} else {
+		logrus.Infof("Building helm dependencies...")
+		if err := h.helm(ctx, out, "dep", "build", r.ChartPath); err != nil {
+			return nil, errors.Wrap(err, "building helm dependencies")
+		}
 	}
 
 	var args []string
@@ -164,9 +206,9 @@ func (h *HelmDeployer) deployRelease(ctx context.Context, out io.Writer, r lates
 	}
 
 	// There are 2 strategies:
-	// 1) Deploy chart directly from filesystem path or from repository
-	//    (like stable/kubernetes-dashboard). Version only applies to a
-	//    chart from repository.
+	// 1) Deploy chart directly from filesystem path, from repository
+	//    (like stable/kubernetes-dashboard) or from an OCI registry. Version
+	//    only applies to a chart from repository or registry.
 	// 2) Package chart into a .tgz archive with specific version and then deploy
 	//    that packaged chart. This way user can apply any version and appVersion
 	//    for the chart.
@@ -183,12 +225,7 @@ func (h *HelmDeployer) deployRelease(ctx context.Context, out io.Writer, r lates
 		args = append(args, chartPath)
 	}
 
-	var ns string
-	if h.namespace != "" {
-		ns = h.namespace
-	} else if r.Namespace != "" {
-		ns = r.Namespace
-	}
+	ns := h.releaseNamespace(r)
 	if ns != "" {
 		args = append(args, "--namespace", ns)
 	}
@@ -250,12 +287,72 @@ func (h *HelmDeployer) deployRelease(ctx context.Context, out io.Writer, r lates
 	if r.Wait {
 		args = append(args, "--wait")
 	}
+	if util.ToolVerbosity() == util.VerboseOutput {
+		args = append(args, "--debug")
+	}
+	if r.PostRenderer != nil && len(r.PostRenderer.Command) > 0 {
+		args = append(args, "--post-renderer", r.PostRenderer.Command[0])
+		for _, arg := range r.PostRenderer.Command[1:] {
+			args = append(args, "--post-renderer-args", arg)
+		}
+	}
 	args = append(args, setOpts...)
 
 	helmErr := h.helm(ctx, out, args...)
 	return h.getDeployResults(ctx, ns, releaseName), helmErr
 }
 
+// Render describes the releases that would be installed or upgraded, with the
+// image values set from the given builds. The charts themselves are not rendered.
+// Charts from an OCI registry are pinned to the digest of their version.
+func (h *HelmDeployer) Render(ctx context.Context, out io.Writer, builds []build.Artifact) ([]Artifact, error) {
+	for _, r := range h.Releases {
+		releaseName, err := evaluateReleaseName(r.Name)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot parse the release name template")
+		}
+		params, err := h.joinTagsToBuildResult(builds, r.Values)
+		if err != nil {
+			return nil, errors.Wrap(err, "matching build results to chart values")
+		}
+
+		chart := r.ChartPath
+		if isOCIChart(chart) {
+			if err := h.loginChartRegistry(ctx, ioutil.Discard, chart); err != nil {
+				return nil, errors.Wrap(err, "logging into the chart registry")
+			}
+			if chart, err = h.pinChart(ctx, r); err != nil {
+				return nil, errors.Wrap(err, "resolving the chart digest")
+			}
+		}
+
+		fmt.Fprintf(out, "# helm release %s of chart %s", releaseName, chart)
+		if ns := h.releaseNamespace(r); ns != "" {
+			fmt.Fprintf(out, " in namespace %s", ns)
+		}
+		fmt.Fprintln(out)
+
+		var keys []string
+		for k := range params {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(out, "#   --set %s=%s\n", k, params[k].Tag)
+		}
+	}
+
+	return nil, nil
+}
+
+// releaseNamespace returns the namespace a release is deployed to, if any.
+func (h *HelmDeployer) releaseNamespace(r latest.HelmRelease) string {
+	if h.namespace != "" {
+		return h.namespace
+	}
+	return r.Namespace
+}
+
 // imageName if the given string includes a fully qualified docker image name then lets trim just the tag part out
 func extractTag(imageName string) string {
 	idx := strings.LastIndex(imageName, "/")
@@ -0,0 +1,111 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+)
+
+const ociChartPrefix = "oci://"
+
+// registryLogin is the same as the keychain used to push images.
+var registryLogin = docker.DefaultKeychain.Login // for testing
+
+// isOCIChart tells if a chart is pulled from an OCI registry rather than
+// read from the filesystem or a chart repository.
+func isOCIChart(chartPath string) bool {
+	return strings.HasPrefix(chartPath, ociChartPrefix)
+}
+
+// chartRegistry returns the registry hosting an OCI chart.
+func chartRegistry(chartPath string) string {
+	return strings.SplitN(strings.TrimPrefix(chartPath, ociChartPrefix), "/", 2)[0]
+}
+
+// loginChartRegistry logs helm into the registry of an OCI chart, with the
+// credentials used to push images. Registries without credentials are
+// accessed anonymously.
+func (h *HelmDeployer) loginChartRegistry(ctx context.Context, out io.Writer, chartPath string) error {
+	registry := chartRegistry(chartPath)
+	username, password, err := registryLogin(registry)
+	if err != nil {
+		return err
+	}
+	if password == "" {
+		return nil
+	}
+
+	cmd := h.helmCmd(ctx, out, "registry", "login", registry, "--username", username, "--password-stdin")
+	cmd.Stdin = strings.NewReader(password)
+	if err := util.RunCmd(cmd); err != nil {
+		return errors.Wrapf(err, "logging into %s", registry)
+	}
+	return nil
+}
+
+// pinChart pulls an OCI chart and returns its reference pinned to the digest
+// of the pulled version, like oci://registry/chart:1.2.3@sha256:...
+func (h *HelmDeployer) pinChart(ctx context.Context, r latest.HelmRelease) (string, error) {
+	tmp, err := ioutil.TempDir("", "skaffold-chart")
+	if err != nil {
+		return "", errors.Wrap(err, "creating temp dir")
+	}
+	defer os.RemoveAll(tmp)
+
+	args := []string{"pull", r.ChartPath, "--destination", tmp}
+	if r.Version != "" {
+		args = append(args, "--version", r.Version)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := h.helm(ctx, buf, args...); err != nil {
+		return "", errors.Wrapf(err, "pulling chart %s (%s)", r.ChartPath, strings.TrimSpace(buf.String()))
+	}
+
+	return parsePulledChart(buf.String())
+}
+
+// parsePulledChart reads the reference and the digest of a chart in
+// the output of `helm pull`.
+func parsePulledChart(output string) (string, error) {
+	var ref, digest string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "Pulled:"):
+			ref = strings.TrimSpace(strings.TrimPrefix(line, "Pulled:"))
+		case strings.HasPrefix(line, "Digest:"):
+			digest = strings.TrimSpace(strings.TrimPrefix(line, "Digest:"))
+		}
+	}
+	if ref == "" || digest == "" {
+		return "", fmt.Errorf("unable to find the chart digest in: %s", output)
+	}
+
+	return ociChartPrefix + strings.TrimPrefix(ref, ociChartPrefix) + "@" + digest, nil
+}
@@ -67,6 +67,19 @@ var testDeployConfig = &latest.HelmDeploy{
 	},
 }
 
+var testDeployOCIChartConfig = &latest.HelmDeploy{
+	Releases: []latest.HelmRelease{
+		{
+			Name:      "skaffold-helm",
+			ChartPath: "oci://europe-docker.pkg.dev/project/charts/app",
+			Version:   "1.2.3",
+			Values: map[string]string{
+				"image": "skaffold-helm",
+			},
+		},
+	},
+}
+
 var testDeployRecreatePodsConfig = &latest.HelmDeploy{
 	Releases: []latest.HelmRelease{
 		{
@@ -320,6 +333,39 @@ func TestHelmDeploy(t *testing.T) {
 			deployer: NewHelmDeployer(testDeployConfig, testKubeContext, testNamespace, ""),
 			builds:   testBuilds,
 		},
+		{
+			description: "deploy with post-renderer",
+			cmd: &MockHelm{
+				t: t,
+				upgradeMatcher: func(cmd *exec.Cmd) bool {
+					return strings.Contains(strings.Join(cmd.Args, " "), "--post-renderer /hack/render.sh --post-renderer-args overlays/dev")
+				},
+			},
+			deployer: NewHelmDeployer(&latest.HelmDeploy{
+				Releases: []latest.HelmRelease{{
+					Name:         "skaffold-helm",
+					ChartPath:    "examples/test",
+					Values:       map[string]string{"image": "skaffold-helm"},
+					PostRenderer: &latest.HelmPostRenderer{Command: []string{"/hack/render.sh", "overlays/dev"}},
+				}},
+			}, testKubeContext, testNamespace, ""),
+			builds: testBuilds,
+		},
+		{
+			description: "deploy chart from an OCI registry",
+			cmd: &MockHelm{
+				t:         t,
+				depResult: fmt.Errorf("should not have built dependencies"),
+				loginMatcher: func(cmd *exec.Cmd) bool {
+					return strings.Join(cmd.Args[3:], " ") == "registry login europe-docker.pkg.dev --username oauth2accesstoken --password-stdin"
+				},
+				upgradeMatcher: func(cmd *exec.Cmd) bool {
+					return strings.Contains(strings.Join(cmd.Args, " "), "--version 1.2.3 oci://europe-docker.pkg.dev/project/charts/app")
+				},
+			},
+			deployer: NewHelmDeployer(testDeployOCIChartConfig, testKubeContext, testNamespace, ""),
+			builds:   testBuilds,
+		},
 		{
 			description: "deploy error",
 			cmd: &MockHelm{
@@ -382,6 +428,8 @@ func TestHelmDeploy(t *testing.T) {
 		t.Run(tt.description, func(t *testing.T) {
 			defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
 			util.DefaultExecCommand = tt.cmd
+			defer func(l func(string) (string, string, error)) { registryLogin = l }(registryLogin)
+			registryLogin = fakeRegistryLogin
 
 			_, err := tt.deployer.Deploy(context.Background(), ioutil.Discard, tt.builds)
 
@@ -402,11 +450,18 @@ type MockHelm struct {
 	upgradeResult  error
 	upgradeMatcher CommandMatcher
 	depResult      error
+	loginMatcher   CommandMatcher
+	pullOut        io.Reader
+	pullResult     error
 
 	packageOut    io.Reader
 	packageResult error
 }
 
+func fakeRegistryLogin(registry string) (string, string, error) {
+	return "oauth2accesstoken", "token", nil
+}
+
 func (m *MockHelm) RunCmdOut(c *exec.Cmd) ([]byte, error) {
 	m.t.Error("Shouldn't be used")
 	return nil, nil
@@ -445,6 +500,18 @@ func (m *MockHelm) RunCmd(c *exec.Cmd) error {
 		return m.upgradeResult
 	case "dep":
 		return m.depResult
+	case "registry":
+		if m.loginMatcher != nil && !m.loginMatcher(c) {
+			m.t.Errorf("login matcher failed to match cmd")
+		}
+		return nil
+	case "pull":
+		if m.pullOut != nil {
+			if _, err := io.Copy(c.Stdout, m.pullOut); err != nil {
+				m.t.Errorf("Failed to copy stdout")
+			}
+		}
+		return m.pullResult
 	case "package":
 		if m.packageOut != nil {
 			if _, err := io.Copy(c.Stdout, m.packageOut); err != nil {
@@ -458,6 +525,51 @@ func (m *MockHelm) RunCmd(c *exec.Cmd) error {
 	}
 }
 
+func TestHelmRenderOCIChart(t *testing.T) {
+	defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+	util.DefaultExecCommand = &MockHelm{
+		t:       t,
+		pullOut: bytes.NewBufferString("Pulled: europe-docker.pkg.dev/project/charts/app:1.2.3\nDigest: sha256:1234\n"),
+	}
+	defer func(l func(string) (string, string, error)) { registryLogin = l }(registryLogin)
+	registryLogin = fakeRegistryLogin
+
+	var out bytes.Buffer
+	deployer := NewHelmDeployer(testDeployOCIChartConfig, testKubeContext, testNamespace, "")
+	_, err := deployer.Render(context.Background(), &out, testBuilds)
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, `# helm release skaffold-helm of chart oci://europe-docker.pkg.dev/project/charts/app:1.2.3@sha256:1234 in namespace testNamespace
+#   --set image=docker.io:5000/skaffold-helm:3605e7bc17cf46e53f4d81c4cbc24e5b4c495184
+`, out.String())
+}
+
+func TestParsePulledChart(t *testing.T) {
+	var tests = []struct {
+		description string
+		output      string
+		shouldErr   bool
+		expected    string
+	}{
+		{
+			description: "pulled chart",
+			output:      "Pulled: ghcr.io/org/app:0.1.0\nDigest: sha256:abcd\n",
+			expected:    "oci://ghcr.io/org/app:0.1.0@sha256:abcd",
+		},
+		{
+			description: "missing digest",
+			output:      "Pulled: ghcr.io/org/app:0.1.0\n",
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			chart, err := parsePulledChart(test.output)
+
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, chart)
+		})
+	}
+}
+
 func TestParseHelmRelease(t *testing.T) {
 	var tests = []struct {
 		name      string
@@ -498,10 +610,11 @@ func TestExtractChartFilename(t *testing.T) {
 
 func TestHelmDependencies(t *testing.T) {
 	var tests = []struct {
-		description string
-		files       []string
-		valuesFiles []string
-		expected    func(folder *testutil.TempDir) []string
+		description  string
+		files        []string
+		valuesFiles  []string
+		postRenderer func(folder *testutil.TempDir) *latest.HelmPostRenderer
+		expected     func(folder *testutil.TempDir) []string
 	}{
 		{
 			description: "charts dir is excluded",
@@ -518,6 +631,19 @@ func TestHelmDependencies(t *testing.T) {
 				return []string{"/folder/values.yaml", folder.Path("Chart.yaml")}
 			},
 		},
+		{
+			description: "post-renderer files are included",
+			files:       []string{"Chart.yaml", "render.sh", "overlays/kustomization.yaml"},
+			postRenderer: func(folder *testutil.TempDir) *latest.HelmPostRenderer {
+				return &latest.HelmPostRenderer{
+					Command:      []string{folder.Path("render.sh")},
+					Dependencies: []string{folder.Path("overlays/*")},
+				}
+			},
+			expected: func(folder *testutil.TempDir) []string {
+				return []string{folder.Path("Chart.yaml"), folder.Path("overlays/kustomization.yaml"), folder.Path("render.sh")}
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -527,16 +653,21 @@ func TestHelmDependencies(t *testing.T) {
 			for _, file := range tt.files {
 				folder.Write(file, "")
 			}
+			var postRenderer *latest.HelmPostRenderer
+			if tt.postRenderer != nil {
+				postRenderer = tt.postRenderer(folder)
+			}
 
 			deployer := NewHelmDeployer(&latest.HelmDeploy{
 				Releases: []latest.HelmRelease{
 					{
-						Name:        "skaffold-helm",
-						ChartPath:   folder.Root(),
-						ValuesFiles: tt.valuesFiles,
-						Values:      map[string]string{"image": "skaffold-helm"},
-						Overrides:   map[string]interface{}{"foo": "bar"},
-						SetValues:   map[string]string{"some.key": "somevalue"},
+						Name:         "skaffold-helm",
+						ChartPath:    folder.Root(),
+						ValuesFiles:  tt.valuesFiles,
+						Values:       map[string]string{"image": "skaffold-helm"},
+						Overrides:    map[string]interface{}{"foo": "bar"},
+						SetValues:    map[string]string{"some.key": "somevalue"},
+						PostRenderer: postRenderer,
 					},
 				},
 			}, testKubeContext, testNamespace, "")
@@ -0,0 +1,175 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// for testing
+var (
+	jobPollInterval = time.Second
+	logsGracePeriod = 2 * time.Second
+)
+
+// RunJobs waits for the given Jobs, found in the deploy results, to run to completion
+// while streaming the logs of their pods. It returns an error as soon as one of them fails.
+// Jobs that are not part of the results weren't applied again because their manifests
+// didn't change, so there's nothing to wait for.
+func RunJobs(ctx context.Context, out io.Writer, dRes []Artifact, names []string, timeout time.Duration) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	client, err := kubernetes.Client()
+	if err != nil {
+		return errors.Wrap(err, "getting kubernetes client")
+	}
+
+	for _, name := range names {
+		namespace, found, err := jobNamespace(dRes, name)
+		if err != nil {
+			return err
+		}
+		if !found {
+			logrus.Infof("Job %s wasn't applied again, not waiting for it", name)
+			continue
+		}
+
+		color.Default.Fprintf(out, "Waiting for job %s to complete...\n", name)
+		stopLogs := streamJobLogs(out, client.CoreV1().Pods(namespace), name)
+		err = kubernetes.WaitForJobComplete(ctx, client, namespace, name, timeout)
+		stopLogs()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// jobNamespace finds the namespace a Job was deployed to, if it's part of the deploy results.
+func jobNamespace(dRes []Artifact, name string) (string, bool, error) {
+	for _, a := range dRes {
+		if a.Obj == nil || (*a.Obj).GetObjectKind().GroupVersionKind().Kind != "Job" {
+			continue
+		}
+
+		accessor, err := meta.Accessor(*a.Obj)
+		if err != nil {
+			return "", false, errors.Wrap(err, "reading job metadata")
+		}
+		if accessor.GetName() == name {
+			namespace, err := namespaceOf(a, accessor.GetNamespace())
+			return namespace, true, err
+		}
+	}
+
+	return "", false, nil
+}
+
+// streamJobLogs prints the logs of the pods of a Job as they start.
+// The returned function stops looking for new pods and waits a little
+// for the logs of the current ones to be printed.
+func streamJobLogs(out io.Writer, pods corev1.PodInterface, name string) func() {
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		streamed := map[string]bool{}
+		for {
+			list, err := pods.List(metav1.ListOptions{LabelSelector: "job-name=" + name})
+			if err != nil {
+				logrus.Debugf("Listing the pods of job %s: %s", name, err)
+			} else {
+				for _, pod := range list.Items {
+					if streamed[pod.Name] || pod.Status.Phase == v1.PodPending {
+						continue
+					}
+					streamed[pod.Name] = true
+
+					wg.Add(1)
+					go func(pod string) {
+						defer wg.Done()
+						printPodLogs(out, &lock, pods, pod)
+					}(pod.Name)
+				}
+			}
+
+			select {
+			case <-stop:
+				return
+			case <-time.After(jobPollInterval):
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(logsGracePeriod):
+		}
+	}
+}
+
+func printPodLogs(out io.Writer, lock *sync.Mutex, pods corev1.PodInterface, name string) {
+	r, err := pods.GetLogs(name, &v1.PodLogOptions{Follow: true}).Stream()
+	if err != nil {
+		logrus.Debugf("Unable to get the logs of %s: %s", name, err)
+		return
+	}
+	defer r.Close()
+
+	// The lock is held for one line at a time so that the logs
+	// of the pods of a Job are interleaved but never mixed up.
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			lock.Lock()
+			out.Write(line)
+			lock.Unlock()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgo "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func job(name string, condition batchv1.JobConditionType) *batchv1.Job {
+	return &batchv1.Job{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "batch/v1", Kind: "Job"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+		Status: batchv1.JobStatus{
+			Conditions: []batchv1.JobCondition{{Type: condition, Status: v1.ConditionTrue, Message: "BackoffLimitExceeded"}},
+		},
+	}
+}
+
+func deployedJob(j *batchv1.Job) Artifact {
+	var obj runtime.Object = j
+	return Artifact{Obj: &obj}
+}
+
+func TestRunJobs(t *testing.T) {
+	migrate := job("migrate", batchv1.JobComplete)
+	seed := job("seed", batchv1.JobFailed)
+
+	var tests = []struct {
+		description string
+		jobs        []string
+		shouldErr   bool
+	}{
+		{
+			description: "no jobs",
+		},
+		{
+			description: "completed",
+			jobs:        []string{"migrate"},
+		},
+		{
+			description: "failed",
+			jobs:        []string{"migrate", "seed"},
+			shouldErr:   true,
+		},
+		{
+			description: "not applied again",
+			jobs:        []string{"unknown"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			defer func(c func() (clientgo.Interface, error)) { kubernetes.Client = c }(kubernetes.Client)
+			kubernetes.Client = func() (clientgo.Interface, error) { return fake.NewSimpleClientset(migrate, seed), nil }
+			defer func(d time.Duration) { jobPollInterval = d }(jobPollInterval)
+			jobPollInterval = time.Millisecond
+
+			err := RunJobs(context.Background(), ioutil.Discard, []Artifact{deployedJob(migrate), deployedJob(seed)}, test.jobs, time.Minute)
+
+			testutil.CheckError(t, test.shouldErr, err)
+		})
+	}
+}
@@ -20,18 +20,22 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"fmt"
 	"io"
-	"io/ioutil"
 	"strings"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/kubectl"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
+	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
 )
 
 // KubectlDeployer deploys workflows using kubectl CLI.
@@ -41,6 +45,7 @@ type KubectlDeployer struct {
 	workingDir  string
 	kubectl     kubectl.CLI
 	defaultRepo string
+	cache       *manifestCache
 }
 
 // NewKubectlDeployer returns a new KubectlDeployer for a DeployConfig filled
@@ -52,9 +57,17 @@ func NewKubectlDeployer(workingDir string, cfg *latest.KubectlDeploy, kubeContex
 		kubectl: kubectl.CLI{
 			Namespace:   namespace,
 			KubeContext: kubeContext,
+			KubeConfig:  kubectx.KubeConfigFile(),
 			Flags:       cfg.Flags,
+
+			Force:          cfg.Force,
+			GracePeriod:    cfg.GracePeriod,
+			Cascade:        cfg.Cascade,
+			PruneWhitelist: cfg.PruneWhitelist,
+			Strategy:       cfg.Strategy,
 		},
 		defaultRepo: defaultRepo,
+		cache:       newManifestCache(),
 	}
 }
 
@@ -68,9 +81,6 @@ func (k *KubectlDeployer) Labels() map[string]string {
 // runs `kubectl apply` on those manifests
 func (k *KubectlDeployer) Deploy(ctx context.Context, out io.Writer, builds []build.Artifact) ([]Artifact, error) {
 	color.Default.Fprintln(out, "kubectl client version:", k.kubectl.Version())
-	if err := k.kubectl.CheckVersion(); err != nil {
-		color.Default.Fprintln(out, err)
-	}
 
 	manifests, err := k.readManifests(ctx)
 	if err != nil {
@@ -81,9 +91,9 @@ func (k *KubectlDeployer) Deploy(ctx context.Context, out io.Writer, builds []bu
 		return nil, nil
 	}
 
-	manifests, err = manifests.ReplaceImages(builds, k.defaultRepo)
+	manifests, err = k.cache.render(manifests, builds, k.defaultRepo)
 	if err != nil {
-		return nil, errors.Wrap(err, "replacing images in manifests")
+		return nil, k.blameInvalidManifest(err)
 	}
 
 	updated, err := k.kubectl.Apply(ctx, out, manifests)
@@ -108,6 +118,33 @@ func (k *KubectlDeployer) Cleanup(ctx context.Context, out io.Writer) error {
 	return nil
 }
 
+// blameInvalidManifest attributes an error to the first local manifest
+// that isn't valid yaml, so that it can be reported along with the error.
+func (k *KubectlDeployer) blameInvalidManifest(err error) error {
+	files, listErr := k.manifestFiles(k.Manifests)
+	if listErr != nil {
+		return err
+	}
+
+	for _, file := range files {
+		buf, readErr := k.cache.readFile(file)
+		if readErr != nil {
+			continue
+		}
+
+		var manifests kubectl.ManifestList
+		manifests.Append(buf)
+		for _, manifest := range manifests {
+			m := make(map[interface{}]interface{})
+			if yaml.Unmarshal(manifest, &m) != nil {
+				return event.WithFile(err, file)
+			}
+		}
+	}
+
+	return err
+}
+
 func (k *KubectlDeployer) Dependencies() ([]string, error) {
 	return k.manifestFiles(k.KubectlDeploy.Manifests)
 }
@@ -122,6 +159,9 @@ func (k *KubectlDeployer) manifestFiles(manifests []string) ([]string, error) {
 	for _, f := range list {
 		if !util.IsSupportedKubernetesFormat(f) {
 			if !util.StrSliceContains(manifests, f) {
+				if warnings.IsStrict() {
+					return nil, errors.Errorf("refusing to deploy/delete non {json, yaml} file %s", f)
+				}
 				logrus.Infof("refusing to deploy/delete non {json, yaml} file %s", f)
 				logrus.Info("If you still wish to deploy this file, please specify it directly, outside a glob pattern.")
 				continue
@@ -144,8 +184,45 @@ func parseManifestsForDeploys(namespace string, manifests kubectl.ManifestList)
 	return results, nil
 }
 
+// Render writes the manifests that would be applied. Remote manifests are
+// skipped since they can only be read from the cluster.
+func (k *KubectlDeployer) Render(ctx context.Context, out io.Writer, builds []build.Artifact) ([]Artifact, error) {
+	manifests, err := k.readLocalManifests()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading manifests")
+	}
+
+	if len(k.RemoteManifests) > 0 {
+		fmt.Fprintf(out, "# Remote manifests are not rendered: %s\n", strings.Join(k.RemoteManifests, ", "))
+	}
+
+	return renderManifests(out, k.kubectl.Namespace, manifests, builds, k.defaultRepo)
+}
+
 // readManifests reads the manifests to deploy/delete.
 func (k *KubectlDeployer) readManifests(ctx context.Context) (kubectl.ManifestList, error) {
+	manifests, err := k.readLocalManifests()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range k.RemoteManifests {
+		manifest, err := k.cache.remoteManifest(m, func() ([]byte, error) { return k.readRemoteManifest(ctx, m) })
+		if err != nil {
+			return nil, errors.Wrap(err, "get remote manifests")
+		}
+
+		manifests = append(manifests, manifest)
+	}
+
+	if logrus.GetLevel() >= logrus.DebugLevel {
+		logrus.Debugln("manifests", manifests.String())
+	}
+
+	return manifests, nil
+}
+
+func (k *KubectlDeployer) readLocalManifests() (kubectl.ManifestList, error) {
 	files, err := k.manifestFiles(k.Manifests)
 	if err != nil {
 		return nil, errors.Wrap(err, "expanding user manifest list")
@@ -153,7 +230,7 @@ func (k *KubectlDeployer) readManifests(ctx context.Context) (kubectl.ManifestLi
 
 	var manifests kubectl.ManifestList
 	for _, manifest := range files {
-		buf, err := ioutil.ReadFile(manifest)
+		buf, err := k.cache.readFile(manifest)
 		if err != nil {
 			return nil, errors.Wrap(err, "reading manifest")
 		}
@@ -161,17 +238,6 @@ func (k *KubectlDeployer) readManifests(ctx context.Context) (kubectl.ManifestLi
 		manifests.Append(buf)
 	}
 
-	for _, m := range k.RemoteManifests {
-		manifest, err := k.readRemoteManifest(ctx, m)
-		if err != nil {
-			return nil, errors.Wrap(err, "get remote manifests")
-		}
-
-		manifests = append(manifests, manifest)
-	}
-
-	logrus.Debugln("manifests", manifests.String())
-
 	return manifests, nil
 }
 
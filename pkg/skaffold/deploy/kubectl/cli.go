@@ -18,31 +18,98 @@ package kubectl
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"os/exec"
+	"strings"
 	"sync"
 
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
 )
 
 // CLI holds parameters to run kubectl.
 type CLI struct {
 	Namespace   string
 	KubeContext string
+	KubeConfig  string
 	Flags       latest.KubectlFlags
 
+	// Force, GracePeriod, Cascade, PruneWhitelist and Strategy mirror the options of the kubectl deployer.
+	Force          *bool
+	GracePeriod    *int
+	Cascade        *bool
+	PruneWhitelist []string
+	Strategy       *latest.RolloutStrategy
+
 	version       ClientVersion
 	versionOnce   sync.Once
 	previousApply ManifestList
+	liveColor     string
 }
 
-// Delete runs `kubectl delete` on a list of manifests.
+// Delete runs `kubectl delete` on a list of manifests. With a rollout strategy,
+// the canaries or blue/green versions of the Deployments are deleted too.
+// If the manifests were applied during this session, only the resources
+// labelled with its run ID are deleted.
 func (c *CLI) Delete(ctx context.Context, out io.Writer, manifests ManifestList) error {
-	if err := c.Run(ctx, manifests.Reader(), out, "delete", c.Flags.Delete, "--ignore-not-found=true", "-f", "-"); err != nil {
-		return errors.Wrap(err, "kubectl delete")
+	if c.Strategy != nil {
+		versions, err := c.rolloutVersions(manifests)
+		if err != nil {
+			return err
+		}
+		manifests = append(append(ManifestList(nil), manifests...), versions...)
+	}
+
+	if id, err := runID(c.previousApply); err == nil {
+		return c.deleteRun(ctx, out, manifests, id)
+	}
+	return c.delete(ctx, out, manifests)
+}
+
+func (c *CLI) delete(ctx context.Context, out io.Writer, manifests ManifestList) error {
+	args := append(c.terminationArgs(), "--ignore-not-found=true", "-f", "-")
+
+	for _, group := range manifests.byNamespace() {
+		if err := c.run(ctx, c.namespaceFor(group.namespace), group.manifests.Reader(), out, "delete", append(verbosityArgs(), c.Flags.Delete...), args...); err != nil {
+			return errors.Wrap(err, "kubectl delete")
+		}
+	}
+
+	return nil
+}
+
+// deleteRun deletes the resources of the given kinds that were deployed by
+// the skaffold run with the given ID, leaving the ones deployed by other runs alone.
+func (c *CLI) deleteRun(ctx context.Context, out io.Writer, manifests ManifestList, id string) error {
+	args := append(c.terminationArgs(), "--ignore-not-found=true", "-l", constants.Labels.RunID+"="+id)
+
+	for _, group := range manifests.byNamespace() {
+		workloads, err := decodeWorkloads(group.manifests)
+		if err != nil {
+			return err
+		}
+
+		var kinds []string
+		seen := map[string]bool{}
+		for _, w := range workloads {
+			if w.Kind != "" && !seen[w.Kind] {
+				seen[w.Kind] = true
+				kinds = append(kinds, w.Kind)
+			}
+		}
+		if len(kinds) == 0 {
+			continue
+		}
+
+		if err := c.run(ctx, c.namespaceFor(group.namespace), nil, out, "delete", append(verbosityArgs(), c.Flags.Delete...), append([]string{strings.Join(kinds, ",")}, args...)...); err != nil {
+			return errors.Wrap(err, "kubectl delete")
+		}
 	}
 
 	return nil
@@ -54,34 +121,158 @@ func (c *CLI) Apply(ctx context.Context, out io.Writer, manifests ManifestList)
 	// TODO(dgageot): should we delete a manifest that was deployed and is not anymore?
 	updated := c.previousApply.Diff(manifests)
 	logrus.Debugln(len(manifests), "manifests to deploy.", len(updated), "are updated or new")
+	firstApply := c.previousApply == nil
 	c.previousApply = manifests
 	if len(updated) == 0 {
 		return nil, nil
 	}
 
 	// Add --force flag to delete and redeploy image if changes can't be applied
-	if err := c.Run(ctx, updated.Reader(), out, "apply", c.Flags.Apply, "--force", "-f", "-"); err != nil {
-		return nil, errors.Wrap(err, "kubectl apply")
+	var args []string
+	if c.Force == nil || *c.Force {
+		args = append(args, "--force")
+	}
+	args = append(args, c.terminationArgs()...)
+
+	// Pruning deletes whatever is not applied, so all the manifests have to be applied.
+	toApply := updated
+	if len(c.PruneWhitelist) > 0 {
+		selector, err := pruneSelector(manifests)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, "--prune", "-l", selector)
+		for _, kind := range c.PruneWhitelist {
+			args = append(args, "--prune-whitelist="+kind)
+		}
+		toApply = manifests
+	}
+	args = append(args, "-f", "-")
+
+	if c.Strategy != nil {
+		return c.rollout(ctx, out, manifests, updated, firstApply, args)
+	}
+
+	if err := c.apply(ctx, out, toApply, args); err != nil {
+		return nil, err
 	}
 
 	return updated, nil
 }
 
+func (c *CLI) apply(ctx context.Context, out io.Writer, manifests ManifestList, args []string) error {
+	for _, group := range manifests.byNamespace() {
+		if err := c.run(ctx, c.namespaceFor(group.namespace), group.manifests.Reader(), out, "apply", append(verbosityArgs(), c.Flags.Apply...), args...); err != nil {
+			return errors.Wrap(err, "kubectl apply")
+		}
+	}
+
+	return nil
+}
+
+// verbosityArgs makes kubectl log the requests it sends at the debug level.
+func verbosityArgs() []string {
+	if util.ToolVerbosity() == util.VerboseOutput {
+		return []string{"-v=4"}
+	}
+	return nil
+}
+
+// terminationArgs returns the flags that control how resources are deleted.
+func (c *CLI) terminationArgs() []string {
+	var args []string
+	if c.GracePeriod != nil {
+		args = append(args, fmt.Sprintf("--grace-period=%d", *c.GracePeriod))
+	}
+	if c.Cascade != nil && !*c.Cascade {
+		args = append(args, "--cascade=false")
+	}
+	return args
+}
+
+// pruneSelector selects the resources deployed during this skaffold session.
+// kubectl only applies the manifests matched by the selector so they all must carry the run ID label.
+func pruneSelector(manifests ManifestList) (string, error) {
+	id, err := runID(manifests)
+	if err != nil {
+		return "", errors.Wrap(err, "pruning")
+	}
+
+	return constants.Labels.RunID + "=" + id, nil
+}
+
+// runID returns the run ID all the manifests are labelled with.
+func runID(manifests ManifestList) (string, error) {
+	var runID string
+
+	for _, manifest := range manifests {
+		var m struct {
+			Metadata struct {
+				Labels map[string]string `yaml:"labels"`
+			} `yaml:"metadata"`
+		}
+		if err := yaml.Unmarshal(manifest, &m); err != nil {
+			return "", errors.Wrap(err, "reading kubernetes YAML")
+		}
+
+		id := m.Metadata.Labels[constants.Labels.RunID]
+		if id == "" || (runID != "" && id != runID) {
+			return "", errors.New("all the manifests must be labelled with the same skaffold run ID")
+		}
+		runID = id
+	}
+	if runID == "" {
+		return "", errors.New("no manifests")
+	}
+
+	return runID, nil
+}
+
 // Run shells out kubectl CLI.
 func (c *CLI) Run(ctx context.Context, in io.Reader, out io.Writer, command string, commandFlags []string, arg ...string) error {
-	args := []string{"--context", c.KubeContext}
-	if c.Namespace != "" {
-		args = append(args, "--namespace", c.Namespace)
+	return c.run(ctx, c.Namespace, in, out, command, commandFlags, arg...)
+}
+
+// namespaceFor returns the namespace kubectl should target for manifests
+// that declare the given namespace. kubectl refuses to apply a manifest
+// to a namespace other than the one it declares.
+func (c *CLI) namespaceFor(declared string) string {
+	if declared != "" {
+		return declared
 	}
-	args = append(args, c.Flags.Global...)
-	args = append(args, command)
-	args = append(args, commandFlags...)
-	args = append(args, arg...)
+	return c.Namespace
+}
 
-	cmd := exec.CommandContext(ctx, "kubectl", args...)
+func (c *CLI) run(ctx context.Context, namespace string, in io.Reader, out io.Writer, command string, commandFlags []string, arg ...string) error {
+	cmd := c.command(ctx, namespace, command, commandFlags, arg...)
 	cmd.Stdin = in
 	cmd.Stdout = out
 	cmd.Stderr = out
 
 	return util.RunCmd(cmd)
 }
+
+// runOut shells out kubectl CLI and returns its output.
+func (c *CLI) runOut(ctx context.Context, namespace string, command string, commandFlags []string, arg ...string) ([]byte, error) {
+	return util.RunCmdOut(c.command(ctx, namespace, command, commandFlags, arg...))
+}
+
+func (c *CLI) command(ctx context.Context, namespace string, command string, commandFlags []string, arg ...string) *exec.Cmd {
+	var args []string
+	if c.KubeContext != "" {
+		args = append(args, "--context", c.KubeContext)
+	}
+	if c.KubeConfig != "" {
+		args = append(args, "--kubeconfig", c.KubeConfig)
+	}
+	if namespace != "" {
+		args = append(args, "--namespace", namespace)
+	}
+	args = append(args, c.Flags.Global...)
+	args = append(args, command)
+	args = append(args, commandFlags...)
+	args = append(args, arg...)
+
+	return kubectx.KubectlCommand(ctx, args...)
+}
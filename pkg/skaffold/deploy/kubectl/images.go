@@ -17,12 +17,15 @@ limitations under the License.
 package kubectl
 
 import (
+	"sync"
+
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
 )
 
 // for testing
@@ -37,8 +40,12 @@ func (l *ManifestList) ReplaceImages(builds []build.Artifact, defaultRepo string
 		return nil, errors.Wrap(err, "replacing images")
 	}
 
-	replacer.Check()
-	logrus.Debugln("manifests with tagged images", updated.String())
+	if err := replacer.Check(); err != nil {
+		return nil, err
+	}
+	if logrus.GetLevel() >= logrus.DebugLevel {
+		logrus.Debugln("manifests with tagged images", updated.String())
+	}
 
 	return updated, nil
 }
@@ -46,7 +53,9 @@ func (l *ManifestList) ReplaceImages(builds []build.Artifact, defaultRepo string
 type imageReplacer struct {
 	defaultRepo     string
 	tagsByImageName map[string]string
-	found           map[string]bool
+
+	lock  sync.Mutex
+	found map[string]bool
 }
 
 func newImageReplacer(builds []build.Artifact, defaultRepo string) *imageReplacer {
@@ -90,22 +99,35 @@ func (r *imageReplacer) parseAndReplace(image string) (bool, interface{}) {
 	if tag, present := r.tagsByImageName[parsed.BaseName]; present {
 		if parsed.FullyQualified {
 			if tag == image {
-				r.found[parsed.BaseName] = true
+				r.setFound(parsed.BaseName)
 			}
 		} else {
-			r.found[parsed.BaseName] = true
+			r.setFound(parsed.BaseName)
 			return true, tag
 		}
 	}
 	return false, nil
 }
 
-func (r *imageReplacer) Check() {
+func (r *imageReplacer) setFound(imageName string) {
+	r.lock.Lock()
+	r.found[imageName] = true
+	r.lock.Unlock()
+}
+
+// Check warns about the images that were built but are not used by the
+// deployment. In strict mode, an error is returned instead.
+func (r *imageReplacer) Check() error {
 	for imageName := range r.tagsByImageName {
-		if !r.found[imageName] {
-			warner.Warnf("image [%s] is not used by the deployment", imageName)
+		if r.found[imageName] {
+			continue
+		}
+		if warnings.IsStrict() {
+			return errors.Errorf("image [%s] is not used by the deployment", imageName)
 		}
+		warner.Warnf("image [%s] is not used by the deployment", imageName)
 	}
+	return nil
 }
 
 func (r *imageReplacer) substituteRepoIntoImage(originalImage string) string {
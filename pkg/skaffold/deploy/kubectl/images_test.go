@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
 	"github.com/GoogleContainerTools/skaffold/testutil"
 )
 
@@ -110,6 +111,30 @@ spec:
 	}, fakeWarner.warnings)
 }
 
+func TestReplaceImagesStrict(t *testing.T) {
+	manifests := ManifestList{[]byte(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: getting-started
+spec:
+  containers:
+  - image: skaffold/example
+    name: example
+`)}
+	builds := []build.Artifact{{
+		ImageName: "skaffold/unused",
+		Tag:       "skaffold/unused:TAG",
+	}}
+
+	warnings.SetStrict(true)
+	defer warnings.SetStrict(false)
+
+	_, err := manifests.ReplaceImages(builds, "")
+
+	testutil.CheckError(t, true, err)
+}
+
 func TestReplaceEmptyManifest(t *testing.T) {
 	manifests := ManifestList{[]byte(""), []byte("  ")}
 	expected := ManifestList{}
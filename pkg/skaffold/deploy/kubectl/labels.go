@@ -0,0 +1,80 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectl
+
+// SetLabels adds labels to the metadata of a list of manifests.
+func (l *ManifestList) SetLabels(labels map[string]string) (ManifestList, error) {
+	if len(labels) == 0 {
+		return *l, nil
+	}
+
+	return l.transform(func(m map[interface{}]interface{}) {
+		setLabels(m, labels)
+	})
+}
+
+// setLabels sets labels in the metadata of an object or a template.
+func setLabels(obj map[interface{}]interface{}, labels map[string]string) {
+	metadata, ok := obj["metadata"].(map[interface{}]interface{})
+	if !ok {
+		metadata = map[interface{}]interface{}{}
+		obj["metadata"] = metadata
+	}
+
+	existing, ok := metadata["labels"].(map[interface{}]interface{})
+	if !ok {
+		existing = map[interface{}]interface{}{}
+		metadata["labels"] = existing
+	}
+
+	for k, v := range labels {
+		existing[k] = v
+	}
+}
+
+// SetPodTemplateLabels adds labels to the pod templates of a list of manifests,
+// so that the pods created from them carry the labels.
+func (l *ManifestList) SetPodTemplateLabels(labels map[string]string) (ManifestList, error) {
+	if len(labels) == 0 {
+		return *l, nil
+	}
+
+	return l.transform(func(m map[interface{}]interface{}) {
+		for _, template := range podTemplates(m) {
+			setLabels(template, labels)
+		}
+	})
+}
+
+// podTemplates finds the pod templates of workloads:
+// spec.template and, for CronJobs, spec.jobTemplate.spec.template.
+func podTemplates(obj map[interface{}]interface{}) []map[interface{}]interface{} {
+	spec, ok := obj["spec"].(map[interface{}]interface{})
+	if !ok {
+		return nil
+	}
+
+	var templates []map[interface{}]interface{}
+	if template, ok := spec["template"].(map[interface{}]interface{}); ok {
+		templates = append(templates, template)
+	}
+	if jobTemplate, ok := spec["jobTemplate"].(map[interface{}]interface{}); ok {
+		templates = append(templates, podTemplates(jobTemplate)...)
+	}
+
+	return templates
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectl
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestSetLabels(t *testing.T) {
+	manifests := ManifestList{[]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    app: web
+  name: web
+spec:
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - image: gcr.io/k8s-skaffold/example
+        name: web
+`), []byte(`
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: job
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - image: gcr.io/k8s-skaffold/example
+            name: job
+`)}
+
+	expected := ManifestList{[]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    app: web
+    run-id: "123"
+  name: web
+spec:
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - image: gcr.io/k8s-skaffold/example
+        name: web
+`), []byte(`
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  labels:
+    run-id: "123"
+  name: job
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - image: gcr.io/k8s-skaffold/example
+            name: job
+`)}
+
+	resultManifest, err := manifests.SetLabels(map[string]string{"run-id": "123"})
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, expected.String(), resultManifest.String())
+}
+
+func TestSetPodTemplateLabels(t *testing.T) {
+	manifests := ManifestList{[]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    metadata:
+      labels:
+        app: web
+`), []byte(`
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: job
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          restartPolicy: Never
+`), []byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`)}
+
+	expected := ManifestList{[]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    metadata:
+      labels:
+        app: web
+        deployed-with: skaffold
+`), []byte(`
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: job
+spec:
+  jobTemplate:
+    spec:
+      template:
+        metadata:
+          labels:
+            deployed-with: skaffold
+        spec:
+          restartPolicy: Never
+`), []byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`)}
+
+	resultManifest, err := manifests.SetPodTemplateLabels(map[string]string{"deployed-with": "skaffold"})
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, expected.String(), resultManifest.String())
+}
@@ -20,20 +20,24 @@ import (
 	"bytes"
 	"io"
 	"strings"
+
+	"gopkg.in/yaml.v2"
 )
 
 // ManifestList is a list of yaml manifests.
 type ManifestList [][]byte
 
+const separator = "\n---\n"
+
 func (l *ManifestList) String() string {
-	var str string
+	var str strings.Builder
 	for i, manifest := range *l {
 		if i != 0 {
-			str += "\n---\n"
+			str.WriteString(separator)
 		}
-		str += string(bytes.TrimSpace(manifest))
+		str.Write(bytes.TrimSpace(manifest))
 	}
-	return str
+	return str.String()
 }
 
 // Append appends the yaml manifests defined in the given buffer.
@@ -67,6 +71,49 @@ func (l *ManifestList) Diff(latest ManifestList) ManifestList {
 }
 
 // Reader returns a reader on the raw yaml descriptors.
+// The manifests are streamed rather than copied into a single buffer.
 func (l *ManifestList) Reader() io.Reader {
-	return strings.NewReader(l.String())
+	var readers []io.Reader
+	for i, manifest := range *l {
+		if i != 0 {
+			readers = append(readers, strings.NewReader(separator))
+		}
+		readers = append(readers, bytes.NewReader(bytes.TrimSpace(manifest)))
+	}
+	return io.MultiReader(readers...)
+}
+
+type namespacedManifests struct {
+	namespace string
+	manifests ManifestList
+}
+
+// byNamespace groups the manifests by the namespace they declare, in order of
+// first appearance. Manifests that don't declare one are grouped under "".
+func (l *ManifestList) byNamespace() []namespacedManifests {
+	var groups []namespacedManifests
+	index := map[string]int{}
+
+	for _, manifest := range *l {
+		var m struct {
+			Metadata struct {
+				Namespace string `yaml:"namespace"`
+			} `yaml:"metadata"`
+		}
+		if err := yaml.Unmarshal(manifest, &m); err != nil {
+			// Let kubectl report invalid manifests.
+			m.Metadata.Namespace = ""
+		}
+
+		ns := m.Metadata.Namespace
+		i, found := index[ns]
+		if !found {
+			i = len(groups)
+			index[ns] = i
+			groups = append(groups, namespacedManifests{namespace: ns})
+		}
+		groups[i].manifests = append(groups[i].manifests, manifest)
+	}
+
+	return groups
 }
@@ -0,0 +1,76 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectl
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestByNamespace(t *testing.T) {
+	manifests := ManifestList{
+		[]byte("apiVersion: v1\nkind: Pod\nmetadata:\n  name: web"),
+		[]byte("apiVersion: v1\nkind: Pod\nmetadata:\n  name: db\n  namespace: backend"),
+		[]byte("apiVersion: v1\nkind: Pod\nmetadata:\n  name: app"),
+		[]byte("apiVersion: v1\nkind: Pod\nmetadata:\n  name: cache\n  namespace: backend"),
+	}
+
+	var namespaces []string
+	var grouped []ManifestList
+	for _, group := range manifests.byNamespace() {
+		namespaces = append(namespaces, group.namespace)
+		grouped = append(grouped, group.manifests)
+	}
+
+	testutil.CheckDeepEqual(t, []string{"", "backend"}, namespaces)
+	testutil.CheckDeepEqual(t, []ManifestList{
+		{manifests[0], manifests[2]},
+		{manifests[1], manifests[3]},
+	}, grouped)
+}
+
+func TestReader(t *testing.T) {
+	manifests := ManifestList{
+		[]byte("apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\n"),
+		[]byte("\napiVersion: v1\nkind: Pod\nmetadata:\n  name: db"),
+	}
+
+	content, err := ioutil.ReadAll(manifests.Reader())
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, manifests.String(), string(content))
+}
+
+func TestTransformKeepsOrder(t *testing.T) {
+	defer func(p int) { transformParallelism = p }(transformParallelism)
+	transformParallelism = 4
+
+	var manifests, expected ManifestList
+	for i := 0; i < 100; i++ {
+		manifests = append(manifests, []byte(fmt.Sprintf("kind: Pod\nmetadata:\n  name: pod%d\n", i)))
+		if i%10 == 0 {
+			manifests = append(manifests, []byte("\n"))
+		}
+		expected = append(expected, []byte(fmt.Sprintf("kind: Pod\nmetadata:\n  labels:\n    key: value\n  name: pod%d\n", i)))
+	}
+
+	updated, err := manifests.SetLabels(map[string]string{"key": "value"})
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, expected.String(), updated.String())
+}
@@ -0,0 +1,408 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectl
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// rolloutLabel tells apart the pods of a canary or of a blue/green version.
+	rolloutLabel = "skaffold.dev/rollout"
+
+	canary = "canary"
+	blue   = "blue"
+	green  = "green"
+)
+
+// workload holds the fields of a manifest that rollouts look at.
+type workload struct {
+	Kind     string `yaml:"kind"`
+	Metadata struct {
+		Name      string `yaml:"name"`
+		Namespace string `yaml:"namespace"`
+	} `yaml:"metadata"`
+	Spec struct {
+		Template struct {
+			Metadata struct {
+				Labels map[string]string `yaml:"labels"`
+			} `yaml:"metadata"`
+		} `yaml:"template"`
+	} `yaml:"spec"`
+}
+
+// rollout applies the updated manifests with the configured strategy.
+func (c *CLI) rollout(ctx context.Context, out io.Writer, manifests, updated ManifestList, firstApply bool, args []string) (ManifestList, error) {
+	if c.Strategy.BlueGreen != nil {
+		return c.blueGreen(ctx, out, manifests, updated, args)
+	}
+
+	// There's nothing to compare a canary with on the first deployment.
+	if firstApply {
+		return updated, c.apply(ctx, out, updated, args)
+	}
+	return updated, c.canary(ctx, out, updated, args)
+}
+
+// canary runs the new versions of the updated Deployments on a share of their
+// replicas for a while. They are then promoted and the canaries are deleted.
+func (c *CLI) canary(ctx context.Context, out io.Writer, updated ManifestList, args []string) error {
+	deployments, _, err := splitKind(updated, "Deployment")
+	if err != nil {
+		return err
+	}
+	if len(deployments) == 0 {
+		return c.apply(ctx, out, updated, args)
+	}
+
+	pause, err := time.ParseDuration(c.Strategy.Canary.Pause)
+	if err != nil {
+		return errors.Wrap(err, "parsing canary pause")
+	}
+	canaries, err := canaries(deployments, c.Strategy.Canary.Weight)
+	if err != nil {
+		return err
+	}
+
+	if err := c.apply(ctx, out, canaries, args); err != nil {
+		return errors.Wrap(err, "deploying canaries")
+	}
+	if err := c.waitForRollout(ctx, out, canaries); err != nil {
+		return errors.Wrap(err, "waiting for canaries")
+	}
+
+	color.Default.Fprintf(out, "Canaries are running on %d%% of the replicas. Promoting them in %v...\n", c.Strategy.Canary.Weight, pause)
+	select {
+	case <-ctx.Done():
+		// The context is cancelled but the canaries shouldn't be left behind.
+		if err := c.delete(context.Background(), out, canaries); err != nil {
+			logrus.Warnln("Unable to delete the canaries:", err)
+		}
+		return ctx.Err()
+	case <-time.After(pause):
+	}
+
+	if err := c.apply(ctx, out, updated, args); err != nil {
+		return err
+	}
+	if err := c.waitForRollout(ctx, out, deployments); err != nil {
+		return errors.Wrap(err, "promoting canaries")
+	}
+
+	return c.delete(ctx, out, canaries)
+}
+
+// blueGreen deploys the Deployments under alternating blue and green names.
+// When one of them is updated, a whole new version is deployed next to the live
+// one. The Services are switched to it once it's available and the previous
+// version is deleted.
+func (c *CLI) blueGreen(ctx context.Context, out io.Writer, manifests, updated ManifestList, args []string) (ManifestList, error) {
+	podLabels, err := deploymentPodLabels(manifests)
+	if err != nil {
+		return nil, err
+	}
+	if c.liveColor == "" {
+		c.liveColor = c.detectLiveColor(ctx, manifests)
+	}
+
+	changed, _, err := splitKind(updated, "Deployment")
+	if err != nil {
+		return nil, err
+	}
+	if c.liveColor != "" && len(changed) == 0 {
+		live, err := colorize(updated, c.liveColor, podLabels)
+		if err != nil {
+			return nil, err
+		}
+		return live, c.apply(ctx, out, live, args)
+	}
+
+	next, previous := blue, green
+	if c.liveColor == blue {
+		next, previous = green, blue
+	}
+
+	colored, err := colorize(manifests, next, podLabels)
+	if err != nil {
+		return nil, err
+	}
+	services, others, err := splitKind(colored, "Service")
+	if err != nil {
+		return nil, err
+	}
+
+	color.Default.Fprintf(out, "Deploying the %s version...\n", next)
+	if err := c.apply(ctx, out, others, args); err != nil {
+		return nil, err
+	}
+	deployments, _, err := splitKind(others, "Deployment")
+	if err != nil {
+		return nil, err
+	}
+	if err := c.waitForRollout(ctx, out, deployments); err != nil {
+		return nil, errors.Wrapf(err, "waiting for the %s version", next)
+	}
+
+	color.Default.Fprintf(out, "Switching the services to the %s version\n", next)
+	if err := c.apply(ctx, out, services, args); err != nil {
+		return nil, err
+	}
+	c.liveColor = next
+
+	// Also deletes the Deployments deployed before the blue/green strategy was used.
+	originals, _, err := splitKind(manifests, "Deployment")
+	if err != nil {
+		return nil, err
+	}
+	stale, err := colorize(originals, previous, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.delete(ctx, out, append(originals, stale...)); err != nil {
+		return nil, errors.Wrapf(err, "deleting the %s version", previous)
+	}
+
+	return colored, nil
+}
+
+// detectLiveColor finds the live version left by a previous session: the one that
+// the Services select or, without Services, the only version of the Deployments that
+// exists. It's empty if there's none.
+func (c *CLI) detectLiveColor(ctx context.Context, manifests ManifestList) string {
+	workloads, err := decodeWorkloads(manifests)
+	if err != nil {
+		return ""
+	}
+
+	for _, w := range workloads {
+		if w.Kind != "Service" {
+			continue
+		}
+
+		selected, err := c.runOut(ctx, c.namespaceFor(w.Metadata.Namespace), "get", nil, "service", w.Metadata.Name, "--ignore-not-found", "-o", `jsonpath={.spec.selector.skaffold\.dev/rollout}`)
+		if err != nil {
+			logrus.Debugf("Unable to get the version selected by service %s: %s", w.Metadata.Name, err)
+			continue
+		}
+		if version := strings.TrimSpace(string(selected)); version == blue || version == green {
+			return version
+		}
+	}
+
+	for _, w := range workloads {
+		if w.Kind != "Deployment" {
+			continue
+		}
+
+		existing, err := c.runOut(ctx, c.namespaceFor(w.Metadata.Namespace), "get", nil, "deployment", w.Metadata.Name+"-"+blue, w.Metadata.Name+"-"+green, "--ignore-not-found", "-o", "name")
+		if err != nil {
+			logrus.Debugf("Unable to get the versions of deployment %s: %s", w.Metadata.Name, err)
+			continue
+		}
+		if names := strings.Fields(string(existing)); len(names) == 1 {
+			if strings.HasSuffix(names[0], "-"+blue) {
+				return blue
+			}
+			return green
+		}
+	}
+
+	return ""
+}
+
+// rolloutVersions lists the canaries or the blue/green versions that
+// can be deployed for the Deployments of a list of manifests.
+func (c *CLI) rolloutVersions(manifests ManifestList) (ManifestList, error) {
+	deployments, _, err := splitKind(manifests, "Deployment")
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Strategy.BlueGreen == nil {
+		return canaries(deployments, 100)
+	}
+
+	var versions ManifestList
+	for _, version := range []string{blue, green} {
+		colored, err := colorize(deployments, version, nil)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, colored...)
+	}
+	return versions, nil
+}
+
+// waitForRollout waits for Deployments to be rolled out.
+func (c *CLI) waitForRollout(ctx context.Context, out io.Writer, deployments ManifestList) error {
+	workloads, err := decodeWorkloads(deployments)
+	if err != nil {
+		return err
+	}
+
+	for _, w := range workloads {
+		if err := c.run(ctx, c.namespaceFor(w.Metadata.Namespace), nil, out, "rollout", nil, "status", "deployment/"+w.Metadata.Name); err != nil {
+			return errors.Wrapf(err, "rollout of %s", w.Metadata.Name)
+		}
+	}
+
+	return nil
+}
+
+// canaries derives from Deployments the canaries that run their new version
+// on the given percentage of their replicas.
+func canaries(deployments ManifestList, weight int) (ManifestList, error) {
+	return deployments.transform(func(m map[interface{}]interface{}) {
+		rename(m, canary)
+		spec := childMap(m, "spec")
+
+		replicas := 1
+		if r, ok := spec["replicas"].(int); ok {
+			replicas = r
+		}
+		spec["replicas"] = canaryReplicas(replicas, weight)
+
+		setSelectorLabel(spec, canary)
+	})
+}
+
+// canaryReplicas rounds up, so that there's always at least one canary.
+func canaryReplicas(replicas, weight int) int {
+	if n := (replicas*weight + 99) / 100; n > 1 {
+		return n
+	}
+	return 1
+}
+
+// colorize renames Deployments after a color and labels their pods with it.
+// Services that select the pods of one of the Deployments select the pods of
+// that color.
+func colorize(manifests ManifestList, version string, podLabels []map[string]string) (ManifestList, error) {
+	return manifests.transform(func(m map[interface{}]interface{}) {
+		switch m["kind"] {
+		case "Deployment":
+			rename(m, version)
+			setSelectorLabel(childMap(m, "spec"), version)
+
+		case "Service":
+			spec := childMap(m, "spec")
+			selector, ok := spec["selector"].(map[interface{}]interface{})
+			if ok && selectsAny(selector, podLabels) {
+				selector[rolloutLabel] = version
+			}
+		}
+	})
+}
+
+// setSelectorLabel labels the pods of a Deployment with a rollout label
+// and only selects the pods that carry it.
+func setSelectorLabel(spec map[interface{}]interface{}, value string) {
+	childMap(childMap(spec, "selector"), "matchLabels")[rolloutLabel] = value
+	if template, ok := spec["template"].(map[interface{}]interface{}); ok {
+		setLabels(template, map[string]string{rolloutLabel: value})
+	}
+}
+
+func rename(m map[interface{}]interface{}, suffix string) {
+	metadata := childMap(m, "metadata")
+	if name, ok := metadata["name"].(string); ok {
+		metadata["name"] = name + "-" + suffix
+	}
+}
+
+func selectsAny(selector map[interface{}]interface{}, podLabels []map[string]string) bool {
+	if len(selector) == 0 {
+		return false
+	}
+
+	for _, labels := range podLabels {
+		matches := true
+		for k, v := range selector {
+			key, _ := k.(string)
+			value, _ := v.(string)
+			if labels[key] != value {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+func childMap(m map[interface{}]interface{}, key string) map[interface{}]interface{} {
+	child, ok := m[key].(map[interface{}]interface{})
+	if !ok {
+		child = map[interface{}]interface{}{}
+		m[key] = child
+	}
+	return child
+}
+
+// deploymentPodLabels lists the labels of the pod templates of Deployments.
+func deploymentPodLabels(manifests ManifestList) ([]map[string]string, error) {
+	workloads, err := decodeWorkloads(manifests)
+	if err != nil {
+		return nil, err
+	}
+
+	var podLabels []map[string]string
+	for _, w := range workloads {
+		if w.Kind == "Deployment" {
+			podLabels = append(podLabels, w.Spec.Template.Metadata.Labels)
+		}
+	}
+	return podLabels, nil
+}
+
+// splitKind separates the manifests of a given kind from the others.
+func splitKind(manifests ManifestList, kind string) (ManifestList, ManifestList, error) {
+	workloads, err := decodeWorkloads(manifests)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var matching, others ManifestList
+	for i, w := range workloads {
+		if w.Kind == kind {
+			matching = append(matching, manifests[i])
+		} else {
+			others = append(others, manifests[i])
+		}
+	}
+	return matching, others, nil
+}
+
+func decodeWorkloads(manifests ManifestList) ([]workload, error) {
+	workloads := make([]workload, len(manifests))
+	for i, manifest := range manifests {
+		if err := yaml.Unmarshal(manifest, &workloads[i]); err != nil {
+			return nil, errors.Wrap(err, "reading kubernetes YAML")
+		}
+	}
+	return workloads, nil
+}
@@ -0,0 +1,299 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectl
+
+import (
+	"context"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+const webDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 4
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - image: web:v1
+        name: web`
+
+const webService = `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  selector:
+    app: web`
+
+const dbService = `apiVersion: v1
+kind: Service
+metadata:
+  name: db
+spec:
+  selector:
+    app: db`
+
+// recordingKubectl records the kubectl commands, along with the names of
+// the manifests they are given. Commands can be given an output.
+type recordingKubectl struct {
+	commands []string
+	outputs  map[string]string
+}
+
+func (r *recordingKubectl) RunCmdOut(cmd *exec.Cmd) ([]byte, error) {
+	err := r.RunCmd(cmd)
+	return []byte(r.outputs[strings.Join(cmd.Args[1:], " ")]), err
+}
+
+func (r *recordingKubectl) RunCmd(cmd *exec.Cmd) error {
+	command := strings.Join(cmd.Args[1:], " ")
+	if cmd.Stdin != nil {
+		in, _ := ioutil.ReadAll(cmd.Stdin)
+		var names []string
+		for _, line := range strings.Split(string(in), "\n") {
+			if strings.HasPrefix(line, "  name: ") {
+				names = append(names, strings.TrimPrefix(line, "  name: "))
+			}
+		}
+		command += " " + strings.Join(names, ",")
+	}
+	r.commands = append(r.commands, command)
+	return nil
+}
+
+func TestCanaries(t *testing.T) {
+	canaries, err := canaries(ManifestList{[]byte(webDeployment)}, 30)
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web-canary
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: web
+      skaffold.dev/rollout: canary
+  template:
+    metadata:
+      labels:
+        app: web
+        skaffold.dev/rollout: canary
+    spec:
+      containers:
+      - image: web:v1
+        name: web`, canaries.String())
+}
+
+func TestCanaryReplicas(t *testing.T) {
+	testutil.CheckDeepEqual(t, 1, canaryReplicas(4, 20))
+	testutil.CheckDeepEqual(t, 2, canaryReplicas(10, 20))
+	testutil.CheckDeepEqual(t, 3, canaryReplicas(10, 25))
+	testutil.CheckDeepEqual(t, 1, canaryReplicas(0, 50))
+}
+
+func TestColorize(t *testing.T) {
+	manifests := ManifestList{[]byte(webDeployment), []byte(webService), []byte(dbService)}
+
+	colored, err := colorize(manifests, blue, []map[string]string{{"app": "web"}})
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web-blue
+spec:
+  replicas: 4
+  selector:
+    matchLabels:
+      app: web
+      skaffold.dev/rollout: blue
+  template:
+    metadata:
+      labels:
+        app: web
+        skaffold.dev/rollout: blue
+    spec:
+      containers:
+      - image: web:v1
+        name: web
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  selector:
+    app: web
+    skaffold.dev/rollout: blue
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+spec:
+  selector:
+    app: db`, colored.String())
+}
+
+const (
+	getServiceVersion     = `get service web --ignore-not-found -o jsonpath={.spec.selector.skaffold\.dev/rollout}`
+	getDeploymentVersions = "get deployment web-blue web-green --ignore-not-found -o name"
+)
+
+func TestRolloutStrategies(t *testing.T) {
+	updatedDeployment := strings.Replace(webDeployment, "web:v1", "web:v2", 1)
+
+	var tests = []struct {
+		description      string
+		strategy         *latest.RolloutStrategy
+		expectedCommands []string
+	}{
+		{
+			description: "canary",
+			strategy:    &latest.RolloutStrategy{Canary: &latest.CanaryStrategy{Weight: 20, Pause: "0s"}},
+			expectedCommands: []string{
+				"apply --force -f - web,web",
+				"apply --force -f - web-canary",
+				"rollout status deployment/web-canary",
+				"apply --force -f - web",
+				"rollout status deployment/web",
+				"delete --ignore-not-found=true -f - web-canary",
+			},
+		},
+		{
+			description: "blue-green",
+			strategy:    &latest.RolloutStrategy{BlueGreen: &latest.BlueGreenStrategy{}},
+			expectedCommands: []string{
+				getServiceVersion,
+				getDeploymentVersions,
+				"apply --force -f - web-blue",
+				"rollout status deployment/web-blue",
+				"apply --force -f - web",
+				"delete --ignore-not-found=true -f - web,web-green",
+				"apply --force -f - web-green",
+				"rollout status deployment/web-green",
+				"apply --force -f - web",
+				"delete --ignore-not-found=true -f - web,web-blue",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			kubectl := &recordingKubectl{}
+			defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+			util.DefaultExecCommand = kubectl
+
+			cli := &CLI{Strategy: test.strategy}
+			_, err := cli.Apply(context.Background(), ioutil.Discard, ManifestList{[]byte(webDeployment), []byte(webService)})
+			testutil.CheckError(t, false, err)
+			_, err = cli.Apply(context.Background(), ioutil.Discard, ManifestList{[]byte(updatedDeployment), []byte(webService)})
+
+			testutil.CheckErrorAndDeepEqual(t, false, err, test.expectedCommands, kubectl.commands)
+		})
+	}
+}
+
+func TestBlueGreenLiveVersionOfPreviousSession(t *testing.T) {
+	var tests = []struct {
+		description      string
+		outputs          map[string]string
+		expectedCommands []string
+	}{
+		{
+			description: "selected by the service",
+			outputs:     map[string]string{getServiceVersion: "blue"},
+			expectedCommands: []string{
+				getServiceVersion,
+				"apply --force -f - web-green",
+				"rollout status deployment/web-green",
+				"apply --force -f - web",
+				"delete --ignore-not-found=true -f - web,web-blue",
+			},
+		},
+		{
+			description: "only deployed version",
+			outputs:     map[string]string{getDeploymentVersions: "deployment.apps/web-green\n"},
+			expectedCommands: []string{
+				getServiceVersion,
+				getDeploymentVersions,
+				"apply --force -f - web-blue",
+				"rollout status deployment/web-blue",
+				"apply --force -f - web",
+				"delete --ignore-not-found=true -f - web,web-green",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			kubectl := &recordingKubectl{outputs: test.outputs}
+			defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+			util.DefaultExecCommand = kubectl
+
+			cli := &CLI{Strategy: &latest.RolloutStrategy{BlueGreen: &latest.BlueGreenStrategy{}}}
+			_, err := cli.Apply(context.Background(), ioutil.Discard, ManifestList{[]byte(webDeployment), []byte(webService)})
+
+			testutil.CheckErrorAndDeepEqual(t, false, err, test.expectedCommands, kubectl.commands)
+		})
+	}
+}
+
+func TestCanaryCancelledDuringPause(t *testing.T) {
+	kubectl := &recordingKubectl{}
+	defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+	util.DefaultExecCommand = kubectl
+
+	cli := &CLI{Strategy: &latest.RolloutStrategy{Canary: &latest.CanaryStrategy{Weight: 20, Pause: "1h"}}}
+	_, err := cli.Apply(context.Background(), ioutil.Discard, ManifestList{[]byte(webDeployment)})
+	testutil.CheckError(t, false, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = cli.Apply(ctx, ioutil.Discard, ManifestList{[]byte(strings.Replace(webDeployment, "web:v1", "web:v2", 1))})
+
+	testutil.CheckErrorAndDeepEqual(t, true, err, []string{
+		"apply --force -f - web",
+		"apply --force -f - web-canary",
+		"rollout status deployment/web-canary",
+		"delete --ignore-not-found=true -f - web-canary",
+	}, kubectl.commands)
+}
+
+func TestDeleteRolloutVersions(t *testing.T) {
+	kubectl := &recordingKubectl{}
+	defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+	util.DefaultExecCommand = kubectl
+
+	cli := &CLI{Strategy: &latest.RolloutStrategy{BlueGreen: &latest.BlueGreenStrategy{}}}
+	err := cli.Delete(context.Background(), ioutil.Discard, ManifestList{[]byte(webDeployment), []byte(webService)})
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, []string{"delete --ignore-not-found=true -f - web,web,web-blue,web-green"}, kubectl.commands)
+}
@@ -19,11 +19,9 @@ package kubectl
 import (
 	"context"
 	"encoding/json"
-	"os/exec"
-	"strconv"
 
+	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
-	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
@@ -48,18 +46,6 @@ func (v ClientVersion) String() string {
 	return v.Major + "." + v.Minor
 }
 
-// CheckVersion warns the user if their kubectl version is < 1.12.0
-func (c *CLI) CheckVersion() error {
-	m, err := strconv.Atoi(c.Version().Minor)
-	if err != nil {
-		return errors.Wrap(err, "couldn't get kubectl minor version")
-	}
-	if m < 12 {
-		return errors.New("kubectl version 1.12.0 or greater is recommended for use with skaffold")
-	}
-	return nil
-}
-
 // Version returns the client version of kubectl.
 func (c *CLI) Version() ClientVersion {
 	c.versionOnce.Do(func() {
@@ -84,6 +70,6 @@ func (c *CLI) Version() ClientVersion {
 }
 
 func (c *CLI) getVersion(ctx context.Context) ([]byte, error) {
-	cmd := exec.CommandContext(ctx, "kubectl", "version", "--client", "-ojson")
+	cmd := kubectx.KubectlCommand(ctx, "version", "--client", "-ojson")
 	return util.RunCmdOut(cmd)
 }
@@ -17,11 +17,18 @@ limitations under the License.
 package kubectl
 
 import (
+	"runtime"
+
 	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
 	"gopkg.in/yaml.v2"
 )
 
+// for testing
+var transformParallelism = runtime.NumCPU()
+
 // Replacer is used to replace portions of yaml manifests that match a given key.
+// Manifests are visited in parallel so a Replacer must be safe for concurrent use.
 type Replacer interface {
 	Matches(key string) bool
 
@@ -30,26 +37,59 @@ type Replacer interface {
 
 // Visit recursively visits a list of manifests and applies transformations of them.
 func (l *ManifestList) Visit(replacer Replacer) (ManifestList, error) {
-	var updated ManifestList
+	return l.transform(func(m map[interface{}]interface{}) {
+		recursiveVisit(m, replacer)
+	})
+}
 
-	for _, manifest := range *l {
-		m := make(map[interface{}]interface{})
-		if err := yaml.Unmarshal(manifest, &m); err != nil {
-			return nil, errors.Wrap(err, "reading kubernetes YAML")
-		}
+// transform decodes each manifest, applies a transformation and encodes it back.
+// Manifests are processed in parallel, on up to transformParallelism goroutines,
+// which speeds up large manifest sets like CRD bundles. Empty manifests are
+// dropped and the order is preserved.
+func (l *ManifestList) transform(f func(map[interface{}]interface{})) (ManifestList, error) {
+	manifests := *l
+	transformed := make(ManifestList, len(manifests))
 
-		if len(m) == 0 {
-			continue
-		}
+	var g errgroup.Group
+	sem := make(chan struct{}, transformParallelism)
 
-		recursiveVisit(m, replacer)
+	for i, manifest := range manifests {
+		i, manifest := i, manifest
 
-		updatedManifest, err := yaml.Marshal(m)
-		if err != nil {
-			return nil, errors.Wrap(err, "marshalling yaml")
-		}
+		sem <- struct{}{}
+		g.Go(func() error {
+			defer func() { <-sem }()
+
+			m := make(map[interface{}]interface{})
+			if err := yaml.Unmarshal(manifest, &m); err != nil {
+				return errors.Wrap(err, "reading kubernetes YAML")
+			}
 
-		updated = append(updated, updatedManifest)
+			if len(m) == 0 {
+				return nil
+			}
+
+			f(m)
+
+			updatedManifest, err := yaml.Marshal(m)
+			if err != nil {
+				return errors.Wrap(err, "marshalling yaml")
+			}
+
+			transformed[i] = updatedManifest
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	var updated ManifestList
+	for _, manifest := range transformed {
+		if manifest != nil {
+			updated = append(updated, manifest)
+		}
 	}
 
 	return updated, nil
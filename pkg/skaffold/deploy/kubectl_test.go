@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/kubectl"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/GoogleContainerTools/skaffold/testutil"
@@ -49,6 +50,31 @@ spec:
   - name: leeroy-app
     image: leeroy-app`
 
+const deploymentBackendYAML = `apiVersion: v1
+kind: Pod
+metadata:
+  name: leeroy-db
+  namespace: backend
+spec:
+  containers:
+  - name: leeroy-db
+    image: leeroy-db`
+
+const deploymentLabelledYAML = `apiVersion: v1
+kind: Pod
+metadata:
+  name: leeroy-web
+  labels:
+    skaffold-run-id: abc
+spec:
+  containers:
+  - name: leeroy-web
+    image: leeroy-web`
+
+func intPtr(i int) *int {
+	return &i
+}
+
 func TestKubectlDeploy(t *testing.T) {
 	var tests = []struct {
 		description string
@@ -96,6 +122,46 @@ func TestKubectlDeploy(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "deploy without force",
+			cfg: &latest.KubectlDeploy{
+				Manifests: []string{"deployment.yaml"},
+				Force:     util.BoolPtr(false),
+			},
+			command: testutil.NewFakeCmd("kubectl --context kubecontext --namespace testNamespace apply -f -", nil),
+		},
+		{
+			description: "deploy with grace period, without cascading",
+			cfg: &latest.KubectlDeploy{
+				Manifests:   []string{"deployment.yaml"},
+				GracePeriod: intPtr(5),
+				Cascade:     util.BoolPtr(false),
+			},
+			command: testutil.NewFakeCmd("kubectl --context kubecontext --namespace testNamespace apply --force --grace-period=5 --cascade=false -f -", nil),
+		},
+		{
+			description: "deploy with pruning",
+			cfg: &latest.KubectlDeploy{
+				Manifests:      []string{"labelled.yaml"},
+				PruneWhitelist: []string{"core/v1/ConfigMap", "apps/v1/StatefulSet"},
+			},
+			command: testutil.NewFakeCmd("kubectl --context kubecontext --namespace testNamespace apply --force --prune -l skaffold-run-id=abc --prune-whitelist=core/v1/ConfigMap --prune-whitelist=apps/v1/StatefulSet -f -", nil),
+		},
+		{
+			description: "pruning needs the run ID label",
+			cfg: &latest.KubectlDeploy{
+				Manifests:      []string{"deployment.yaml"},
+				PruneWhitelist: []string{"core/v1/ConfigMap"},
+			},
+			shouldErr: true,
+		},
+		{
+			description: "deploy to declared namespace",
+			cfg: &latest.KubectlDeploy{
+				Manifests: []string{"backend.yaml"},
+			},
+			command: testutil.NewFakeCmd("kubectl --context kubecontext --namespace backend apply --force -f -", nil),
+		},
 		{
 			description: "deploy command error",
 			shouldErr:   true,
@@ -135,6 +201,8 @@ func TestKubectlDeploy(t *testing.T) {
 	defer cleanup()
 
 	tmpDir.Write("deployment.yaml", deploymentWebYAML)
+	tmpDir.Write("backend.yaml", deploymentBackendYAML)
+	tmpDir.Write("labelled.yaml", deploymentLabelledYAML)
 
 	for _, test := range tests {
 		t.Run(test.description, func(t *testing.T) {
@@ -185,6 +253,15 @@ func TestKubectlCleanup(t *testing.T) {
 			},
 			command: testutil.NewFakeCmd("kubectl --context kubecontext --namespace testNamespace -v=0 delete --grace-period=1 --ignore-not-found=true -f -", nil),
 		},
+		{
+			description: "grace period, without cascading",
+			cfg: &latest.KubectlDeploy{
+				Manifests:   []string{"deployment.yaml"},
+				GracePeriod: intPtr(0),
+				Cascade:     util.BoolPtr(false),
+			},
+			command: testutil.NewFakeCmd("kubectl --context kubecontext --namespace testNamespace delete --grace-period=0 --cascade=false --ignore-not-found=true -f -", nil),
+		},
 	}
 
 	tmpDir, cleanup := testutil.NewTempDir(t)
@@ -207,6 +284,27 @@ func TestKubectlCleanup(t *testing.T) {
 	}
 }
 
+func TestKubectlCleanupDeployedRun(t *testing.T) {
+	defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+	util.DefaultExecCommand = testutil.NewFakeCmd("kubectl --context kubecontext --namespace testNamespace apply --force -f -", nil)
+
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+	tmpDir.Write("labelled.yaml", deploymentLabelledYAML)
+
+	cfg := &latest.KubectlDeploy{
+		Manifests: []string{"labelled.yaml"},
+	}
+	k := NewKubectlDeployer(tmpDir.Root(), cfg, testKubeContext, testNamespace, "")
+	_, err := k.Deploy(context.Background(), ioutil.Discard, nil)
+	testutil.CheckError(t, false, err)
+
+	util.DefaultExecCommand = testutil.NewFakeCmd("kubectl --context kubecontext --namespace testNamespace delete Pod --ignore-not-found=true -l skaffold-run-id=abc", nil)
+	err = k.Cleanup(context.Background(), ioutil.Discard)
+
+	testutil.CheckError(t, false, err)
+}
+
 func TestKubectlRedeploy(t *testing.T) {
 	defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
 	util.DefaultExecCommand = testutil.NewFakeCmd("kubectl --context kubecontext --namespace testNamespace apply --force -f -", nil)
@@ -242,3 +340,16 @@ func TestKubectlRedeploy(t *testing.T) {
 	})
 	testutil.CheckErrorAndDeepEqual(t, false, err, 0, len(deployed))
 }
+
+func TestParseManifestsForDeploysNamespaces(t *testing.T) {
+	deployed, err := parseManifestsForDeploys(testNamespace, kubectl.ManifestList{
+		[]byte(deploymentWebYAML),
+		[]byte(deploymentBackendYAML),
+	})
+
+	var namespaces []string
+	for _, d := range deployed {
+		namespaces = append(namespaces, d.Namespace)
+	}
+	testutil.CheckErrorAndDeepEqual(t, false, err, []string{testNamespace, "backend"}, namespaces)
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/kubectl"
+	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/pkg/errors"
@@ -57,6 +58,7 @@ type KustomizeDeployer struct {
 
 	kubectl     kubectl.CLI
 	defaultRepo string
+	cache       *manifestCache
 }
 
 func NewKustomizeDeployer(cfg *latest.KustomizeDeploy, kubeContext string, namespace string, defaultRepo string) *KustomizeDeployer {
@@ -65,9 +67,11 @@ func NewKustomizeDeployer(cfg *latest.KustomizeDeploy, kubeContext string, names
 		kubectl: kubectl.CLI{
 			Namespace:   namespace,
 			KubeContext: kubeContext,
+			KubeConfig:  kubectx.KubeConfigFile(),
 			Flags:       cfg.Flags,
 		},
 		defaultRepo: defaultRepo,
+		cache:       newManifestCache(),
 	}
 }
 
@@ -89,9 +93,9 @@ func (k *KustomizeDeployer) Deploy(ctx context.Context, out io.Writer, builds []
 		return nil, nil
 	}
 
-	manifests, err = manifests.ReplaceImages(builds, k.defaultRepo)
+	manifests, err = k.cache.render(manifests, builds, k.defaultRepo)
 	if err != nil {
-		return nil, errors.Wrap(err, "replacing images in manifests")
+		return nil, err
 	}
 
 	updated, err := k.kubectl.Apply(ctx, out, manifests)
@@ -168,6 +172,16 @@ func (k *KustomizeDeployer) Dependencies() ([]string, error) {
 	return dependenciesForKustomization(k.KustomizePath)
 }
 
+// Render writes the manifests built by kustomize that would be applied.
+func (k *KustomizeDeployer) Render(ctx context.Context, out io.Writer, builds []build.Artifact) ([]Artifact, error) {
+	manifests, err := k.readManifests(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading manifests")
+	}
+
+	return renderManifests(out, k.kubectl.Namespace, manifests, builds, k.defaultRepo)
+}
+
 func (k *KustomizeDeployer) readManifests(ctx context.Context) (kubectl.ManifestList, error) {
 	cmd := exec.CommandContext(ctx, "kustomize", "build", k.KustomizePath)
 	out, err := util.RunCmdOut(cmd)
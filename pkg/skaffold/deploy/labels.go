@@ -26,13 +26,18 @@ import (
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/kubectl"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
-	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	patch "k8s.io/apimachinery/pkg/util/strategicpatch"
@@ -46,6 +51,14 @@ type Labeller interface {
 	Labels() map[string]string
 }
 
+// StaticLabels are labels set by users, for example in skaffold.yaml.
+type StaticLabels map[string]string
+
+// Labels returns the labels.
+func (l StaticLabels) Labels() map[string]string {
+	return l
+}
+
 type withLabels struct {
 	Deployer
 
@@ -63,11 +76,85 @@ func WithLabels(d Deployer, labellers ...Labeller) Deployer {
 func (w *withLabels) Deploy(ctx context.Context, out io.Writer, artifacts []build.Artifact) ([]Artifact, error) {
 	dRes, err := w.Deployer.Deploy(ctx, out, artifacts)
 
-	labelDeployResults(merge(w.labellers...), dRes)
+	if labelErr := labelDeployResults(merge(w.labellers...), dRes); labelErr != nil && err == nil {
+		return dRes, errors.Wrap(labelErr, "labelling deployed resources")
+	}
 
 	return dRes, err
 }
 
+// LabelRunID returns a manifest transform that labels the deployed objects with the
+// run ID, so that pruning and cleanup only affect the objects of a given skaffold run.
+// Pod templates aren't labelled: a new run ID would roll out every workload.
+// The pods are labelled once they're created instead, see RunPodLabels.
+func LabelRunID(runID string) ManifestTransform {
+	return func(l kubectl.ManifestList, builds []build.Artifact) (kubectl.ManifestList, error) {
+		return l.SetLabels(map[string]string{
+			constants.Labels.RunID: runID,
+		})
+	}
+}
+
+// RunPodLabels are the labels of the pods deployed by the skaffold run with the given ID.
+// The pods are labelled once they're created, see kubernetes.PodCache.Adopt.
+func RunPodLabels(runID string) map[string]string {
+	return map[string]string{
+		constants.Labels.RunID: runID,
+	}
+}
+
+// Workloads returns the selectors of the pods created by the deployed objects,
+// whichever deployer deployed them. The pods of CronJobs aren't selected.
+func Workloads(dRes []Artifact) []kubernetes.Workload {
+	var workloads []kubernetes.Workload
+
+	for _, res := range dRes {
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(*res.Obj)
+		if err != nil {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(obj, "metadata", "name")
+
+		workload := kubernetes.Workload{Namespace: res.Namespace}
+		switch (*res.Obj).GetObjectKind().GroupVersionKind().Kind {
+		case "Pod":
+			workload.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		case "Job":
+			workload.LabelSelector = labels.SelectorFromSet(map[string]string{"job-name": name}).String()
+		case "Deployment", "ReplicaSet", "StatefulSet", "DaemonSet", "ReplicationController":
+			workload.LabelSelector = podSelector(obj)
+		}
+
+		if workload.LabelSelector != "" || workload.FieldSelector != "" {
+			workloads = append(workloads, workload)
+		}
+	}
+
+	return workloads
+}
+
+// podSelector returns the selector of a workload's pods, which defaults to the labels of its pod template.
+func podSelector(obj map[string]interface{}) string {
+	if spec, found, _ := unstructured.NestedMap(obj, "spec", "selector"); found {
+		var selector metav1.LabelSelector
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(spec, &selector); err == nil {
+			if s, err := metav1.LabelSelectorAsSelector(&selector); err == nil && !s.Empty() {
+				return s.String()
+			}
+		}
+		// ReplicationControllers select with a plain map of labels.
+		if matchLabels, found, _ := unstructured.NestedStringMap(obj, "spec", "selector"); found && len(matchLabels) > 0 {
+			return labels.SelectorFromSet(matchLabels).String()
+		}
+	}
+
+	templateLabels, _, _ := unstructured.NestedStringMap(obj, "spec", "template", "metadata", "labels")
+	if len(templateLabels) == 0 {
+		return ""
+	}
+	return labels.SelectorFromSet(templateLabels).String()
+}
+
 // merge merges the labels from multiple sources.
 func merge(sources ...Labeller) map[string]string {
 	merged := make(map[string]string)
@@ -85,18 +172,16 @@ const (
 	sleeptime = 300 * time.Millisecond
 )
 
-func labelDeployResults(labels map[string]string, results []Artifact) {
+func labelDeployResults(labels map[string]string, results []Artifact) error {
 	// use the kubectl client to update all k8s objects with a skaffold watermark
 	dynClient, err := kubernetes.DynamicClient()
 	if err != nil {
-		logrus.Warnf("error retrieving kubernetes dynamic client: %s", err.Error())
-		return
+		return warnings.Warnf("error retrieving kubernetes dynamic client: %s", err.Error())
 	}
 
 	client, err := kubernetes.GetClientset()
 	if err != nil {
-		logrus.Warnf("error retrieving kubernetes client: %s", err.Error())
-		return
+		return warnings.Warnf("error retrieving kubernetes client: %s", err.Error())
 	}
 
 	for _, res := range results {
@@ -108,9 +193,13 @@ func labelDeployResults(labels map[string]string, results []Artifact) {
 			time.Sleep(sleeptime)
 		}
 		if err != nil {
-			logrus.Warnf("error adding label to runtime object: %s", err.Error())
+			if err := warnings.Warnf("error adding label to runtime object: %s", err.Error()); err != nil {
+				return err
+			}
 		}
 	}
+
+	return nil
 }
 
 func addLabels(labels map[string]string, accessor metav1.Object) {
@@ -138,45 +227,35 @@ func updateRuntimeObject(client dynamic.Interface, disco discovery.DiscoveryInte
 		return nil
 	}
 
-	namespace := res.Namespace
 	addLabels(labels, accessor)
 
 	modifiedJSON, _ := json.Marshal(modifiedObj)
 	p, _ := patch.CreateTwoWayMergePatch(originalJSON, modifiedJSON, modifiedObj)
-	gvr, err := groupVersionResource(disco, modifiedObj.GetObjectKind().GroupVersionKind())
+	var gvr schema.GroupVersionResource
+	err = kubernetes.RetryOnConnectionError(func() error {
+		gvr, err = groupVersionResource(disco, modifiedObj.GetObjectKind().GroupVersionKind())
+		return err
+	})
 	if err != nil {
 		return errors.Wrap(err, "getting group version resource from obj")
 	}
 
-	ns, err := resolveNamespace(namespace)
+	ns, err := namespaceOf(res, accessor.GetNamespace())
 	if err != nil {
 		return errors.Wrap(err, "resolving namespace")
 	}
 	logrus.Debugln("Patching", name, "in namespace", ns)
 
-	if _, err := client.Resource(gvr).Namespace(ns).Patch(name, types.StrategicMergePatchType, p); err != nil {
-		return errors.Wrapf(err, "patching resource %s/%s", namespace, name)
+	if err := kubernetes.RetryOnConnectionError(func() error {
+		_, err := client.Resource(gvr).Namespace(ns).Patch(name, types.StrategicMergePatchType, p)
+		return err
+	}); err != nil {
+		return errors.Wrapf(err, "patching resource %s/%s", ns, name)
 	}
 
 	return nil
 }
 
-func resolveNamespace(ns string) (string, error) {
-	if ns != "" {
-		return ns, nil
-	}
-	cfg, err := kubectx.CurrentConfig()
-	if err != nil {
-		return "", errors.Wrap(err, "getting kubeconfig")
-	}
-
-	current, present := cfg.Contexts[cfg.CurrentContext]
-	if present && current.Namespace != "" {
-		return current.Namespace, nil
-	}
-	return "default", nil
-}
-
 func groupVersionResource(disco discovery.DiscoveryInterface, gvk schema.GroupVersionKind) (schema.GroupVersionResource, error) {
 	resources, err := disco.ServerResourcesForGroupVersion(gvk.GroupVersion().String())
 	if err != nil {
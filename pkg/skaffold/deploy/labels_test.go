@@ -0,0 +1,100 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/kubectl"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestWorkloads(t *testing.T) {
+	var tests = []struct {
+		description string
+		manifest    string
+		expected    []kubernetes.Workload
+	}{
+		{
+			description: "deployment",
+			manifest: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+        tier: front
+`,
+			expected: []kubernetes.Workload{{Namespace: "ns", LabelSelector: "app=web"}},
+		},
+		{
+			description: "selector defaults to the template labels",
+			manifest: `apiVersion: extensions/v1beta1
+kind: DaemonSet
+metadata:
+  name: agent
+spec:
+  template:
+    metadata:
+      labels:
+        app: agent
+`,
+			expected: []kubernetes.Workload{{Namespace: "ns", LabelSelector: "app=agent"}},
+		},
+		{
+			description: "job",
+			manifest: `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+`,
+			expected: []kubernetes.Workload{{Namespace: "ns", LabelSelector: "job-name=migrate"}},
+		},
+		{
+			description: "pod",
+			manifest: `apiVersion: v1
+kind: Pod
+metadata:
+  name: debug
+`,
+			expected: []kubernetes.Workload{{Namespace: "ns", FieldSelector: "metadata.name=debug"}},
+		},
+		{
+			description: "no pods",
+			manifest: `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			dRes, err := parseManifestsForDeploys("ns", kubectl.ManifestList{[]byte(test.manifest)})
+			testutil.CheckError(t, false, err)
+
+			testutil.CheckDeepEqual(t, test.expected, Workloads(dRes))
+		})
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/kubectl"
+	"github.com/pkg/errors"
+)
+
+// manifestCache spares the dev loop from reading, fetching and rendering
+// the same manifests again and again. A nil cache caches nothing.
+type manifestCache struct {
+	lock   sync.Mutex
+	files  map[string]cachedManifest
+	remote map[string][]byte
+
+	renderedKey string
+	rendered    kubectl.ManifestList
+}
+
+type cachedManifest struct {
+	modTime time.Time
+	size    int64
+	content []byte
+}
+
+func newManifestCache() *manifestCache {
+	return &manifestCache{
+		files:  map[string]cachedManifest{},
+		remote: map[string][]byte{},
+	}
+}
+
+// readFile returns the content of a manifest file. The file is only read
+// again if it was modified.
+func (c *manifestCache) readFile(path string) ([]byte, error) {
+	if c == nil {
+		return ioutil.ReadFile(path)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	cached, found := c.files[path]
+	c.lock.Unlock()
+	if found && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached.content, nil
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	c.files[path] = cachedManifest{modTime: info.ModTime(), size: info.Size(), content: content}
+	c.lock.Unlock()
+	return content, nil
+}
+
+// remoteManifest fetches a manifest from the cluster once. Later fetches
+// would return what skaffold applied, not the original manifest.
+func (c *manifestCache) remoteManifest(name string, fetch func() ([]byte, error)) ([]byte, error) {
+	if c == nil {
+		return fetch()
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if manifest, found := c.remote[name]; found {
+		return manifest, nil
+	}
+
+	manifest, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	c.remote[name] = manifest
+	return manifest, nil
+}
+
+// render replaces the images and applies the transforms, unless the manifests,
+// the builds and the default repo are the same as the last time.
+func (c *manifestCache) render(manifests kubectl.ManifestList, builds []build.Artifact, defaultRepo string) (kubectl.ManifestList, error) {
+	var key string
+	if c != nil {
+		key = renderKey(manifests, builds, defaultRepo)
+
+		c.lock.Lock()
+		rendered, found := c.rendered, c.renderedKey == key
+		c.lock.Unlock()
+		if found {
+			return rendered, nil
+		}
+	}
+
+	rendered, err := manifests.ReplaceImages(builds, defaultRepo)
+	if err != nil {
+		return nil, errors.Wrap(err, "replacing images in manifests")
+	}
+
+	rendered, err = applyManifestTransforms(rendered, builds)
+	if err != nil {
+		return nil, err
+	}
+
+	if c != nil {
+		c.lock.Lock()
+		c.renderedKey, c.rendered = key, rendered
+		c.lock.Unlock()
+	}
+	return rendered, nil
+}
+
+func renderKey(manifests kubectl.ManifestList, builds []build.Artifact, defaultRepo string) string {
+	hasher := sha256.New()
+	fmt.Fprintf(hasher, "%s\x00", defaultRepo)
+	for _, b := range builds {
+		fmt.Fprintf(hasher, "%s=%s\x00", b.ImageName, b.Tag)
+	}
+	for _, manifest := range manifests {
+		fmt.Fprintf(hasher, "%d\x00", len(manifest))
+		hasher.Write(manifest)
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
+}
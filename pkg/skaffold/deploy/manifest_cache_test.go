@@ -0,0 +1,90 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/kubectl"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestManifestCacheReadFile(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	modTime := time.Now().Add(-time.Hour)
+	tmpDir.Write("pod.yaml", "first").Chtimes("pod.yaml", modTime)
+	cache := newManifestCache()
+
+	content, err := cache.readFile(tmpDir.Path("pod.yaml"))
+	testutil.CheckErrorAndDeepEqual(t, false, err, "first", string(content))
+
+	// Same size and modification time: the file is not read again.
+	tmpDir.Write("pod.yaml", "other").Chtimes("pod.yaml", modTime)
+	content, err = cache.readFile(tmpDir.Path("pod.yaml"))
+	testutil.CheckErrorAndDeepEqual(t, false, err, "first", string(content))
+
+	tmpDir.Chtimes("pod.yaml", time.Now())
+	content, err = cache.readFile(tmpDir.Path("pod.yaml"))
+	testutil.CheckErrorAndDeepEqual(t, false, err, "other", string(content))
+}
+
+func TestManifestCacheRemoteManifest(t *testing.T) {
+	fetched := 0
+	fetch := func() ([]byte, error) {
+		fetched++
+		return []byte("manifest"), nil
+	}
+	cache := newManifestCache()
+
+	cache.remoteManifest("deployment/web", fetch)
+	content, err := cache.remoteManifest("deployment/web", fetch)
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, "manifest", string(content))
+	testutil.CheckDeepEqual(t, 1, fetched)
+}
+
+func TestManifestCacheRender(t *testing.T) {
+	manifests := kubectl.ManifestList{[]byte(`apiVersion: v1
+kind: Pod
+metadata:
+  name: getting-started
+spec:
+  containers:
+  - image: gcr.io/k8s-skaffold/example
+    name: example
+`)}
+	cache := newManifestCache()
+
+	render := func(tag string) string {
+		rendered, err := cache.render(manifests, []build.Artifact{{ImageName: "gcr.io/k8s-skaffold/example", Tag: tag}}, "")
+		testutil.CheckError(t, false, err)
+		return rendered.String()
+	}
+
+	first := render("gcr.io/k8s-skaffold/example:v1")
+	testutil.CheckDeepEqual(t, first, render("gcr.io/k8s-skaffold/example:v1"))
+	testutil.CheckDeepEqual(t, cache.renderedKey, renderKey(manifests, []build.Artifact{{ImageName: "gcr.io/k8s-skaffold/example", Tag: "gcr.io/k8s-skaffold/example:v1"}}, ""))
+
+	second := render("gcr.io/k8s-skaffold/example:v2")
+	if first == second {
+		t.Error("Expected the manifests to be rendered again with the new tag")
+	}
+}
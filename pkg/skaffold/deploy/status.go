@@ -0,0 +1,79 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// StatusCheck waits for the deployments found in the deploy results
+// to be rolled out. It returns an error as soon as one of them fails.
+func StatusCheck(ctx context.Context, out io.Writer, dRes []Artifact, timeout time.Duration) error {
+	var deployments []Artifact
+	for _, a := range dRes {
+		if a.Obj == nil || (*a.Obj).GetObjectKind().GroupVersionKind().Kind != "Deployment" {
+			continue
+		}
+		deployments = append(deployments, a)
+	}
+
+	if len(deployments) == 0 {
+		return nil
+	}
+
+	client, err := kubernetes.Client()
+	if err != nil {
+		return errors.Wrap(err, "getting kubernetes client")
+	}
+
+	for _, a := range deployments {
+		accessor, err := meta.Accessor(*a.Obj)
+		if err != nil {
+			return errors.Wrap(err, "reading deployment metadata")
+		}
+
+		name := accessor.GetName()
+		namespace, err := namespaceOf(a, accessor.GetNamespace())
+		if err != nil {
+			return err
+		}
+
+		color.Default.Fprintf(out, "Waiting for deployment %s to roll out...\n", name)
+		if err := kubernetes.WaitForDeploymentRollout(ctx, client, namespace, name, timeout); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// namespaceOf returns the namespace an object was deployed to: the one it declares,
+// the one it was deployed with, or the namespace of the current context.
+func namespaceOf(a Artifact, objectNamespace string) (string, error) {
+	if objectNamespace != "" {
+		return objectNamespace, nil
+	}
+	return kubectx.Namespace(a.Namespace)
+}
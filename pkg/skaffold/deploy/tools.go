@@ -0,0 +1,142 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"os/exec"
+	"regexp"
+
+	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// tool is a CLI that a deployer shells out to.
+type tool struct {
+	binary      string
+	wrapper     []string
+	versionArgs []string
+	installURL  string
+
+	// incompatible explains why a version is known not to work well with skaffold.
+	// It returns an empty string for compatible versions.
+	incompatible func(semver.Version) string
+}
+
+var (
+	kubectlTool = tool{
+		binary:      "kubectl",
+		versionArgs: []string{"version", "--client", "--short"},
+		installURL:  "https://kubernetes.io/docs/tasks/tools/install-kubectl/",
+		incompatible: func(v semver.Version) string {
+			if v.LT(semver.Version{Major: 1, Minor: 12}) {
+				return "kubectl version 1.12.0 or greater is recommended for use with skaffold"
+			}
+			return ""
+		},
+	}
+
+	helmTool = tool{
+		binary:      "helm",
+		versionArgs: []string{"version", "--client", "--short"},
+		installURL:  "https://docs.helm.sh/using_helm/#installing-helm",
+		incompatible: func(v semver.Version) string {
+			if v.Major >= 3 {
+				return "helm 3 is not supported yet, the helm deployer needs helm 2 and tiller"
+			}
+			return ""
+		},
+	}
+
+	kustomizeTool = tool{
+		binary:      "kustomize",
+		versionArgs: []string{"version"},
+		installURL:  "https://github.com/kubernetes-sigs/kustomize",
+	}
+)
+
+// for testing
+var lookPath = exec.LookPath
+
+var versionRegex = regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
+
+// CheckTools makes sure that the CLIs used by the deployers are installed.
+// It warns about versions that are known not to work well with skaffold.
+func CheckTools(ctx context.Context, cfg *latest.DeployConfig) error {
+	for _, tool := range requiredTools(cfg) {
+		// A wrapper, such as `tsh kubectl`, is responsible for finding the binary.
+		command := append(append([]string{}, tool.wrapper...), tool.binary)
+		command = append(command, tool.versionArgs...)
+
+		path, err := lookPath(command[0])
+		if err != nil {
+			return errors.Errorf("%s is required to deploy but wasn't found in PATH, see %s", command[0], tool.installURL)
+		}
+
+		version, err := toolVersion(ctx, path, command[1:])
+		if err != nil {
+			logrus.Warnf("Unable to get %s version: %s", tool.binary, err)
+			continue
+		}
+		logrus.Debugf("Using %s %s", tool.binary, version)
+
+		if tool.incompatible == nil {
+			continue
+		}
+		if reason := tool.incompatible(version); reason != "" {
+			if err := warnings.Warnf("%s %s: %s", tool.binary, version, reason); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func requiredTools(cfg *latest.DeployConfig) []tool {
+	kubectlTool := kubectlTool
+	kubectlTool.binary, kubectlTool.wrapper = kubectx.Kubectl()
+
+	switch {
+	case cfg.HelmDeploy != nil:
+		return []tool{kubectlTool, helmTool}
+	case cfg.KubectlDeploy != nil:
+		return []tool{kubectlTool}
+	case cfg.KustomizeDeploy != nil:
+		return []tool{kubectlTool, kustomizeTool}
+	default:
+		return nil
+	}
+}
+
+func toolVersion(ctx context.Context, path string, args []string) (semver.Version, error) {
+	out, err := util.RunCmdOut(exec.CommandContext(ctx, path, args...))
+	if err != nil {
+		return semver.Version{}, err
+	}
+
+	match := versionRegex.FindSubmatch(out)
+	if match == nil {
+		return semver.Version{}, errors.Errorf("no version found in %q", out)
+	}
+	return semver.Parse(string(match[1]))
+}
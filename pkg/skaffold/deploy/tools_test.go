@@ -0,0 +1,138 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"testing"
+
+	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestCheckTools(t *testing.T) {
+	var tests = []struct {
+		description string
+		cfg         latest.DeployConfig
+		kubectl     string
+		wrapper     string
+		installed   bool
+		missing     string
+		command     util.Command
+		strict      bool
+		shouldErr   bool
+	}{
+		{
+			description: "recent kubectl",
+			cfg:         latest.DeployConfig{DeployType: latest.DeployType{KubectlDeploy: &latest.KubectlDeploy{}}},
+			installed:   true,
+			command:     testutil.NewFakeCmdOut("kubectl version --client --short", "Client Version: v1.13.1", nil),
+		},
+		{
+			description: "pinned kubectl",
+			cfg:         latest.DeployConfig{DeployType: latest.DeployType{KubectlDeploy: &latest.KubectlDeploy{}}},
+			kubectl:     "/opt/kubectl",
+			installed:   true,
+			command:     testutil.NewFakeCmdOut("/opt/kubectl version --client --short", "Client Version: v1.13.1", nil),
+		},
+		{
+			description: "wrapped kubectl",
+			cfg:         latest.DeployConfig{DeployType: latest.DeployType{KubectlDeploy: &latest.KubectlDeploy{}}},
+			wrapper:     "tsh",
+			installed:   true,
+			command:     testutil.NewFakeCmdOut("tsh kubectl version --client --short", "Client Version: v1.13.1", nil),
+		},
+		{
+			description: "missing kubectl",
+			cfg:         latest.DeployConfig{DeployType: latest.DeployType{KubectlDeploy: &latest.KubectlDeploy{}}},
+			shouldErr:   true,
+		},
+		{
+			description: "old kubectl",
+			cfg:         latest.DeployConfig{DeployType: latest.DeployType{KubectlDeploy: &latest.KubectlDeploy{}}},
+			installed:   true,
+			command:     testutil.NewFakeCmdOut("kubectl version --client --short", "Client Version: v1.10.3", nil),
+		},
+		{
+			description: "old kubectl in strict mode",
+			cfg:         latest.DeployConfig{DeployType: latest.DeployType{KubectlDeploy: &latest.KubectlDeploy{}}},
+			installed:   true,
+			command:     testutil.NewFakeCmdOut("kubectl version --client --short", "Client Version: v1.10.3", nil),
+			strict:      true,
+			shouldErr:   true,
+		},
+		{
+			description: "helm 2",
+			cfg:         latest.DeployConfig{DeployType: latest.DeployType{HelmDeploy: &latest.HelmDeploy{}}},
+			installed:   true,
+			command:     testutil.NewFakeCmdOut("helm version --client --short", "Client: v2.11.0+g2e55dbe", nil),
+			strict:      true,
+		},
+		{
+			description: "helm 3",
+			cfg:         latest.DeployConfig{DeployType: latest.DeployType{HelmDeploy: &latest.HelmDeploy{}}},
+			installed:   true,
+			command:     testutil.NewFakeCmdOut("helm version --client --short", "v3.0.0-alpha.1+gb6fd4b5", nil),
+			strict:      true,
+			shouldErr:   true,
+		},
+		{
+			description: "unknown version",
+			cfg:         latest.DeployConfig{DeployType: latest.DeployType{HelmDeploy: &latest.HelmDeploy{}}},
+			installed:   true,
+			command:     testutil.NewFakeCmdOut("helm version --client --short", "", fmt.Errorf("tiller not found")),
+			strict:      true,
+		},
+		{
+			description: "helm without kubectl",
+			cfg:         latest.DeployConfig{DeployType: latest.DeployType{HelmDeploy: &latest.HelmDeploy{}}},
+			installed:   true,
+			missing:     "kubectl",
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+			util.DefaultExecCommand = test.command
+
+			defer func(l func(string) (string, error)) { lookPath = l }(lookPath)
+			lookPath = func(binary string) (string, error) {
+				if !test.installed || binary == test.missing {
+					return "", &exec.Error{Name: binary, Err: exec.ErrNotFound}
+				}
+				return binary, nil
+			}
+
+			defer kubectx.SetKubectl("", "")
+			kubectx.SetKubectl(test.kubectl, test.wrapper)
+
+			defer warnings.SetStrict(false)
+			warnings.SetStrict(test.strict)
+
+			err := CheckTools(context.Background(), &test.cfg)
+
+			testutil.CheckError(t, test.shouldErr, err)
+		})
+	}
+}
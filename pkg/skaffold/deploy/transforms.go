@@ -0,0 +1,70 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/kubectl"
+	"github.com/pkg/errors"
+)
+
+// ManifestTransform modifies manifests before they are deployed.
+type ManifestTransform func(l kubectl.ManifestList, builds []build.Artifact) (kubectl.ManifestList, error)
+
+var manifestTransforms []ManifestTransform
+
+// AddManifestTransform registers a transform applied to the manifests
+// deployed by kubectl and kustomize.
+func AddManifestTransform(transform ManifestTransform) {
+	manifestTransforms = append(manifestTransforms, transform)
+}
+
+func applyManifestTransforms(manifests kubectl.ManifestList, builds []build.Artifact) (kubectl.ManifestList, error) {
+	var err error
+	for _, transform := range manifestTransforms {
+		manifests, err = transform(manifests, builds)
+		if err != nil {
+			return nil, errors.Wrap(err, "transforming manifests")
+		}
+	}
+
+	return manifests, nil
+}
+
+// renderManifests replaces the images and applies the transforms like a deploy
+// would, then writes the manifests and returns the objects they describe.
+func renderManifests(out io.Writer, namespace string, manifests kubectl.ManifestList, builds []build.Artifact, defaultRepo string) ([]Artifact, error) {
+	if len(manifests) == 0 {
+		return nil, nil
+	}
+
+	manifests, err := manifests.ReplaceImages(builds, defaultRepo)
+	if err != nil {
+		return nil, errors.Wrap(err, "replacing images in manifests")
+	}
+
+	manifests, err = applyManifestTransforms(manifests, builds)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(out, "%s\n---\n", manifests.String())
+	return parseManifestsForDeploys(namespace, manifests)
+}
@@ -23,16 +23,22 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/kubernetes/scheme"
 )
 
+// parseRuntimeObject decodes a deployed object. Objects that declare their own
+// namespace keep it, the others are considered deployed to the given namespace.
 func parseRuntimeObject(namespace string, b []byte) (Artifact, error) {
 	d := scheme.Codecs.UniversalDeserializer()
 	obj, _, err := d.Decode(b, nil, nil)
 	if err != nil {
 		return Artifact{}, fmt.Errorf("error decoding parsed yaml: %s", err.Error())
 	}
+	if accessor, err := meta.Accessor(obj); err == nil && accessor.GetNamespace() != "" {
+		namespace = accessor.GetNamespace()
+	}
 	return Artifact{
 		Obj:       &obj,
 		Namespace: namespace,
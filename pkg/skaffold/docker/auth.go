@@ -23,12 +23,12 @@ import (
 	"os"
 	"path/filepath"
 
-	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/gcp"
 	"github.com/docker/cli/cli/config"
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/pkg/homedir"
 	"github.com/docker/docker/registry"
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
@@ -37,27 +37,22 @@ const (
 	configFileDir = ".docker"
 )
 
-var (
-	// DefaultAuthHelper is exposed so that other packages can override it for testing
-	DefaultAuthHelper AuthConfigHelper
-	configDir         = os.Getenv("DOCKER_CONFIG")
-)
+var configDir = os.Getenv("DOCKER_CONFIG")
 
 func init() {
-	DefaultAuthHelper = credsHelper{}
 	if configDir == "" {
 		configDir = filepath.Join(homedir.Get(), configFileDir)
 	}
 }
 
-// AuthConfigHelper exists for testing purposes since GetAuthConfig shells out
-// to native store helpers.
-// Ideally this shouldn't be public, but the LocalBuilder needs to use it.
+// AuthConfigHelper is a source of registry credentials.
 type AuthConfigHelper interface {
 	GetAuthConfig(registry string) (types.AuthConfig, error)
 	GetAllAuthConfigs() (map[string]types.AuthConfig, error)
 }
 
+// credsHelper gets the credentials the same way docker does: with docker's
+// config.json auths, global store and per-registry credential helpers.
 type credsHelper struct{}
 
 func (credsHelper) GetAuthConfig(registry string) (types.AuthConfig, error) {
@@ -66,8 +61,6 @@ func (credsHelper) GetAuthConfig(registry string) (types.AuthConfig, error) {
 		return types.AuthConfig{}, errors.Wrap(err, "docker config")
 	}
 
-	gcp.AutoConfigureGCRCredentialHelper(cf, registry)
-
 	return cf.GetAuthConfig(registry)
 }
 
@@ -82,28 +75,35 @@ func (credsHelper) GetAllAuthConfigs() (map[string]types.AuthConfig, error) {
 	return cf.GetCredentialsStore("").GetAll()
 }
 
-func encodedRegistryAuth(ctx context.Context, cli APIClient, a AuthConfigHelper, image string) (string, error) {
-	ref, err := reference.ParseNormalizedNamed(image)
-	if err != nil {
-		return "", errors.Wrap(err, "parsing image name for registry")
+func authenticator(ac types.AuthConfig) authn.Authenticator {
+	switch {
+	case ac.RegistryToken != "":
+		return &authn.Bearer{Token: ac.RegistryToken}
+	case ac.IdentityToken != "":
+		// Registries such as ACR accept the identity token as a password.
+		return &authn.Basic{Username: ac.Username, Password: ac.IdentityToken}
+	case ac.Username != "" || ac.Password != "":
+		return &authn.Basic{Username: ac.Username, Password: ac.Password}
+	default:
+		return authn.Anonymous
 	}
+}
 
-	repoInfo, err := registry.ParseRepositoryInfo(ref)
+func encodedRegistryAuth(ctx context.Context, cli APIClient, a AuthConfigHelper, image string) (string, error) {
+	configKey, err := registryConfigKey(ctx, cli, image)
 	if err != nil {
 		return "", err
 	}
 
-	index := repoInfo.Index
-	configKey := index.Name
-	if index.Official {
-		configKey = officialRegistry(ctx, cli)
-	}
-
 	ac, err := a.GetAuthConfig(configKey)
 	if err != nil {
 		return "", errors.Wrap(err, "getting auth config")
 	}
+	return encodeAuthConfig(ac)
+}
 
+// encodeAuthConfig encodes credentials the way the docker API expects them.
+func encodeAuthConfig(ac types.AuthConfig) (string, error) {
 	buf, err := json.Marshal(ac)
 	if err != nil {
 		return "", err
@@ -112,6 +112,25 @@ func encodedRegistryAuth(ctx context.Context, cli APIClient, a AuthConfigHelper,
 	return base64.URLEncoding.EncodeToString(buf), nil
 }
 
+// registryConfigKey is the key under which docker stores the credentials of an image's registry.
+func registryConfigKey(ctx context.Context, cli APIClient, image string) (string, error) {
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", errors.Wrap(err, "parsing image name for registry")
+	}
+
+	repoInfo, err := registry.ParseRepositoryInfo(ref)
+	if err != nil {
+		return "", err
+	}
+
+	index := repoInfo.Index
+	if index.Official {
+		return officialRegistry(ctx, cli), nil
+	}
+	return index.Name, nil
+}
+
 func officialRegistry(ctx context.Context, cli APIClient) string {
 	serverAddress := registry.IndexServer
 
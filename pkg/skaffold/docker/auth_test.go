@@ -23,6 +23,8 @@ import (
 
 	"github.com/GoogleContainerTools/skaffold/testutil"
 	"github.com/docker/docker/api/types"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
 )
 
 type testAuthHelper struct {
@@ -75,11 +77,75 @@ func TestGetEncodedRegistryAuth(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.description, func(t *testing.T) {
-			defer func(h AuthConfigHelper) { DefaultAuthHelper = h }(DefaultAuthHelper)
-			DefaultAuthHelper = test.authType
-
 			out, err := encodedRegistryAuth(context.Background(), nil, test.authType, test.image)
 			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, out)
 		})
 	}
 }
+
+type recordingAuthHelper struct {
+	authConfig types.AuthConfig
+	registries []string
+}
+
+func (r *recordingAuthHelper) GetAuthConfig(registry string) (types.AuthConfig, error) {
+	r.registries = append(r.registries, registry)
+	return r.authConfig, nil
+}
+
+func (r *recordingAuthHelper) GetAllAuthConfigs() (map[string]types.AuthConfig, error) {
+	return nil, nil
+}
+
+func TestKeychain(t *testing.T) {
+	var tests = []struct {
+		description  string
+		image        string
+		authConfig   types.AuthConfig
+		expectedKey  string
+		expectedAuth authn.Authenticator
+	}{
+		{
+			description:  "basic auth",
+			image:        "harbor.example.com/project/image",
+			authConfig:   types.AuthConfig{Username: "user", Password: "password"},
+			expectedKey:  "harbor.example.com",
+			expectedAuth: &authn.Basic{Username: "user", Password: "password"},
+		},
+		{
+			description:  "identity token",
+			image:        "registry.azurecr.io/image",
+			authConfig:   types.AuthConfig{Username: "00000000-0000-0000-0000-000000000000", IdentityToken: "token"},
+			expectedKey:  "registry.azurecr.io",
+			expectedAuth: &authn.Basic{Username: "00000000-0000-0000-0000-000000000000", Password: "token"},
+		},
+		{
+			description:  "registry token",
+			image:        "123456789.dkr.ecr.us-east-1.amazonaws.com/image",
+			authConfig:   types.AuthConfig{RegistryToken: "token"},
+			expectedKey:  "123456789.dkr.ecr.us-east-1.amazonaws.com",
+			expectedAuth: &authn.Bearer{Token: "token"},
+		},
+		{
+			description:  "docker hub",
+			image:        "library/busybox",
+			expectedKey:  "https://index.docker.io/v1/",
+			expectedAuth: authn.Anonymous,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			helper := &recordingAuthHelper{authConfig: test.authConfig}
+			keychain := NewKeychain(helper)
+
+			ref, err := name.ParseReference(test.image, name.WeakValidation)
+			testutil.CheckError(t, false, err)
+
+			auth, err := keychain.Resolve(ref.Context().Registry)
+
+			testutil.CheckErrorAndDeepEqual(t, false, err, test.expectedAuth, auth)
+			testutil.CheckDeepEqual(t, []string{test.expectedKey}, helper.registries)
+		})
+	}
+}
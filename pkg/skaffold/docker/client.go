@@ -25,10 +25,11 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 
-	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/cluster"
 	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/docker/docker/api"
@@ -45,9 +46,21 @@ type APIClient interface {
 var (
 	dockerAPIClientOnce sync.Once
 	dockerAPIClient     APIClient
+	dockerAPIClientEnv  []string
 	dockerAPIClientErr  error
+
+	localCluster      *bool
+	localClusterRules []cluster.Rule
 )
 
+// SetLocalCluster configures how the cluster of the current kube-context is detected,
+// like for the builder, to know if images are built by the docker daemon of minikube.
+// It has to be called before the client is created.
+func SetLocalCluster(local *bool, rules []cluster.Rule) {
+	localCluster = local
+	localClusterRules = rules
+}
+
 // NewAPIClient guesses the docker client to use based on current kubernetes context.
 func NewAPIClient() (APIClient, error) {
 	dockerAPIClientOnce.Do(func() {
@@ -57,25 +70,53 @@ func NewAPIClient() (APIClient, error) {
 			return
 		}
 
-		dockerAPIClient, dockerAPIClientErr = newAPIClient(kubeContext)
+		dockerAPIClient, dockerAPIClientEnv, dockerAPIClientErr = newAPIClient(kubeContext)
 	})
 
 	return dockerAPIClient, dockerAPIClientErr
 }
 
+// DaemonEnv returns the environment variables that point the docker CLI
+// to the daemon used by the API client, for example the minikube daemon.
+// It's empty when the daemon is configured by the user's environment.
+func DaemonEnv() []string {
+	return dockerAPIClientEnv
+}
+
 // newAPIClient guesses the docker client to use based on current kubernetes context.
-func newAPIClient(kubeContext string) (APIClient, error) {
-	if kubeContext == constants.DefaultMinikubeContext {
+func newAPIClient(kubeContext string) (APIClient, []string, error) {
+	if cluster.Detect(kubeContext, localCluster, localClusterRules).Type == cluster.Minikube {
 		return newMinikubeAPIClient()
 	}
-	return newEnvAPIClient()
+
+	cli, err := newEnvAPIClient()
+	return cli, nil, err
 }
 
-// newEnvAPIClient returns a docker client based on the environment variables set.
+// newEnvAPIClient returns a docker client based on the environment variables set
+// or, if DOCKER_HOST is not set, on the current docker context.
 // It will "negotiate" the highest possible API version supported by both the client
 // and the server if there is a mismatch.
 func newEnvAPIClient() (APIClient, error) {
-	cli, err := client.NewClientWithOpts(client.FromEnv)
+	opts := []func(*client.Client) error{client.FromEnv}
+
+	if os.Getenv("DOCKER_HOST") == "" {
+		dockerContext, err := currentDockerContext()
+		if err != nil {
+			return nil, err
+		}
+		if dockerContext != nil {
+			logrus.Debugf("Using docker context %s: %s", dockerContext.name, dockerContext.host)
+
+			contextOpts, err := dockerContext.clientOpts()
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, contextOpts...)
+		}
+	}
+
+	cli, err := client.NewClientWithOpts(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("error getting docker client: %s", err)
 	}
@@ -85,12 +126,14 @@ func newEnvAPIClient() (APIClient, error) {
 }
 
 // newMinikubeAPIClient returns a docker client using the environment variables
-// provided by minikube.
-func newMinikubeAPIClient() (APIClient, error) {
+// provided by minikube, along with those variables for the docker CLI.
+// Unless minikube pins the API version, it's negotiated with the daemon.
+func newMinikubeAPIClient() (APIClient, []string, error) {
 	env, err := getMinikubeDockerEnv()
 	if err != nil {
 		logrus.Warnf("Could not get minikube docker env, falling back to local docker daemon: %s", err)
-		return newEnvAPIClient()
+		cli, err := newEnvAPIClient()
+		return cli, nil, err
 	}
 
 	var httpclient *http.Client
@@ -103,7 +146,7 @@ func newMinikubeAPIClient() (APIClient, error) {
 		}
 		tlsc, err := tlsconfig.Client(options)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		httpclient = &http.Client{
@@ -123,7 +166,25 @@ func newMinikubeAPIClient() (APIClient, error) {
 		version = api.DefaultVersion
 	}
 
-	return client.NewClient(host, version, httpclient, nil)
+	cli, err := client.NewClient(host, version, httpclient, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if env["DOCKER_API_VERSION"] == "" {
+		cli.NegotiateAPIVersion(context.Background())
+	}
+
+	return cli, envList(env), nil
+}
+
+// envList turns environment variables into a sorted list of KEY=VALUE pairs.
+func envList(env map[string]string) []string {
+	var list []string
+	for k, v := range env {
+		list = append(list, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(list)
+	return list
 }
 
 func detectWsl() (bool, error) {
@@ -19,6 +19,7 @@ package docker
 import (
 	"testing"
 
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/cluster"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/GoogleContainerTools/skaffold/testutil"
 )
@@ -61,8 +62,8 @@ func TestNewMinikubeImageAPIClient(t *testing.T) {
 		description string
 		cmd         util.Command
 
-		expected  APIClient
-		shouldErr bool
+		expectedEnv []string
+		shouldErr   bool
 	}{
 		{
 			description: "correct client",
@@ -70,6 +71,7 @@ func TestNewMinikubeImageAPIClient(t *testing.T) {
 DOCKER_HOST=http://127.0.0.1:8080
 DOCKER_CERT_PATH=testdata
 DOCKER_API_VERSION=1.23`, nil),
+			expectedEnv: []string{"DOCKER_API_VERSION=1.23", "DOCKER_CERT_PATH=testdata", "DOCKER_HOST=http://127.0.0.1:8080", "DOCKER_TLS_VERIFY=1"},
 		},
 		{
 			description: "correct client",
@@ -84,15 +86,17 @@ DOCKER_API_VERSION=1.23`, nil),
 			cmd: testutil.NewFakeCmdOut("minikube docker-env --shell none", `DOCKER_TLS_VERIFY=1
 DOCKER_CERT_PATH=testdata
 DOCKER_API_VERSION=1.23`, nil),
+			expectedEnv: []string{"DOCKER_API_VERSION=1.23", "DOCKER_CERT_PATH=testdata", "DOCKER_TLS_VERIFY=1"},
 		},
 		{
-			description: "missing version env, no error",
+			description: "missing version env, negotiate version",
 			cmd: testutil.NewFakeCmdOut("minikube docker-env --shell none", `DOCKER_TLS_VERIFY=1
 DOCKER_HOST=http://127.0.0.1:8080
 DOCKER_CERT_PATH=testdata`, nil),
+			expectedEnv: []string{"DOCKER_CERT_PATH=testdata", "DOCKER_HOST=http://127.0.0.1:8080", "DOCKER_TLS_VERIFY=1"},
 		},
 		{
-			description: "missing version env, no error",
+			description: "bad host",
 			cmd: testutil.NewFakeCmdOut("minikube docker-env --shell none", `DOCKER_TLS_VERIFY=1
 DOCKER_HOST=badurl
 DOCKER_CERT_PATH=testdata
@@ -113,8 +117,23 @@ DOCKER_API_VERSION=1.23`, nil),
 			defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
 			util.DefaultExecCommand = test.cmd
 
-			_, err := newMinikubeAPIClient()
-			testutil.CheckError(t, test.shouldErr, err)
+			_, env, err := newMinikubeAPIClient()
+
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expectedEnv, env)
 		})
 	}
 }
+
+func TestNewAPIClientDetectsMinikube(t *testing.T) {
+	defer SetLocalCluster(nil, nil)
+	defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+	util.DefaultExecCommand = testutil.NewFakeCmdOut("minikube docker-env --shell none", "DOCKER_HOST=http://127.0.0.1:8080\n", nil)
+
+	_, env, err := newAPIClient("minikube")
+	testutil.CheckErrorAndDeepEqual(t, false, err, []string{"DOCKER_HOST=http://127.0.0.1:8080"}, env)
+
+	// A rule makes the context a custom local cluster that uses the host's daemon.
+	SetLocalCluster(nil, []cluster.Rule{{Pattern: "minikube"}})
+	_, env, err = newAPIClient("minikube")
+	testutil.CheckErrorAndDeepEqual(t, false, err, []string(nil), env)
+}
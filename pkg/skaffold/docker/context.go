@@ -17,15 +17,28 @@ limitations under the License.
 package docker
 
 import (
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
 	"io"
+	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	cstorage "cloud.google.com/go/storage"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/gcp"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/api/iterator"
 )
 
 // NormalizeDockerfilePath returns the absolute path to the dockerfile.
@@ -40,6 +53,9 @@ func NormalizeDockerfilePath(context, dockerfile string) (string, error) {
 	return filepath.Abs(dockerfile)
 }
 
+// CreateDockerTarContext creates a tar of the build context with only the files
+// that docker build would see: those used by the Dockerfile and not excluded by the
+// .dockerignore, or the Dockerfile's own ignore file.
 func CreateDockerTarContext(ctx context.Context, w io.Writer, workspace string, a *latest.DockerArtifact) error {
 	paths, err := GetDependencies(ctx, workspace, a)
 	if err != nil {
@@ -53,6 +69,7 @@ func CreateDockerTarContext(ctx context.Context, w io.Writer, workspace string,
 	return nil
 }
 
+// CreateDockerTarGzContext is like CreateDockerTarContext but compresses the tar.
 func CreateDockerTarGzContext(ctx context.Context, w io.Writer, workspace string, a *latest.DockerArtifact) error {
 	paths, err := GetDependencies(ctx, workspace, a)
 	if err != nil {
@@ -66,16 +83,266 @@ func CreateDockerTarGzContext(ctx context.Context, w io.Writer, workspace string
 	return nil
 }
 
+// ContextDigest computes a digest of the files in the build context, and their content.
+// Google Cloud Build names the uploaded sources after it, to skip uploading unchanged sources.
+func ContextDigest(ctx context.Context, workspace string, a *latest.DockerArtifact) (string, error) {
+	paths, err := GetDependencies(ctx, workspace, a)
+	if err != nil {
+		return "", errors.Wrap(err, "getting relative tar paths")
+	}
+
+	return digestFiles(workspace, paths)
+}
+
+// digestFiles computes a digest of the files' paths, modes and content.
+func digestFiles(workspace string, paths []string) (string, error) {
+	paths = append([]string(nil), paths...)
+	sort.Strings(paths)
+
+	hasher := sha256.New()
+	for _, p := range paths {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(workspace, p)
+		}
+
+		fi, err := os.Lstat(p)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(hasher, "%s %s %d\n", filepath.ToSlash(p), fi.Mode(), fi.Size())
+
+		if !fi.Mode().IsRegular() {
+			continue
+		}
+		if err := hashFile(hasher, p); err != nil {
+			return "", errors.Wrapf(err, "hashing %s", p)
+		}
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+func hashFile(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// contextChunks is the number of chunks the build context is split into.
+// GCS can compose up to 32 objects: the chunks and the end-of-archive marker.
+const contextChunks = 31
+
+// chunksPrefix is where the chunks of the build contexts are stored.
+const chunksPrefix = "source/chunks/"
+
+// chunkRetention is how long a chunk that no build used is kept.
+// The chunks that are reused are touched, at most once per half retention period.
+const chunkRetention = 7 * 24 * time.Hour
+
+// GCSUploadOptions tune the uploads of build contexts to GCS.
+type GCSUploadOptions struct {
+	// CompressionLevel is the gzip level, from 1 (fastest) to 9 (smallest).
+	// Zero uses the default level.
+	CompressionLevel int
+	// ChunkSizeMB is the size of the requests chunks are uploaded with.
+	// Zero uses the default of the GCS client.
+	ChunkSizeMB int
+	// Parallelism is the number of chunks uploaded concurrently.
+	Parallelism int
+}
+
+const defaultGCSUploadParallelism = 4
+
+var gcsUploadOptions = GCSUploadOptions{Parallelism: defaultGCSUploadParallelism}
+
+// SetGCSUploadOptions configures the uploads of build contexts to GCS.
+func SetGCSUploadOptions(opts GCSUploadOptions) error {
+	if opts.CompressionLevel < 0 || opts.CompressionLevel > gzip.BestCompression {
+		return fmt.Errorf("invalid compression level %d, expected a value between %d and %d", opts.CompressionLevel, gzip.BestSpeed, gzip.BestCompression)
+	}
+	if opts.ChunkSizeMB < 0 {
+		return fmt.Errorf("invalid chunk size %d", opts.ChunkSizeMB)
+	}
+	if opts.Parallelism < 0 {
+		return fmt.Errorf("invalid upload parallelism %d", opts.Parallelism)
+	}
+
+	if opts.Parallelism == 0 {
+		opts.Parallelism = defaultGCSUploadParallelism
+	}
+	gcsUploadOptions = opts
+	return nil
+}
+
+// UploadContextToGCS uploads the build context as a tar.gz. The context is split
+// into chunks, stored under content-addressed names, so that only the chunks holding
+// modified files are uploaded again. The chunks are uploaded in parallel then
+// composed into objectName. The chunks that weren't used for a while are then deleted.
 func UploadContextToGCS(ctx context.Context, workspace string, a *latest.DockerArtifact, bucket, objectName string) error {
-	c, err := cstorage.NewClient(ctx)
+	c, err := gcp.StorageClient()
 	if err != nil {
-		return errors.Wrap(err, "creating GCS client")
+		return err
 	}
-	defer c.Close()
 
-	w := c.Bucket(bucket).Object(objectName).NewWriter(ctx)
-	if err := CreateDockerTarGzContext(ctx, w, workspace, a); err != nil {
-		return errors.Wrap(err, "uploading targz to google storage")
+	paths, err := GetDependencies(ctx, workspace, a)
+	if err != nil {
+		return errors.Wrap(err, "getting relative tar paths")
+	}
+
+	chunks, err := splitContext(workspace, paths)
+	if err != nil {
+		return errors.Wrap(err, "splitting context")
+	}
+
+	opts := gcsUploadOptions
+	level := opts.CompressionLevel
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+
+	b := c.Bucket(bucket)
+	sources := make([]*cstorage.ObjectHandle, len(chunks))
+	var uploaded int32
+	var g errgroup.Group
+	sem := make(chan bool, opts.Parallelism)
+	used := map[string]bool{}
+	for i := range chunks {
+		chunk := chunks[i]
+		name := fmt.Sprintf("%s%s.tar.gz", chunksPrefix, chunk.digest)
+		object := b.Object(name)
+		sources[i] = object
+		used[name] = true
+
+		g.Go(func() error {
+			sem <- true
+			defer func() { <-sem }()
+
+			if attrs, err := object.Attrs(ctx); err == nil {
+				touchChunk(ctx, object, attrs)
+				return nil
+			}
+			if err := uploadToGCS(ctx, object, opts.ChunkSizeMB, func(w io.Writer) error {
+				return util.CreateTarGzChunk(w, workspace, chunk.paths, level)
+			}); err != nil {
+				return errors.Wrap(err, "uploading targz chunk to google storage")
+			}
+			atomic.AddInt32(&uploaded, 1)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	endOfArchiveName := chunksPrefix + "end-of-archive.tar.gz"
+	endOfArchive := b.Object(endOfArchiveName)
+	used[endOfArchiveName] = true
+	if _, err := endOfArchive.Attrs(ctx); err != nil {
+		if err := uploadToGCS(ctx, endOfArchive, opts.ChunkSizeMB, util.TarGzEndOfArchive); err != nil {
+			return errors.Wrap(err, "uploading end of archive to google storage")
+		}
+	}
+	sources = append(sources, endOfArchive)
+	logrus.Debugf("Uploaded %d of %d chunks of the build context", uploaded, len(chunks))
+
+	if _, err := b.Object(objectName).ComposerFrom(sources...).Run(ctx); err != nil {
+		return errors.Wrap(err, "composing targz chunks")
+	}
+
+	deleteExpiredChunks(ctx, b, used)
+	return nil
+}
+
+// touchChunk updates a chunk that's reused, so that it doesn't expire.
+func touchChunk(ctx context.Context, object *cstorage.ObjectHandle, attrs *cstorage.ObjectAttrs) {
+	if time.Since(attrs.Updated) < chunkRetention/2 {
+		return
+	}
+
+	if _, err := object.Update(ctx, cstorage.ObjectAttrsToUpdate{
+		Metadata: map[string]string{"last-used": time.Now().UTC().Format(time.RFC3339)},
+	}); err != nil {
+		logrus.Debugf("Unable to touch chunk %s: %s", attrs.Name, err)
+	}
+}
+
+// deleteExpiredChunks deletes the chunks that weren't uploaded or used for longer than
+// the retention period. This is best effort: the errors are only logged.
+func deleteExpiredChunks(ctx context.Context, b *cstorage.BucketHandle, used map[string]bool) {
+	it := b.Objects(ctx, &cstorage.Query{Prefix: chunksPrefix})
+	deleted := 0
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			logrus.Debugln("Unable to list the chunks of build contexts:", err)
+			return
+		}
+
+		if !isExpiredChunk(attrs, used, time.Now()) {
+			continue
+		}
+		if err := b.Object(attrs.Name).Delete(ctx); err != nil && err != cstorage.ErrObjectNotExist {
+			logrus.Debugf("Unable to delete chunk %s: %s", attrs.Name, err)
+			continue
+		}
+		deleted++
+	}
+	logrus.Debugf("Deleted %d expired chunks of build contexts", deleted)
+}
+
+// isExpiredChunk says if a chunk can be deleted.
+func isExpiredChunk(attrs *cstorage.ObjectAttrs, used map[string]bool, now time.Time) bool {
+	return !used[attrs.Name] && now.Sub(attrs.Updated) > chunkRetention
+}
+
+func uploadToGCS(ctx context.Context, object *cstorage.ObjectHandle, chunkSizeMB int, write func(io.Writer) error) error {
+	w := object.NewWriter(ctx)
+	if chunkSizeMB > 0 {
+		w.ChunkSize = chunkSizeMB << 20
+	}
+	if err := write(w); err != nil {
+		w.Close()
+		return err
 	}
 	return w.Close()
 }
+
+type contextChunk struct {
+	paths  []string
+	digest string
+}
+
+// splitContext spreads the files into chunks by hashing their paths, so that
+// a file always lands in the same chunk. Empty chunks are skipped.
+func splitContext(workspace string, paths []string) ([]contextChunk, error) {
+	buckets := make([][]string, contextChunks)
+	for _, p := range paths {
+		h := fnv.New32a()
+		h.Write([]byte(filepath.ToSlash(p)))
+		i := h.Sum32() % contextChunks
+		buckets[i] = append(buckets[i], p)
+	}
+
+	var chunks []contextChunk
+	for _, bucket := range buckets {
+		if len(bucket) == 0 {
+			continue
+		}
+		sort.Strings(bucket)
+
+		digest, err := digestFiles(workspace, bucket)
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, contextChunk{paths: bucket, digest: digest})
+	}
+	return chunks, nil
+}
@@ -19,9 +19,12 @@ package docker
 import (
 	"archive/tar"
 	"context"
+	"fmt"
 	"io"
 	"testing"
+	"time"
 
+	cstorage "cloud.google.com/go/storage"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/testutil"
 )
@@ -86,3 +89,159 @@ func TestDockerContext(t *testing.T) {
 		t.Error("File Dockerfile should have been included, but was not")
 	}
 }
+
+func TestContextDigest(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	imageFetcher := fakeImageFetcher{}
+	RetrieveImage = imageFetcher.fetch
+	defer func() { RetrieveImage = retrieveImage }()
+
+	artifact := &latest.DockerArtifact{
+		DockerfilePath: "Dockerfile",
+	}
+
+	tmpDir.Write("Dockerfile", "FROM alpine\nCOPY . /files")
+	tmpDir.Write(".dockerignore", "ignored.txt")
+	tmpDir.Write("file.txt", "content")
+
+	digest := func() string {
+		digest, err := ContextDigest(context.Background(), tmpDir.Root(), artifact)
+		testutil.CheckError(t, false, err)
+		return digest
+	}
+
+	initial := digest()
+	testutil.CheckDeepEqual(t, initial, digest())
+
+	tmpDir.Write("ignored.txt", "ignored")
+	testutil.CheckDeepEqual(t, initial, digest())
+
+	tmpDir.Write("file.txt", "changed")
+	if digest() == initial {
+		t.Error("Digest should change when a file changes")
+	}
+}
+
+func TestSplitContext(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	var paths []string
+	for i := 0; i < 100; i++ {
+		path := fmt.Sprintf("src/file%d.txt", i)
+		tmpDir.Write(path, "content")
+		paths = append(paths, path)
+	}
+
+	split := func() map[string]string {
+		chunks, err := splitContext(tmpDir.Root(), paths)
+		testutil.CheckError(t, false, err)
+
+		digests := map[string]string{}
+		for _, chunk := range chunks {
+			for _, path := range chunk.paths {
+				digests[path] = chunk.digest
+			}
+		}
+		return digests
+	}
+
+	initial := split()
+	testutil.CheckDeepEqual(t, len(paths), len(initial))
+
+	tmpDir.Write("src/file42.txt", "changed")
+	changed := split()
+
+	for _, path := range paths {
+		sameChunk := initial[path] == initial["src/file42.txt"]
+		if sameChunk && changed[path] == initial[path] {
+			t.Errorf("Digest of the chunk holding %s should have changed", path)
+		}
+		if !sameChunk && changed[path] != initial[path] {
+			t.Errorf("Digest of the chunk holding %s shouldn't have changed", path)
+		}
+	}
+}
+
+func TestIsExpiredChunk(t *testing.T) {
+	now := time.Now()
+	used := map[string]bool{"source/chunks/used.tar.gz": true}
+
+	var tests = []struct {
+		description string
+		name        string
+		updated     time.Time
+		expected    bool
+	}{
+		{
+			description: "recent chunk",
+			name:        "source/chunks/recent.tar.gz",
+			updated:     now.Add(-time.Hour),
+			expected:    false,
+		},
+		{
+			description: "old chunk",
+			name:        "source/chunks/old.tar.gz",
+			updated:     now.Add(-8 * 24 * time.Hour),
+			expected:    true,
+		},
+		{
+			description: "old chunk used by this build",
+			name:        "source/chunks/used.tar.gz",
+			updated:     now.Add(-8 * 24 * time.Hour),
+			expected:    false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			attrs := &cstorage.ObjectAttrs{Name: test.name, Updated: test.updated}
+
+			testutil.CheckDeepEqual(t, test.expected, isExpiredChunk(attrs, used, now))
+		})
+	}
+}
+
+func TestSetGCSUploadOptions(t *testing.T) {
+	defer func(o GCSUploadOptions) { gcsUploadOptions = o }(gcsUploadOptions)
+
+	var tests = []struct {
+		description string
+		opts        GCSUploadOptions
+		shouldErr   bool
+		expected    GCSUploadOptions
+	}{
+		{
+			description: "defaults",
+			expected:    GCSUploadOptions{Parallelism: defaultGCSUploadParallelism},
+		},
+		{
+			description: "custom",
+			opts:        GCSUploadOptions{CompressionLevel: 1, ChunkSizeMB: 32, Parallelism: 16},
+			expected:    GCSUploadOptions{CompressionLevel: 1, ChunkSizeMB: 32, Parallelism: 16},
+		},
+		{
+			description: "invalid compression level",
+			opts:        GCSUploadOptions{CompressionLevel: 10},
+			shouldErr:   true,
+		},
+		{
+			description: "invalid parallelism",
+			opts:        GCSUploadOptions{Parallelism: -1},
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := SetGCSUploadOptions(test.opts)
+
+			testutil.CheckError(t, test.shouldErr, err)
+			if !test.shouldErr {
+				testutil.CheckDeepEqual(t, test.expected, gcsUploadOptions)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/tlsconfig"
+	"github.com/pkg/errors"
+)
+
+// dockerContext is the docker endpoint of a context created with `docker context create`.
+type dockerContext struct {
+	name          string
+	host          string
+	skipTLSVerify bool
+	tlsDir        string
+}
+
+// currentDockerContext reads the context selected with DOCKER_CONTEXT or `docker context use`.
+// It returns nil for the default context, that is configured with environment variables.
+func currentDockerContext() (*dockerContext, error) {
+	name := os.Getenv("DOCKER_CONTEXT")
+	if name == "" {
+		buf, err := ioutil.ReadFile(filepath.Join(configDir, "config.json"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, nil
+			}
+			return nil, errors.Wrap(err, "reading docker config")
+		}
+
+		var cfg struct {
+			CurrentContext string `json:"currentContext"`
+		}
+		if err := json.Unmarshal(buf, &cfg); err != nil {
+			return nil, errors.Wrap(err, "parsing docker config")
+		}
+		name = cfg.CurrentContext
+	}
+
+	if name == "" || name == "default" {
+		return nil, nil
+	}
+
+	// Contexts are stored in folders named after the digest of their name.
+	id := fmt.Sprintf("%x", sha256.Sum256([]byte(name)))
+	buf, err := ioutil.ReadFile(filepath.Join(configDir, "contexts", "meta", id, "meta.json"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading docker context %s", name)
+	}
+
+	var meta struct {
+		Endpoints map[string]struct {
+			Host          string
+			SkipTLSVerify bool
+		}
+	}
+	if err := json.Unmarshal(buf, &meta); err != nil {
+		return nil, errors.Wrapf(err, "parsing docker context %s", name)
+	}
+
+	endpoint, present := meta.Endpoints["docker"]
+	if !present || endpoint.Host == "" {
+		return nil, fmt.Errorf("docker context %s has no docker endpoint", name)
+	}
+	if strings.HasPrefix(endpoint.Host, "ssh://") {
+		return nil, fmt.Errorf("docker context %s uses an ssh endpoint, which is not supported. Set DOCKER_HOST instead", name)
+	}
+
+	return &dockerContext{
+		name:          name,
+		host:          endpoint.Host,
+		skipTLSVerify: endpoint.SkipTLSVerify,
+		tlsDir:        filepath.Join(configDir, "contexts", "tls", id, "docker"),
+	}, nil
+}
+
+// clientOpts configures a docker client to use the context's endpoint and TLS material.
+func (c *dockerContext) clientOpts() ([]func(*client.Client) error, error) {
+	var opts []func(*client.Client) error
+
+	options := tlsconfig.Options{
+		CAFile:             existingFile(filepath.Join(c.tlsDir, "ca.pem")),
+		CertFile:           existingFile(filepath.Join(c.tlsDir, "cert.pem")),
+		KeyFile:            existingFile(filepath.Join(c.tlsDir, "key.pem")),
+		InsecureSkipVerify: c.skipTLSVerify,
+	}
+	if options.CAFile != "" || options.CertFile != "" || c.skipTLSVerify {
+		tlsc, err := tlsconfig.Client(options)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading TLS material of docker context %s", c.name)
+		}
+
+		opts = append(opts, client.WithHTTPClient(&http.Client{
+			Transport:     &http.Transport{TLSClientConfig: tlsc},
+			CheckRedirect: client.CheckRedirect,
+		}))
+	}
+
+	return append(opts, client.WithHost(c.host)), nil
+}
+
+func existingFile(path string) string {
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func contextID(name string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(name)))
+}
+
+func TestCurrentDockerContext(t *testing.T) {
+	var tests = []struct {
+		description  string
+		envContext   string
+		config       string
+		meta         map[string]string
+		expectedHost string
+		shouldErr    bool
+	}{
+		{
+			description: "no config",
+		},
+		{
+			description: "default context",
+			config:      `{"currentContext":"default"}`,
+		},
+		{
+			description:  "current context",
+			config:       `{"currentContext":"remote"}`,
+			meta:         map[string]string{"remote": `{"Name":"remote","Endpoints":{"docker":{"Host":"tcp://10.0.0.1:2376"}}}`},
+			expectedHost: "tcp://10.0.0.1:2376",
+		},
+		{
+			description:  "DOCKER_CONTEXT takes precedence",
+			envContext:   "rootless",
+			config:       `{"currentContext":"remote"}`,
+			meta:         map[string]string{"rootless": `{"Name":"rootless","Endpoints":{"docker":{"Host":"unix:///run/user/1000/docker.sock"}}}`},
+			expectedHost: "unix:///run/user/1000/docker.sock",
+		},
+		{
+			description: "unknown context",
+			config:      `{"currentContext":"unknown"}`,
+			shouldErr:   true,
+		},
+		{
+			description: "ssh endpoint",
+			config:      `{"currentContext":"ssh"}`,
+			meta:        map[string]string{"ssh": `{"Name":"ssh","Endpoints":{"docker":{"Host":"ssh://user@host"}}}`},
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			tmpDir, cleanup := testutil.NewTempDir(t)
+			defer cleanup()
+			defer func(d string) { configDir = d }(configDir)
+			configDir = tmpDir.Root()
+			unsetEnvs := testutil.SetEnvs(t, map[string]string{"DOCKER_CONTEXT": test.envContext})
+			defer unsetEnvs(t)
+
+			if test.config != "" {
+				tmpDir.Write("config.json", test.config)
+			}
+			for name, meta := range test.meta {
+				tmpDir.Write(filepath.Join("contexts", "meta", contextID(name), "meta.json"), meta)
+			}
+
+			dockerContext, err := currentDockerContext()
+
+			var host string
+			if dockerContext != nil {
+				host = dockerContext.host
+			}
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expectedHost, host)
+		})
+	}
+}
+
+func TestDockerContextClientOpts(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	for _, file := range []string{"ca.pem", "cert.pem", "key.pem"} {
+		content, err := ioutil.ReadFile(filepath.Join("testdata", file))
+		testutil.CheckError(t, false, err)
+		tmpDir.Write(filepath.Join("tls", file), string(content))
+	}
+
+	withTLS := &dockerContext{name: "tls", host: "tcp://10.0.0.1:2376", tlsDir: tmpDir.Path("tls")}
+	opts, err := withTLS.clientOpts()
+	testutil.CheckErrorAndDeepEqual(t, false, err, 2, len(opts))
+
+	withoutTLS := &dockerContext{name: "plain", host: "tcp://10.0.0.1:2375", tlsDir: tmpDir.Path("missing")}
+	opts, err = withoutTLS.clientOpts()
+	testutil.CheckErrorAndDeepEqual(t, false, err, 1, len(opts))
+}
@@ -20,16 +20,18 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"regexp"
 	"sort"
+	"time"
 
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
-	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/docker/docker/pkg/progress"
 	"github.com/docker/docker/pkg/streamformatter"
-	"github.com/docker/docker/pkg/term"
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/v1"
@@ -39,13 +41,14 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// BuildArtifact performs a docker build and returns nothing
-func BuildArtifact(ctx context.Context, out io.Writer, cli APIClient, workspace string, a *latest.DockerArtifact, initialTag string) error {
+// BuildArtifact performs a docker build and returns nothing.
+// The image is built for the given platform, such as linux/amd64, or for the daemon's if empty.
+func BuildArtifact(ctx context.Context, out io.Writer, cli APIClient, workspace string, a *latest.DockerArtifact, initialTag, platform string) error {
 	logrus.Debugf("Running docker build: context: %s, dockerfile: %s", workspace, a.DockerfilePath)
 
 	// Like `docker build`, we ignore the errors
 	// See https://github.com/docker/cli/blob/75c1bb1f33d7cedbaf48404597d5bf9818199480/cli/command/image/build.go#L364
-	authConfigs, _ := DefaultAuthHelper.GetAllAuthConfigs()
+	authConfigs, _ := DefaultKeychain.GetAllAuthConfigs()
 
 	buildCtx, buildCtxWriter := io.Pipe()
 	go func() {
@@ -67,27 +70,66 @@ func BuildArtifact(ctx context.Context, out io.Writer, cli APIClient, workspace
 		CacheFrom:   a.CacheFrom,
 		AuthConfigs: authConfigs,
 		Target:      a.Target,
+		Platform:    platform,
 	})
 	if err != nil {
 		return errors.Wrap(err, "docker build")
 	}
 	defer resp.Body.Close()
 
-	return StreamDockerMessages(out, resp.Body)
+	return streamBuildMessages(out, resp.Body)
 }
 
-// StreamDockerMessages streams formatted json output from the docker daemon
-// TODO(@r2d4): Make this output much better, this is the bare minimum
-func StreamDockerMessages(dst io.Writer, src io.Reader) error {
-	fd, _ := term.GetFdInfo(dst)
-	return jsonmessage.DisplayJSONMessagesStream(src, dst, fd, false, nil)
-}
+// pushRetries is how many times a push is retried after a transient registry error.
+const pushRetries = 4
+
+// pushBackoff is the delay before the first retry. It doubles with each retry.
+var pushBackoff = time.Second // for testing
+
+// transientPushError matches the errors reported by registries, or the proxies
+// in front of them, that are worth retrying.
+var transientPushError = regexp.MustCompile(`(?i)(status:? (code )?5\d\d|timeout|timed out|blob upload (invalid|unknown)|connection reset by peer)`)
+
+// unauthorizedPushError matches the errors reported by registries when the credentials are missing or expired.
+var unauthorizedPushError = regexp.MustCompile(`(?i)(unauthorized|authentication required|denied: .*(token|credentials))`)
 
+// RunPush pushes an image and retries on transient registry errors with an exponential backoff.
+// Layers that were already uploaded are skipped by the daemon, so a retry resumes the push.
+// If the registry rejects the credentials, they are refreshed and the push is retried once,
+// since cloud registries hand out tokens that expire during long dev sessions.
 func RunPush(ctx context.Context, cli APIClient, ref string, out io.Writer) error {
-	registryAuth, err := encodedRegistryAuth(ctx, cli, DefaultAuthHelper, ref)
+	registryAuth, err := encodedRegistryAuth(ctx, cli, DefaultKeychain, ref)
 	if err != nil {
 		return errors.Wrapf(err, "getting auth config for %s", ref)
 	}
+
+	refreshed := false
+	backoff := pushBackoff
+	for retry := 0; ; retry++ {
+		err := push(ctx, cli, ref, registryAuth, out)
+		if err != nil && !refreshed && isUnauthorizedPushError(err) {
+			refreshed = true
+			if registryAuth, err = refreshRegistryAuth(ctx, cli, ref); err != nil {
+				return errors.Wrapf(err, "refreshing auth config for %s", ref)
+			}
+			logrus.Debugf("Retrying to push %s with refreshed credentials", ref)
+			continue
+		}
+		if err == nil || retry == pushRetries || !isTransientPushError(err) {
+			return err
+		}
+
+		color.Yellow.Fprintf(out, "Pushing %s failed: %s. Retrying in %s...\n", ref, err, backoff)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func push(ctx context.Context, cli APIClient, ref, registryAuth string, out io.Writer) error {
 	rc, err := cli.ImagePush(ctx, ref, types.ImagePushOptions{
 		RegistryAuth: registryAuth,
 	})
@@ -98,23 +140,48 @@ func RunPush(ctx context.Context, cli APIClient, ref string, out io.Writer) erro
 	return StreamDockerMessages(out, rc)
 }
 
+// refreshRegistryAuth resolves the credentials of an image's registry again, bypassing the cache.
+func refreshRegistryAuth(ctx context.Context, cli APIClient, ref string) (string, error) {
+	configKey, err := registryConfigKey(ctx, cli, ref)
+	if err != nil {
+		return "", err
+	}
+
+	ac, err := DefaultKeychain.RefreshAuthConfig(configKey)
+	if err != nil {
+		return "", errors.Wrap(err, "getting auth config")
+	}
+	return encodeAuthConfig(ac)
+}
+
+func isUnauthorizedPushError(err error) bool {
+	return unauthorizedPushError.MatchString(err.Error())
+}
+
+func isTransientPushError(err error) bool {
+	if netErr, ok := errors.Cause(err).(net.Error); ok && netErr.Timeout() {
+		return true
+	}
+	return transientPushError.MatchString(err.Error())
+}
+
 func AddTag(src, target string) error {
-	srcRef, err := name.ParseReference(src, name.WeakValidation)
+	srcRef, err := parseReference(src)
 	if err != nil {
 		return errors.Wrap(err, "getting source reference")
 	}
 
-	auth, err := authn.DefaultKeychain.Resolve(srcRef.Context().Registry)
+	auth, err := DefaultKeychain.Resolve(srcRef.Context().Registry)
 	if err != nil {
 		return err
 	}
 
-	targetRef, err := name.ParseReference(target, name.WeakValidation)
+	targetRef, err := parseReference(target)
 	if err != nil {
 		return errors.Wrap(err, "getting target reference")
 	}
 
-	return addTag(srcRef, targetRef, auth, http.DefaultTransport)
+	return addTag(srcRef, targetRef, auth, registryTransport)
 }
 
 func addTag(ref name.Reference, targetRef name.Reference, auth authn.Authenticator, t http.RoundTripper) error {
@@ -146,20 +213,36 @@ func Digest(ctx context.Context, cli APIClient, ref string) (string, error) {
 	return image.ID, nil
 }
 
+// ImagePlatform returns the platform, such as linux/amd64, that an image of the local daemon is built for.
+// It is empty if the image is unknown.
+func ImagePlatform(ctx context.Context, cli APIClient, ref string) (string, error) {
+	image, _, err := cli.ImageInspectWithRaw(ctx, ref)
+	if err != nil {
+		return "", errors.Wrap(err, "inspecting image")
+	}
+
+	if image.Architecture == "" {
+		return "", nil
+	}
+	return image.Os + "/" + image.Architecture, nil
+}
+
 func remoteImage(identifier string) (v1.Image, error) {
-	ref, err := name.ParseReference(identifier, name.WeakValidation)
+	ref, err := parseReference(identifier)
 	if err != nil {
 		return nil, errors.Wrap(err, "parsing initial ref")
 	}
 
-	auth, err := authn.DefaultKeychain.Resolve(ref.Context().Registry)
+	auth, err := DefaultKeychain.Resolve(ref.Context().Registry)
 	if err != nil {
-		return nil, errors.Wrap(err, "getting default keychain auth")
+		return nil, errors.Wrap(err, "getting registry credentials")
 	}
 
-	return remote.Image(ref, remote.WithAuth(auth), remote.WithTransport(http.DefaultTransport))
+	return remote.Image(ref, remote.WithAuth(auth), remote.WithTransport(registryTransport))
 }
 
+// RemoteDigest returns the digest of an image pushed to a registry.
+// Private registries are accessed with the credentials configured for docker.
 func RemoteDigest(identifier string) (string, error) {
 	img, err := remoteImage(identifier)
 	if err != nil {
@@ -19,20 +19,24 @@ package docker
 import (
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/GoogleContainerTools/skaffold/testutil"
+	"github.com/docker/docker/api/types"
 	"github.com/google/go-cmp/cmp"
 )
 
 func TestMain(m *testing.M) {
 	// So we don't shell out to credentials helpers or try to read dockercfg
-	defer func(h AuthConfigHelper) { DefaultAuthHelper = h }(DefaultAuthHelper)
-	DefaultAuthHelper = testAuthHelper{}
+	defer func(k *RegistryKeychain) { DefaultKeychain = k }(DefaultKeychain)
+	DefaultKeychain = NewKeychain(testAuthHelper{})
 
 	os.Exit(m.Run())
 }
@@ -93,6 +97,79 @@ func TestRunPush(t *testing.T) {
 	}
 }
 
+type flakyPushAPI struct {
+	*testutil.FakeImageAPIClient
+	failures []string
+	pushes   int
+}
+
+func (f *flakyPushAPI) ImagePush(ctx context.Context, ref string, opts types.ImagePushOptions) (io.ReadCloser, error) {
+	f.pushes++
+	if len(f.failures) == 0 {
+		return f.FakeImageAPIClient.ImagePush(ctx, ref, opts)
+	}
+	message := f.failures[0]
+	f.failures = f.failures[1:]
+	return ioutil.NopCloser(strings.NewReader(fmt.Sprintf(`{"errorDetail":{"message":%q},"error":%q}`, message, message))), nil
+}
+
+func TestRunPushRetries(t *testing.T) {
+	defer func(b time.Duration) { pushBackoff = b }(pushBackoff)
+	pushBackoff = 0
+
+	var tests = []struct {
+		description    string
+		failures       []string
+		shouldErr      bool
+		expectedPushes int
+	}{
+		{
+			description:    "no failure",
+			expectedPushes: 1,
+		},
+		{
+			description:    "retry server errors and timeouts",
+			failures:       []string{"received unexpected HTTP status: 502 Bad Gateway", "net/http: TLS handshake timeout"},
+			expectedPushes: 3,
+		},
+		{
+			description:    "retry invalid blob uploads",
+			failures:       []string{"blob upload invalid"},
+			expectedPushes: 2,
+		},
+		{
+			description:    "refresh credentials once on auth errors",
+			failures:       []string{"unauthorized: authentication required"},
+			expectedPushes: 2,
+		},
+		{
+			description:    "don't retry auth errors twice",
+			failures:       []string{"unauthorized: authentication required", "unauthorized: authentication required"},
+			shouldErr:      true,
+			expectedPushes: 2,
+		},
+		{
+			description:    "give up after too many retries",
+			failures:       []string{"status: 503", "status: 503", "status: 503", "status: 503", "status: 503"},
+			shouldErr:      true,
+			expectedPushes: 5,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			api := &flakyPushAPI{
+				FakeImageAPIClient: testutil.NewFakeImageAPIClient(map[string]string{}, nil),
+				failures:           test.failures,
+			}
+
+			err := RunPush(context.Background(), api, "gcr.io/project/image", ioutil.Discard)
+
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expectedPushes, api.pushes)
+		})
+	}
+}
+
 func TestRunBuildArtifact(t *testing.T) {
 	var tests = []testImageAPI{
 		{
@@ -121,7 +198,7 @@ func TestRunBuildArtifact(t *testing.T) {
 		t.Run(test.description, func(t *testing.T) {
 			api := testutil.NewFakeImageAPIClient(test.tagToImageID, test.testOpts)
 
-			err := BuildArtifact(context.Background(), ioutil.Discard, api, ".", &latest.DockerArtifact{}, "finalimage")
+			err := BuildArtifact(context.Background(), ioutil.Discard, api, ".", &latest.DockerArtifact{}, "finalimage", "")
 
 			testutil.CheckError(t, test.shouldErr, err)
 		})
@@ -220,3 +297,17 @@ func TestGetBuildArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestImagePlatform(t *testing.T) {
+	api := testutil.NewFakeImageAPIClient(map[string]string{
+		"image:v1": "sha256:111",
+	}, &testutil.FakeImageAPIOptions{
+		ImagePlatform: "linux/arm64",
+	})
+
+	platform, err := ImagePlatform(context.Background(), api, "image:v1")
+	testutil.CheckErrorAndDeepEqual(t, false, err, "linux/arm64", platform)
+
+	platform, err = ImagePlatform(context.Background(), api, "unknown:v1")
+	testutil.CheckErrorAndDeepEqual(t, false, err, "", platform)
+}
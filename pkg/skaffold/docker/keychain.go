@@ -0,0 +1,285 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/aws"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/gcp"
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/config/credentials"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/registry"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultKeychain resolves the credentials used to push images, read remote digests
+// and generate the kaniko secrets. The gcloud, ECR and ACR credential helpers come
+// first for the registries they manage, since the credentials that docker stores
+// for those are often stale tokens. The other registries, or cloud registries whose
+// helper isn't installed, use docker's config.json auths, global store and
+// per-registry credential helpers.
+//
+// It is exposed so that other packages can override it for testing.
+var DefaultKeychain = NewKeychain(
+	&cloudHelper{name: "gcloud", matches: gcp.IsGCR},
+	&cloudHelper{name: "ecr-login", matches: aws.IsECR},
+	&cloudHelper{name: "acr-env", matches: isACR},
+	credsHelper{},
+)
+
+// credentialsTTL is how long resolved credentials are reused. Cloud registries
+// hand out short-lived tokens, so they have to be refreshed during long dev sessions.
+var credentialsTTL = 5 * time.Minute // for testing
+
+func isACR(registry string) bool {
+	return strings.HasSuffix(registry, ".azurecr.io")
+}
+
+// RegistryKeychain chains credential sources: the first source that knows the
+// credentials of a registry wins. Resolved credentials are cached for a short time.
+type RegistryKeychain struct {
+	sources []AuthConfigHelper
+
+	lock  sync.Mutex
+	cache map[string]cachedAuth
+}
+
+type cachedAuth struct {
+	authConfig types.AuthConfig
+	expiry     time.Time
+}
+
+// NewKeychain creates a keychain that queries the given sources in order.
+func NewKeychain(sources ...AuthConfigHelper) *RegistryKeychain {
+	return &RegistryKeychain{
+		sources: sources,
+		cache:   map[string]cachedAuth{},
+	}
+}
+
+// GetAuthConfig returns the credentials of a registry.
+func (k *RegistryKeychain) GetAuthConfig(registry string) (types.AuthConfig, error) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	if cached, present := k.cache[registry]; present && now().Before(cached.expiry) {
+		return cached.authConfig, nil
+	}
+
+	return k.resolve(registry)
+}
+
+// RefreshAuthConfig resolves the credentials of a registry again, ignoring the
+// cached ones, for example after they were rejected.
+func (k *RegistryKeychain) RefreshAuthConfig(registry string) (types.AuthConfig, error) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	return k.resolve(registry)
+}
+
+// resolve asks the sources for the credentials of a registry and caches them.
+// It must be called with the lock held.
+func (k *RegistryKeychain) resolve(registry string) (types.AuthConfig, error) {
+	delete(k.cache, registry)
+
+	var firstErr error
+	for _, source := range k.sources {
+		ac, err := source.GetAuthConfig(registry)
+		if err != nil {
+			logrus.Debugf("Unable to get credentials for %s: %s", registry, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if isEmpty(ac) {
+			continue
+		}
+
+		k.cache[registry] = cachedAuth{
+			authConfig: ac,
+			expiry:     now().Add(credentialsTTL),
+		}
+		return ac, nil
+	}
+
+	return types.AuthConfig{}, firstErr
+}
+
+// GetAllAuthConfigs returns the credentials known by all the sources.
+// Sources listed first take precedence.
+func (k *RegistryKeychain) GetAllAuthConfigs() (map[string]types.AuthConfig, error) {
+	all := map[string]types.AuthConfig{}
+
+	var firstErr error
+	for _, source := range k.sources {
+		authConfigs, err := source.GetAllAuthConfigs()
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		for registry, ac := range authConfigs {
+			if _, present := all[registry]; !present {
+				all[registry] = ac
+			}
+		}
+	}
+
+	return all, firstErr
+}
+
+// Resolve implements authn.Keychain. Registries without credentials are accessed anonymously.
+func (k *RegistryKeychain) Resolve(reg name.Registry) (authn.Authenticator, error) {
+	configKey := configKey(reg)
+
+	ac, err := k.GetAuthConfig(configKey)
+	if err != nil {
+		logrus.Debugf("Unable to get credentials for %s, using anonymous access: %s", configKey, err)
+		return authn.Anonymous, nil
+	}
+
+	return authenticator(ac), nil
+}
+
+// Login returns the username and password that tools other than docker, such as
+// helm, can use to log into a registry. Both are empty if the registry has no
+// credentials or only a registry token.
+func (k *RegistryKeychain) Login(registry string) (string, string, error) {
+	reg, err := name.NewRegistry(registry, name.WeakValidation)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "parsing registry %s", registry)
+	}
+
+	ac, err := k.GetAuthConfig(configKey(reg))
+	if err != nil {
+		logrus.Debugf("Unable to get credentials for %s: %s", registry, err)
+		return "", "", nil
+	}
+
+	switch {
+	case ac.IdentityToken != "":
+		return ac.Username, ac.IdentityToken, nil
+	case ac.Password != "":
+		return ac.Username, ac.Password, nil
+	default:
+		return "", "", nil
+	}
+}
+
+// ConfigJSON generates a docker config.json holding the current credentials
+// of the given registries. Registries without credentials are skipped.
+// It returns nil if none of the registries has credentials.
+func (k *RegistryKeychain) ConfigJSON(registries []string) ([]byte, error) {
+	auths := map[string]configAuth{}
+
+	for _, reg := range registries {
+		parsed, err := name.NewRegistry(reg, name.WeakValidation)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing registry %s", reg)
+		}
+		configKey := configKey(parsed)
+
+		ac, err := k.GetAuthConfig(configKey)
+		if err != nil {
+			logrus.Debugf("Unable to get credentials for %s: %s", configKey, err)
+			continue
+		}
+
+		switch {
+		case ac.IdentityToken != "":
+			auths[configKey] = configAuth{
+				Auth:          base64.StdEncoding.EncodeToString([]byte(ac.Username + ":")),
+				IdentityToken: ac.IdentityToken,
+			}
+		case ac.Username != "" || ac.Password != "":
+			auths[configKey] = configAuth{
+				Auth: base64.StdEncoding.EncodeToString([]byte(ac.Username + ":" + ac.Password)),
+			}
+		case ac.RegistryToken != "":
+			auths[configKey] = configAuth{
+				RegistryToken: ac.RegistryToken,
+			}
+		}
+	}
+
+	if len(auths) == 0 {
+		return nil, nil
+	}
+
+	return json.Marshal(struct {
+		Auths map[string]configAuth `json:"auths"`
+	}{auths})
+}
+
+type configAuth struct {
+	Auth          string `json:"auth,omitempty"`
+	IdentityToken string `json:"identitytoken,omitempty"`
+	RegistryToken string `json:"registrytoken,omitempty"`
+}
+
+// configKey is the key under which docker stores the credentials of a registry.
+func configKey(reg name.Registry) string {
+	if reg.RegistryStr() == name.DefaultRegistry {
+		return registry.IndexServer
+	}
+	return reg.RegistryStr()
+}
+
+func isEmpty(ac types.AuthConfig) bool {
+	return ac.Username == "" && ac.Password == "" && ac.IdentityToken == "" && ac.RegistryToken == "" && ac.Auth == ""
+}
+
+// cloudHelper gets the credentials of the registries managed by a cloud provider
+// with its docker credential helper, even if docker isn't configured to use it.
+type cloudHelper struct {
+	name    string
+	matches func(registry string) bool
+}
+
+func (h *cloudHelper) GetAuthConfig(registry string) (types.AuthConfig, error) {
+	if !h.matches(registry) {
+		return types.AuthConfig{}, nil
+	}
+
+	if path, _ := exec.LookPath("docker-credential-" + h.name); path == "" {
+		logrus.Debugf("Skipping %s credentials because docker-credential-%s is not on PATH.", registry, h.name)
+		return types.AuthConfig{}, nil
+	}
+
+	cf, err := config.Load(configDir)
+	if err != nil {
+		return types.AuthConfig{}, errors.Wrap(err, "docker config")
+	}
+
+	return credentials.NewNativeStore(cf, h.name).Get(registry)
+}
+
+// GetAllAuthConfigs doesn't list anything since that would mean
+// calling the cloud APIs for every build.
+func (h *cloudHelper) GetAllAuthConfigs() (map[string]types.AuthConfig, error) {
+	return nil, nil
+}
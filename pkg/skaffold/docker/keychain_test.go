@@ -0,0 +1,165 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/aws"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"github.com/docker/docker/api/types"
+)
+
+type countingAuthHelper struct {
+	authConfigs map[string]types.AuthConfig
+	err         error
+	calls       int
+}
+
+func (c *countingAuthHelper) GetAuthConfig(registry string) (types.AuthConfig, error) {
+	c.calls++
+	return c.authConfigs[registry], c.err
+}
+
+func (c *countingAuthHelper) GetAllAuthConfigs() (map[string]types.AuthConfig, error) {
+	return c.authConfigs, c.err
+}
+
+func TestKeychainChainsSources(t *testing.T) {
+	failing := &countingAuthHelper{err: errors.New("helper failed")}
+	docker := &countingAuthHelper{authConfigs: map[string]types.AuthConfig{
+		"registry.example.com": {Username: "user", Password: "docker"},
+	}}
+	cloud := &countingAuthHelper{authConfigs: map[string]types.AuthConfig{
+		"registry.example.com": {Username: "user", Password: "cloud"},
+		"gcr.io":               {Username: "oauth2accesstoken", Password: "token"},
+	}}
+	keychain := NewKeychain(failing, docker, cloud)
+
+	ac, err := keychain.GetAuthConfig("registry.example.com")
+	testutil.CheckErrorAndDeepEqual(t, false, err, "docker", ac.Password)
+
+	ac, err = keychain.GetAuthConfig("gcr.io")
+	testutil.CheckErrorAndDeepEqual(t, false, err, "token", ac.Password)
+
+	_, err = keychain.GetAuthConfig("unknown.example.com")
+	testutil.CheckError(t, true, err)
+
+	all, err := keychain.GetAllAuthConfigs()
+	testutil.CheckError(t, true, err)
+	testutil.CheckDeepEqual(t, map[string]types.AuthConfig{
+		"registry.example.com": {Username: "user", Password: "docker"},
+		"gcr.io":               {Username: "oauth2accesstoken", Password: "token"},
+	}, all)
+}
+
+func TestKeychainRefreshesCredentials(t *testing.T) {
+	defer func(f func() time.Time) { now = f }(now)
+	current := time.Now()
+	now = func() time.Time { return current }
+
+	helper := &countingAuthHelper{authConfigs: map[string]types.AuthConfig{
+		"gcr.io": {Username: "oauth2accesstoken", Password: "token"},
+	}}
+	keychain := NewKeychain(helper)
+
+	keychain.GetAuthConfig("gcr.io")
+	keychain.GetAuthConfig("gcr.io")
+	testutil.CheckDeepEqual(t, 1, helper.calls)
+
+	current = current.Add(credentialsTTL)
+	keychain.GetAuthConfig("gcr.io")
+	testutil.CheckDeepEqual(t, 2, helper.calls)
+
+	keychain.RefreshAuthConfig("gcr.io")
+	testutil.CheckDeepEqual(t, 3, helper.calls)
+
+	keychain.GetAuthConfig("gcr.io")
+	testutil.CheckDeepEqual(t, 3, helper.calls)
+}
+
+func TestKeychainConfigJSON(t *testing.T) {
+	keychain := NewKeychain(&countingAuthHelper{authConfigs: map[string]types.AuthConfig{
+		"gcr.io":                      {Username: "oauth2accesstoken", Password: "token"},
+		"registry.azurecr.io":         {Username: "00000000-0000-0000-0000-000000000000", IdentityToken: "refresh"},
+		"https://index.docker.io/v1/": {Username: "user", Password: "password"},
+	}})
+
+	config, err := keychain.ConfigJSON([]string{"gcr.io", "registry.azurecr.io", "index.docker.io", "unknown.example.com"})
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, `{"auths":{"gcr.io":{"auth":"b2F1dGgyYWNjZXNzdG9rZW46dG9rZW4="},"https://index.docker.io/v1/":{"auth":"dXNlcjpwYXNzd29yZA=="},"registry.azurecr.io":{"auth":"MDAwMDAwMDAtMDAwMC0wMDAwLTAwMDAtMDAwMDAwMDAwMDAwOg==","identitytoken":"refresh"}}}`, string(config))
+
+	config, err = keychain.ConfigJSON([]string{"unknown.example.com"})
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, []byte(nil), config)
+}
+
+func TestKeychainLogin(t *testing.T) {
+	keychain := NewKeychain(&countingAuthHelper{authConfigs: map[string]types.AuthConfig{
+		"gcr.io":                      {Username: "oauth2accesstoken", Password: "token"},
+		"registry.azurecr.io":         {Username: "00000000-0000-0000-0000-000000000000", IdentityToken: "refresh"},
+		"https://index.docker.io/v1/": {Username: "user", Password: "password"},
+	}})
+
+	var tests = []struct {
+		registry         string
+		expectedUsername string
+		expectedPassword string
+	}{
+		{registry: "gcr.io", expectedUsername: "oauth2accesstoken", expectedPassword: "token"},
+		{registry: "registry.azurecr.io", expectedUsername: "00000000-0000-0000-0000-000000000000", expectedPassword: "refresh"},
+		{registry: "docker.io", expectedUsername: "user", expectedPassword: "password"},
+		{registry: "unknown.example.com"},
+	}
+	for _, test := range tests {
+		t.Run(test.registry, func(t *testing.T) {
+			username, password, err := keychain.Login(test.registry)
+
+			testutil.CheckErrorAndDeepEqual(t, false, err, test.expectedUsername, username)
+			testutil.CheckDeepEqual(t, test.expectedPassword, password)
+		})
+	}
+}
+
+func TestCloudHelper(t *testing.T) {
+	tmp, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	reset := testutil.SetEnvs(t, map[string]string{
+		"PATH": tmp.Root(),
+	})
+	defer reset(t)
+
+	helper := &cloudHelper{name: "ecr-login", matches: aws.IsECR}
+
+	var tests = []struct {
+		description string
+		registry    string
+	}{
+		{description: "ecr", registry: "123456789.dkr.ecr.us-east-1.amazonaws.com"},
+		{description: "not ecr", registry: "registry.amazonaws.com.example.com"},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			// The helper isn't on PATH: no credentials and no error.
+			ac, err := helper.GetAuthConfig(test.registry)
+			testutil.CheckErrorAndDeepEqual(t, false, err, types.AuthConfig{}, ac)
+		})
+	}
+}
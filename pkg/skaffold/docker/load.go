@@ -0,0 +1,72 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/pkg/errors"
+)
+
+// LoadImage loads an image tarball, as written by `docker save`, into the daemon.
+// It returns the name of the loaded image or its ID if the tarball has no name.
+func LoadImage(ctx context.Context, out io.Writer, cli APIClient, tarball io.Reader) (string, error) {
+	resp, err := cli.ImageLoad(ctx, tarball, false)
+	if err != nil {
+		return "", errors.Wrap(err, "loading image into docker daemon")
+	}
+	defer resp.Body.Close()
+
+	var messages bytes.Buffer
+	if err := StreamDockerMessages(out, io.TeeReader(resp.Body, &messages)); err != nil {
+		return "", errors.Wrap(err, "reading from image load response")
+	}
+
+	return loadedImage(&messages)
+}
+
+// loadedImage finds the last image reported by the daemon as loaded.
+func loadedImage(messages io.Reader) (string, error) {
+	var image string
+
+	decoder := json.NewDecoder(messages)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := decoder.Decode(&msg); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", errors.Wrap(err, "decoding image load response")
+		}
+
+		for _, prefix := range []string{"Loaded image: ", "Loaded image ID: "} {
+			if strings.HasPrefix(msg.Stream, prefix) {
+				image = strings.TrimSpace(strings.TrimPrefix(msg.Stream, prefix))
+			}
+		}
+	}
+
+	if image == "" {
+		return "", errors.New("unable to find the name of the loaded image")
+	}
+	return image, nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"github.com/docker/docker/api/types"
+)
+
+type fakeLoadClient struct {
+	APIClient
+	response string
+}
+
+func (f *fakeLoadClient) ImageLoad(_ context.Context, input io.Reader, _ bool) (types.ImageLoadResponse, error) {
+	return types.ImageLoadResponse{
+		Body: ioutil.NopCloser(strings.NewReader(f.response)),
+		JSON: true,
+	}, nil
+}
+
+func TestLoadImage(t *testing.T) {
+	var tests = []struct {
+		description string
+		response    string
+		expected    string
+		shouldErr   bool
+	}{
+		{
+			description: "named image",
+			response: `{"status":"Loading layer","progressDetail":{"current":32768,"total":1000000},"id":"8d3ac3489996"}
+{"stream":"Loaded image: gcr.io/project/app:v1\n"}`,
+			expected: "gcr.io/project/app:v1",
+		},
+		{
+			description: "unnamed image",
+			response:    `{"stream":"Loaded image ID: sha256:4d9d5bbd0d9b\n"}`,
+			expected:    "sha256:4d9d5bbd0d9b",
+		},
+		{
+			description: "no image",
+			response:    `{"status":"Loading layer"}`,
+			shouldErr:   true,
+		},
+		{
+			description: "load error",
+			response:    `{"errorDetail":{"message":"invalid tar header"},"error":"invalid tar header"}`,
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			cli := &fakeLoadClient{response: test.response}
+
+			image, err := LoadImage(context.Background(), ioutil.Discard, cli, strings.NewReader("tarball"))
+
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, image)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/docker/docker/api/types"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sirupsen/logrus"
+)
+
+var registryMirrors []string
+
+// SetRegistryMirrors sets the mirrors used to pull Docker Hub images during builds.
+// Like with the docker daemon, mirrors can be given as urls or hostnames.
+func SetRegistryMirrors(mirrors []string) {
+	registryMirrors = nil
+	for _, mirror := range mirrors {
+		mirror = strings.TrimPrefix(mirror, "https://")
+		mirror = strings.TrimPrefix(mirror, "http://")
+		registryMirrors = append(registryMirrors, strings.TrimSuffix(mirror, "/"))
+	}
+}
+
+// RegistryMirrors returns the hostnames of the registry mirrors.
+func RegistryMirrors() []string {
+	return registryMirrors
+}
+
+// MirroredImage returns the name of an image on a registry mirror.
+// Only Docker Hub images are mirrored.
+func MirroredImage(image, mirror string) (string, bool) {
+	ref, err := name.ParseReference(image, name.WeakValidation)
+	if err != nil {
+		return "", false
+	}
+
+	repo := ref.Context()
+	if repo.RegistryStr() != name.DefaultRegistry {
+		return "", false
+	}
+
+	switch r := ref.(type) {
+	case name.Tag:
+		return fmt.Sprintf("%s/%s:%s", mirror, repo.RepositoryStr(), r.TagStr()), true
+	case name.Digest:
+		return fmt.Sprintf("%s/%s@%s", mirror, repo.RepositoryStr(), r.DigestStr()), true
+	default:
+		return "", false
+	}
+}
+
+// PullFromMirrors pulls the Docker Hub base images of an artifact through the
+// registry mirrors and tags them with their original names so that the
+// build doesn't have to hit Docker Hub. Images that are already present are not pulled.
+// Failures are not fatal: the daemon will try to pull the images itself.
+func PullFromMirrors(ctx context.Context, out io.Writer, cli APIClient, workspace string, a *latest.DockerArtifact) {
+	if len(registryMirrors) == 0 {
+		return
+	}
+
+	images, err := BaseImages(workspace, a)
+	if err != nil {
+		logrus.Debugf("Unable to list base images: %s", err)
+		return
+	}
+
+	for _, image := range images {
+		if _, _, err := cli.ImageInspectWithRaw(ctx, image); err == nil {
+			continue
+		}
+
+		for _, mirror := range registryMirrors {
+			mirrored, ok := MirroredImage(image, mirror)
+			if !ok {
+				break
+			}
+
+			if err := pullAndTag(ctx, out, cli, mirrored, image); err != nil {
+				logrus.Debugf("Unable to pull %s from mirror %s: %s", image, mirror, err)
+				continue
+			}
+			break
+		}
+	}
+}
+
+func pullAndTag(ctx context.Context, out io.Writer, cli APIClient, mirrored, image string) error {
+	rc, err := cli.ImagePull(ctx, mirrored, types.ImagePullOptions{})
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	if err := StreamDockerMessages(out, rc); err != nil {
+		return err
+	}
+
+	return cli.ImageTag(ctx, mirrored, image)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestSetRegistryMirrors(t *testing.T) {
+	defer SetRegistryMirrors(nil)
+
+	SetRegistryMirrors([]string{"https://mirror.gcr.io/", "http://registry.local:5000", "mirror.local"})
+
+	testutil.CheckDeepEqual(t, []string{"mirror.gcr.io", "registry.local:5000", "mirror.local"}, RegistryMirrors())
+}
+
+func TestMirroredImage(t *testing.T) {
+	var tests = []struct {
+		description      string
+		image            string
+		expectedImage    string
+		expectedMirrored bool
+	}{
+		{
+			description:      "official image",
+			image:            "golang:1.11",
+			expectedImage:    "mirror.gcr.io/library/golang:1.11",
+			expectedMirrored: true,
+		},
+		{
+			description:      "default tag",
+			image:            "user/image",
+			expectedImage:    "mirror.gcr.io/user/image:latest",
+			expectedMirrored: true,
+		},
+		{
+			description:      "digest",
+			image:            "golang@sha256:81daf011d63b68cfa514ddab7741a1adddd59d3264118dfb0fd9266328bb8883",
+			expectedImage:    "mirror.gcr.io/library/golang@sha256:81daf011d63b68cfa514ddab7741a1adddd59d3264118dfb0fd9266328bb8883",
+			expectedMirrored: true,
+		},
+		{
+			description: "other registry",
+			image:       "gcr.io/project/image:tag",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			image, mirrored := MirroredImage(test.image, "mirror.gcr.io")
+
+			testutil.CheckDeepEqual(t, test.expectedMirrored, mirrored)
+			testutil.CheckDeepEqual(t, test.expectedImage, image)
+		})
+	}
+}
+
+func TestBaseImages(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+	tmpDir.Write("Dockerfile", `ARG VERSION=1.11
+FROM golang:${VERSION} as builder
+FROM scratch as empty
+FROM gcr.io/distroless/base
+COPY --from=builder /app /app
+`)
+
+	images, err := BaseImages(tmpDir.Root(), &latest.DockerArtifact{DockerfilePath: "Dockerfile"})
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, []string{"golang:1.11", "gcr.io/distroless/base"}, images)
+}
+
+func TestBuildsGo(t *testing.T) {
+	var tests = []struct {
+		description string
+		dockerfile  string
+		target      string
+		expected    bool
+	}{
+		{
+			description: "golang base image",
+			dockerfile:  "FROM docker.io/library/golang:1.11-alpine\nCOPY . .",
+			expected:    true,
+		},
+		{
+			description: "go build",
+			dockerfile:  "FROM alpine\nRUN apk add go && go build -o /app .",
+			expected:    true,
+		},
+		{
+			description: "java",
+			dockerfile:  "FROM openjdk:8\nCOPY app.jar .\nRUN java -version",
+		},
+		{
+			description: "unreachable golang stage",
+			dockerfile:  "FROM golang as tools\nFROM node:10 as app\nRUN npm install",
+			target:      "app",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			tmpDir, cleanup := testutil.NewTempDir(t)
+			defer cleanup()
+			tmpDir.Write("Dockerfile", test.dockerfile)
+
+			buildsGo, err := BuildsGo(tmpDir.Root(), &latest.DockerArtifact{DockerfilePath: "Dockerfile", Target: test.target})
+
+			testutil.CheckErrorAndDeepEqual(t, false, err, test.expected, buildsGo)
+		})
+	}
+}
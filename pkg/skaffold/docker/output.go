@@ -0,0 +1,201 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/docker/pkg/term"
+	"github.com/pkg/errors"
+)
+
+const (
+	// RawBuildOutput streams the docker build messages as the docker CLI does.
+	RawBuildOutput = "raw"
+
+	// SummaryBuildOutput prints one line per Dockerfile step, with its duration.
+	SummaryBuildOutput = "summary"
+)
+
+var buildOutput = RawBuildOutput
+
+// for testing
+var now = time.Now
+
+// SetBuildOutput chooses how the output of builds run by the docker daemon is printed.
+func SetBuildOutput(mode string) error {
+	switch mode {
+	case "":
+		buildOutput = RawBuildOutput
+	case RawBuildOutput, SummaryBuildOutput:
+		buildOutput = mode
+	default:
+		return fmt.Errorf("unknown docker build output %q, expected %s or %s", mode, RawBuildOutput, SummaryBuildOutput)
+	}
+	return nil
+}
+
+// layerID matches the short ids of the layers that docker reports progress for.
+var layerID = regexp.MustCompile(`^[0-9a-f]{12}$`)
+
+// StreamDockerMessages streams formatted json output from the docker daemon.
+// The progress of each layer pulled or pushed is only shown at the debug log level.
+func StreamDockerMessages(dst io.Writer, src io.Reader) error {
+	if util.ToolVerbosity() < util.VerboseOutput {
+		filtered := withoutLayerProgress(src)
+		defer filtered.Close()
+		src = filtered
+	}
+
+	fd, _ := term.GetFdInfo(dst)
+	return jsonmessage.DisplayJSONMessagesStream(src, dst, fd, false, nil)
+}
+
+// withoutLayerProgress drops the per-layer messages from a docker message stream.
+func withoutLayerProgress(src io.Reader) io.ReadCloser {
+	r, w := io.Pipe()
+
+	go func() {
+		decoder := json.NewDecoder(src)
+		encoder := json.NewEncoder(w)
+		for {
+			var msg jsonmessage.JSONMessage
+			if err := decoder.Decode(&msg); err != nil {
+				if err == io.EOF {
+					err = nil
+				}
+				w.CloseWithError(err)
+				return
+			}
+
+			if msg.Error == nil && msg.Stream == "" && layerID.MatchString(msg.ID) {
+				continue
+			}
+			if err := encoder.Encode(msg); err != nil {
+				return
+			}
+		}
+	}()
+
+	return r
+}
+
+func streamBuildMessages(dst io.Writer, src io.Reader) error {
+	if buildOutput == SummaryBuildOutput {
+		return summarizeBuildMessages(dst, src)
+	}
+	return StreamDockerMessages(dst, src)
+}
+
+// summarizeBuildMessages collapses the output of a docker build into
+// one line per step. Layer pulls and the output of commands are hidden,
+// unless the step fails, in which case its output is printed along with the error.
+func summarizeBuildMessages(dst io.Writer, src io.Reader) error {
+	s := &stepSummary{out: dst}
+
+	decoder := json.NewDecoder(src)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := decoder.Decode(&msg); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return errors.Wrap(err, "decoding docker build output")
+		}
+
+		if msg.Error != nil {
+			s.fail(msg.Error)
+			return msg.Error
+		}
+		if msg.ErrorMessage != "" {
+			jsonErr := &jsonmessage.JSONError{Message: msg.ErrorMessage}
+			s.fail(jsonErr)
+			return jsonErr
+		}
+
+		s.write(msg.Stream)
+	}
+
+	s.flush()
+	s.done()
+	return nil
+}
+
+type stepSummary struct {
+	out   io.Writer
+	steps util.BuildSteps
+	step  string
+	start time.Time
+}
+
+// write processes the complete lines of a chunk of build output.
+func (s *stepSummary) write(stream string) {
+	s.steps.Write(stream, s.handle)
+}
+
+func (s *stepSummary) handle(line string, kind util.BuildLine) {
+	switch kind {
+	case util.StepStart:
+		s.done()
+		s.step = line
+		s.start = now()
+	case util.BuildEnd:
+		s.done()
+		fmt.Fprintln(s.out, line)
+	case util.BuildOutput:
+		fmt.Fprintln(s.out, line)
+	}
+}
+
+// flush processes the last line if it's not terminated.
+func (s *stepSummary) flush() {
+	s.steps.Flush(s.handle)
+}
+
+// done prints the step that just completed.
+func (s *stepSummary) done() {
+	if s.step == "" {
+		return
+	}
+
+	fmt.Fprintf(s.out, "%s (%s)\n", s.step, s.elapsed())
+	s.step = ""
+}
+
+// fail highlights the failing step and prints its output.
+func (s *stepSummary) fail(err error) {
+	s.flush()
+
+	if s.step != "" {
+		color.Red.Fprintf(s.out, "%s FAILED after %s\n", s.step, s.elapsed())
+		for _, line := range s.steps.Lines() {
+			fmt.Fprintf(s.out, "  %s\n", line)
+		}
+	}
+	color.Red.Fprintln(s.out, err.Error())
+}
+
+func (s *stepSummary) elapsed() time.Duration {
+	return now().Sub(s.start).Round(time.Millisecond)
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"github.com/sirupsen/logrus"
+)
+
+func TestSetBuildOutput(t *testing.T) {
+	defer SetBuildOutput(RawBuildOutput)
+
+	testutil.CheckError(t, false, SetBuildOutput("summary"))
+	testutil.CheckDeepEqual(t, SummaryBuildOutput, buildOutput)
+
+	testutil.CheckError(t, false, SetBuildOutput(""))
+	testutil.CheckDeepEqual(t, RawBuildOutput, buildOutput)
+
+	testutil.CheckError(t, true, SetBuildOutput("verbose"))
+}
+
+func TestSummarizeBuildMessages(t *testing.T) {
+	var tests = []struct {
+		description string
+		messages    []string
+		expected    string
+		shouldErr   bool
+	}{
+		{
+			description: "successful build",
+			messages: []string{
+				`{"stream":"Step 1/3 : FROM golang:1.11"}`,
+				`{"stream":"\n"}`,
+				`{"status":"Pulling fs layer","progressDetail":{},"id":"d660b1f15b9b"}`,
+				`{"stream":" ---> 1234\n"}`,
+				`{"stream":"Step 2/3 : RUN go build\n"}`,
+				`{"stream":" ---> Running in 99\n"}`,
+				`{"stream":"compiling\n"}`,
+				`{"stream":"Removing intermediate container 99\n"}`,
+				`{"stream":" ---> 5678\n"}`,
+				`{"stream":"Step 3/3 : CMD [\"app\"]\n"}`,
+				`{"aux":{"ID":"sha256:5678"}}`,
+				`{"stream":"Successfully built 5678\n"}`,
+				`{"stream":"Successfully tagged image:latest\n"}`,
+			},
+			expected: "Step 1/3 : FROM golang:1.11 (1s)\nStep 2/3 : RUN go build (1s)\nStep 3/3 : CMD [\"app\"] (1s)\nSuccessfully built 5678\nSuccessfully tagged image:latest\n",
+		},
+		{
+			description: "failed step",
+			messages: []string{
+				`{"stream":"Step 1/2 : FROM golang:1.11\n"}`,
+				`{"stream":" ---> 1234\n"}`,
+				`{"stream":"Step 2/2 : RUN go build\n"}`,
+				`{"stream":" ---> Running in 99\n"}`,
+				`{"stream":"compiling\n"}`,
+				`{"stream":"main.go:3: undefined: foo"}`,
+				`{"errorDetail":{"code":2,"message":"The command '/bin/sh -c go build' returned a non-zero code: 2"},"error":"The command '/bin/sh -c go build' returned a non-zero code: 2"}`,
+			},
+			expected:  "Step 1/2 : FROM golang:1.11 (1s)\nStep 2/2 : RUN go build FAILED after 1s\n  compiling\n  main.go:3: undefined: foo\nThe command '/bin/sh -c go build' returned a non-zero code: 2\n",
+			shouldErr: true,
+		},
+		{
+			description: "invalid message",
+			messages:    []string{"not json"},
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			defer func(n func() time.Time) { now = n }(now)
+			clock := time.Now()
+			now = func() time.Time {
+				clock = clock.Add(time.Second)
+				return clock
+			}
+
+			var out bytes.Buffer
+			err := summarizeBuildMessages(&out, strings.NewReader(strings.Join(test.messages, "\n")))
+
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, out.String())
+		})
+	}
+}
+
+func TestStreamDockerMessages(t *testing.T) {
+	messages := strings.Join([]string{
+		`{"status":"The push refers to repository [gcr.io/project/image]"}`,
+		`{"status":"Preparing","progressDetail":{},"id":"d660b1f15b9b"}`,
+		`{"status":"Pushing","progressDetail":{"current":512,"total":1024},"progress":"[==>  ]","id":"d660b1f15b9b"}`,
+		`{"status":"Pushed","progressDetail":{},"id":"d660b1f15b9b"}`,
+		`{"status":"latest: digest: sha256:1234 size: 528"}`,
+	}, "\n")
+
+	var tests = []struct {
+		description string
+		level       logrus.Level
+		expected    string
+	}{
+		{
+			description: "layers are hidden by default",
+			level:       logrus.WarnLevel,
+			expected:    "The push refers to repository [gcr.io/project/image]\nlatest: digest: sha256:1234 size: 528\n",
+		},
+		{
+			description: "layers are shown in debug",
+			level:       logrus.DebugLevel,
+			expected:    "The push refers to repository [gcr.io/project/image]\nd660b1f15b9b: Preparing\nd660b1f15b9b: Pushed\nlatest: digest: sha256:1234 size: 528\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			level := logrus.GetLevel()
+			defer logrus.SetLevel(level)
+			logrus.SetLevel(test.level)
+
+			out := new(bytes.Buffer)
+			err := StreamDockerMessages(out, strings.NewReader(messages))
+
+			testutil.CheckErrorAndDeepEqual(t, false, err, test.expected, out.String())
+		})
+	}
+}
+
+func TestStreamDockerMessagesError(t *testing.T) {
+	messages := strings.Join([]string{
+		`{"status":"Preparing","progressDetail":{},"id":"d660b1f15b9b"}`,
+		`{"errorDetail":{"message":"denied"},"error":"denied"}`,
+		`{"status":"Pushed","progressDetail":{},"id":"d660b1f15b9b"}`,
+	}, "\n")
+
+	err := StreamDockerMessages(new(bytes.Buffer), strings.NewReader(messages))
+
+	testutil.CheckError(t, true, err)
+}
@@ -22,12 +22,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
-	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/docker/docker/builder/dockerignore"
 	"github.com/docker/docker/pkg/fileutils"
 	"github.com/google/go-containerregistry/pkg/v1"
@@ -47,162 +48,377 @@ type from struct {
 // RetrieveImage is overridden for unit testing
 var RetrieveImage = retrieveImage
 
+// ValidateDockerfile says if a file is a valid Dockerfile.
 func ValidateDockerfile(path string) bool {
-	f, err := os.Open(path)
-	if err != nil {
-		logrus.Warnf("opening file %s: %s", path, err.Error())
-		return false
-	}
-	res, err := parser.Parse(f)
-	if err != nil || res == nil || len(res.AST.Children) == 0 {
+	if err := checkDockerfile(path); err != nil {
+		logrus.Debugf("Not a valid Dockerfile: %s", err)
 		return false
 	}
-	// validate each node contains valid dockerfile directive
-	for _, child := range res.AST.Children {
-		_, ok := command.Commands[child.Value]
-		if !ok {
-			return false
-		}
+	return true
+}
+
+// CheckDockerfile looks for syntax errors in an artifact's Dockerfile before it is built.
+// Errors give the location of the faulty instruction as file:line.
+func CheckDockerfile(workspace string, a *latest.DockerArtifact) error {
+	absDockerfilePath, err := NormalizeDockerfilePath(workspace, a.DockerfilePath)
+	if err != nil {
+		return errors.Wrap(err, "normalizing dockerfile path")
 	}
 
-	return true
+	return checkDockerfile(absDockerfilePath)
 }
 
-func expandBuildArgs(nodes []*parser.Node, buildArgs map[string]*string) {
-	for i, node := range nodes {
-		if node.Value != command.Arg {
-			continue
-		}
+func checkDockerfile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.Wrapf(err, "opening dockerfile: %s", path)
+	}
+	defer f.Close()
 
-		// build arg's key
-		keyValue := strings.Split(node.Next.Value, "=")
-		key := keyValue[0]
+	res, err := parser.Parse(f)
+	if err != nil {
+		return fmt.Errorf("%s: %s", path, err)
+	}
+	if len(res.AST.Children) == 0 {
+		return fmt.Errorf("%s: file with no instructions", path)
+	}
 
-		// build arg's value
-		var value string
-		if buildArgs[key] != nil {
-			value = *buildArgs[key]
-		} else if len(keyValue) > 1 {
-			value = keyValue[1]
+	seenFrom := false
+	for _, child := range res.AST.Children {
+		instruction := strings.ToUpper(child.Value)
+
+		if _, ok := command.Commands[child.Value]; !ok {
+			return fmt.Errorf("%s:%d: unknown instruction: %s", path, child.StartLine, instruction)
 		}
 
-		for _, node := range nodes[i+1:] {
-			// Stop replacements if an arg is redefined with the same key
-			if node.Value == command.Arg && strings.Split(node.Next.Value, "=")[0] == key {
-				break
+		switch child.Value {
+		case command.From:
+			seenFrom = true
+		case command.Arg:
+		default:
+			if !seenFrom {
+				return fmt.Errorf("%s:%d: the first instruction must be FROM, found %s", path, child.StartLine, instruction)
 			}
+		}
 
-			// replace $key with value
-			for curr := node; curr != nil; curr = curr.Next {
-				curr.Value = util.Expand(curr.Value, key, value)
-			}
+		if minArgs := requiredArgs[child.Value]; countArgs(child) < minArgs {
+			return fmt.Errorf("%s:%d: %s requires at least %d argument(s)", path, child.StartLine, instruction, minArgs)
 		}
 	}
+
+	return nil
 }
 
-func fromInstructions(nodes []*parser.Node) []from {
-	var list []from
+// requiredArgs lists the minimum number of arguments of the instructions
+// that can't be used without arguments.
+var requiredArgs = map[string]int{
+	command.From:    1,
+	command.Arg:     1,
+	command.Copy:    2,
+	command.Add:     2,
+	command.Workdir: 1,
+	command.User:    1,
+	command.Expose:  1,
+}
 
-	for _, node := range nodes {
-		if node.Value == command.From {
-			list = append(list, fromInstruction(node))
-		}
+func countArgs(node *parser.Node) int {
+	count := 0
+	for n := node.Next; n != nil; n = n.Next {
+		count++
 	}
+	return count
+}
 
-	return list
+// argValue returns the value of an ARG instruction's word, like `FOO` or `FOO=default`.
+// Build args from the configuration take precedence over the default value. An ARG
+// without a default value, redeclared in a stage, takes the value of the global ARG.
+// The second return value is false if the ARG has no value.
+func argValue(word string, buildArgs map[string]*string, globalArgs, vars map[string]string) (string, string, bool, error) {
+	kv := strings.SplitN(word, "=", 2)
+	key := kv[0]
+
+	if value := buildArgs[key]; value != nil {
+		return key, *value, true, nil
+	}
+	if len(kv) == 2 {
+		value, err := processShellWord(kv[1], vars)
+		return key, value, true, err
+	}
+	value, found := globalArgs[key]
+	return key, value, found, nil
 }
 
-func fromInstruction(node *parser.Node) from {
+func fromInstruction(node *parser.Node, globalArgs map[string]string) (from, error) {
 	var as string
 	if next := node.Next.Next; next != nil && strings.ToLower(next.Value) == "as" && next.Next != nil {
 		as = next.Next.Value
 	}
 
+	// Only the ARGs declared before the first FROM can be used in FROM instructions.
+	image, err := processShellWord(node.Next.Value, globalArgs)
+	if err != nil {
+		return from{}, errors.Wrapf(err, "processing base image %s", node.Next.Value)
+	}
+
 	return from{
-		image: strings.ToLower(node.Next.Value),
+		image: strings.ToLower(image),
 		as:    strings.ToLower(as),
+	}, nil
+}
+
+// stage is a build stage of a, possibly multi-stage, Dockerfile.
+type stage struct {
+	from
+	nodes []*parser.Node
+}
+
+// buildStages splits the instructions of a Dockerfile into build stages.
+// It also returns the values of the ARGs declared before the first FROM.
+func buildStages(nodes []*parser.Node, buildArgs map[string]*string) ([]*stage, map[string]string, error) {
+	var stages []*stage
+	globalArgs := map[string]string{}
+
+	for _, node := range nodes {
+		switch {
+		case node.Value == command.From:
+			from, err := fromInstruction(node, globalArgs)
+			if err != nil {
+				return nil, nil, err
+			}
+			stages = append(stages, &stage{from: from})
+
+		case len(stages) > 0:
+			current := stages[len(stages)-1]
+			current.nodes = append(current.nodes, node)
+
+		case node.Value == command.Arg:
+			for word := node.Next; word != nil; word = word.Next {
+				key, value, found, err := argValue(word.Value, buildArgs, nil, globalArgs)
+				if err != nil {
+					return nil, nil, errors.Wrapf(err, "processing ARG %s", word.Value)
+				}
+				if found {
+					globalArgs[key] = value
+				}
+			}
+		}
 	}
+
+	return stages, globalArgs, nil
 }
 
-func onbuildInstructions(nodes []*parser.Node) ([]*parser.Node, error) {
-	var instructions []string
+// findStage finds a stage given its name or its index.
+func findStage(stages []*stage, nameOrIndex string) (int, bool) {
+	if i, found := findNamedStage(stages, strings.ToLower(nameOrIndex)); found {
+		return i, true
+	}
 
-	stages := map[string]bool{}
-	for _, from := range fromInstructions(nodes) {
-		stages[from.as] = true
+	if i, err := strconv.Atoi(nameOrIndex); err == nil && i >= 0 && i < len(stages) {
+		return i, true
+	}
 
-		if from.image == "scratch" {
+	return -1, false
+}
+
+// reachableStages lists the stages needed to build the target stage, or
+// the last stage if no target is given. Stages are needed if they are used
+// as a base image or as the source of a COPY --from.
+func reachableStages(stages []*stage, target string) ([]bool, error) {
+	reachable := make([]bool, len(stages))
+	if len(stages) == 0 {
+		return reachable, nil
+	}
+
+	targetIndex := len(stages) - 1
+	if target != "" {
+		i, found := findStage(stages, target)
+		if !found {
+			return nil, fmt.Errorf("failed to reach build target %s in Dockerfile", target)
+		}
+		targetIndex = i
+	}
+
+	var visit func(i int)
+	visit = func(i int) {
+		if reachable[i] {
+			return
+		}
+		reachable[i] = true
+
+		// Only previous stages can be referenced
+		previous := stages[:i]
+		if base, found := findNamedStage(previous, stages[i].image); found {
+			visit(base)
+		}
+		for _, node := range stages[i].nodes {
+			if node.Value != command.Copy {
+				continue
+			}
+			if source := copyFromFlag(node.Flags); source != "" {
+				if j, found := findStage(previous, source); found {
+					visit(j)
+				}
+			}
+		}
+	}
+	visit(targetIndex)
+
+	return reachable, nil
+}
+
+// findNamedStage finds a stage used as a base image. Contrary to COPY --from,
+// FROM can't reference a stage by its index.
+func findNamedStage(stages []*stage, name string) (int, bool) {
+	for i, stage := range stages {
+		if stage.as != "" && stage.as == name {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func onbuildInstructions(stages []*stage, reachable []bool) ([][]*parser.Node, error) {
+	instructions := make([][]*parser.Node, len(stages))
+
+	for i, stage := range stages {
+		if !reachable[i] || stage.image == "scratch" {
 			continue
 		}
 
-		if _, found := stages[from.image]; found {
+		if _, found := findNamedStage(stages[:i], stage.image); found {
 			continue
 		}
 
-		logrus.Debugf("Checking base image %s for ONBUILD triggers.", from.image)
-		img, err := RetrieveImage(from.image)
+		logrus.Debugf("Checking base image %s for ONBUILD triggers.", stage.image)
+		img, err := RetrieveImage(stage.image)
 		if err != nil {
 			logrus.Warnf("Error processing base image for ONBUILD triggers: %s. Dependencies may be incomplete.", err)
 			continue
 		}
 
-		if len(img.Config.OnBuild) > 0 {
-			logrus.Debugf("Found ONBUILD triggers %v in image %s", img.Config.OnBuild, from.image)
-			instructions = append(instructions, img.Config.OnBuild...)
+		if len(img.Config.OnBuild) == 0 {
+			continue
 		}
-	}
+		logrus.Debugf("Found ONBUILD triggers %v in image %s", img.Config.OnBuild, stage.image)
 
-	obRes, err := parser.Parse(strings.NewReader(strings.Join(instructions, "\n")))
-	if err != nil {
-		return nil, errors.Wrap(err, "parsing ONBUILD instructions")
+		obRes, err := parser.Parse(strings.NewReader(strings.Join(img.Config.OnBuild, "\n")))
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing ONBUILD instructions")
+		}
+		instructions[i] = obRes.AST.Children
 	}
 
-	return obRes.AST.Children, nil
+	return instructions, nil
 }
 
-func copiedFiles(nodes []*parser.Node) ([][]string, error) {
+// copiedFiles lists the files copied by the reachable stages.
+// ARG and ENV instructions are evaluated like docker does: ARGs are scoped
+// to a stage while ENVs are inherited by the stages based on it.
+func copiedFiles(stages []*stage, reachable []bool, onbuild [][]*parser.Node, buildArgs map[string]*string, globalArgs map[string]string) ([][]string, error) {
 	var copied [][]string
 
-	envs := map[string]string{}
-	for _, node := range nodes {
-		switch node.Value {
-		case command.Add, command.Copy:
-			files, err := processCopy(node, envs)
-			if err != nil {
-				return nil, err
+	envsByStage := make([]map[string]string, len(stages))
+	for i, stage := range stages {
+		envs := map[string]string{}
+		if base, found := findNamedStage(stages[:i], stage.image); found {
+			for k, v := range envsByStage[base] {
+				envs[k] = v
+			}
+		}
+		envsByStage[i] = envs
+
+		args := map[string]string{}
+		vars := func() map[string]string {
+			merged := map[string]string{}
+			for k, v := range args {
+				merged[k] = v
+			}
+			// ENV takes precedence over ARG
+			for k, v := range envs {
+				merged[k] = v
 			}
+			return merged
+		}
+
+		for _, node := range append(onbuild[i], stage.nodes...) {
+			switch node.Value {
+			case command.Add, command.Copy:
+				if !reachable[i] {
+					continue
+				}
+
+				files, err := processCopy(node, vars())
+				if err != nil {
+					return nil, err
+				}
 
-			if len(files) > 0 {
-				copied = append(copied, files)
+				if len(files) > 0 {
+					copied = append(copied, files)
+				}
+			case command.Arg:
+				for word := node.Next; word != nil; word = word.Next {
+					key, value, found, err := argValue(word.Value, buildArgs, globalArgs, vars())
+					if err != nil {
+						return nil, errors.Wrapf(err, "processing ARG %s", word.Value)
+					}
+					if found {
+						args[key] = value
+					}
+				}
+			case command.Env:
+				for kv := node.Next; kv != nil && kv.Next != nil; kv = kv.Next.Next {
+					value, err := processShellWord(kv.Next.Value, vars())
+					if err != nil {
+						return nil, errors.Wrapf(err, "processing ENV %s", kv.Value)
+					}
+					envs[kv.Value] = value
+				}
 			}
-		case command.Env:
-			envs[node.Next.Value] = node.Next.Next.Value
 		}
 	}
 
 	return copied, nil
 }
 
-func readDockerfile(workspace, absDockerfilePath string, buildArgs map[string]*string) ([]string, error) {
+// parseStages parses a Dockerfile into build stages and says which
+// stages are needed to build the target.
+func parseStages(absDockerfilePath string, buildArgs map[string]*string, target string) ([]*stage, []bool, map[string]string, error) {
 	f, err := os.Open(absDockerfilePath)
 	if err != nil {
-		return nil, errors.Wrapf(err, "opening dockerfile: %s", absDockerfilePath)
+		return nil, nil, nil, errors.Wrapf(err, "opening dockerfile: %s", absDockerfilePath)
 	}
 	defer f.Close()
 
 	res, err := parser.Parse(f)
 	if err != nil {
-		return nil, errors.Wrap(err, "parsing dockerfile")
+		return nil, nil, nil, errors.Wrap(err, "parsing dockerfile")
 	}
 
-	expandBuildArgs(res.AST.Children, buildArgs)
+	stages, globalArgs, err := buildStages(res.AST.Children, buildArgs)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "listing build stages")
+	}
+
+	reachable, err := reachableStages(stages, target)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return stages, reachable, globalArgs, nil
+}
 
-	instructions, err := onbuildInstructions(res.AST.Children)
+func readDockerfile(workspace, absDockerfilePath string, buildArgs map[string]*string, target string) ([]string, error) {
+	stages, reachable, globalArgs, err := parseStages(absDockerfilePath, buildArgs, target)
+	if err != nil {
+		return nil, err
+	}
+
+	instructions, err := onbuildInstructions(stages, reachable)
 	if err != nil {
 		return nil, errors.Wrap(err, "listing ONBUILD instructions")
 	}
 
-	copied, err := copiedFiles(append(instructions, res.AST.Children...))
+	copied, err := copiedFiles(stages, reachable, instructions, buildArgs, globalArgs)
 	if err != nil {
 		return nil, errors.Wrap(err, "listing copied files")
 	}
@@ -210,6 +426,72 @@ func readDockerfile(workspace, absDockerfilePath string, buildArgs map[string]*s
 	return expandPaths(workspace, copied)
 }
 
+// BaseImages lists the images that the stages needed to build
+// an artifact are based on.
+func BaseImages(workspace string, a *latest.DockerArtifact) ([]string, error) {
+	absDockerfilePath, err := NormalizeDockerfilePath(workspace, a.DockerfilePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "normalizing dockerfile path")
+	}
+
+	stages, reachable, _, err := parseStages(absDockerfilePath, a.BuildArgs, a.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	var images []string
+	for i, stage := range stages {
+		if !reachable[i] || stage.image == "scratch" {
+			continue
+		}
+		if _, found := findNamedStage(stages[:i], stage.image); found {
+			continue
+		}
+		images = append(images, stage.image)
+	}
+
+	return images, nil
+}
+
+var goBuildRegex = regexp.MustCompile(`\bgo\s+(build|install)\b`)
+
+// BuildsGo tells whether the stages needed to build an artifact compile Go code:
+// one of them is based on a golang image or runs `go build` or `go install`.
+func BuildsGo(workspace string, a *latest.DockerArtifact) (bool, error) {
+	absDockerfilePath, err := NormalizeDockerfilePath(workspace, a.DockerfilePath)
+	if err != nil {
+		return false, errors.Wrap(err, "normalizing dockerfile path")
+	}
+
+	stages, reachable, _, err := parseStages(absDockerfilePath, a.BuildArgs, a.Target)
+	if err != nil {
+		return false, err
+	}
+
+	for i, stage := range stages {
+		if !reachable[i] {
+			continue
+		}
+		if imageRepository(stage.image) == "golang" {
+			return true, nil
+		}
+		for _, node := range stage.nodes {
+			if node.Value == command.Run && goBuildRegex.MatchString(node.Original) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// imageRepository is the last path component of an image name, without its tag or digest.
+func imageRepository(image string) string {
+	image = strings.SplitN(image, "@", 2)[0]
+	repository := image[strings.LastIndex(image, "/")+1:]
+	return strings.SplitN(repository, ":", 2)[0]
+}
+
 func expandPaths(workspace string, copied [][]string) ([]string, error) {
 	expandedPaths := make(map[string]bool)
 	for _, files := range copied {
@@ -264,25 +546,16 @@ func GetDependencies(ctx context.Context, workspace string, a *latest.DockerArti
 		return nil, errors.Wrap(err, "normalizing dockerfile path")
 	}
 
-	deps, err := readDockerfile(workspace, absDockerfilePath, a.BuildArgs)
+	deps, err := readDockerfile(workspace, absDockerfilePath, a.BuildArgs, a.Target)
 	if err != nil {
 		return nil, err
 	}
 
 	// Read patterns to ignore
-	var excludes []string
-	dockerignorePath := filepath.Join(workspace, ".dockerignore")
-	if _, err := os.Stat(dockerignorePath); !os.IsNotExist(err) {
-		r, err := os.Open(dockerignorePath)
-		if err != nil {
-			return nil, err
-		}
-		defer r.Close()
-
-		excludes, err = dockerignore.ReadAll(r)
-		if err != nil {
-			return nil, err
-		}
+	dockerignorePath := dockerignoreFile(workspace, absDockerfilePath)
+	excludes, err := readDockerignore(dockerignorePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", dockerignorePath)
 	}
 
 	pExclude, err := fileutils.NewPatternMatcher(excludes)
@@ -303,6 +576,17 @@ func GetDependencies(ctx context.Context, workspace string, a *latest.DockerArti
 
 		switch mode := fi.Mode(); {
 		case mode.IsDir():
+			// Skip ignored folders early, unless files can be re-included with exclusion patterns.
+			if dep != "." && !pExclude.Exclusions() {
+				ignored, err := pExclude.Matches(dep)
+				if err != nil {
+					return nil, err
+				}
+				if ignored {
+					continue
+				}
+			}
+
 			if err := godirwalk.Walk(absDep, &godirwalk.Options{
 				Unsorted: true,
 				Callback: func(fpath string, info *godirwalk.Dirent) error {
@@ -321,7 +605,8 @@ func GetDependencies(ctx context.Context, workspace string, a *latest.DockerArti
 					}
 
 					if info.IsDir() {
-						if ignored {
+						// Files can be re-included with exclusion patterns, even if their folder is ignored.
+						if ignored && !pExclude.Exclusions() {
 							return filepath.SkipDir
 						}
 					} else if !ignored {
@@ -352,8 +637,13 @@ func GetDependencies(ctx context.Context, workspace string, a *latest.DockerArti
 		files[absDockerfilePath] = true
 	}
 
-	// Ignore .dockerignore
+	// Ignore .dockerignore, or the Dockerfile's own ignore file
 	delete(files, ".dockerignore")
+	if absWorkspace, err := filepath.Abs(workspace); err == nil {
+		if rel, err := filepath.Rel(absWorkspace, dockerignorePath); err == nil {
+			delete(files, rel)
+		}
+	}
 
 	var dependencies []string
 	for file := range files {
@@ -364,6 +654,37 @@ func GetDependencies(ctx context.Context, workspace string, a *latest.DockerArti
 	return dependencies, nil
 }
 
+// dockerignoreFile returns the path to the ignore file that applies to a Dockerfile.
+// Like with BuildKit, a Dockerfile can have its own ignore file, named after the
+// Dockerfile and placed next to it, that takes precedence over the .dockerignore
+// at the root of the context. For example: Dockerfile.dev.dockerignore.
+func dockerignoreFile(workspace, absDockerfilePath string) string {
+	perDockerfile := absDockerfilePath + ".dockerignore"
+	if _, err := os.Stat(perDockerfile); err == nil {
+		return perDockerfile
+	}
+
+	path := filepath.Join(workspace, ".dockerignore")
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// readDockerignore reads the patterns of an ignore file, if it exists.
+func readDockerignore(path string) ([]string, error) {
+	r, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return dockerignore.ReadAll(r)
+}
+
 var imageCache sync.Map
 
 func retrieveImage(image string) (*v1.ConfigFile, error) {
@@ -416,19 +737,18 @@ func retrieveRemoteConfig(identifier string) (*v1.ConfigFile, error) {
 func processCopy(value *parser.Node, envs map[string]string) ([]string, error) {
 	var copied []string
 
-	slex := shell.NewLex('\\')
 	for {
 		// Skip last node, since it is the destination, and stop if we arrive at a comment
 		if value.Next.Next == nil || strings.HasPrefix(value.Next.Next.Value, "#") {
 			break
 		}
-		src, err := processShellWord(slex, value.Next.Value, envs)
+		src, err := processShellWord(value.Next.Value, envs)
 		if err != nil {
 			return nil, errors.Wrap(err, "processing word")
 		}
 		// If the --from flag is provided, we are dealing with a multi-stage dockerfile
 		// Adding a dependency from a different stage does not imply a source dependency
-		if hasMultiStageFlag(value.Flags) {
+		if copyFromFlag(value.Flags) != "" {
 			return nil, nil
 		}
 		if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
@@ -443,19 +763,21 @@ func processCopy(value *parser.Node, envs map[string]string) ([]string, error) {
 	return copied, nil
 }
 
-func processShellWord(lex *shell.Lex, word string, envs map[string]string) (string, error) {
+// processShellWord expands the variables in a word, like docker does.
+func processShellWord(word string, envs map[string]string) (string, error) {
 	envSlice := []string{}
 	for envKey, envVal := range envs {
 		envSlice = append(envSlice, fmt.Sprintf("%s=%s", envKey, envVal))
 	}
-	return lex.ProcessWord(word, envSlice)
+	return shell.NewLex('\\').ProcessWord(word, envSlice)
 }
 
-func hasMultiStageFlag(flags []string) bool {
+// copyFromFlag returns the value of the --from flag of a COPY instruction, if any.
+func copyFromFlag(flags []string) string {
 	for _, f := range flags {
 		if strings.HasPrefix(f, "--from=") {
-			return true
+			return strings.TrimPrefix(f, "--from=")
 		}
 	}
-	return false
+	return ""
 }
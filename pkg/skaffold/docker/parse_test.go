@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
@@ -169,6 +170,37 @@ FROM base as dist
 FROM DIST as prod
 `
 
+const unusedStage = `
+FROM golang:1.9.2 as builder
+COPY worker.go .
+FROM nginx as unused
+COPY server.go .
+FROM busybox
+COPY --from=builder /go/bin/worker .
+`
+
+const envFromBaseStage = `
+FROM busybox as base
+ENV foo bar
+FROM base
+COPY $foo /quux
+`
+
+const fromBuildArg = `
+ARG BASE=nginx
+ARG FILE=server.go
+FROM ${BASE}
+ARG FILE
+COPY $FILE .
+`
+
+const envFromArg = `
+FROM busybox
+ARG DIR="docker"
+ENV CONF=${DIR}/nginx.conf OTHER=unused
+COPY $CONF .
+`
+
 const copyAll = `
 FROM nginx
 COPY . /
@@ -203,7 +235,9 @@ func TestGetDependencies(t *testing.T) {
 		dockerfile  string
 		workspace   string
 		ignore      string
+		ignoreFile  string
 		buildArgs   map[string]*string
+		target      string
 
 		expected  []string
 		fetched   []string
@@ -332,6 +366,23 @@ func TestGetDependencies(t *testing.T) {
 			expected:    []string{"Dockerfile", "bar", filepath.Join("docker", "bar"), filepath.Join("docker", "nginx.conf"), "file", "server.go", "test.conf", "worker.go"},
 			fetched:     []string{"nginx"},
 		},
+		{
+			description: "dockerignore with exclusions",
+			dockerfile:  copyAll,
+			workspace:   ".",
+			ignore:      "docker\n*.go\n!docker/bar",
+			expected:    []string{".dot", "Dockerfile", "bar", filepath.Join("docker", "bar"), "file", "test.conf"},
+			fetched:     []string{"nginx"},
+		},
+		{
+			description: "dockerfile specific ignore file",
+			dockerfile:  copyAll,
+			workspace:   ".",
+			ignore:      "*.go",
+			ignoreFile:  "docker\n.*\nbar",
+			expected:    []string{"Dockerfile", "file", "server.go", "test.conf", "worker.go"},
+			fetched:     []string{"nginx"},
+		},
 		{
 			description: "dockerignore with context in parent directory",
 			dockerfile:  copyDirectory,
@@ -437,6 +488,57 @@ func TestGetDependencies(t *testing.T) {
 			expected:    []string{"Dockerfile"},
 			fetched:     []string{"ubuntu:14.04"},
 		},
+		{
+			description: "global build arg in from and copy",
+			dockerfile:  fromBuildArg,
+			workspace:   ".",
+			expected:    []string{"Dockerfile", "server.go"},
+			fetched:     []string{"nginx"},
+		},
+		{
+			description: "override global build arg",
+			dockerfile:  fromBuildArg,
+			workspace:   ".",
+			buildArgs:   map[string]*string{"BASE": util.StringPtr("busybox"), "FILE": util.StringPtr("worker.go")},
+			expected:    []string{"Dockerfile", "worker.go"},
+			fetched:     []string{"busybox"},
+		},
+		{
+			description: "env from build arg",
+			dockerfile:  envFromArg,
+			workspace:   ".",
+			expected:    []string{"Dockerfile", filepath.Join("docker", "nginx.conf")},
+			fetched:     []string{"busybox"},
+		},
+		{
+			description: "ignore unused stage",
+			dockerfile:  unusedStage,
+			workspace:   ".",
+			expected:    []string{"Dockerfile", "worker.go"},
+			fetched:     []string{"golang:1.9.2", "busybox"},
+		},
+		{
+			description: "target stage",
+			dockerfile:  unusedStage,
+			workspace:   ".",
+			target:      "unused",
+			expected:    []string{"Dockerfile", "server.go"},
+			fetched:     []string{"nginx"},
+		},
+		{
+			description: "unknown target stage",
+			dockerfile:  unusedStage,
+			workspace:   ".",
+			target:      "unknown",
+			shouldErr:   true,
+		},
+		{
+			description: "env from base stage",
+			dockerfile:  envFromBaseStage,
+			workspace:   ".",
+			expected:    []string{"Dockerfile", "bar"},
+			fetched:     []string{"busybox"},
+		},
 	}
 
 	for _, test := range tests {
@@ -460,10 +562,15 @@ func TestGetDependencies(t *testing.T) {
 				tmpDir.Write(test.workspace+"/.dockerignore", test.ignore)
 			}
 
+			if test.ignoreFile != "" {
+				tmpDir.Write(test.workspace+"/Dockerfile.dockerignore", test.ignoreFile)
+			}
+
 			workspace := tmpDir.Path(test.workspace)
 			deps, err := GetDependencies(context.Background(), workspace, &latest.DockerArtifact{
 				BuildArgs:      test.buildArgs,
 				DockerfilePath: "Dockerfile",
+				Target:         test.target,
 			})
 
 			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, deps)
@@ -471,3 +578,57 @@ func TestGetDependencies(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckDockerfile(t *testing.T) {
+	var tests = []struct {
+		description string
+		dockerfile  string
+		expected    string
+	}{
+		{
+			description: "valid",
+			dockerfile:  copyServerGo,
+		},
+		{
+			description: "arg before from",
+			dockerfile:  "ARG BASE=ubuntu\nFROM $BASE\nCMD true",
+		},
+		{
+			description: "unknown instruction",
+			dockerfile:  "FROM ubuntu\nRUN true\nCOPPY server.go .",
+			expected:    "Dockerfile:3: unknown instruction: COPPY",
+		},
+		{
+			description: "missing from",
+			dockerfile:  "\nRUN true\nFROM ubuntu",
+			expected:    "Dockerfile:2: the first instruction must be FROM, found RUN",
+		},
+		{
+			description: "copy without destination",
+			dockerfile:  "FROM ubuntu\nCOPY server.go",
+			expected:    "Dockerfile:2: COPY requires at least 2 argument(s)",
+		},
+		{
+			description: "empty",
+			dockerfile:  "# nothing here",
+			expected:    "Dockerfile: file with no instructions",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			tmpDir, cleanup := testutil.NewTempDir(t)
+			defer cleanup()
+
+			tmpDir.Write("Dockerfile", test.dockerfile)
+
+			err := CheckDockerfile(tmpDir.Root(), &latest.DockerArtifact{DockerfilePath: "Dockerfile"})
+
+			var message string
+			if err != nil {
+				message = strings.TrimPrefix(err.Error(), tmpDir.Root()+string(filepath.Separator))
+			}
+			testutil.CheckDeepEqual(t, test.expected, message)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// PruneImages removes the older images of the given repositories from the daemon,
+// keeping the most recent ones. The given temporary names, that images got while they
+// were built, are also removed. Images used by containers are kept.
+func PruneImages(ctx context.Context, out io.Writer, cli APIClient, repos, temporary []string, keepLast int) error {
+	images, err := cli.ImageList(ctx, types.ImageListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "listing images")
+	}
+
+	sort.Slice(images, func(i, j int) bool {
+		return images[i].Created > images[j].Created
+	})
+
+	var stale []string
+	kept := map[string]int{}
+	for _, image := range images {
+		tagsPerRepo := map[string][]string{}
+		for _, tag := range image.RepoTags {
+			repo := repository(tag)
+			if util.StrSliceContains(temporary, tag) {
+				stale = append(stale, tag)
+			} else if util.StrSliceContains(repos, repo) {
+				tagsPerRepo[repo] = append(tagsPerRepo[repo], tag)
+			}
+		}
+
+		for repo, tags := range tagsPerRepo {
+			if kept[repo] < keepLast {
+				kept[repo]++
+				continue
+			}
+			stale = append(stale, tags...)
+		}
+	}
+
+	removed := 0
+	for _, tag := range stale {
+		if _, err := cli.ImageRemove(ctx, tag, types.ImageRemoveOptions{PruneChildren: true}); err != nil {
+			logrus.Debugf("Unable to remove %s: %s", tag, err)
+			continue
+		}
+		removed++
+	}
+
+	if removed > 0 {
+		color.Default.Fprintf(out, "Pruned %d stale images\n", removed)
+	}
+	return nil
+}
+
+// repository strips the tag from an image name.
+func repository(image string) string {
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i]
+	}
+	return image
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"github.com/docker/docker/api/types"
+)
+
+type fakePruneClient struct {
+	APIClient
+	images  []types.ImageSummary
+	inUse   map[string]bool
+	removed []string
+}
+
+func (f *fakePruneClient) ImageList(context.Context, types.ImageListOptions) ([]types.ImageSummary, error) {
+	return f.images, nil
+}
+
+func (f *fakePruneClient) ImageRemove(_ context.Context, image string, _ types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error) {
+	if f.inUse[image] {
+		return nil, fmt.Errorf("image %s is used by a container", image)
+	}
+	f.removed = append(f.removed, image)
+	return nil, nil
+}
+
+func TestPruneImages(t *testing.T) {
+	cli := &fakePruneClient{
+		images: []types.ImageSummary{
+			{Created: 1, RepoTags: []string{"gcr.io/project/app:v1", "other:v1"}},
+			{Created: 4, RepoTags: []string{"gcr.io/project/app:v4", "c3c12a5a1dd1dbbc7e6a1da5c5ab2ff4:latest"}},
+			{Created: 4, RepoTags: []string{"0d5c8f6e6a1f4d2b9c3e7a8b1f2d3c4e:latest"}},
+			{Created: 3, RepoTags: []string{"gcr.io/project/app:v3", "gcr.io/project/app:dirty"}},
+			{Created: 2, RepoTags: []string{"gcr.io/project/app:v2"}},
+			{Created: 2, RepoTags: []string{"localhost:5000/web:v1"}},
+		},
+		inUse: map[string]bool{"gcr.io/project/app:v2": true},
+	}
+
+	var out bytes.Buffer
+	err := PruneImages(context.Background(), &out, cli, []string{"gcr.io/project/app", "localhost:5000/web"}, []string{"c3c12a5a1dd1dbbc7e6a1da5c5ab2ff4:latest"}, 2)
+
+	testutil.CheckError(t, false, err)
+	testutil.CheckDeepEqual(t, []string{"c3c12a5a1dd1dbbc7e6a1da5c5ab2ff4:latest", "gcr.io/project/app:v1"}, cli.removed)
+	testutil.CheckDeepEqual(t, "Pruned 2 stale images\n", out.String())
+}
+
+func TestRepository(t *testing.T) {
+	testutil.CheckDeepEqual(t, "gcr.io/project/app", repository("gcr.io/project/app:v1"))
+	testutil.CheckDeepEqual(t, "localhost:5000/app", repository("localhost:5000/app"))
+	testutil.CheckDeepEqual(t, "localhost:5000/app", repository("localhost:5000/app:latest"))
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+)
+
+var (
+	insecureRegistries = map[string]bool{}
+	registryCABundle   string
+
+	// registryTransport is used by all the calls that skaffold makes to registries.
+	registryTransport http.RoundTripper = http.DefaultTransport
+)
+
+// ConfigureRegistries sets the registries that are accessed over plain HTTP,
+// and the CA bundle used to verify the certificates of self-hosted registries,
+// on top of the system's root certificates.
+func ConfigureRegistries(insecure []string, caBundle string) error {
+	insecureRegistries = map[string]bool{}
+	for _, registry := range insecure {
+		insecureRegistries[registry] = true
+	}
+
+	registryCABundle = caBundle
+	if caBundle == "" {
+		registryTransport = http.DefaultTransport
+		return nil
+	}
+
+	transport, err := transportWithCABundle(caBundle)
+	if err != nil {
+		return errors.Wrapf(err, "loading CA bundle %s", caBundle)
+	}
+	registryTransport = transport
+
+	return nil
+}
+
+// IsInsecureRegistry says if a registry should be accessed over plain HTTP.
+func IsInsecureRegistry(registry string) bool {
+	return insecureRegistries[registry]
+}
+
+// RegistryCABundle returns the path to the CA bundle used to verify self-hosted registries, if any.
+func RegistryCABundle() string {
+	return registryCABundle
+}
+
+func transportWithCABundle(caBundle string) (http.RoundTripper, error) {
+	pem, err := ioutil.ReadFile(caBundle)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.New("no certificate found")
+	}
+
+	// Same settings as http.DefaultTransport
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			DualStack: true,
+		}).DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       &tls.Config{RootCAs: pool},
+	}, nil
+}
+
+// parseReference parses an image reference, taking into account
+// that its registry might be insecure.
+func parseReference(image string) (name.Reference, error) {
+	ref, err := name.ParseReference(image, name.WeakValidation)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := ref.Context().RegistryStr()
+	if !IsInsecureRegistry(registry) {
+		return ref, nil
+	}
+
+	insecure, err := name.NewInsecureRegistry(registry, name.WeakValidation)
+	if err != nil {
+		return nil, err
+	}
+
+	switch r := ref.(type) {
+	case name.Tag:
+		r.Registry = insecure
+		return r, nil
+	case name.Digest:
+		r.Registry = insecure
+		return r, nil
+	default:
+		return ref, nil
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestParseReferenceInsecure(t *testing.T) {
+	defer ConfigureRegistries(nil, "")
+	err := ConfigureRegistries([]string{"registry.internal:5000"}, "")
+	testutil.CheckError(t, false, err)
+
+	var tests = []struct {
+		description    string
+		image          string
+		expectedScheme string
+	}{
+		{
+			description:    "insecure registry",
+			image:          "registry.internal:5000/project/image:tag",
+			expectedScheme: "http",
+		},
+		{
+			description:    "insecure registry with digest",
+			image:          "registry.internal:5000/image@sha256:81daf011d63b68cfa514ddab7741a1adddd59d3264118dfb0fd9266328bb8883",
+			expectedScheme: "http",
+		},
+		{
+			description:    "secure registry",
+			image:          "gcr.io/project/image:tag",
+			expectedScheme: "https",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			ref, err := parseReference(test.image)
+
+			testutil.CheckErrorAndDeepEqual(t, false, err, test.expectedScheme, ref.Context().Registry.Scheme())
+		})
+	}
+}
+
+func TestConfigureRegistriesCABundle(t *testing.T) {
+	defer ConfigureRegistries(nil, "")
+
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+	tmpDir.Write("invalid.pem", "not a certificate")
+
+	err := ConfigureRegistries(nil, tmpDir.Path("invalid.pem"))
+	testutil.CheckError(t, true, err)
+
+	err = ConfigureRegistries(nil, tmpDir.Path("missing.pem"))
+	testutil.CheckError(t, true, err)
+}
@@ -0,0 +1,279 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Phases of the pipeline.
+const (
+	Build       = "build"
+	Push        = "push"
+	Test        = "test"
+	Deploy      = "deploy"
+	StatusCheck = "statusCheck"
+	Verify      = "verify"
+	Summary     = "summary"
+)
+
+// Statuses of a phase.
+const (
+	Started  = "started"
+	Complete = "complete"
+	Failed   = "failed"
+)
+
+// Formats of the events.
+const (
+	// JSONOutput writes one JSON object per line.
+	JSONOutput = "json"
+	// GitHubOutput writes GitHub Actions workflow commands: phases are
+	// grouped and failures are reported as error annotations.
+	GitHubOutput = "github"
+	// JUnitOutput writes a JUnit XML report, with a test case per artifact
+	// or phase, that's updated after each of them.
+	JUnitOutput = "junit"
+)
+
+// Event is a transition of the pipeline, for a single artifact or for a whole phase.
+type Event struct {
+	Time       time.Time `json:"time"`
+	Phase      string    `json:"phase"`
+	Status     string    `json:"status"`
+	Artifact   string    `json:"artifact,omitempty"`
+	Tag        string    `json:"tag,omitempty"`
+	DurationMs int64     `json:"durationMs,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	File       string    `json:"file,omitempty"`
+	Timings    []Timing  `json:"timings,omitempty"`
+}
+
+// Timing is how long a phase, or the build or push of an artifact, took.
+type Timing struct {
+	Phase      string `json:"phase"`
+	Artifact   string `json:"artifact,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// Listener is notified of every event, whether they are written or not.
+// It must not send events.
+type Listener func(Event)
+
+// writer writes events in a given format.
+type writer interface {
+	write(e Event) error
+}
+
+type jsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *jsonWriter) write(e Event) error {
+	return w.encoder.Encode(e)
+}
+
+var (
+	lock      sync.Mutex
+	output    writer
+	listeners = map[int]Listener{}
+	nextID    int
+
+	// for testing
+	now = time.Now
+)
+
+// SetOutput chooses the format of the events and where they are written.
+// No event is written if the format is empty. The destination is a file path,
+// or `fd:N` for an already open file descriptor. It defaults to stderr.
+// JUnit reports are rewritten as the run progresses so they need a file path.
+func SetOutput(format, destination string) error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	switch format {
+	case "":
+		output = nil
+		return nil
+	case JUnitOutput:
+		if destination == "" || strings.HasPrefix(destination, "fd:") {
+			return fmt.Errorf("%s event output needs a file path", JUnitOutput)
+		}
+		output = &junitWriter{path: destination}
+		return nil
+	case JSONOutput, GitHubOutput:
+	default:
+		return fmt.Errorf("unknown event output %q, expected %s, %s or %s", format, JSONOutput, GitHubOutput, JUnitOutput)
+	}
+
+	out, err := open(destination)
+	if err != nil {
+		return errors.Wrap(err, "opening event output")
+	}
+
+	if format == GitHubOutput {
+		output = &githubWriter{out: out}
+	} else {
+		output = &jsonWriter{encoder: json.NewEncoder(out)}
+	}
+	return nil
+}
+
+func open(destination string) (io.Writer, error) {
+	switch {
+	case destination == "":
+		return os.Stderr, nil
+	case strings.HasPrefix(destination, "fd:"):
+		fd, err := strconv.Atoi(strings.TrimPrefix(destination, "fd:"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing file descriptor %s", destination)
+		}
+		return os.NewFile(uintptr(fd), destination), nil
+	default:
+		return os.Create(destination)
+	}
+}
+
+// Start reports that a phase, or the build of an artifact, started.
+// It returns the function that reports its end, with the image tag if any.
+func Start(phase, artifact string) func(tag string, err error) {
+	start := now()
+	send(Event{
+		Time:     start,
+		Phase:    phase,
+		Status:   Started,
+		Artifact: artifact,
+	})
+
+	return func(tag string, err error) {
+		t := now()
+		end := Event{
+			Time:       t,
+			Phase:      phase,
+			Status:     Complete,
+			Artifact:   artifact,
+			Tag:        tag,
+			DurationMs: milliseconds(t.Sub(start)),
+		}
+		if err != nil {
+			end.Status = Failed
+			end.Error = err.Error()
+			end.File = fileOf(err)
+		}
+		send(end)
+	}
+}
+
+// SendSummary reports the timings of a whole run.
+func SendSummary(timings []Timing) {
+	send(Event{
+		Time:    now(),
+		Phase:   Summary,
+		Status:  Complete,
+		Timings: timings,
+	})
+}
+
+// AddListener registers a listener and returns the function that removes it.
+func AddListener(listener Listener) func() {
+	lock.Lock()
+	defer lock.Unlock()
+
+	id := nextID
+	nextID++
+	listeners[id] = listener
+
+	return func() {
+		lock.Lock()
+		defer lock.Unlock()
+
+		delete(listeners, id)
+	}
+}
+
+func send(e Event) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	for _, listener := range listeners {
+		listener(e)
+	}
+
+	if output == nil {
+		return
+	}
+	if err := output.write(e); err != nil {
+		logrus.Debugln("Unable to write event:", err)
+	}
+}
+
+// FileError is an error caused by a given file, such as a Dockerfile
+// or a manifest. The file is reported with the event of the failure.
+type FileError struct {
+	File string
+	Err  error
+}
+
+func (e *FileError) Error() string {
+	return e.Err.Error()
+}
+
+// Cause returns the underlying error.
+func (e *FileError) Cause() error {
+	return e.Err
+}
+
+// WithFile attributes an error to a file.
+func WithFile(err error, file string) error {
+	if err == nil || file == "" {
+		return err
+	}
+	return &FileError{File: file, Err: err}
+}
+
+// fileOf finds the file an error is attributed to, if any.
+func fileOf(err error) string {
+	type causer interface {
+		Cause() error
+	}
+
+	for err != nil {
+		if fileErr, ok := err.(*FileError); ok {
+			return fileErr.File
+		}
+		cause, ok := err.(causer)
+		if !ok {
+			return ""
+		}
+		err = cause.Cause()
+	}
+	return ""
+}
+
+func milliseconds(d time.Duration) int64 {
+	return d.Nanoseconds() / int64(time.Millisecond)
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package event
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	pkgerrors "github.com/pkg/errors"
+)
+
+func TestSetOutput(t *testing.T) {
+	defer SetOutput("", "")
+
+	testutil.CheckError(t, false, SetOutput("", ""))
+	testutil.CheckError(t, false, SetOutput("json", ""))
+	testutil.CheckError(t, true, SetOutput("yaml", ""))
+	testutil.CheckError(t, true, SetOutput("json", "fd:three"))
+	testutil.CheckError(t, false, SetOutput("github", ""))
+	testutil.CheckError(t, true, SetOutput("junit", ""))
+	testutil.CheckError(t, true, SetOutput("junit", "fd:3"))
+}
+
+func TestEvents(t *testing.T) {
+	tmpDir, teardown := testutil.NewTempDir(t)
+	defer teardown()
+	defer SetOutput("", "")
+
+	clock := time.Date(2018, 11, 8, 10, 0, 0, 0, time.UTC)
+	defer func(n func() time.Time) { now = n }(now)
+	now = func() time.Time {
+		clock = clock.Add(1500 * time.Millisecond)
+		return clock
+	}
+
+	path := filepath.Join(tmpDir.Root(), "events.json")
+	testutil.CheckError(t, false, SetOutput("json", path))
+
+	end := Start(Build, "gcr.io/project/image")
+	end("gcr.io/project/image:v1", nil)
+	Start(Deploy, "")("", errors.New("kubectl apply failed"))
+
+	buf, err := ioutil.ReadFile(path)
+	testutil.CheckErrorAndDeepEqual(t, false, err, `{"time":"2018-11-08T10:00:01.5Z","phase":"build","status":"started","artifact":"gcr.io/project/image"}
+{"time":"2018-11-08T10:00:03Z","phase":"build","status":"complete","artifact":"gcr.io/project/image","tag":"gcr.io/project/image:v1","durationMs":1500}
+{"time":"2018-11-08T10:00:04.5Z","phase":"deploy","status":"started"}
+{"time":"2018-11-08T10:00:06Z","phase":"deploy","status":"failed","durationMs":1500,"error":"kubectl apply failed"}
+`, string(buf))
+}
+
+func TestGitHubOutput(t *testing.T) {
+	tmpDir, teardown := testutil.NewTempDir(t)
+	defer teardown()
+	defer SetOutput("", "")
+
+	path := filepath.Join(tmpDir.Root(), "events.txt")
+	testutil.CheckError(t, false, SetOutput("github", path))
+
+	endBuild := Start(Build, "")
+	Start(Build, "image1")("image1:v1", nil)
+	Start(Build, "image2")("", WithFile(errors.New("step 2: exit 1\nno such file"), "app/Dockerfile"))
+	endBuild("", errors.New("build failed"))
+	SendSummary(nil)
+
+	buf, err := ioutil.ReadFile(path)
+	testutil.CheckErrorAndDeepEqual(t, false, err, `::group::build
+::error file=app/Dockerfile,title=build of image2 failed::step 2: exit 1%0Ano such file
+::error title=build failed::build failed
+::endgroup::
+`, string(buf))
+}
+
+func TestJUnitOutput(t *testing.T) {
+	tmpDir, teardown := testutil.NewTempDir(t)
+	defer teardown()
+	defer SetOutput("", "")
+
+	clock := time.Date(2018, 11, 8, 10, 0, 0, 0, time.UTC)
+	defer func(n func() time.Time) { now = n }(now)
+	now = func() time.Time {
+		clock = clock.Add(1500 * time.Millisecond)
+		return clock
+	}
+
+	path := filepath.Join(tmpDir.Root(), "report.xml")
+	testutil.CheckError(t, false, SetOutput("junit", path))
+
+	Start(Build, "image")("image:v1", nil)
+	Start(Deploy, "")("", WithFile(errors.New("invalid yaml"), "k8s/pod.yaml"))
+
+	buf, err := ioutil.ReadFile(path)
+	testutil.CheckErrorAndDeepEqual(t, false, err, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="skaffold" tests="2" failures="1">
+    <testcase classname="build" name="image" time="1.500"></testcase>
+    <testcase classname="deploy" name="deploy" file="k8s/pod.yaml" time="1.500">
+      <failure message="invalid yaml" type="deploy"></failure>
+    </testcase>
+  </testsuite>
+</testsuites>`, string(buf))
+}
+
+func TestFileOf(t *testing.T) {
+	err := WithFile(errors.New("failed"), "Dockerfile")
+
+	testutil.CheckDeepEqual(t, "Dockerfile", fileOf(err))
+	testutil.CheckDeepEqual(t, "Dockerfile", fileOf(pkgerrors.Wrap(err, "building")))
+	testutil.CheckDeepEqual(t, "", fileOf(errors.New("failed")))
+	testutil.CheckDeepEqual(t, nil, WithFile(nil, "Dockerfile"))
+}
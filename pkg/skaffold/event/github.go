@@ -0,0 +1,75 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package event
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// githubWriter writes GitHub Actions workflow commands.
+// See https://help.github.com/en/actions/reference/workflow-commands-for-github-actions
+type githubWriter struct {
+	out io.Writer
+}
+
+func (w *githubWriter) write(e Event) error {
+	if e.Phase == Summary {
+		return nil
+	}
+
+	if e.Status == Failed {
+		title := fmt.Sprintf("%s failed", e.Phase)
+		if e.Artifact != "" {
+			title = fmt.Sprintf("%s of %s failed", e.Phase, e.Artifact)
+		}
+
+		properties := "title=" + escapeProperty(title)
+		if e.File != "" {
+			properties = "file=" + escapeProperty(e.File) + "," + properties
+		}
+
+		if _, err := fmt.Fprintf(w.out, "::error %s::%s\n", properties, escapeData(e.Error)); err != nil {
+			return err
+		}
+	}
+
+	// Whole phases are grouped. Artifacts can't be since they are built in parallel.
+	if e.Artifact != "" {
+		return nil
+	}
+	if e.Status == Started {
+		_, err := fmt.Fprintf(w.out, "::group::%s\n", escapeData(e.Phase))
+		return err
+	}
+	_, err := fmt.Fprintln(w.out, "::endgroup::")
+	return err
+}
+
+var (
+	dataEscaper     = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	propertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+func escapeData(s string) string {
+	return dataEscaper.Replace(s)
+}
+
+func escapeProperty(s string) string {
+	return propertyEscaper.Replace(s)
+}
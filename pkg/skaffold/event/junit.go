@@ -0,0 +1,99 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package event
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+)
+
+// junitWriter writes a JUnit XML report with a test case per artifact or phase.
+// The whole report is rewritten each time one of them ends, so that it's
+// complete even if skaffold is interrupted.
+type junitWriter struct {
+	path  string
+	cases []junitTestCase
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+}
+
+func (w *junitWriter) write(e Event) error {
+	if e.Status == Started || e.Phase == Summary {
+		return nil
+	}
+
+	name := e.Artifact
+	if name == "" {
+		name = e.Phase
+	}
+
+	testCase := junitTestCase{
+		ClassName: e.Phase,
+		Name:      name,
+		File:      e.File,
+		Time:      fmt.Sprintf("%.3f", float64(e.DurationMs)/1000),
+	}
+	if e.Status == Failed {
+		testCase.Failure = &junitFailure{
+			Message: e.Error,
+			Type:    e.Phase,
+		}
+	}
+	w.cases = append(w.cases, testCase)
+
+	suite := junitTestSuite{
+		Name:  "skaffold",
+		Tests: len(w.cases),
+		Cases: w.cases,
+	}
+	for _, c := range w.cases {
+		if c.Failure != nil {
+			suite.Failures++
+		}
+	}
+
+	buf, err := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(w.path, append([]byte(xml.Header), buf...), 0644)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exitcode
+
+// Exit codes returned by skaffold commands, so that CI pipelines can
+// tell the type of failure.
+const (
+	// Failure is the exit code of failures that don't have a specific code.
+	Failure = 1
+
+	// Config is the exit code used when the skaffold configuration can't be read,
+	// parsed or validated.
+	Config = 2
+
+	// Build is the exit code used when an artifact fails to build.
+	Build = 3
+
+	// Deploy is the exit code used when the deployment fails or
+	// the deployed resources fail to roll out.
+	Deploy = 4
+
+	// StatusCheckTimeout is the exit code used when the deployed resources
+	// are not rolled out within the status check timeout.
+	StatusCheckTimeout = 5
+)
+
+// codedError is an error that carries the exit code of its failure class.
+type codedError struct {
+	err  error
+	code int
+}
+
+func (e *codedError) Error() string {
+	return e.err.Error()
+}
+
+// Cause returns the underlying error.
+func (e *codedError) Cause() error {
+	return e.err
+}
+
+// Wrap tags an error with an exit code. It returns nil if err is nil.
+// An error that was already tagged keeps its original code.
+func Wrap(err error, code int) error {
+	if err == nil {
+		return nil
+	}
+	if _, tagged := find(err); tagged {
+		return err
+	}
+	return &codedError{err: err, code: code}
+}
+
+// Of returns the exit code for an error: 0 for nil, the code it was
+// tagged with, or Failure.
+func Of(err error) int {
+	if err == nil {
+		return 0
+	}
+	if code, tagged := find(err); tagged {
+		return code
+	}
+	return Failure
+}
+
+type causer interface {
+	Cause() error
+}
+
+// find walks the chain of wrapped errors and returns the first code it finds.
+func find(err error) (int, bool) {
+	for err != nil {
+		if coded, ok := err.(*codedError); ok {
+			return coded.code, true
+		}
+		cause, ok := err.(causer)
+		if !ok {
+			break
+		}
+		err = cause.Cause()
+	}
+	return 0, false
+}
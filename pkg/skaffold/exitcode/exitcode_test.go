@@ -0,0 +1,70 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exitcode
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"github.com/pkg/errors"
+)
+
+func TestOf(t *testing.T) {
+	var tests = []struct {
+		description string
+		err         error
+		expected    int
+	}{
+		{
+			description: "no error",
+			expected:    0,
+		},
+		{
+			description: "untagged error",
+			err:         fmt.Errorf("failure"),
+			expected:    Failure,
+		},
+		{
+			description: "tagged error",
+			err:         Wrap(fmt.Errorf("failure"), Build),
+			expected:    Build,
+		},
+		{
+			description: "wrapped tagged error",
+			err:         errors.Wrap(Wrap(fmt.Errorf("failure"), Deploy), "deploy step"),
+			expected:    Deploy,
+		},
+		{
+			description: "innermost code wins",
+			err:         Wrap(errors.Wrap(Wrap(fmt.Errorf("failure"), StatusCheckTimeout), "status check"), Deploy),
+			expected:    StatusCheckTimeout,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			testutil.CheckDeepEqual(t, test.expected, Of(test.err))
+		})
+	}
+}
+
+func TestWrapNil(t *testing.T) {
+	if err := Wrap(nil, Build); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+}
@@ -16,37 +16,10 @@ limitations under the License.
 
 package gcp
 
-import (
-	"os/exec"
-	"strings"
+import "strings"
 
-	"github.com/docker/cli/cli/config/configfile"
-	"github.com/sirupsen/logrus"
-)
-
-// AutoConfigureGCRCredentialHelper automatically adds the `gcloud` credential helper
-// to docker's configuration.
-// This doesn't modify the ~/.docker/config.json. It's only in-memory
-func AutoConfigureGCRCredentialHelper(cf *configfile.ConfigFile, registry string) {
-	if registry != "gcr.io" && !strings.HasSuffix(registry, ".gcr.io") {
-		logrus.Debugln("Skipping credential configuration because registry is not gcr.")
-		return
-	}
-
-	if cf.CredentialHelpers != nil && cf.CredentialHelpers[registry] != "" {
-		logrus.Debugln("Skipping credential configuration because credentials are already configured.")
-		return
-	}
-
-	if path, _ := exec.LookPath("docker-credential-gcloud"); path == "" {
-		logrus.Debugln("Skipping credential configuration because docker-credential-gcloud is not on PATH.")
-		return
-	}
-
-	logrus.Debugf("Adding `gcloud` as a docker credential helper for [%s]", registry)
-
-	if cf.CredentialHelpers == nil {
-		cf.CredentialHelpers = make(map[string]string)
-	}
-	cf.CredentialHelpers[registry] = "gcloud"
+// IsGCR says if a registry is a Google Container Registry or an Artifact Registry,
+// whose credentials are given by the `gcloud` credential helper.
+func IsGCR(registry string) bool {
+	return registry == "gcr.io" || strings.HasSuffix(registry, ".gcr.io") || strings.HasSuffix(registry, "-docker.pkg.dev")
 }
@@ -1,5 +1,3 @@
-// +build !windows
-
 /*
 Copyright 2018 The Skaffold Authors
 
@@ -22,98 +20,23 @@ import (
 	"testing"
 
 	"github.com/GoogleContainerTools/skaffold/testutil"
-	"github.com/docker/cli/cli/config/configfile"
 )
 
-func TestAutoConfigureGCRCredentialHelper(t *testing.T) {
+func TestIsGCR(t *testing.T) {
 	var tests = []struct {
-		description  string
-		registry     string
-		helperInPath bool
-		config       *configfile.ConfigFile
-		expected     *configfile.ConfigFile
+		registry string
+		expected bool
 	}{
-		{
-			description:  "add to nil map",
-			registry:     "gcr.io",
-			helperInPath: true,
-			config:       &configfile.ConfigFile{},
-			expected: &configfile.ConfigFile{
-				CredentialHelpers: map[string]string{
-					"gcr.io": "gcloud",
-				},
-			},
-		},
-		{
-			description:  "add to empty map",
-			registry:     "gcr.io",
-			helperInPath: true,
-			config: &configfile.ConfigFile{
-				CredentialHelpers: map[string]string{},
-			},
-			expected: &configfile.ConfigFile{
-				CredentialHelpers: map[string]string{
-					"gcr.io": "gcloud",
-				},
-			},
-		},
-		{
-			description: "leave existing helper",
-			registry:    "gcr.io",
-			config: &configfile.ConfigFile{
-				CredentialHelpers: map[string]string{
-					"gcr.io": "existing",
-				},
-			},
-			expected: &configfile.ConfigFile{
-				CredentialHelpers: map[string]string{
-					"gcr.io": "existing",
-				},
-			},
-		},
-		{
-			description:  "any.gcr.io",
-			registry:     "any.gcr.io",
-			helperInPath: true,
-			config:       &configfile.ConfigFile{},
-			expected: &configfile.ConfigFile{
-				CredentialHelpers: map[string]string{
-					"any.gcr.io": "gcloud",
-				},
-			},
-		},
-		{
-			description:  "case is important",
-			registry:     "GCR.io",
-			helperInPath: true,
-			config:       &configfile.ConfigFile{},
-			expected:     &configfile.ConfigFile{},
-		},
-		{
-			description:  "ignore if gcloud is not in PATH",
-			registry:     "gcr.io",
-			helperInPath: false,
-			config:       &configfile.ConfigFile{},
-			expected:     &configfile.ConfigFile{},
-		},
+		{registry: "gcr.io", expected: true},
+		{registry: "eu.gcr.io", expected: true},
+		{registry: "europe-west1-docker.pkg.dev", expected: true},
+		{registry: "GCR.io", expected: false},
+		{registry: "notgcr.io", expected: false},
+		{registry: "index.docker.io", expected: false},
 	}
 	for _, test := range tests {
-		t.Run(test.description, func(t *testing.T) {
-			tmp, cleanup := testutil.NewTempDir(t)
-			defer cleanup()
-
-			reset := testutil.SetEnvs(t, map[string]string{
-				"PATH": tmp.Root(),
-			})
-			defer reset(t)
-
-			if test.helperInPath {
-				tmp.Write("docker-credential-gcloud", "")
-			}
-
-			AutoConfigureGCRCredentialHelper(test.config, test.registry)
-
-			testutil.CheckDeepEqual(t, test.expected, test.config)
+		t.Run(test.registry, func(t *testing.T) {
+			testutil.CheckDeepEqual(t, test.expected, IsGCR(test.registry))
 		})
 	}
 }
@@ -0,0 +1,100 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcp
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	cstorage "cloud.google.com/go/storage"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/version"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2/google"
+	cloudbuild "google.golang.org/api/cloudbuild/v1"
+)
+
+// The clients are created on first use, so that pipelines that don't use
+// Google Cloud don't pay for reading the credentials, or fail when they are
+// missing. They are then shared by all the builds.
+var (
+	clientsLock      sync.Mutex
+	storageClient    *cstorage.Client
+	cloudBuildClient *cloudbuild.Service
+	httpClient       *http.Client
+)
+
+// StorageClient returns the shared Cloud Storage client.
+func StorageClient() (*cstorage.Client, error) {
+	clientsLock.Lock()
+	defer clientsLock.Unlock()
+
+	if storageClient != nil {
+		return storageClient, nil
+	}
+
+	// The client outlives the builds so it can't be bound to their context.
+	c, err := cstorage.NewClient(context.Background())
+	if err != nil {
+		return nil, errors.Wrap(err, "getting cloud storage client")
+	}
+	storageClient = c
+	return storageClient, nil
+}
+
+// CloudBuildClient returns the shared Cloud Build client.
+func CloudBuildClient() (*cloudbuild.Service, error) {
+	clientsLock.Lock()
+	defer clientsLock.Unlock()
+
+	if cloudBuildClient != nil {
+		return cloudBuildClient, nil
+	}
+
+	client, err := google.DefaultClient(context.Background(), cloudbuild.CloudPlatformScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting google client")
+	}
+
+	c, err := cloudbuild.New(client)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting builder")
+	}
+	c.UserAgent = version.UserAgent()
+
+	cloudBuildClient = c
+	return cloudBuildClient, nil
+}
+
+// HTTPClient returns the shared HTTP client authenticated with the application
+// default credentials, for the Google Cloud APIs that don't have a vendored client.
+func HTTPClient() (*http.Client, error) {
+	clientsLock.Lock()
+	defer clientsLock.Unlock()
+
+	if httpClient != nil {
+		return httpClient, nil
+	}
+
+	client, err := google.DefaultClient(context.Background(), cloudbuild.CloudPlatformScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting google client")
+	}
+
+	httpClient = client
+	return httpClient, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package intercept
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// stopTimeout is how long the local process has to exit once interrupted.
+var stopTimeout = 10 * time.Second // for testing
+
+// Session is a local process that receives the traffic of a workload through telepresence.
+type Session struct {
+	workload string
+	cmd      *exec.Cmd
+	done     chan error
+}
+
+// Start intercepts the workload of an artifact and runs the local process in its place.
+func Start(out io.Writer, namespace string, a *latest.Artifact) (*Session, error) {
+	color.Default.Fprintf(out, "Sending the traffic of %s to a local process: %v\n", a.Intercept.Workload, a.Intercept.Command)
+
+	cmd := exec.Command("telepresence", args(namespace, a.Intercept)...)
+	cmd.Dir = a.Workspace
+	cmd.Stdout = out
+	cmd.Stderr = out
+	if err := cmd.Start(); err != nil {
+		return nil, errors.Wrapf(err, "intercepting %s, is telepresence installed?", a.Intercept.Workload)
+	}
+
+	s := &Session{
+		workload: a.Intercept.Workload,
+		cmd:      cmd,
+		done:     make(chan error, 1),
+	}
+	go func() {
+		err := cmd.Wait()
+		logrus.Debugf("Local process for %s exited: %v", s.workload, err)
+		s.done <- err
+	}()
+
+	return s, nil
+}
+
+// Stop stops the local process and removes the intercept, so that the
+// workload's pods get their traffic back.
+func (s *Session) Stop() {
+	if err := s.cmd.Process.Signal(os.Interrupt); err != nil {
+		s.cmd.Process.Kill()
+	}
+
+	select {
+	case <-s.done:
+	case <-time.After(stopTimeout):
+		s.cmd.Process.Kill()
+		<-s.done
+	}
+
+	// Telepresence leaves the intercept when the process exits, unless it was killed.
+	if _, err := util.RunCmdOut(exec.Command("telepresence", "leave", s.workload)); err != nil {
+		logrus.Debugf("Unable to leave the intercept of %s: %s", s.workload, err)
+	}
+}
+
+func args(namespace string, cfg *latest.InterceptConfig) []string {
+	args := []string{"intercept", cfg.Workload}
+	if namespace != "" {
+		args = append(args, "--namespace", namespace)
+	}
+	if cfg.Port != "" {
+		args = append(args, "--port", cfg.Port)
+	}
+
+	return append(append(args, "--"), cfg.Command...)
+}
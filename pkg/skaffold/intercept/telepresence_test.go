@@ -0,0 +1,50 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package intercept
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestArgs(t *testing.T) {
+	var tests = []struct {
+		description string
+		namespace   string
+		cfg         *latest.InterceptConfig
+		expected    []string
+	}{
+		{
+			description: "minimal",
+			cfg:         &latest.InterceptConfig{Workload: "web", Command: []string{"go", "run", "."}},
+			expected:    []string{"intercept", "web", "--", "go", "run", "."},
+		},
+		{
+			description: "namespace and port",
+			namespace:   "dev",
+			cfg:         &latest.InterceptConfig{Workload: "web", Port: "8080:http", Command: []string{"npm", "start"}},
+			expected:    []string{"intercept", "web", "--namespace", "dev", "--port", "8080:http", "--", "npm", "start"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			testutil.CheckDeepEqual(t, test.expected, args(test.namespace, test.cfg))
+		})
+	}
+}
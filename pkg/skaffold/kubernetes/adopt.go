@@ -0,0 +1,180 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+// Workload selects the pods of a deployed workload.
+type Workload struct {
+	Namespace     string
+	LabelSelector string
+	FieldSelector string
+}
+
+// Adopt labels the pods of the given workloads, and the ones they create later, with
+// the labels the cache selects. This is how the pods of a skaffold run are found,
+// whichever deployer created them. Unlike their pod templates, pods can be labelled
+// without rolling out their workloads, so the labels can change with every run.
+func (c *PodCache) Adopt(workloads []Workload) error {
+	if len(c.labels) == 0 {
+		return nil
+	}
+
+	client, err := Client()
+	if err != nil {
+		return errors.Wrap(err, "getting k8s client")
+	}
+
+	for _, workload := range workloads {
+		c.lock.Lock()
+		adopted := c.adopted[workload]
+		c.adopted[workload] = true
+		c.lock.Unlock()
+		if adopted {
+			continue
+		}
+
+		watcher, err := c.adoptPods(client, workload)
+		if err != nil {
+			c.lock.Lock()
+			delete(c.adopted, workload)
+			c.lock.Unlock()
+			return errors.Wrap(err, "labelling pods")
+		}
+		go c.runAdoption(client, workload, watcher)
+	}
+	return nil
+}
+
+// adoptPods labels the pods of a workload then starts watching for new ones.
+func (c *PodCache) adoptPods(client k8s.Interface, workload Workload) (watch.Interface, error) {
+	pods := client.CoreV1().Pods(workload.Namespace)
+	opts := meta_v1.ListOptions{
+		LabelSelector: workload.LabelSelector,
+		FieldSelector: workload.FieldSelector,
+	}
+
+	var list *v1.PodList
+	var err error
+	if err := RetryOnConnectionError(func() error {
+		list, err = pods.List(opts)
+		return err
+	}); err != nil {
+		return nil, errors.Wrap(classify(err), "listing pods")
+	}
+	for i := range list.Items {
+		c.label(client, workload, &list.Items[i])
+	}
+
+	var forever int64 = 3600 * 24 * 365 * 100
+	opts.ResourceVersion = list.ResourceVersion
+	opts.TimeoutSeconds = &forever
+
+	var watcher watch.Interface
+	if err := RetryOnConnectionError(func() error {
+		watcher, err = pods.Watch(opts)
+		return err
+	}); err != nil {
+		return nil, errors.Wrap(classify(err), "watching pods")
+	}
+	return watcher, nil
+}
+
+// runAdoption labels the pods of a workload as they're created, until the cache is stopped.
+func (c *PodCache) runAdoption(client k8s.Interface, workload Workload, watcher watch.Interface) {
+	defer func() { watcher.Stop() }()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case evt, ok := <-watcher.ResultChan():
+			if !ok || evt.Type == watch.Error {
+				watcher.Stop()
+
+				restarted, err := c.adoptPods(client, workload)
+				if err != nil {
+					logrus.Warnln("Unable to label pods anymore:", err)
+					return
+				}
+				watcher = restarted
+				continue
+			}
+
+			if pod, ok := evt.Object.(*v1.Pod); ok && evt.Type != watch.Deleted {
+				c.label(client, workload, pod)
+			}
+		}
+	}
+}
+
+// label adds the labels of the cache to a pod of the workload that doesn't have them yet.
+func (c *PodCache) label(client k8s.Interface, workload Workload, pod *v1.Pod) {
+	if pod.DeletionTimestamp != nil || hasLabels(pod, c.labels) || !workload.selects(pod) {
+		return
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": c.labels,
+		},
+	})
+	if err != nil {
+		return
+	}
+	if _, err := client.CoreV1().Pods(pod.Namespace).Patch(pod.Name, types.StrategicMergePatchType, patch); err != nil {
+		logrus.Debugf("Unable to label pod %s: %s", pod.Name, err)
+	}
+}
+
+// selects checks that a pod is one of the workload's.
+func (w Workload) selects(pod *v1.Pod) bool {
+	labelSelector, err := labels.Parse(w.LabelSelector)
+	if err != nil || !labelSelector.Matches(labels.Set(pod.Labels)) {
+		return false
+	}
+
+	fieldSelector, err := fields.ParseSelector(w.FieldSelector)
+	if err != nil {
+		return false
+	}
+	return fieldSelector.Matches(fields.Set{
+		"metadata.name":      pod.Name,
+		"metadata.namespace": pod.Namespace,
+	})
+}
+
+func hasLabels(pod *v1.Pod, labels map[string]string) bool {
+	for k, v := range labels {
+		if pod.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
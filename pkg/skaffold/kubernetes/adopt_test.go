@@ -0,0 +1,109 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAdoptTwoRunsInOneNamespace(t *testing.T) {
+	pod := func(name, app string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": app}}}
+	}
+	client := fake.NewSimpleClientset(pod("first-1", "first"), pod("second-1", "second"))
+
+	defer func(c func() (k8s.Interface, error)) { Client = c }(Client)
+	Client = func() (k8s.Interface, error) { return client, nil }
+
+	first := NewPodCache("default", map[string]string{"skaffold-run-id": "run1"})
+	defer first.Stop()
+	second := NewPodCache("default", map[string]string{"skaffold-run-id": "run2"})
+	defer second.Stop()
+
+	err := first.Adopt([]Workload{{Namespace: "default", LabelSelector: "app=first"}})
+	testutil.CheckError(t, false, err)
+	err = second.Adopt([]Workload{{Namespace: "default", LabelSelector: "app=second"}})
+	testutil.CheckError(t, false, err)
+
+	// Pods created later are labelled too.
+	client.CoreV1().Pods("default").Create(pod("first-2", "first"))
+	client.CoreV1().Pods("default").Create(pod("second-2", "second"))
+
+	expected := map[string]string{
+		"first-1":  "run1",
+		"first-2":  "run1",
+		"second-1": "run2",
+		"second-2": "run2",
+	}
+	for name, runID := range expected {
+		waitForLabel(t, client, name, runID)
+	}
+
+	pods, err := first.Pods()
+	testutil.CheckError(t, false, err)
+	testutil.CheckDeepEqual(t, []string{"first-1", "first-2"}, podNames(pods))
+
+	pods, err = second.Pods()
+	testutil.CheckError(t, false, err)
+	testutil.CheckDeepEqual(t, []string{"second-1", "second-2"}, podNames(pods))
+}
+
+func TestAdoptWithoutLabels(t *testing.T) {
+	client := fake.NewSimpleClientset(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default"}})
+
+	defer func(c func() (k8s.Interface, error)) { Client = c }(Client)
+	Client = func() (k8s.Interface, error) { return client, nil }
+
+	cache := NewPodCache("default", nil)
+	defer cache.Stop()
+
+	err := cache.Adopt([]Workload{{Namespace: "default", FieldSelector: "metadata.name=pod"}})
+	testutil.CheckError(t, false, err)
+
+	testutil.CheckDeepEqual(t, 0, len(client.Actions()))
+}
+
+func waitForLabel(t *testing.T, client k8s.Interface, name, runID string) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		pod, err := client.CoreV1().Pods("default").Get(name, metav1.GetOptions{})
+		if err == nil && pod.Labels["skaffold-run-id"] == runID {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("pod %s wasn't labelled with run ID %s", name, runID)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func podNames(pods []v1.Pod) []string {
+	var names []string
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	sort.Strings(names)
+	return names
+}
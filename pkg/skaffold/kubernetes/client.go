@@ -18,7 +18,9 @@ package kubernetes
 
 import (
 	"fmt"
+	"sync"
 
+	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
 	"github.com/pkg/errors"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
@@ -29,28 +31,100 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 )
 
+// The clients are shared by all of skaffold: they are created on first use,
+// from the kubeconfig and context skaffold was configured with, and then
+// reuse the same connections.
+var (
+	clientsLock   sync.Mutex
+	restConfig    *restclient.Config
+	clientset     kubernetes.Interface
+	dynamicClient dynamic.Interface
+)
+
+// A single client serves the pod watchers, port forwarding, status checks,
+// file sync and builders concurrently. client-go's default is 5 QPS.
+const (
+	clientQPS   = 20
+	clientBurst = 50
+)
+
+// GetClientset returns the shared kubernetes clientset.
 func GetClientset() (kubernetes.Interface, error) {
-	config, err := getClientConfig()
+	clientsLock.Lock()
+	defer clientsLock.Unlock()
+
+	if clientset != nil {
+		return clientset, nil
+	}
+
+	config, err := sharedClientConfig()
 	if err != nil {
 		return nil, errors.Wrap(err, "getting client config for kubernetes client")
 	}
-	return kubernetes.NewForConfig(config)
-}
 
-func getClientConfig() (*restclient.Config, error) {
-	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
-	clientConfig, err := kubeConfig.ClientConfig()
+	c, err := kubernetes.NewForConfig(config)
 	if err != nil {
-		return nil, fmt.Errorf("error creating kubeConfig: %s", err)
+		return nil, err
 	}
-	return clientConfig, nil
+	clientset = c
+	return clientset, nil
 }
 
+// GetDynamicClient returns the shared dynamic client.
 func GetDynamicClient() (dynamic.Interface, error) {
-	config, err := getClientConfig()
+	clientsLock.Lock()
+	defer clientsLock.Unlock()
+
+	if dynamicClient != nil {
+		return dynamicClient, nil
+	}
+
+	config, err := sharedClientConfig()
 	if err != nil {
 		return nil, errors.Wrap(err, "getting client config for dynamic client")
 	}
-	return dynamic.NewForConfig(config)
+
+	c, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient = c
+	return dynamicClient, nil
+}
+
+// sharedClientConfig must be called with clientsLock held.
+func sharedClientConfig() (*restclient.Config, error) {
+	if restConfig != nil {
+		return restConfig, nil
+	}
+
+	config, err := getClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	if config.QPS == 0 {
+		config.QPS = clientQPS
+	}
+	if config.Burst == 0 {
+		config.Burst = clientBurst
+	}
+
+	restConfig = config
+	return restConfig, nil
+}
+
+func getClientConfig() (*restclient.Config, error) {
+	if kubectx.InCluster() {
+		return restclient.InClusterConfig()
+	}
+
+	clientConfig, err := kubectx.ClientConfig().ClientConfig()
+	if clientcmd.IsContextNotFound(err) || clientcmd.IsEmptyConfig(err) {
+		current, _ := kubectx.CurrentContext()
+		return nil, &ContextNotFoundError{Context: current}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error creating kubeConfig: %s", err)
+	}
+	return clientConfig, nil
 }
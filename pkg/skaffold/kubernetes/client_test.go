@@ -0,0 +1,53 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestSharedClients(t *testing.T) {
+	unset := testutil.SetupFakeKubernetesContext(t, api.Config{
+		CurrentContext: "cluster1",
+		Clusters: map[string]*api.Cluster{
+			"cluster1": {Server: "https://127.0.0.1:6443"},
+		},
+		Contexts: map[string]*api.Context{
+			"cluster1": {Cluster: "cluster1"},
+		},
+	})
+	defer unset()
+	defer func() {
+		restConfig, clientset, dynamicClient = nil, nil, nil
+	}()
+
+	first, err := GetClientset()
+	testutil.CheckError(t, false, err)
+	second, err := GetClientset()
+	testutil.CheckError(t, false, err)
+	if first != second {
+		t.Error("Expected the clientset to be shared")
+	}
+
+	_, err = GetDynamicClient()
+	testutil.CheckError(t, false, err)
+	testutil.CheckDeepEqual(t, float32(clientQPS), restConfig.QPS)
+	testutil.CheckDeepEqual(t, "https://127.0.0.1:6443", restConfig.Host)
+}
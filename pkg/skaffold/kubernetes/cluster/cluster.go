@@ -0,0 +1,196 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/sirupsen/logrus"
+)
+
+// Types of the local clusters skaffold knows about.
+const (
+	Minikube       = "minikube"
+	DockerDesktop  = "docker-desktop"
+	RancherDesktop = "rancher-desktop"
+	MicroK8s       = "microk8s"
+	Kind           = "kind"
+	K3d            = "k3d"
+
+	// Custom is the type of the clusters declared local by the user.
+	Custom = "custom"
+)
+
+// Rule makes skaffold treat the clusters of the kube-contexts
+// matching a pattern as local.
+type Rule struct {
+	// Pattern is a glob matched against the kube-context.
+	Pattern string
+
+	// Push says if images are pushed to a registry. They aren't by default.
+	Push *bool
+
+	// Load is the command that loads an image into the cluster.
+	// The image name is appended to it.
+	Load string
+}
+
+// Cluster describes the cluster a kube-context points at.
+type Cluster struct {
+	// Type is the type of a local cluster. It's empty for remote clusters.
+	Type string
+
+	// Push says if images have to be pushed to a registry by default.
+	Push bool
+
+	// load is the command that loads an image into the cluster, without
+	// the image. It's empty when the cluster shares the docker daemon.
+	load []string
+}
+
+// IsLocal says if the cluster runs on the same machine as skaffold.
+func (c Cluster) IsLocal() bool {
+	return c.Type != ""
+}
+
+// LoadCommand returns the command that copies an image from the local docker
+// daemon to the nodes of the cluster. It returns nil when the cluster
+// sees the images of the daemon directly.
+func (c Cluster) LoadCommand(ctx context.Context, image string) *exec.Cmd {
+	if len(c.load) == 0 {
+		return nil
+	}
+
+	args := append(append([]string{}, c.load[1:]...), image)
+	return exec.CommandContext(ctx, c.load[0], args...)
+}
+
+// detector recognizes the kube-contexts of a type of local cluster.
+type detector struct {
+	clusterType string
+	push        bool
+
+	// match returns the name of the cluster when the kube-context is one of its.
+	match func(kubeContext string) (string, bool)
+
+	// load returns the command that loads an image into the named cluster.
+	load func(name string) []string
+}
+
+var detectors = []detector{
+	{
+		clusterType: Minikube,
+		match:       equals(constants.DefaultMinikubeContext),
+		load:        func(string) []string { return []string{"minikube", "image", "load"} },
+	},
+	{
+		clusterType: DockerDesktop,
+		match:       equals(constants.DefaultDockerForDesktopContext, "docker-desktop"),
+	},
+	{
+		clusterType: RancherDesktop,
+		match:       equals("rancher-desktop"),
+	},
+	{
+		// MicroK8s has no access to the docker daemon. Its registry
+		// addon, on localhost:32000, is the usual way to get images in.
+		clusterType: MicroK8s,
+		push:        true,
+		match:       equals("microk8s"),
+	},
+	{
+		clusterType: Kind,
+		match:       hasPrefix("kind-"),
+		load:        func(name string) []string { return []string{"kind", "load", "docker-image", "--name", name} },
+	},
+	{
+		clusterType: K3d,
+		match:       hasPrefix("k3d-"),
+		load:        func(name string) []string { return []string{"k3d", "image", "import", "--cluster", name} },
+	},
+}
+
+// Detect finds out the type of the cluster a kube-context points at. The rules
+// given by the user come first, then the well-known contexts of local clusters.
+// When not nil, local overrides whether the cluster is considered local.
+func Detect(kubeContext string, local *bool, rules []Rule) Cluster {
+	c := detect(kubeContext, rules)
+
+	if local != nil && *local != c.IsLocal() {
+		if *local {
+			return Cluster{Type: Custom}
+		}
+		return Cluster{Push: true}
+	}
+	return c
+}
+
+func detect(kubeContext string, rules []Rule) Cluster {
+	for _, rule := range rules {
+		matches, err := filepath.Match(rule.Pattern, kubeContext)
+		if err != nil {
+			logrus.Warnf("Ignoring invalid local cluster pattern %q: %s", rule.Pattern, err)
+			continue
+		}
+		if matches {
+			return Cluster{
+				Type: Custom,
+				Push: rule.Push != nil && *rule.Push,
+				load: strings.Fields(rule.Load),
+			}
+		}
+	}
+
+	for _, d := range detectors {
+		name, found := d.match(kubeContext)
+		if !found {
+			continue
+		}
+
+		c := Cluster{Type: d.clusterType, Push: d.push}
+		if d.load != nil {
+			c.load = d.load(name)
+		}
+		return c
+	}
+
+	return Cluster{Push: true}
+}
+
+func equals(kubeContexts ...string) func(string) (string, bool) {
+	return func(kubeContext string) (string, bool) {
+		for _, c := range kubeContexts {
+			if c == kubeContext {
+				return kubeContext, true
+			}
+		}
+		return "", false
+	}
+}
+
+func hasPrefix(prefix string) func(string) (string, bool) {
+	return func(kubeContext string) (string, bool) {
+		if !strings.HasPrefix(kubeContext, prefix) {
+			return "", false
+		}
+		return strings.TrimPrefix(kubeContext, prefix), true
+	}
+}
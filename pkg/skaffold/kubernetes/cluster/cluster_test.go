@@ -0,0 +1,145 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestDetect(t *testing.T) {
+	var tests = []struct {
+		description  string
+		kubeContext  string
+		local        *bool
+		rules        []Rule
+		expectedType string
+		expectedPush bool
+		expectedLoad string
+	}{
+		{
+			description:  "minikube",
+			kubeContext:  "minikube",
+			expectedType: Minikube,
+			expectedLoad: "minikube image load image:tag",
+		},
+		{
+			description:  "docker for desktop",
+			kubeContext:  "docker-for-desktop",
+			expectedType: DockerDesktop,
+		},
+		{
+			description:  "docker desktop",
+			kubeContext:  "docker-desktop",
+			expectedType: DockerDesktop,
+		},
+		{
+			description:  "rancher desktop",
+			kubeContext:  "rancher-desktop",
+			expectedType: RancherDesktop,
+		},
+		{
+			description:  "microk8s pushes to its registry",
+			kubeContext:  "microk8s",
+			expectedType: MicroK8s,
+			expectedPush: true,
+		},
+		{
+			description:  "kind",
+			kubeContext:  "kind-dev",
+			expectedType: Kind,
+			expectedLoad: "kind load docker-image --name dev image:tag",
+		},
+		{
+			description:  "k3d",
+			kubeContext:  "k3d-dev",
+			expectedType: K3d,
+			expectedLoad: "k3d image import --cluster dev image:tag",
+		},
+		{
+			description:  "remote cluster",
+			kubeContext:  "gke_project_zone_cluster",
+			expectedPush: true,
+		},
+		{
+			description:  "remote cluster declared local",
+			kubeContext:  "gke_project_zone_cluster",
+			local:        util.BoolPtr(true),
+			expectedType: Custom,
+		},
+		{
+			description:  "local cluster declared remote",
+			kubeContext:  "kind-dev",
+			local:        util.BoolPtr(false),
+			expectedPush: true,
+		},
+		{
+			description: "user rule",
+			kubeContext: "lab-cluster",
+			rules: []Rule{
+				{Pattern: "other-*"},
+				{Pattern: "lab-*", Load: "lab load"},
+			},
+			expectedType: Custom,
+			expectedLoad: "lab load image:tag",
+		},
+		{
+			description: "user rule with push",
+			kubeContext: "lab-cluster",
+			rules: []Rule{
+				{Pattern: "lab-*", Push: util.BoolPtr(true)},
+			},
+			expectedType: Custom,
+			expectedPush: true,
+		},
+		{
+			description: "user rules come first",
+			kubeContext: "kind-dev",
+			rules: []Rule{
+				{Pattern: "kind-*"},
+			},
+			expectedType: Custom,
+		},
+		{
+			description: "invalid pattern",
+			kubeContext: "dev",
+			rules: []Rule{
+				{Pattern: "[dev"},
+			},
+			expectedPush: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			c := Detect(test.kubeContext, test.local, test.rules)
+
+			var load string
+			if cmd := c.LoadCommand(context.Background(), "image:tag"); cmd != nil {
+				load = strings.Join(cmd.Args, " ")
+			}
+
+			testutil.CheckDeepEqual(t, test.expectedType, c.Type)
+			testutil.CheckDeepEqual(t, test.expectedPush, c.Push)
+			testutil.CheckDeepEqual(t, test.expectedLoad, load)
+		})
+	}
+}
@@ -18,6 +18,7 @@ package kubernetes
 
 import (
 	"strings"
+	"sync"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
@@ -43,9 +44,11 @@ var colorCodes = []color.Color{
 // from each pod.
 type ColorPicker interface {
 	Pick(pod *v1.Pod) color.Color
+	SetArtifacts(artifacts []*latest.Artifact)
 }
 
 type colorPicker struct {
+	lock        sync.RWMutex
 	imageColors map[string]color.Color
 }
 
@@ -54,17 +57,41 @@ type colorPicker struct {
 // again. The formatter for the associated color will then be returned by `Pick` each
 // time it is called for the artifact and can be used to write to out in that color.
 func NewColorPicker(artifacts []*latest.Artifact) ColorPicker {
-	c := colorPicker{imageColors: map[string]color.Color{}}
-	for i, artifact := range artifacts {
-		c.imageColors[artifact.ImageName] = colorCodes[i%len(colorCodes)]
+	c := &colorPicker{imageColors: map[string]color.Color{}}
+	c.SetArtifacts(artifacts)
+	return c
+}
+
+// SetArtifacts replaces the artifacts, for example when the configuration is reloaded.
+// The artifacts that were already known keep their color.
+func (p *colorPicker) SetArtifacts(artifacts []*latest.Artifact) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	imageColors := map[string]color.Color{}
+	for _, artifact := range artifacts {
+		if c, present := p.imageColors[artifact.ImageName]; present {
+			imageColors[artifact.ImageName] = c
+		}
 	}
-	return &c
+
+	next := len(p.imageColors)
+	for _, artifact := range artifacts {
+		if _, present := imageColors[artifact.ImageName]; !present {
+			imageColors[artifact.ImageName] = colorCodes[next%len(colorCodes)]
+			next++
+		}
+	}
+	p.imageColors = imageColors
 }
 
 // Pick will return the color that was associated with pod when `NewColorPicker` was called.
 // If no color was associated with the pod, the none color will be returned, which will
 // write with no formatting.
 func (p *colorPicker) Pick(pod *v1.Pod) color.Color {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
 	for _, container := range pod.Spec.Containers {
 		if c, present := p.imageColors[stripTag(container.Image)]; present {
 			return c
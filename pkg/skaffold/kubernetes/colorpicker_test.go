@@ -21,6 +21,7 @@ import (
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
 	v1 "k8s.io/api/core/v1"
 )
 
@@ -85,3 +86,22 @@ func TestColorPicker(t *testing.T) {
 		})
 	}
 }
+
+func TestColorPickerSetArtifacts(t *testing.T) {
+	picker := NewColorPicker([]*latest.Artifact{
+		{ImageName: "first"},
+		{ImageName: "second"},
+	})
+	pod := func(image string) *v1.Pod {
+		return &v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{Image: image}}}}
+	}
+
+	picker.SetArtifacts([]*latest.Artifact{
+		{ImageName: "second"},
+		{ImageName: "third"},
+	})
+
+	testutil.CheckDeepEqual(t, color.None, picker.Pick(pod("first")))
+	testutil.CheckDeepEqual(t, colorCodes[1], picker.Pick(pod("second")))
+	testutil.CheckDeepEqual(t, colorCodes[2], picker.Pick(pod("third")))
+}
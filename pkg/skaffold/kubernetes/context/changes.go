@@ -0,0 +1,84 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import (
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// pinned is true when the current context was pinned rather than given with --kube-context.
+var pinned bool
+
+// PinContext makes skaffold keep using the current context, even if another one
+// becomes current in the kubeconfig, for example after `kubectl config use-context`.
+func PinContext() {
+	cfg, err := CurrentConfig()
+	if err != nil || inCluster || kubeContextOverride != "" || cfg.CurrentContext == "" {
+		return
+	}
+
+	kubeContextOverride = cfg.CurrentContext
+	pinned = true
+}
+
+// CheckKubeConfig reads the kubeconfig again to make sure that the context
+// skaffold uses still points at the same cluster. If the context was pinned
+// and another one became current, it returns the new current context.
+func CheckKubeConfig() (string, error) {
+	initial, err := CurrentConfig()
+	if err != nil || inCluster {
+		// Nothing to compare with.
+		return "", nil
+	}
+	used := initial.CurrentContext
+	if _, found := initial.Contexts[used]; !found {
+		return "", nil
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeConfigFile
+	cfg, err := loadingRules.Load()
+	if err != nil {
+		// The kubeconfig might be read while it's being written.
+		logrus.Debugln("Unable to read the kubeconfig again:", err)
+		return "", nil
+	}
+
+	if _, found := cfg.Contexts[used]; !found {
+		return "", &ClusterChangedError{Context: used}
+	}
+	if previous, server := clusterServer(initial, used), clusterServer(*cfg, used); previous != server {
+		return "", &ClusterChangedError{Context: used, Previous: previous, Server: server}
+	}
+
+	if pinned && cfg.CurrentContext != used {
+		return cfg.CurrentContext, nil
+	}
+	return "", nil
+}
+
+// clusterServer returns the endpoint of the cluster a context points at.
+func clusterServer(cfg clientcmdapi.Config, context string) string {
+	if c, found := cfg.Contexts[context]; found {
+		if cluster, found := cfg.Clusters[c.Cluster]; found {
+			return cluster.Server
+		}
+	}
+	return ""
+}
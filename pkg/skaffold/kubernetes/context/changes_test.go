@@ -0,0 +1,102 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func kubeConfigWith(current, devServer string, contexts ...string) api.Config {
+	cfg := api.Config{
+		CurrentContext: current,
+		Clusters: map[string]*api.Cluster{
+			"dev":  {Server: devServer},
+			"prod": {Server: "https://prod.example.com"},
+		},
+		Contexts: map[string]*api.Context{},
+	}
+	for _, context := range contexts {
+		cfg.Contexts[context] = &api.Context{Cluster: context}
+	}
+	return cfg
+}
+
+func TestCheckKubeConfig(t *testing.T) {
+	var tests = []struct {
+		description     string
+		kubeContext     string
+		updated         api.Config
+		expectedCurrent string
+		shouldErr       bool
+	}{
+		{
+			description: "unchanged",
+			updated:     kubeConfigWith("dev", "https://127.0.0.1:6443", "dev", "prod"),
+		},
+		{
+			description:     "other context is current",
+			updated:         kubeConfigWith("prod", "https://127.0.0.1:6443", "dev", "prod"),
+			expectedCurrent: "prod",
+		},
+		{
+			description: "context given on the command line",
+			kubeContext: "dev",
+			updated:     kubeConfigWith("prod", "https://127.0.0.1:6443", "dev", "prod"),
+		},
+		{
+			description: "cluster replaced",
+			updated:     kubeConfigWith("dev", "https://127.0.0.1:34567", "dev", "prod"),
+			shouldErr:   true,
+		},
+		{
+			description: "context removed",
+			updated:     kubeConfigWith("prod", "https://127.0.0.1:6443", "prod"),
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			kubeConfig, cleanup := testutil.TempFile(t, "kubeconfig", nil)
+			defer cleanup()
+			if err := clientcmd.WriteToFile(kubeConfigWith("dev", "https://127.0.0.1:6443", "dev", "prod"), kubeConfig); err != nil {
+				t.Fatal(err)
+			}
+
+			resetCurrentConfig()
+			SetKubeConfig(kubeConfig, test.kubeContext)
+			defer func() {
+				resetCurrentConfig()
+				SetKubeConfig("", "")
+				pinned = false
+			}()
+
+			PinContext()
+			if err := clientcmd.WriteToFile(test.updated, kubeConfig); err != nil {
+				t.Fatal(err)
+			}
+			current, err := CheckKubeConfig()
+
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expectedCurrent, current)
+			testutil.CheckDeepEqual(t, []string{"--kubeconfig", kubeConfig, "--context", "dev"}, KubectlArgs())
+		})
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package context
 
 import (
+	"os"
 	"sync"
 
 	"github.com/pkg/errors"
@@ -28,22 +29,102 @@ var (
 	currentConfigOnce sync.Once
 	currentConfig     clientcmdapi.Config
 	currentConfigErr  error
+
+	kubeConfigFile      string
+	kubeContextOverride string
+	inCluster           bool
 )
 
+// serviceAccountTokenFile is where kubernetes mounts the service account token of a pod.
+var serviceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token" // for testing
+
+// SetKubeConfig makes skaffold use the given kubeconfig file and context instead
+// of the ones found in the environment. Empty values keep the defaults.
+// It has to be called before the kubeconfig is loaded.
+func SetKubeConfig(file, context string) {
+	kubeConfigFile = file
+	kubeContextOverride = context
+}
+
+// KubeConfigFile returns the kubeconfig file given with --kubeconfig, if any.
+func KubeConfigFile() string {
+	return kubeConfigFile
+}
+
+// ClientConfig loads the kubeconfig, honoring the --kubeconfig and --kube-context overrides.
+func ClientConfig() clientcmd.ClientConfig {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeConfigFile
+
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{
+		CurrentContext: kubeContextOverride,
+	})
+}
+
+// Namespace returns the namespace skaffold works in: the given one if any,
+// otherwise the namespace of the current context, which defaults to "default".
+// It's the single place where the --namespace option is resolved.
+func Namespace(namespace string) (string, error) {
+	if namespace != "" {
+		return namespace, nil
+	}
+	current, _, err := ClientConfig().Namespace()
+	if err != nil {
+		return "", errors.Wrap(err, "getting current namespace")
+	}
+	return current, nil
+}
+
+// KubectlArgs returns the flags that make kubectl use the same
+// kubeconfig file and context as skaffold.
+func KubectlArgs() []string {
+	var args []string
+	if kubeConfigFile != "" {
+		args = append(args, "--kubeconfig", kubeConfigFile)
+	}
+	if kubeContextOverride != "" {
+		args = append(args, "--context", kubeContextOverride)
+	}
+	return args
+}
+
 func CurrentConfig() (clientcmdapi.Config, error) {
 	currentConfigOnce.Do(func() {
-		loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-		kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
-		cfg, err := kubeConfig.RawConfig()
+		cfg, err := ClientConfig().RawConfig()
 		if err != nil {
 			currentConfigErr = errors.Wrap(err, "loading kubeconfig")
 			return
 		}
+
+		if kubeContextOverride != "" {
+			if _, present := cfg.Contexts[kubeContextOverride]; !present {
+				currentConfigErr = &ContextNotFoundError{Context: kubeContextOverride}
+				return
+			}
+			cfg.CurrentContext = kubeContextOverride
+		}
+
 		currentConfig = cfg
+		inCluster = len(cfg.Contexts) == 0 && kubeConfigFile == "" && inClusterPossible()
 	})
 	return currentConfig, currentConfigErr
 }
 
+// InCluster says if skaffold runs inside a pod with no kubeconfig, in which
+// case it talks to the cluster with the pod's service account and there is no current context.
+func InCluster() bool {
+	_, err := CurrentConfig()
+	return err == nil && inCluster
+}
+
+func inClusterPossible() bool {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") == "" || os.Getenv("KUBERNETES_SERVICE_PORT") == "" {
+		return false
+	}
+	_, err := os.Stat(serviceAccountTokenFile)
+	return err == nil
+}
+
 func CurrentContext() (string, error) {
 	cfg, err := CurrentConfig()
 	if err != nil {
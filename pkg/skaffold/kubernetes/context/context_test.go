@@ -17,9 +17,11 @@ limitations under the License.
 package context
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/GoogleContainerTools/skaffold/testutil"
+	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
 )
 
@@ -31,3 +33,181 @@ func TestCurrentContext(t *testing.T) {
 
 	testutil.CheckErrorAndDeepEqual(t, false, err, "cluster1", context)
 }
+
+func TestKubeConfigOverrides(t *testing.T) {
+	restore := testutil.SetupFakeKubernetesContext(t, api.Config{CurrentContext: "cluster1"})
+	defer restore()
+
+	kubeConfig, cleanup := testutil.TempFile(t, "kubeconfig", nil)
+	defer cleanup()
+	if err := clientcmd.WriteToFile(api.Config{
+		CurrentContext: "cluster2",
+		Contexts: map[string]*api.Context{
+			"cluster2": {},
+			"cluster3": {},
+		},
+	}, kubeConfig); err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		description  string
+		kubeConfig   string
+		kubeContext  string
+		expected     string
+		expectedArgs []string
+		shouldErr    bool
+	}{
+		{
+			description: "environment",
+			expected:    "cluster1",
+		},
+		{
+			description:  "kubeconfig file",
+			kubeConfig:   kubeConfig,
+			expected:     "cluster2",
+			expectedArgs: []string{"--kubeconfig", kubeConfig},
+		},
+		{
+			description:  "kubeconfig file and context",
+			kubeConfig:   kubeConfig,
+			kubeContext:  "cluster3",
+			expected:     "cluster3",
+			expectedArgs: []string{"--kubeconfig", kubeConfig, "--context", "cluster3"},
+		},
+		{
+			description:  "unknown context",
+			kubeConfig:   kubeConfig,
+			kubeContext:  "unknown",
+			expectedArgs: []string{"--kubeconfig", kubeConfig, "--context", "unknown"},
+			shouldErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			resetCurrentConfig()
+			SetKubeConfig(test.kubeConfig, test.kubeContext)
+			defer func() {
+				resetCurrentConfig()
+				SetKubeConfig("", "")
+			}()
+
+			context, err := CurrentContext()
+
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, context)
+			testutil.CheckDeepEqual(t, test.expectedArgs, KubectlArgs())
+			if _, notFound := err.(*ContextNotFoundError); test.shouldErr && !notFound {
+				t.Errorf("expected a ContextNotFoundError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNamespace(t *testing.T) {
+	kubeConfig, cleanup := testutil.TempFile(t, "kubeconfig", nil)
+	defer cleanup()
+	if err := clientcmd.WriteToFile(api.Config{
+		CurrentContext: "cluster1",
+		Clusters: map[string]*api.Cluster{
+			"cluster": {Server: "https://127.0.0.1:6443"},
+		},
+		Contexts: map[string]*api.Context{
+			"cluster1": {Cluster: "cluster", Namespace: "team-a"},
+			"cluster2": {Cluster: "cluster"},
+		},
+	}, kubeConfig); err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		description string
+		kubeContext string
+		namespace   string
+		expected    string
+	}{
+		{
+			description: "namespace given",
+			namespace:   "ns",
+			expected:    "ns",
+		},
+		{
+			description: "namespace of the current context",
+			expected:    "team-a",
+		},
+		{
+			description: "context without namespace",
+			kubeContext: "cluster2",
+			expected:    "default",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			SetKubeConfig(kubeConfig, test.kubeContext)
+			defer SetKubeConfig("", "")
+
+			namespace, err := Namespace(test.namespace)
+
+			testutil.CheckErrorAndDeepEqual(t, false, err, test.expected, namespace)
+		})
+	}
+}
+
+func TestInCluster(t *testing.T) {
+	token, cleanup := testutil.TempFile(t, "token", []byte("token"))
+	defer cleanup()
+
+	var tests = []struct {
+		description string
+		kubeConfig  api.Config
+		env         map[string]string
+		tokenFile   string
+		expected    bool
+	}{
+		{
+			description: "kubeconfig",
+			kubeConfig:  api.Config{CurrentContext: "cluster1", Contexts: map[string]*api.Context{"cluster1": {}}},
+			env:         map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1", "KUBERNETES_SERVICE_PORT": "443"},
+			tokenFile:   token,
+		},
+		{
+			description: "in cluster",
+			env:         map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1", "KUBERNETES_SERVICE_PORT": "443"},
+			tokenFile:   token,
+			expected:    true,
+		},
+		{
+			description: "no service account",
+			env:         map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1", "KUBERNETES_SERVICE_PORT": "443"},
+			tokenFile:   "/does/not/exist",
+		},
+		{
+			description: "outside of a cluster",
+			env:         map[string]string{"KUBERNETES_SERVICE_HOST": "", "KUBERNETES_SERVICE_PORT": ""},
+			tokenFile:   token,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			restore := testutil.SetupFakeKubernetesContext(t, test.kubeConfig)
+			defer restore()
+			unsetEnvs := testutil.SetEnvs(t, test.env)
+			defer unsetEnvs(t)
+
+			defer func(f string) { serviceAccountTokenFile = f }(serviceAccountTokenFile)
+			serviceAccountTokenFile = test.tokenFile
+			resetCurrentConfig()
+			defer resetCurrentConfig()
+
+			testutil.CheckDeepEqual(t, test.expected, InCluster())
+		})
+	}
+}
+
+func resetCurrentConfig() {
+	currentConfigOnce = sync.Once{}
+	currentConfig = api.Config{}
+	currentConfigErr = nil
+}
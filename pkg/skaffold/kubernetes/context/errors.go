@@ -0,0 +1,56 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import "fmt"
+
+// ContextNotFoundError is returned when the kube-context to use doesn't exist.
+type ContextNotFoundError struct {
+	Context string
+}
+
+func (e *ContextNotFoundError) Error() string {
+	if e.Context == "" {
+		return "no current kubernetes context is set"
+	}
+	return fmt.Sprintf("kubernetes context %q not found in kubeconfig", e.Context)
+}
+
+// Remediation tells users how to fix the error.
+func (e *ContextNotFoundError) Remediation() string {
+	return "List the available contexts with `kubectl config get-contexts`, then choose one with --kube-context or `kubectl config use-context`."
+}
+
+// ClusterChangedError is returned when the kube-context used by a dev session is
+// removed from the kubeconfig or now points at another cluster.
+type ClusterChangedError struct {
+	Context  string
+	Previous string
+	Server   string
+}
+
+func (e *ClusterChangedError) Error() string {
+	if e.Server == "" {
+		return fmt.Sprintf("kubernetes context %q was removed from the kubeconfig", e.Context)
+	}
+	return fmt.Sprintf("kubernetes context %q now points at %s instead of %s", e.Context, e.Server, e.Previous)
+}
+
+// Remediation tells users how to fix the error.
+func (e *ClusterChangedError) Remediation() string {
+	return "Restart skaffold dev to deploy to the new cluster, or restore the previous kubeconfig."
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+)
+
+var (
+	kubectlBinary  = "kubectl"
+	kubectlWrapper []string
+)
+
+// SetKubectl makes skaffold run the given kubectl binary, optionally through a
+// wrapper command such as `tsh`. Empty values keep the defaults.
+func SetKubectl(binary, wrapper string) {
+	kubectlBinary = "kubectl"
+	if binary != "" {
+		kubectlBinary = binary
+	}
+	kubectlWrapper = strings.Fields(wrapper)
+}
+
+// Kubectl returns the kubectl binary and the wrapper command it's run through, if any.
+func Kubectl() (string, []string) {
+	return kubectlBinary, kubectlWrapper
+}
+
+// KubectlCommand creates a command that runs kubectl with the given arguments.
+func KubectlCommand(ctx context.Context, args ...string) *exec.Cmd {
+	command := append(append([]string{}, kubectlWrapper...), kubectlBinary)
+	command = append(command, args...)
+
+	return exec.CommandContext(ctx, command[0], command[1:]...)
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestKubectlCommand(t *testing.T) {
+	var tests = []struct {
+		description string
+		binary      string
+		wrapper     string
+		expected    []string
+	}{
+		{
+			description: "default",
+			expected:    []string{"kubectl", "get", "pods"},
+		},
+		{
+			description: "pinned binary",
+			binary:      "/opt/kubectl-1.12/kubectl",
+			expected:    []string{"/opt/kubectl-1.12/kubectl", "get", "pods"},
+		},
+		{
+			description: "wrapper",
+			wrapper:     "tsh --proxy=teleport.example.com",
+			expected:    []string{"tsh", "--proxy=teleport.example.com", "kubectl", "get", "pods"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			defer SetKubectl("", "")
+			SetKubectl(test.binary, test.wrapper)
+
+			cmd := KubectlCommand(context.Background(), "get", "pods")
+
+			testutil.CheckDeepEqual(t, test.expected, cmd.Args)
+		})
+	}
+}
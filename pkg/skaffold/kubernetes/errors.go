@@ -0,0 +1,131 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
+	"github.com/pkg/errors"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Remediable is an error that knows how users can fix it.
+// The CLI prints the remediation instead of the whole chain of wrapped errors.
+type Remediable interface {
+	error
+	Remediation() string
+}
+
+// ContextNotFoundError is returned when the kube-context to use doesn't exist.
+type ContextNotFoundError = kubectx.ContextNotFoundError
+
+// UnauthorizedError is returned when the API server rejects the credentials of the current context.
+type UnauthorizedError struct {
+	Err error
+}
+
+func (e *UnauthorizedError) Error() string {
+	return fmt.Sprintf("the cluster rejected the credentials of the current context: %s", e.Err)
+}
+
+// Remediation tells users how to fix the error.
+func (e *UnauthorizedError) Remediation() string {
+	return "Your credentials may have expired. Refresh them, for example with `gcloud container clusters get-credentials`, and check the user of the current context with `kubectl config view --minify`."
+}
+
+// ClusterUnreachableError is returned when the API server can't be reached.
+type ClusterUnreachableError struct {
+	Err error
+}
+
+func (e *ClusterUnreachableError) Error() string {
+	return fmt.Sprintf("unable to reach the cluster: %s", e.Err)
+}
+
+// Remediation tells users how to fix the error.
+func (e *ClusterUnreachableError) Remediation() string {
+	return "Make sure the cluster is running and reachable, for example with `kubectl cluster-info`. Local clusters such as minikube may need to be started."
+}
+
+// ImagePullError is returned when a pod can't pull the image of one of its containers.
+type ImagePullError struct {
+	Pod       string
+	Container string
+	Image     string
+	Reason    string
+	Message   string
+}
+
+func (e *ImagePullError) Error() string {
+	msg := fmt.Sprintf("pod %s can't pull image %s for container %s: %s", e.Pod, e.Image, e.Container, e.Reason)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// Remediation tells users how to fix the error.
+func (e *ImagePullError) Remediation() string {
+	return "Check that the image was pushed to a registry the cluster can access and that the pod has the credentials to pull it, for example with an imagePullSecret."
+}
+
+// RolloutTimeoutError is returned when a deployment is not rolled out within the status check timeout.
+type RolloutTimeoutError struct {
+	Deployment string
+	Timeout    time.Duration
+}
+
+func (e *RolloutTimeoutError) Error() string {
+	return fmt.Sprintf("deployment %s was not rolled out within %v", e.Deployment, e.Timeout)
+}
+
+// Remediation tells users how to fix the error.
+func (e *RolloutTimeoutError) Remediation() string {
+	return "Check why the pods are not ready, for example with `kubectl describe deployment`. Slow deployments may need a longer `statusCheckTimeout`."
+}
+
+// classify turns the errors returned by the API server into typed errors
+// when it knows how users can fix them. Other errors are returned as is.
+func classify(err error) error {
+	cause := errors.Cause(err)
+	switch {
+	case cause == nil:
+		return nil
+	case apierrs.IsUnauthorized(cause):
+		return &UnauthorizedError{Err: cause}
+	case isUnreachable(cause):
+		return &ClusterUnreachableError{Err: cause}
+	default:
+		return err
+	}
+}
+
+func isUnreachable(err error) bool {
+	// The API server answered.
+	if _, ok := err.(apierrs.APIStatus); ok {
+		return false
+	}
+	if IsConnectionError(err) {
+		return true
+	}
+
+	msg := err.Error()
+	return strings.Contains(msg, "no such host") || strings.Contains(msg, "network is unreachable")
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"github.com/pkg/errors"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestClassify(t *testing.T) {
+	notFound := apierrs.NewNotFound(schema.GroupResource{Resource: "pods"}, "web")
+
+	var tests = []struct {
+		description  string
+		err          error
+		expectedType error
+	}{
+		{
+			description:  "unauthorized",
+			err:          errors.Wrap(apierrs.NewUnauthorized("token expired"), "listing pods"),
+			expectedType: &UnauthorizedError{},
+		},
+		{
+			description:  "unreachable",
+			err:          errors.New("Get https://10.0.0.1/api: dial tcp 10.0.0.1:443: connect: connection refused"),
+			expectedType: &ClusterUnreachableError{},
+		},
+		{
+			description:  "connection reset",
+			err:          syscall.ECONNRESET,
+			expectedType: &ClusterUnreachableError{},
+		},
+		{
+			description:  "server timeout means the cluster was reached",
+			err:          apierrs.NewServerTimeout(schema.GroupResource{Resource: "pods"}, "list", 1),
+			expectedType: &apierrs.StatusError{},
+		},
+		{
+			description:  "other errors are kept",
+			err:          notFound,
+			expectedType: notFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			classified := classify(test.err)
+
+			testutil.CheckErrorAndTypeEquality(t, false, nil, test.expectedType, classified)
+		})
+	}
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sync"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// reportedEventReasons lists the warning events that usually
+// explain why a deployed pod doesn't work as expected.
+var reportedEventReasons = map[string]bool{
+	"FailedMount":        true,
+	"FailedAttachVolume": true,
+	"BackOff":            true,
+	"Unhealthy":          true,
+}
+
+// containerFieldPath extracts the container name from an event's field path,
+// such as `spec.containers{web}`.
+var containerFieldPath = regexp.MustCompile(`^spec\.(?:initContainers|containers)\{(.+)\}$`)
+
+// EventReporter watches the Kubernetes events and prints the warnings
+// about the deployed pods, along with the artifact that created them.
+type EventReporter struct {
+	output      io.Writer
+	pods        *PodCache
+	podSelector PodSelector
+	images      map[string]bool
+
+	lock     sync.Mutex
+	reported map[string]bool
+	cancel   context.CancelFunc
+}
+
+// NewEventReporter creates a new EventReporter for a given output.
+func NewEventReporter(out io.Writer, pods *PodCache, podSelector PodSelector, artifacts []*latest.Artifact) *EventReporter {
+	return &EventReporter{
+		output:      out,
+		pods:        pods,
+		podSelector: podSelector,
+		images:      artifactImages(artifacts),
+		reported:    map[string]bool{},
+	}
+}
+
+// SetArtifacts replaces the artifacts whose pods are reported, for example
+// when the configuration is reloaded.
+func (e *EventReporter) SetArtifacts(artifacts []*latest.Artifact) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.images = artifactImages(artifacts)
+}
+
+// Start starts watching the warning events of the namespaces where the deployed pods run.
+func (e *EventReporter) Start(ctx context.Context) error {
+	cancelCtx, cancel := context.WithCancel(ctx)
+	e.cancel = cancel
+
+	watcher, err := e.pods.Watch()
+	if err != nil {
+		return errors.Wrap(err, "watching pods")
+	}
+
+	go func() {
+		defer watcher.Stop()
+
+		watched := map[string]bool{}
+		for {
+			select {
+			case <-cancelCtx.Done():
+				return
+			case evt, ok := <-watcher.ResultChan():
+				if !ok {
+					return
+				}
+
+				pod, ok := evt.Object.(*v1.Pod)
+				if !ok || watched[pod.Namespace] || !e.podSelector.Select(pod) {
+					continue
+				}
+
+				watched[pod.Namespace] = true
+				go e.watchEvents(cancelCtx, pod.Namespace)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// watchEvents reports the warning events of a namespace. The events that
+// happened before the watch started are listed first, so that none is missed.
+func (e *EventReporter) watchEvents(ctx context.Context, namespace string) {
+	client, err := Client()
+	if err != nil {
+		logrus.Warnln("Unable to report Kubernetes events:", errors.Wrap(err, "getting k8s client"))
+		return
+	}
+	events := client.CoreV1().Events(namespace)
+
+	options := metav1.ListOptions{FieldSelector: "type=" + v1.EventTypeWarning}
+	list, err := events.List(options)
+	if err != nil {
+		logrus.Warnln("Unable to report Kubernetes events:", errors.Wrap(err, "listing events"))
+		return
+	}
+
+	options.ResourceVersion = list.ResourceVersion
+	watcher, err := events.Watch(options)
+	if err != nil {
+		logrus.Warnln("Unable to report Kubernetes events:", errors.Wrap(err, "watching events"))
+		return
+	}
+	defer watcher.Stop()
+
+	for i := range list.Items {
+		e.report(&list.Items[i])
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt, ok := <-watcher.ResultChan():
+			if !ok {
+				return
+			}
+
+			if event, ok := evt.Object.(*v1.Event); ok {
+				e.report(event)
+			}
+		}
+	}
+}
+
+// Stop stops the reporter.
+func (e *EventReporter) Stop() {
+	if e.cancel != nil {
+		e.cancel()
+	}
+}
+
+// report prints an event once, if it's about one of the deployed pods.
+func (e *EventReporter) report(event *v1.Event) {
+	if event.InvolvedObject.Kind != "Pod" || !reportedEventReasons[event.Reason] {
+		return
+	}
+
+	pod := e.findPod(event.InvolvedObject.Namespace, event.InvolvedObject.Name)
+	if pod == nil || !e.podSelector.Select(pod) {
+		return
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	message := eventMessage(event, pod, e.images)
+
+	key := string(event.InvolvedObject.UID) + "/" + message
+	if e.reported[key] {
+		return
+	}
+	e.reported[key] = true
+
+	color.Yellow.Fprintln(e.output, message)
+}
+
+func (e *EventReporter) findPod(namespace, name string) *v1.Pod {
+	pods, err := e.pods.Pods()
+	if err != nil {
+		logrus.Debugln("Unable to list pods:", err)
+		return nil
+	}
+
+	for i := range pods {
+		if pods[i].Namespace == namespace && pods[i].Name == name {
+			return &pods[i]
+		}
+	}
+	return nil
+}
+
+func artifactImages(artifacts []*latest.Artifact) map[string]bool {
+	images := map[string]bool{}
+	for _, artifact := range artifacts {
+		images[artifact.ImageName] = true
+	}
+	return images
+}
+
+// eventMessage formats an event, attributed to the artifact whose image runs
+// in the involved container or, failing that, to the pod's owner.
+func eventMessage(event *v1.Event, pod *v1.Pod, images map[string]bool) string {
+	containers := append([]v1.Container{}, pod.Spec.InitContainers...)
+	containers = append(containers, pod.Spec.Containers...)
+
+	header := fmt.Sprintf("[%s]", pod.Name)
+	if match := containerFieldPath.FindStringSubmatch(event.InvolvedObject.FieldPath); match != nil {
+		name := match[1]
+		if name != pod.Name {
+			header = fmt.Sprintf("[%s %s]", pod.Name, name)
+		}
+
+		var involved []v1.Container
+		for _, container := range containers {
+			if container.Name == name {
+				involved = append(involved, container)
+			}
+		}
+		containers = involved
+	}
+
+	message := fmt.Sprintf("%s %s: %s", header, event.Reason, event.Message)
+
+	for _, container := range containers {
+		if image := stripTag(container.Image); images[image] {
+			return fmt.Sprintf("%s (artifact %s)", message, image)
+		}
+	}
+	if len(pod.OwnerReferences) > 0 {
+		owner := pod.OwnerReferences[0]
+		return fmt.Sprintf("%s (%s %s)", message, owner.Kind, owner.Name)
+	}
+	return message
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// eventLines sends what's printed to a channel.
+type eventLines chan string
+
+func (l eventLines) Write(p []byte) (int, error) {
+	l <- string(p)
+	return len(p), nil
+}
+
+func TestEventReporter(t *testing.T) {
+	deployed := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "ns1"},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "web", Image: "web:abcdef"}}},
+	}
+	other := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "ns2"},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "db", Image: "postgres"}}},
+	}
+	// Happened before the reporter started.
+	earlier := &v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "web.1", Namespace: "ns1"},
+		InvolvedObject: v1.ObjectReference{Kind: "Pod", Namespace: "ns1", Name: "web"},
+		Type:           v1.EventTypeWarning,
+		Reason:         "FailedMount",
+		Message:        "secret not found",
+	}
+	client := fake.NewSimpleClientset(deployed, other, earlier)
+
+	defer func(c func() (k8s.Interface, error)) { Client = c }(Client)
+	Client = func() (k8s.Interface, error) { return client, nil }
+
+	pods := NewPodCache("", nil)
+	defer pods.Stop()
+	images := NewImageList()
+	images.Add("web:abcdef")
+
+	lines := make(eventLines, 10)
+	reporter := NewEventReporter(lines, pods, images, nil)
+	err := reporter.Start(context.Background())
+	defer reporter.Stop()
+	testutil.CheckError(t, false, err)
+
+	testutil.CheckDeepEqual(t, true, strings.Contains(nextLine(t, lines), "FailedMount: secret not found"))
+
+	client.CoreV1().Events("ns1").Create(&v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "web.2", Namespace: "ns1"},
+		InvolvedObject: v1.ObjectReference{Kind: "Pod", Namespace: "ns1", Name: "web"},
+		Type:           v1.EventTypeWarning,
+		Reason:         "BackOff",
+		Message:        "Back-off restarting failed container",
+	})
+
+	testutil.CheckDeepEqual(t, true, strings.Contains(nextLine(t, lines), "BackOff: Back-off restarting failed container"))
+
+	// Only the namespace of the deployed pods is watched.
+	testutil.CheckDeepEqual(t, []string{"ns1"}, watchedNamespaces(client, "events"))
+}
+
+func nextLine(t *testing.T, lines eventLines) string {
+	select {
+	case line := <-lines:
+		return line
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for event")
+		return ""
+	}
+}
+
+func watchedNamespaces(client *fake.Clientset, resource string) []string {
+	var namespaces []string
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "watch" && action.GetResource().Resource == resource {
+			namespaces = append(namespaces, action.GetNamespace())
+		}
+	}
+	return namespaces
+}
+
+func TestEventMessage(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "web-5d8f7",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web-5d8f"}},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{Name: "proxy", Image: "envoy:1.8"},
+				{Name: "web", Image: "gcr.io/k8s-skaffold/web:abcdef"},
+			},
+		},
+	}
+	images := map[string]bool{"gcr.io/k8s-skaffold/web": true}
+
+	var tests = []struct {
+		description string
+		event       *v1.Event
+		expected    string
+	}{
+		{
+			description: "container of an artifact",
+			event: &v1.Event{
+				InvolvedObject: v1.ObjectReference{FieldPath: "spec.containers{web}"},
+				Reason:         "BackOff",
+				Message:        "Back-off restarting failed container",
+			},
+			expected: "[web-5d8f7 web] BackOff: Back-off restarting failed container (artifact gcr.io/k8s-skaffold/web)",
+		},
+		{
+			description: "other container",
+			event: &v1.Event{
+				InvolvedObject: v1.ObjectReference{FieldPath: "spec.containers{proxy}"},
+				Reason:         "Unhealthy",
+				Message:        "Readiness probe failed",
+			},
+			expected: "[web-5d8f7 proxy] Unhealthy: Readiness probe failed (ReplicaSet web-5d8f)",
+		},
+		{
+			description: "pod event",
+			event: &v1.Event{
+				Reason:  "FailedMount",
+				Message: "MountVolume.SetUp failed for volume \"config\"",
+			},
+			expected: "[web-5d8f7] FailedMount: MountVolume.SetUp failed for volume \"config\" (artifact gcr.io/k8s-skaffold/web)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			testutil.CheckDeepEqual(t, test.expected, eventMessage(test.event, pod, images))
+		})
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/pkg/errors"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// unhealthyReasons lists the container waiting reasons that
+// usually mean that a pod won't become ready without help.
+var unhealthyReasons = map[string]bool{
+	"ErrImagePull":               true,
+	"ImagePullBackOff":           true,
+	"InvalidImageName":           true,
+	"CrashLoopBackOff":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"RunContainerError":          true,
+}
+
+// HealthReporter watches the deployed pods and prints the reasons
+// why they are stuck, so that users don't have to go digging with kubectl.
+type HealthReporter struct {
+	output      io.Writer
+	pods        *PodCache
+	podSelector PodSelector
+
+	lock     sync.Mutex
+	reported map[string]string
+	cancel   context.CancelFunc
+}
+
+// NewHealthReporter creates a new HealthReporter for a given output.
+func NewHealthReporter(out io.Writer, pods *PodCache, podSelector PodSelector) *HealthReporter {
+	return &HealthReporter{
+		output:      out,
+		pods:        pods,
+		podSelector: podSelector,
+		reported:    map[string]string{},
+	}
+}
+
+// Start starts watching the pods matched by the `podSelector`.
+func (h *HealthReporter) Start(ctx context.Context) error {
+	cancelCtx, cancel := context.WithCancel(ctx)
+	h.cancel = cancel
+
+	watcher, err := h.pods.Watch()
+	if err != nil {
+		return errors.Wrap(err, "initializing pod watcher")
+	}
+
+	go func() {
+		defer watcher.Stop()
+
+		for {
+			select {
+			case <-cancelCtx.Done():
+				return
+			case evt, ok := <-watcher.ResultChan():
+				if !ok {
+					return
+				}
+
+				pod, ok := evt.Object.(*v1.Pod)
+				if !ok || !h.podSelector.Select(pod) {
+					continue
+				}
+
+				if evt.Type == watch.Deleted {
+					h.forget(pod)
+					continue
+				}
+
+				h.report(pod)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop stops the reporter.
+func (h *HealthReporter) Stop() {
+	if h.cancel != nil {
+		h.cancel()
+	}
+}
+
+// report prints each problem of a pod once, until it's resolved.
+func (h *HealthReporter) report(pod *v1.Pod) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	current := map[string]string{}
+	for _, problem := range podProblems(pod) {
+		current[problem.key] = problem.message
+		if h.reported[problem.key] == problem.message {
+			continue
+		}
+
+		color.Red.Fprintln(h.output, problem.message)
+	}
+
+	prefix := pod.Namespace + "/" + pod.Name + "/"
+	for key := range h.reported {
+		if _, present := current[key]; !present && strings.HasPrefix(key, prefix) {
+			delete(h.reported, key)
+		}
+	}
+	for key, message := range current {
+		h.reported[key] = message
+	}
+}
+
+func (h *HealthReporter) forget(pod *v1.Pod) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	prefix := pod.Namespace + "/" + pod.Name + "/"
+	for key := range h.reported {
+		if strings.HasPrefix(key, prefix) {
+			delete(h.reported, key)
+		}
+	}
+}
+
+type podProblem struct {
+	key     string
+	message string
+}
+
+// podProblems lists the reasons why a pod is not healthy.
+func podProblems(pod *v1.Pod) []podProblem {
+	var problems []podProblem
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodScheduled && condition.Status == v1.ConditionFalse && condition.Reason == v1.PodReasonUnschedulable {
+			problems = append(problems, podProblem{
+				key:     pod.Namespace + "/" + pod.Name + "/",
+				message: fmt.Sprintf("[%s] FailedScheduling: %s", pod.Name, condition.Message),
+			})
+		}
+	}
+
+	statuses := append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		waiting := status.State.Waiting
+		if waiting == nil || !unhealthyReasons[waiting.Reason] {
+			continue
+		}
+
+		message := fmt.Sprintf("%s %s", prefix(pod, status), waiting.Reason)
+		if waiting.Message != "" {
+			message += ": " + waiting.Message
+		}
+
+		problems = append(problems, podProblem{
+			key:     pod.Namespace + "/" + pod.Name + "/" + status.Name,
+			message: message,
+		})
+	}
+
+	return problems
+}
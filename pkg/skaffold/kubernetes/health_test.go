@@ -0,0 +1,105 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func waitingPod(reason, message string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Status: v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{{
+				Name: "app",
+				State: v1.ContainerState{
+					Waiting: &v1.ContainerStateWaiting{Reason: reason, Message: message},
+				},
+			}},
+		},
+	}
+}
+
+func TestPodProblems(t *testing.T) {
+	var tests = []struct {
+		description string
+		pod         *v1.Pod
+		expected    []string
+	}{
+		{
+			description: "healthy",
+			pod:         &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web"}},
+		},
+		{
+			description: "image pull",
+			pod:         waitingPod("ImagePullBackOff", "Back-off pulling image \"web:v1\""),
+			expected:    []string{"[web app] ImagePullBackOff: Back-off pulling image \"web:v1\""},
+		},
+		{
+			description: "crash loop",
+			pod:         waitingPod("CrashLoopBackOff", ""),
+			expected:    []string{"[web app] CrashLoopBackOff"},
+		},
+		{
+			description: "starting",
+			pod:         waitingPod("ContainerCreating", ""),
+		},
+		{
+			description: "unschedulable",
+			pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "web"},
+				Status: v1.PodStatus{
+					Conditions: []v1.PodCondition{{
+						Type:    v1.PodScheduled,
+						Status:  v1.ConditionFalse,
+						Reason:  v1.PodReasonUnschedulable,
+						Message: "0/1 nodes are available: 1 Insufficient cpu.",
+					}},
+				},
+			},
+			expected: []string{"[web] FailedScheduling: 0/1 nodes are available: 1 Insufficient cpu."},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			var messages []string
+			for _, problem := range podProblems(test.pod) {
+				messages = append(messages, problem.message)
+			}
+
+			testutil.CheckDeepEqual(t, test.expected, messages)
+		})
+	}
+}
+
+func TestReportOnce(t *testing.T) {
+	var out bytes.Buffer
+	reporter := NewHealthReporter(&out, NewPodCache("", nil), NewImageList())
+
+	reporter.report(waitingPod("CrashLoopBackOff", ""))
+	reporter.report(waitingPod("CrashLoopBackOff", ""))
+	reporter.report(waitingPod("", ""))
+	reporter.report(waitingPod("CrashLoopBackOff", ""))
+
+	testutil.CheckDeepEqual(t, "[web app] CrashLoopBackOff\n[web app] CrashLoopBackOff\n", out.String())
+}
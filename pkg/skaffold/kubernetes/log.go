@@ -21,12 +21,12 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"os/exec"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"k8s.io/api/core/v1"
@@ -40,6 +40,7 @@ var DynamicClient = GetDynamicClient
 // LogAggregator aggregates the logs for all the deployed pods.
 type LogAggregator struct {
 	output      io.Writer
+	pods        *PodCache
 	podSelector PodSelector
 	colorPicker ColorPicker
 
@@ -50,9 +51,10 @@ type LogAggregator struct {
 }
 
 // NewLogAggregator creates a new LogAggregator for a given output.
-func NewLogAggregator(out io.Writer, podSelector PodSelector, colorPicker ColorPicker) *LogAggregator {
+func NewLogAggregator(out io.Writer, pods *PodCache, podSelector PodSelector, colorPicker ColorPicker) *LogAggregator {
 	return &LogAggregator{
 		output:      out,
+		pods:        pods,
 		podSelector: podSelector,
 		colorPicker: colorPicker,
 		trackedContainers: trackedContainers{
@@ -68,7 +70,7 @@ func (a *LogAggregator) Start(ctx context.Context) error {
 	a.cancel = cancel
 	a.startTime = time.Now()
 
-	watcher, err := PodWatcher()
+	watcher, err := a.pods.Watch()
 	if err != nil {
 		return errors.Wrap(err, "initializing pod watcher")
 	}
@@ -106,7 +108,9 @@ func (a *LogAggregator) Start(ctx context.Context) error {
 
 // Stop stops the logger.
 func (a *LogAggregator) Stop() {
-	a.cancel()
+	if a.cancel != nil {
+		a.cancel()
+	}
 }
 
 func sinceSeconds(d time.Duration) int64 {
@@ -132,25 +136,48 @@ func (a *LogAggregator) streamLogs(ctx context.Context, pod *v1.Pod) {
 		}
 
 		logrus.Infof("Stream logs from pod: %s container: %s", pod.Name, container.Name)
+		go func(container v1.ContainerStatus) {
+			a.streamContainerLogs(ctx, pod, container)
+			a.trackedContainers.remove(containerID)
+		}(container)
+	}
+}
+
+// streamContainerLogs streams the logs of a container until it terminates.
+// If kubectl fails, for example because the API server was unreachable
+// for a while, it's restarted where it left off.
+func (a *LogAggregator) streamContainerLogs(ctx context.Context, pod *v1.Pod, container v1.ContainerStatus) {
+	color := a.colorPicker.Pick(pod)
+	prefix := prefix(pod, container)
 
+	since := a.startTime
+	backoff := apiRetryBackoff
+	for attempt := 0; ; attempt++ {
 		// In theory, it's more precise to use --since-time='' but there can be a time
 		// difference between the user's machine and the server.
 		// So we use --since=Xs and round up to the nearest second to not lose any log.
-		sinceSeconds := fmt.Sprintf("--since=%ds", sinceSeconds(time.Since(a.startTime)))
+		sinceSeconds := fmt.Sprintf("--since=%ds", sinceSeconds(time.Since(since)))
 
 		tr, tw := io.Pipe()
-		cmd := exec.CommandContext(ctx, "kubectl", "logs", sinceSeconds, "-f", pod.Name, "-c", container.Name, "--namespace", pod.Namespace)
+		cmd := kubectx.KubectlCommand(ctx, append(kubectx.KubectlArgs(), "logs", sinceSeconds, "-f", pod.Name, "-c", container.Name, "--namespace", pod.Namespace)...)
 		cmd.Stdout = tw
-		go cmd.Run()
-
-		color := a.colorPicker.Pick(pod)
-		prefix := prefix(pod, container)
 		go func() {
-			if err := a.streamRequest(ctx, color, prefix, tr); err != nil {
-				logrus.Errorf("streaming request %s", err)
-			}
-			a.trackedContainers.remove(containerID)
+			tw.CloseWithError(cmd.Run())
 		}()
+
+		err := a.streamRequest(ctx, color, prefix, tr)
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		if attempt == apiRetries {
+			logrus.Errorf("streaming request %s", err)
+			return
+		}
+
+		logrus.Debugf("Streaming logs of %s failed, retrying in %s: %s", prefix, backoff, err)
+		since = time.Now()
+		time.Sleep(backoff)
+		backoff *= 2
 	}
 }
 
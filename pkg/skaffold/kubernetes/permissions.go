@@ -0,0 +1,81 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Permission is an action skaffold needs to be allowed to perform on the cluster.
+type Permission struct {
+	Verb        string
+	Group       string
+	Resource    string
+	Subresource string
+	Namespace   string
+
+	// Reason explains why the permission is needed.
+	Reason string
+}
+
+func (p Permission) String() string {
+	resource := p.Resource
+	if p.Group != "" {
+		resource += "." + p.Group
+	}
+	if p.Subresource != "" {
+		resource += "/" + p.Subresource
+	}
+	return fmt.Sprintf("%s %s in namespace %s", p.Verb, resource, p.Namespace)
+}
+
+// CheckPermissions asks the cluster, with SelfSubjectAccessReviews, if the current user
+// is granted each permission. The error lists all the permissions that are denied.
+func CheckPermissions(client kubernetes.Interface, permissions []Permission) error {
+	var denied []string
+
+	for _, p := range permissions {
+		review, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(&authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace:   p.Namespace,
+					Verb:        p.Verb,
+					Group:       p.Group,
+					Resource:    p.Resource,
+					Subresource: p.Subresource,
+				},
+			},
+		})
+		if err != nil {
+			return errors.Wrapf(classify(err), "checking permission to %s", p)
+		}
+
+		if !review.Status.Allowed {
+			denied = append(denied, fmt.Sprintf(" - cannot %s, needed to %s", p, p.Reason))
+		}
+	}
+
+	if len(denied) > 0 {
+		return fmt.Errorf("missing permissions on the cluster, ask your cluster admin to grant them:\n%s", strings.Join(denied, "\n"))
+	}
+	return nil
+}
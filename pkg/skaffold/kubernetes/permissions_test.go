@@ -0,0 +1,74 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestCheckPermissions(t *testing.T) {
+	var tests = []struct {
+		description string
+		allowed     map[string]bool
+		shouldErr   bool
+		expected    string
+	}{
+		{
+			description: "all allowed",
+			allowed:     map[string]bool{"pods": true, "secrets": true},
+		},
+		{
+			description: "secrets denied",
+			allowed:     map[string]bool{"pods": true},
+			shouldErr:   true,
+			expected:    "missing permissions on the cluster, ask your cluster admin to grant them:\n - cannot get secrets in namespace ns, needed to read secrets",
+		},
+		{
+			description: "all denied",
+			shouldErr:   true,
+			expected:    "missing permissions on the cluster, ask your cluster admin to grant them:\n - cannot create pods/exec in namespace ns, needed to exec\n - cannot get secrets in namespace ns, needed to read secrets",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			client := fake.NewSimpleClientset()
+			client.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+				review.Status.Allowed = test.allowed[review.Spec.ResourceAttributes.Resource]
+				return true, review, nil
+			})
+
+			err := CheckPermissions(client, []Permission{
+				{Verb: "create", Resource: "pods", Subresource: "exec", Namespace: "ns", Reason: "exec"},
+				{Verb: "get", Resource: "secrets", Namespace: "ns", Reason: "read secrets"},
+			})
+
+			var message string
+			if err != nil {
+				message = err.Error()
+			}
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, message)
+		})
+	}
+}
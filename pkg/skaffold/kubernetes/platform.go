@@ -0,0 +1,51 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// NodePlatforms returns the distinct platforms, such as linux/amd64, of the cluster's nodes.
+func NodePlatforms(client kubernetes.Interface) ([]string, error) {
+	nodes, err := client.CoreV1().Nodes().List(meta_v1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing nodes")
+	}
+
+	seen := map[string]bool{}
+	var platforms []string
+	for _, node := range nodes.Items {
+		info := node.Status.NodeInfo
+		if info.Architecture == "" {
+			continue
+		}
+
+		platform := info.OperatingSystem + "/" + info.Architecture
+		if !seen[platform] {
+			seen[platform] = true
+			platforms = append(platforms, platform)
+		}
+	}
+
+	sort.Strings(platforms)
+	return platforms, nil
+}
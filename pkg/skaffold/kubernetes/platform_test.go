@@ -0,0 +1,65 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func node(name, os, arch string) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: v1.NodeStatus{
+			NodeInfo: v1.NodeSystemInfo{OperatingSystem: os, Architecture: arch},
+		},
+	}
+}
+
+func TestNodePlatforms(t *testing.T) {
+	var tests = []struct {
+		description string
+		nodes       []runtime.Object
+		expected    []string
+	}{
+		{
+			description: "no nodes",
+		},
+		{
+			description: "single platform",
+			nodes:       []runtime.Object{node("n1", "linux", "amd64"), node("n2", "linux", "amd64")},
+			expected:    []string{"linux/amd64"},
+		},
+		{
+			description: "mixed platforms",
+			nodes:       []runtime.Object{node("n1", "linux", "arm64"), node("n2", "linux", "amd64"), node("n3", "", "")},
+			expected:    []string{"linux/amd64", "linux/arm64"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			platforms, err := NodePlatforms(fake.NewSimpleClientset(test.nodes...))
+
+			testutil.CheckErrorAndDeepEqual(t, false, err, test.expected, platforms)
+		})
+	}
+}
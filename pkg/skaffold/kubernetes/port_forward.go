@@ -27,6 +27,7 @@ import (
 	"syscall"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -42,6 +43,7 @@ type PortForwarder struct {
 	Forwarder
 
 	output      io.Writer
+	pods        *PodCache
 	podSelector PodSelector
 
 	// forwardedPods is a map of portForwardEntry.key() (string) -> portForwardEntry
@@ -49,6 +51,9 @@ type PortForwarder struct {
 
 	// forwardedPorts is a map of port (int32) -> container name (string)
 	forwardedPorts *sync.Map
+
+	cancel  context.CancelFunc
+	stopped chan struct{}
 }
 
 type portForwardEntry struct {
@@ -74,7 +79,7 @@ type kubectlForwarder struct{}
 func (*kubectlForwarder) Forward(pfe *portForwardEntry) error {
 	logrus.Debugf("Port forwarding %s", pfe)
 	portNumber := fmt.Sprintf("%d", pfe.port)
-	cmd := exec.Command("kubectl", "port-forward", pfe.podName, portNumber, portNumber, "--namespace", pfe.namespace)
+	cmd := kubectx.KubectlCommand(context.Background(), append(kubectx.KubectlArgs(), "port-forward", pfe.podName, portNumber, portNumber, "--namespace", pfe.namespace)...)
 	pfe.cmd = cmd
 
 	buf := &bytes.Buffer{}
@@ -100,10 +105,11 @@ func (*kubectlForwarder) Stop(p *portForwardEntry) error {
 }
 
 // NewPortForwarder returns a struct that tracks and port-forwards pods as they are created and modified
-func NewPortForwarder(out io.Writer, podSelector PodSelector) *PortForwarder {
+func NewPortForwarder(out io.Writer, pods *PodCache, podSelector PodSelector) *PortForwarder {
 	return &PortForwarder{
 		Forwarder:      &kubectlForwarder{},
 		output:         out,
+		pods:           pods,
 		podSelector:    podSelector,
 		forwardedPods:  &sync.Map{},
 		forwardedPorts: &sync.Map{},
@@ -116,24 +122,28 @@ func (p *PortForwarder) cleanupPorts() {
 		if err := p.Stop(entry); err != nil {
 			logrus.Warnf("cleaning up port forwards: %s", err)
 		}
-		return false
+		return true
 	})
 }
 
 // Start begins a pod watcher that port forwards any pods involving containers with exposed ports.
-// TODO(r2d4): merge this event loop with pod watcher from log writer
 func (p *PortForwarder) Start(ctx context.Context) error {
-	watcher, err := PodWatcher()
+	watcher, err := p.pods.Watch()
 	if err != nil {
 		return errors.Wrap(err, "initializing pod watcher")
 	}
 
+	cancelCtx, cancel := context.WithCancel(ctx)
+	p.cancel = cancel
+	p.stopped = make(chan struct{})
+
 	go func() {
+		defer close(p.stopped)
 		defer watcher.Stop()
 
 		for {
 			select {
-			case <-ctx.Done():
+			case <-cancelCtx.Done():
 				p.cleanupPorts()
 				return
 			case evt, ok := <-watcher.ResultChan():
@@ -165,6 +175,17 @@ func (p *PortForwarder) Start(ctx context.Context) error {
 	return nil
 }
 
+// Terminate stops watching pods and terminates the port-forwards.
+// It waits for the port-forwards to be stopped.
+func (p *PortForwarder) Terminate() {
+	if p.cancel == nil {
+		return
+	}
+
+	p.cancel()
+	<-p.stopped
+}
+
 func (p *PortForwarder) portForwardPod(pod *v1.Pod) error {
 	resourceVersion, err := strconv.Atoi(pod.ResourceVersion)
 	if err != nil {
@@ -328,7 +328,7 @@ func TestPortForwardPod(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.description, func(t *testing.T) {
-			p := NewPortForwarder(ioutil.Discard, NewImageList())
+			p := NewPortForwarder(ioutil.Discard, NewPodCache("", nil), NewImageList())
 			if test.forwarder == nil {
 				test.forwarder = newTestForwarder(nil, nil)
 			}
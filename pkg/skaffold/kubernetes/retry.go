@@ -0,0 +1,74 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+)
+
+// apiRetries is how many times a call to the API server is retried
+// when the connection fails.
+const apiRetries = 5
+
+// apiRetryBackoff is the delay before the first retry. It doubles after each retry.
+var apiRetryBackoff = 500 * time.Millisecond // for testing
+
+// IsConnectionError says if an error is caused by a failure to reach the
+// API server, such as a connection reset or a restarting API server,
+// rather than by the request itself.
+func IsConnectionError(err error) bool {
+	err = errors.Cause(err)
+	if err == nil {
+		return false
+	}
+
+	if apierrs.IsServerTimeout(err) || apierrs.IsTimeout(err) || apierrs.IsTooManyRequests(err) || apierrs.IsServiceUnavailable(err) {
+		return true
+	}
+	if err == io.ErrUnexpectedEOF || utilnet.IsProbableEOF(err) || utilnet.IsConnectionReset(err) {
+		return true
+	}
+	if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+		return true
+	}
+	return strings.Contains(err.Error(), "connection refused")
+}
+
+// RetryOnConnectionError calls fn until it succeeds, it fails for a reason other
+// than a connection error or the retries are exhausted.
+func RetryOnConnectionError(fn func() error) error {
+	backoff := apiRetryBackoff
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		if err = fn(); err == nil || !IsConnectionError(err) || attempt == apiRetries {
+			return err
+		}
+
+		logrus.Debugf("Unable to reach the API server, retrying in %s: %s", backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"errors"
+	"io"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestIsConnectionError(t *testing.T) {
+	var tests = []struct {
+		description string
+		err         error
+		expected    bool
+	}{
+		{"nil", nil, false},
+		{"connection reset", syscall.ECONNRESET, true},
+		{"connection refused", errors.New("dial tcp 10.0.0.1:443: connect: connection refused"), true},
+		{"unexpected eof", io.ErrUnexpectedEOF, true},
+		{"server timeout", apierrs.NewServerTimeout(schema.GroupResource{Resource: "pods"}, "list", 1), true},
+		{"not found", apierrs.NewNotFound(schema.GroupResource{Resource: "pods"}, "web"), false},
+		{"forbidden", apierrs.NewForbidden(schema.GroupResource{Resource: "pods"}, "web", errors.New("rbac")), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			testutil.CheckDeepEqual(t, test.expected, IsConnectionError(test.err))
+		})
+	}
+}
+
+func TestRetryOnConnectionError(t *testing.T) {
+	defer func(b time.Duration) { apiRetryBackoff = b }(apiRetryBackoff)
+	apiRetryBackoff = time.Millisecond
+
+	var tests = []struct {
+		description   string
+		errors        []error
+		shouldErr     bool
+		expectedCalls int
+	}{
+		{
+			description:   "success",
+			expectedCalls: 1,
+		},
+		{
+			description:   "transient failures",
+			errors:        []error{io.ErrUnexpectedEOF, syscall.ECONNRESET},
+			expectedCalls: 3,
+		},
+		{
+			description:   "permanent failure",
+			errors:        []error{apierrs.NewNotFound(schema.GroupResource{Resource: "pods"}, "web")},
+			shouldErr:     true,
+			expectedCalls: 1,
+		},
+		{
+			description:   "retries exhausted",
+			errors:        []error{io.ErrUnexpectedEOF, io.ErrUnexpectedEOF, io.ErrUnexpectedEOF, io.ErrUnexpectedEOF, io.ErrUnexpectedEOF, io.ErrUnexpectedEOF, io.ErrUnexpectedEOF},
+			shouldErr:     true,
+			expectedCalls: apiRetries + 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			calls := 0
+			err := RetryOnConnectionError(func() error {
+				calls++
+				if calls <= len(test.errors) {
+					return test.errors[calls-1]
+				}
+				return nil
+			})
+
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expectedCalls, calls)
+		})
+	}
+}
@@ -19,11 +19,13 @@ package kubernetes
 import (
 	"context"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/golang/glog"
 	"github.com/sirupsen/logrus"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	"k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -68,8 +70,16 @@ func WaitForPodReady(ctx context.Context, pods corev1.PodInterface, podName stri
 			IncludeUninitialized: true,
 		})
 		if err != nil {
+			if IsConnectionError(err) {
+				logrus.Debugf("Getting pod %s: %s", podName, err)
+				return false, nil
+			}
 			return false, fmt.Errorf("not found: %s", podName)
 		}
+		if err := imagePullError(pod); err != nil {
+			return false, err
+		}
+
 		switch pod.Status.Phase {
 		case v1.PodRunning:
 			return true, nil
@@ -82,13 +92,26 @@ func WaitForPodReady(ctx context.Context, pods corev1.PodInterface, podName stri
 	}, ctx.Done())
 }
 
-func WaitForPodComplete(ctx context.Context, pods corev1.PodInterface, podName string, timeout time.Duration) error {
-	logrus.Infof("Waiting for %s to be ready", podName)
+// podStatusInterval is how often the status of a pod is repeated while it doesn't change.
+var podStatusInterval = 30 * time.Second // for testing
 
-	ctx, cancelTimeout := context.WithTimeout(ctx, timeout)
-	defer cancelTimeout()
+// WaitForPodComplete waits for a pod to succeed. It prints the status of the pod
+// when it changes and, if the pod can't be scheduled, the warning events that explain why.
+// A zero timeout waits until the context is cancelled.
+func WaitForPodComplete(ctx context.Context, out io.Writer, pods corev1.PodInterface, events corev1.EventInterface, podName string, timeout time.Duration) error {
+	logrus.Infof("Waiting for %s to complete", podName)
 
-	return wait.PollImmediateUntil(time.Millisecond*500, func() (bool, error) {
+	if timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, timeout)
+		defer cancelTimeout()
+	}
+
+	var lastStatus string
+	var lastPrinted time.Time
+	seenEvents := map[string]bool{}
+
+	err := wait.PollImmediateUntil(time.Millisecond*500, func() (bool, error) {
 		pod, err := pods.Get(podName, meta_v1.GetOptions{
 			IncludeUninitialized: true,
 		})
@@ -96,6 +119,20 @@ func WaitForPodComplete(ctx context.Context, pods corev1.PodInterface, podName s
 			logrus.Infof("Getting pod %s", err)
 			return false, nil
 		}
+
+		status := podStatus(pod)
+		if status != lastStatus || time.Since(lastPrinted) >= podStatusInterval {
+			fmt.Fprintf(out, "Pod %s: %s\n", podName, status)
+			lastStatus = status
+			lastPrinted = time.Now()
+		}
+		if err := imagePullError(pod); err != nil {
+			return false, err
+		}
+		if isUnschedulable(pod) {
+			printPodEvents(out, events, podName, seenEvents)
+		}
+
 		switch pod.Status.Phase {
 		case v1.PodSucceeded:
 			return true, nil
@@ -108,6 +145,102 @@ func WaitForPodComplete(ctx context.Context, pods corev1.PodInterface, podName s
 		}
 		return false, fmt.Errorf("unknown phase: %s", pod.Status.Phase)
 	}, ctx.Done())
+
+	if err == wait.ErrWaitTimeout && ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %s waiting for pod %s, last status: %s", timeout, podName, lastStatus)
+	}
+	return err
+}
+
+// podStatus describes the phase of a pod along with the reason
+// why it is waiting, such as ContainerCreating or ImagePullBackOff.
+func podStatus(pod *v1.Pod) string {
+	status := string(pod.Status.Phase)
+	if status == "" {
+		status = string(v1.PodPending)
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodScheduled && condition.Status == v1.ConditionFalse && condition.Reason != "" {
+			return fmt.Sprintf("%s (%s)", status, condition.Reason)
+		}
+	}
+
+	var statuses []v1.ContainerStatus
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for _, c := range statuses {
+		if c.State.Waiting != nil && c.State.Waiting.Reason != "" {
+			return fmt.Sprintf("%s (%s)", status, c.State.Waiting.Reason)
+		}
+	}
+
+	return status
+}
+
+// imagePullError returns an ImagePullError if a container of the pod
+// repeatedly failed to pull its image, or if the image name is invalid.
+func imagePullError(pod *v1.Pod) error {
+	var statuses []v1.ContainerStatus
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+
+	for _, c := range statuses {
+		waiting := c.State.Waiting
+		if waiting == nil {
+			continue
+		}
+
+		switch waiting.Reason {
+		case "ImagePullBackOff", "InvalidImageName", "ErrImageNeverPull":
+			return &ImagePullError{
+				Pod:       pod.Name,
+				Container: c.Name,
+				Image:     c.Image,
+				Reason:    waiting.Reason,
+				Message:   waiting.Message,
+			}
+		}
+	}
+
+	return nil
+}
+
+func isUnschedulable(pod *v1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodScheduled && condition.Status == v1.ConditionFalse {
+			return true
+		}
+	}
+	return false
+}
+
+// printPodEvents prints the warning events of a pod that were not printed yet.
+func printPodEvents(out io.Writer, events corev1.EventInterface, podName string, seen map[string]bool) {
+	list, err := events.List(meta_v1.ListOptions{
+		FieldSelector: fields.Set{
+			"involvedObject.kind": "Pod",
+			"involvedObject.name": podName,
+		}.AsSelector().String(),
+	})
+	if err != nil {
+		logrus.Debugf("Listing events of %s: %s", podName, err)
+		return
+	}
+
+	for _, event := range list.Items {
+		if event.InvolvedObject.Name != podName || event.Type != v1.EventTypeWarning {
+			continue
+		}
+
+		key := fmt.Sprintf("%s/%d", event.Name, event.Count)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		fmt.Fprintf(out, " - %s: %s\n", event.Reason, event.Message)
+	}
 }
 
 // WaitForPodInitialized waits until init containers have started running
@@ -126,6 +259,10 @@ func WaitForPodInitialized(ctx context.Context, pods corev1.PodInterface, podNam
 			IncludeUninitialized: true,
 		})
 		if err != nil {
+			if IsConnectionError(err) {
+				logrus.Debugf("Getting pod %s: %s", podName, err)
+				return false, nil
+			}
 			return false, fmt.Errorf("not found: %s", podName)
 		}
 		for _, ic := range pod.Status.InitContainerStatuses {
@@ -169,6 +306,74 @@ func WaitForDeploymentToStabilize(ctx context.Context, c kubernetes.Interface, n
 	return err
 }
 
+// WaitForDeploymentRollout waits until all the replicas of a Deployment are updated and available.
+// It fails early if the Deployment exceeded its progress deadline.
+func WaitForDeploymentRollout(ctx context.Context, c kubernetes.Interface, ns, name string, timeout time.Duration) error {
+	ctx, cancelTimeout := context.WithTimeout(ctx, timeout)
+	defer cancelTimeout()
+
+	err := wait.PollImmediateUntil(time.Second, func() (bool, error) {
+		dp, err := c.AppsV1().Deployments(ns).Get(name, meta_v1.GetOptions{})
+		if err != nil {
+			logrus.Debugf("Getting deployment %s: %s", name, err)
+			return false, nil
+		}
+
+		for _, condition := range dp.Status.Conditions {
+			if condition.Type == appsv1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+				return false, fmt.Errorf("deployment %s exceeded its progress deadline: %s", name, condition.Message)
+			}
+		}
+
+		replicas := int32(1)
+		if dp.Spec.Replicas != nil {
+			replicas = *dp.Spec.Replicas
+		}
+
+		return dp.Generation <= dp.Status.ObservedGeneration &&
+			dp.Status.UpdatedReplicas == replicas &&
+			dp.Status.Replicas == replicas &&
+			dp.Status.AvailableReplicas == replicas, nil
+	}, ctx.Done())
+
+	if err == wait.ErrWaitTimeout {
+		return &RolloutTimeoutError{Deployment: name, Timeout: timeout}
+	}
+	return err
+}
+
+// WaitForJobComplete waits until a Job succeeds. It fails as soon as the Job fails.
+func WaitForJobComplete(ctx context.Context, c kubernetes.Interface, ns, name string, timeout time.Duration) error {
+	ctx, cancelTimeout := context.WithTimeout(ctx, timeout)
+	defer cancelTimeout()
+
+	err := wait.PollImmediateUntil(time.Second, func() (bool, error) {
+		job, err := c.BatchV1().Jobs(ns).Get(name, meta_v1.GetOptions{})
+		if err != nil {
+			logrus.Debugf("Getting job %s: %s", name, err)
+			return false, nil
+		}
+
+		for _, condition := range job.Status.Conditions {
+			if condition.Status != v1.ConditionTrue {
+				continue
+			}
+			switch condition.Type {
+			case batchv1.JobComplete:
+				return true, nil
+			case batchv1.JobFailed:
+				return false, fmt.Errorf("job %s failed: %s", name, condition.Message)
+			}
+		}
+		return false, nil
+	}, ctx.Done())
+
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("job %s didn't complete within %v", name, timeout)
+	}
+	return err
+}
+
 // WaitForJobToStabilize waits till the Job has at least one active pod
 func WaitForJobToStabilize(ctx context.Context, c kubernetes.Interface, ns, name string, timeout time.Duration) error {
 	ctx, cancelTimeout := context.WithTimeout(ctx, timeout)
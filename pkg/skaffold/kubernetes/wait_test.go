@@ -17,13 +17,17 @@ limitations under the License.
 package kubernetes
 
 import (
+	"bytes"
 	"context"
+	"io/ioutil"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/GoogleContainerTools/skaffold/testutil"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
 )
 
@@ -131,3 +135,117 @@ func TestWaitForPodReady(t *testing.T) {
 		})
 	}
 }
+
+func TestWaitForPodComplete(t *testing.T) {
+	var tests = []struct {
+		description    string
+		pod            *v1.Pod
+		events         []runtime.Object
+		timeout        time.Duration
+		shouldErr      bool
+		expectedOutput string
+	}{
+		{
+			description: "pod succeeded",
+			pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "podname"},
+				Status:     v1.PodStatus{Phase: v1.PodSucceeded},
+			},
+			expectedOutput: "Pod podname: Succeeded\n",
+		},
+		{
+			description: "pod failed",
+			pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "podname"},
+				Status:     v1.PodStatus{Phase: v1.PodFailed},
+			},
+			shouldErr:      true,
+			expectedOutput: "Pod podname: Failed\n",
+		},
+		{
+			description: "pod can't be scheduled",
+			pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "podname"},
+				Status: v1.PodStatus{
+					Phase: v1.PodPending,
+					Conditions: []v1.PodCondition{{
+						Type:   v1.PodScheduled,
+						Status: v1.ConditionFalse,
+						Reason: "Unschedulable",
+					}},
+				},
+			},
+			events: []runtime.Object{
+				&v1.Event{
+					ObjectMeta:     metav1.ObjectMeta{Name: "event1"},
+					InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "podname"},
+					Type:           v1.EventTypeWarning,
+					Reason:         "FailedScheduling",
+					Message:        "0/3 nodes are available: 3 Insufficient cpu.",
+					Count:          1,
+				},
+				&v1.Event{
+					ObjectMeta:     metav1.ObjectMeta{Name: "event2"},
+					InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "otherpod"},
+					Type:           v1.EventTypeWarning,
+					Reason:         "FailedScheduling",
+				},
+			},
+			timeout:        time.Second,
+			shouldErr:      true,
+			expectedOutput: "Pod podname: Pending (Unschedulable)\n - FailedScheduling: 0/3 nodes are available: 3 Insufficient cpu.\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			client := fake.NewSimpleClientset(append(test.events, test.pod)...)
+
+			var out bytes.Buffer
+			err := WaitForPodComplete(context.Background(), &out, client.CoreV1().Pods(""), client.CoreV1().Events(""), "podname", test.timeout)
+
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expectedOutput, out.String())
+		})
+	}
+}
+
+func TestWaitForPodCompleteTimeout(t *testing.T) {
+	client := fake.NewSimpleClientset(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "podname"},
+		Status: v1.PodStatus{
+			Phase: v1.PodPending,
+			ContainerStatuses: []v1.ContainerStatus{{
+				State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ContainerCreating"}},
+			}},
+		},
+	})
+
+	err := WaitForPodComplete(context.Background(), &bytes.Buffer{}, client.CoreV1().Pods(""), client.CoreV1().Events(""), "podname", time.Second)
+
+	testutil.CheckDeepEqual(t, true, err != nil && strings.Contains(err.Error(), "last status: Pending (ContainerCreating)"))
+}
+
+func TestWaitForPodCompleteImagePull(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "podname"},
+		Status: v1.PodStatus{
+			Phase: v1.PodPending,
+			ContainerStatuses: []v1.ContainerStatus{{
+				Name:  "verify",
+				Image: "gcr.io/k8s-skaffold/e2e:v1",
+				State: v1.ContainerState{
+					Waiting: &v1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image"},
+				},
+			}},
+		},
+	}
+	client := fake.NewSimpleClientset(pod)
+
+	err := WaitForPodComplete(context.Background(), ioutil.Discard, client.CoreV1().Pods(""), client.CoreV1().Events(""), "podname", 0)
+
+	pullErr, ok := err.(*ImagePullError)
+	if !ok {
+		t.Fatalf("expected an ImagePullError, got %v", err)
+	}
+	testutil.CheckDeepEqual(t, "gcr.io/k8s-skaffold/e2e:v1", pullErr.Image)
+}
@@ -17,21 +17,302 @@ limitations under the License.
 package kubernetes
 
 import (
+	"sync"
+
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/watch"
 )
 
-// PodWatcher returns a watcher that will report on all Pod Events (additions, modifications, etc.)
-func PodWatcher() (watch.Interface, error) {
-	kubeclient, err := Client()
+// PodCache keeps track of the pods in the cluster with a single list and watch.
+// It's shared by the log aggregator, the port forwarder, the health reporter and
+// the file syncer so that they all see the same pods without each querying the API server.
+// The cache is started on first use. It watches the namespace skaffold was
+// asked to work in, or all the namespaces if none was given, plus the namespaces
+// the manifests were deployed to, and only the pods that carry its labels, if any.
+type PodCache struct {
+	labels   map[string]string
+	selector string
+
+	startOnce sync.Once
+	startErr  error
+	stopOnce  sync.Once
+	stop      chan struct{}
+	running   sync.WaitGroup
+
+	lock        sync.RWMutex
+	namespaces  map[string]bool
+	started     bool
+	stopping    bool
+	pods        map[string]*v1.Pod
+	adopted     map[Workload]bool
+	closed      bool
+	broadcaster *watch.Broadcaster
+}
+
+// NewPodCache creates a PodCache for the pods of a namespace that carry the given labels.
+// An empty namespace means all namespaces and no labels means all pods.
+func NewPodCache(namespace string, podLabels map[string]string) *PodCache {
+	return &PodCache{
+		labels:      podLabels,
+		selector:    labels.SelectorFromSet(podLabels).String(),
+		stop:        make(chan struct{}),
+		namespaces:  map[string]bool{namespace: true},
+		pods:        map[string]*v1.Pod{},
+		adopted:     map[Workload]bool{},
+		broadcaster: watch.NewBroadcaster(100, watch.WaitIfChannelFull),
+	}
+}
+
+// WatchNamespaces adds namespaces to the ones being watched. This is how the pods of
+// manifests that declare their own namespace are found. If the cache is already
+// started, the pods of the new namespaces are reported to the watchers as added.
+func (c *PodCache) WatchNamespaces(namespaces ...string) error {
+	c.lock.Lock()
+	var added []string
+	for _, namespace := range namespaces {
+		if c.namespaces[""] || c.namespaces[namespace] {
+			continue
+		}
+		c.namespaces[namespace] = true
+		added = append(added, namespace)
+	}
+	started := c.started
+	c.lock.Unlock()
+
+	if !started {
+		return nil
+	}
+	for _, namespace := range added {
+		if err := c.listAndWatch(namespace, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Watch returns a watcher that will report on all Pod Events (additions, modifications, etc.)
+// It starts with an Added event for each pod already known.
+func (c *PodCache) Watch() (watch.Interface, error) {
+	if err := c.start(); err != nil {
+		return nil, err
+	}
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.closed {
+		return nil, errors.New("pod watch was closed")
+	}
+
+	var events []watch.Event
+	for _, pod := range c.pods {
+		events = append(events, watch.Event{Type: watch.Added, Object: pod})
+	}
+	return c.broadcaster.WatchWithPrefix(events), nil
+}
+
+// Pods returns the pods currently known.
+func (c *PodCache) Pods() ([]v1.Pod, error) {
+	if err := c.start(); err != nil {
+		return nil, err
+	}
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	var pods []v1.Pod
+	for _, pod := range c.pods {
+		pods = append(pods, *pod)
+	}
+	return pods, nil
+}
+
+// Stop stops watching the pods and closes the watchers.
+func (c *PodCache) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
+}
+
+func (c *PodCache) start() error {
+	c.startOnce.Do(func() {
+		c.lock.Lock()
+		c.started = true
+		var namespaces []string
+		for namespace := range c.namespaces {
+			namespaces = append(namespaces, namespace)
+		}
+		c.lock.Unlock()
+
+		// The watchers are closed once every namespace stopped being watched.
+		go func() {
+			<-c.stop
+			c.lock.Lock()
+			c.stopping = true
+			c.lock.Unlock()
+
+			c.running.Wait()
+			c.close()
+		}()
+
+		for _, namespace := range namespaces {
+			if err := c.listAndWatch(namespace, false); err != nil {
+				c.startErr = err
+				c.Stop()
+				return
+			}
+		}
+	})
+	return c.startErr
+}
+
+func (c *PodCache) listAndWatch(namespace string, notify bool) error {
+	c.lock.Lock()
+	if c.stopping {
+		c.lock.Unlock()
+		return errors.New("pod watch was closed")
+	}
+	c.running.Add(1)
+	c.lock.Unlock()
+
+	watcher, err := c.relist(namespace, notify)
+	if err != nil {
+		c.running.Done()
+		return err
+	}
+
+	go c.run(namespace, watcher)
+	return nil
+}
+
+// relist lists the pods of a namespace, optionally notifies the watchers of the
+// changes since the last list, then starts watching from there.
+func (c *PodCache) relist(namespace string, notify bool) (watch.Interface, error) {
+	client, err := Client()
 	if err != nil {
 		return nil, errors.Wrap(err, "getting k8s client")
 	}
-	client := kubeclient.CoreV1()
+	pods := client.CoreV1().Pods(namespace)
+
+	var list *v1.PodList
+	if err := RetryOnConnectionError(func() error {
+		list, err = pods.List(meta_v1.ListOptions{
+			IncludeUninitialized: true,
+			LabelSelector:        c.selector,
+		})
+		return err
+	}); err != nil {
+		return nil, errors.Wrap(classify(err), "listing pods")
+	}
+
 	var forever int64 = 3600 * 24 * 365 * 100
-	return client.Pods("").Watch(meta_v1.ListOptions{
-		IncludeUninitialized: true,
-		TimeoutSeconds:       &forever,
-	})
+	var watcher watch.Interface
+	if err := RetryOnConnectionError(func() error {
+		watcher, err = pods.Watch(meta_v1.ListOptions{
+			IncludeUninitialized: true,
+			LabelSelector:        c.selector,
+			ResourceVersion:      list.ResourceVersion,
+			TimeoutSeconds:       &forever,
+		})
+		return err
+	}); err != nil {
+		return nil, errors.Wrap(classify(err), "watching pods")
+	}
+
+	c.update(namespace, list.Items, notify)
+	return watcher, nil
+}
+
+// update replaces the known pods of a namespace with a fresh list.
+func (c *PodCache) update(namespace string, pods []v1.Pod, notify bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	listed := map[string]bool{}
+	for i := range pods {
+		pod := &pods[i]
+		key := podKey(pod)
+		listed[key] = true
+
+		previous, found := c.pods[key]
+		c.pods[key] = pod
+		switch {
+		case !notify:
+		case !found:
+			c.broadcaster.Action(watch.Added, pod)
+		case previous.ResourceVersion != pod.ResourceVersion:
+			c.broadcaster.Action(watch.Modified, pod)
+		}
+	}
+
+	for key, pod := range c.pods {
+		if namespace != "" && pod.Namespace != namespace {
+			continue
+		}
+		if !listed[key] {
+			delete(c.pods, key)
+			if notify {
+				c.broadcaster.Action(watch.Deleted, pod)
+			}
+		}
+	}
+}
+
+// run keeps the pods of a namespace up to date until the cache is stopped. When the
+// watch is interrupted, for example because the API server was unreachable
+// for a while, the pods are listed again and a new watch is started.
+func (c *PodCache) run(namespace string, watcher watch.Interface) {
+	defer c.running.Done()
+	defer func() { watcher.Stop() }()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case evt, ok := <-watcher.ResultChan():
+			if !ok || evt.Type == watch.Error {
+				watcher.Stop()
+				logrus.Debugln("Pod watch was interrupted, watching again")
+
+				restarted, err := c.relist(namespace, true)
+				if err != nil {
+					logrus.Warnln("Unable to watch pods anymore:", err)
+					c.Stop()
+					return
+				}
+				watcher = restarted
+				continue
+			}
+
+			pod, ok := evt.Object.(*v1.Pod)
+			if !ok {
+				continue
+			}
+
+			c.lock.Lock()
+			if evt.Type == watch.Deleted {
+				delete(c.pods, podKey(pod))
+			} else {
+				c.pods[podKey(pod)] = pod
+			}
+			c.broadcaster.Action(evt.Type, pod)
+			c.lock.Unlock()
+		}
+	}
+}
+
+func (c *PodCache) close() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.closed = true
+	c.broadcaster.Shutdown()
+}
+
+func podKey(pod *v1.Pod) string {
+	return pod.Namespace + "/" + pod.Name
 }
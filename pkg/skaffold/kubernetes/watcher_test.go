@@ -0,0 +1,163 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestPodCache(t *testing.T) {
+	existing := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default"}}
+	client := fake.NewSimpleClientset(existing)
+
+	defer func(c func() (k8s.Interface, error)) { Client = c }(Client)
+	Client = func() (k8s.Interface, error) { return client, nil }
+
+	cache := NewPodCache("", nil)
+	defer cache.Stop()
+
+	pods, err := cache.Pods()
+	testutil.CheckErrorAndDeepEqual(t, false, err, []v1.Pod{*existing}, pods)
+
+	first, err := cache.Watch()
+	testutil.CheckError(t, false, err)
+	second, err := cache.Watch()
+	testutil.CheckError(t, false, err)
+
+	testutil.CheckDeepEqual(t, watch.Added, nextEvent(t, first).Type)
+	testutil.CheckDeepEqual(t, watch.Added, nextEvent(t, second).Type)
+
+	created := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "created", Namespace: "default"}}
+	client.CoreV1().Pods("default").Create(created)
+
+	for _, watcher := range []watch.Interface{first, second} {
+		evt := nextEvent(t, watcher)
+		testutil.CheckDeepEqual(t, watch.Added, evt.Type)
+		testutil.CheckDeepEqual(t, "created", evt.Object.(*v1.Pod).Name)
+	}
+
+	pods, err = cache.Pods()
+	testutil.CheckErrorAndDeepEqual(t, false, err, 2, len(pods))
+}
+
+func TestPodCacheSelector(t *testing.T) {
+	deployed := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "deployed", Namespace: "default", Labels: map[string]string{"deployed-with": "skaffold"}}}
+	other := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"}}
+	client := fake.NewSimpleClientset(deployed, other)
+
+	defer func(c func() (k8s.Interface, error)) { Client = c }(Client)
+	Client = func() (k8s.Interface, error) { return client, nil }
+
+	cache := NewPodCache("", map[string]string{"deployed-with": "skaffold"})
+	defer cache.Stop()
+
+	pods, err := cache.Pods()
+	testutil.CheckErrorAndDeepEqual(t, false, err, []v1.Pod{*deployed}, pods)
+}
+
+func TestPodCacheWatchNamespaces(t *testing.T) {
+	first := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "ns1"}}
+	second := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "ns2"}}
+	other := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "ns3"}}
+	client := fake.NewSimpleClientset(first, second, other)
+
+	defer func(c func() (k8s.Interface, error)) { Client = c }(Client)
+	Client = func() (k8s.Interface, error) { return client, nil }
+
+	cache := NewPodCache("ns1", nil)
+	defer cache.Stop()
+
+	watcher, err := cache.Watch()
+	testutil.CheckError(t, false, err)
+	testutil.CheckDeepEqual(t, "first", nextEvent(t, watcher).Object.(*v1.Pod).Name)
+
+	err = cache.WatchNamespaces("ns1", "ns2")
+	testutil.CheckError(t, false, err)
+
+	evt := nextEvent(t, watcher)
+	testutil.CheckDeepEqual(t, watch.Added, evt.Type)
+	testutil.CheckDeepEqual(t, "second", evt.Object.(*v1.Pod).Name)
+
+	created := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "created", Namespace: "ns2"}}
+	client.CoreV1().Pods("ns2").Create(created)
+
+	evt = nextEvent(t, watcher)
+	testutil.CheckDeepEqual(t, watch.Added, evt.Type)
+	testutil.CheckDeepEqual(t, "created", evt.Object.(*v1.Pod).Name)
+
+	pods, err := cache.Pods()
+	testutil.CheckErrorAndDeepEqual(t, false, err, 3, len(pods))
+}
+
+func TestPodCacheWatchesAgainWhenInterrupted(t *testing.T) {
+	existing := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default"}}
+	client := fake.NewSimpleClientset(existing)
+
+	// The first watch gets interrupted, the next ones are served by the fake clientset.
+	interrupted := watch.NewFake()
+	var once sync.Once
+	client.PrependWatchReactor("pods", func(k8stesting.Action) (bool, watch.Interface, error) {
+		first := false
+		once.Do(func() { first = true })
+		return first, interrupted, nil
+	})
+
+	defer func(c func() (k8s.Interface, error)) { Client = c }(Client)
+	Client = func() (k8s.Interface, error) { return client, nil }
+
+	cache := NewPodCache("", nil)
+	defer cache.Stop()
+
+	watcher, err := cache.Watch()
+	testutil.CheckError(t, false, err)
+	testutil.CheckDeepEqual(t, "existing", nextEvent(t, watcher).Object.(*v1.Pod).Name)
+
+	missed := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "missed", Namespace: "default"}}
+	client.CoreV1().Pods("default").Create(missed)
+	interrupted.Stop()
+
+	evt := nextEvent(t, watcher)
+	testutil.CheckDeepEqual(t, watch.Added, evt.Type)
+	testutil.CheckDeepEqual(t, "missed", evt.Object.(*v1.Pod).Name)
+
+	created := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "created", Namespace: "default"}}
+	client.CoreV1().Pods("default").Create(created)
+
+	evt = nextEvent(t, watcher)
+	testutil.CheckDeepEqual(t, watch.Added, evt.Type)
+	testutil.CheckDeepEqual(t, "created", evt.Object.(*v1.Pod).Name)
+}
+
+func nextEvent(t *testing.T, watcher watch.Interface) watch.Event {
+	select {
+	case evt := <-watcher.ResultChan():
+		return evt
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for pod event")
+		return watch.Event{}
+	}
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+)
+
+// Desktop shows notifications with notify-send on Linux and osascript on macOS.
+type Desktop struct{}
+
+func (d *Desktop) Notify(ctx context.Context, out io.Writer, n Notification) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "linux":
+		cmd = exec.CommandContext(ctx, "notify-send", "Skaffold", n.Message())
+	case "darwin":
+		cmd = exec.CommandContext(ctx, "osascript", "-e", fmt.Sprintf("display notification %q with title \"Skaffold\"", n.Message()))
+	default:
+		return fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS)
+	}
+
+	return util.RunCmd(cmd)
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
+)
+
+// Names of the notifications.
+const (
+	BuildSucceeded  = "buildSucceeded"
+	BuildFailed     = "buildFailed"
+	DeploySucceeded = "deploySucceeded"
+	DeployFailed    = "deployFailed"
+)
+
+// Notification tells about the end of a build or a deploy.
+type Notification struct {
+	Name   string   `json:"name"`
+	Images []string `json:"images,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// Message is a human readable description of the notification.
+func (n Notification) Message() string {
+	var msg string
+	switch n.Name {
+	case BuildSucceeded:
+		msg = "Build succeeded"
+	case BuildFailed:
+		msg = "Build failed"
+	case DeploySucceeded:
+		msg = "Deploy succeeded"
+	case DeployFailed:
+		msg = "Deploy failed"
+	default:
+		msg = n.Name
+	}
+
+	if len(n.Images) > 0 {
+		msg += ": " + strings.Join(n.Images, ", ")
+	}
+	if n.Error != "" {
+		msg += ": " + n.Error
+	}
+	return msg
+}
+
+// Notifier sends notifications.
+type Notifier interface {
+	Notify(ctx context.Context, out io.Writer, n Notification) error
+}
+
+// NewNotifiers creates the notifiers configured in skaffold.yaml.
+func NewNotifiers(cfg latest.NotifyConfig) ([]Notifier, error) {
+	var notifiers []Notifier
+
+	for i, c := range cfg {
+		notifier, err := newNotifier(c)
+		if err != nil {
+			return nil, errors.Wrapf(err, "notify[%d]", i)
+		}
+
+		if len(c.On) == 0 {
+			notifiers = append(notifiers, notifier)
+			continue
+		}
+
+		on := map[string]bool{}
+		for _, name := range c.On {
+			switch name {
+			case BuildSucceeded, BuildFailed, DeploySucceeded, DeployFailed:
+				on[name] = true
+			default:
+				return nil, fmt.Errorf("notify[%d]: unknown notification %q, expected %s, %s, %s or %s", i, name, BuildSucceeded, BuildFailed, DeploySucceeded, DeployFailed)
+			}
+		}
+		notifiers = append(notifiers, filtered{Notifier: notifier, on: on})
+	}
+
+	return notifiers, nil
+}
+
+func newNotifier(c *latest.Notifier) (Notifier, error) {
+	switch {
+	case c.Desktop != nil:
+		return &Desktop{}, nil
+	case c.Slack != nil:
+		if c.Slack.WebhookURL == "" {
+			return nil, errors.New("slack.webhookURL is required")
+		}
+		return &Slack{WebhookURL: c.Slack.WebhookURL}, nil
+	case c.Webhook != nil:
+		if c.Webhook.URL == "" {
+			return nil, errors.New("webhook.url is required")
+		}
+		return &Webhook{URL: c.Webhook.URL, Headers: c.Webhook.Headers}, nil
+	default:
+		return nil, errors.New("one of desktop, slack or webhook is required")
+	}
+}
+
+// filtered only sends some of the notifications.
+type filtered struct {
+	Notifier
+	on map[string]bool
+}
+
+func (f filtered) Notify(ctx context.Context, out io.Writer, n Notification) error {
+	if !f.on[n.Name] {
+		return nil
+	}
+	return f.Notifier.Notify(ctx, out, n)
+}
+
+// Bell beeps in the terminal when a deploy succeeds.
+type Bell struct{}
+
+// terminalBell is the sequence that triggers a beep in the terminal
+const terminalBell = "\007"
+
+func (Bell) Notify(ctx context.Context, out io.Writer, n Notification) error {
+	if n.Name == DeploySucceeded {
+		fmt.Fprint(out, terminalBell)
+	}
+	return nil
+}
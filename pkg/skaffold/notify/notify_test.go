@@ -0,0 +1,144 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestNewNotifiers(t *testing.T) {
+	var tests = []struct {
+		description string
+		cfg         latest.NotifyConfig
+		expected    []Notifier
+		shouldErr   bool
+	}{
+		{
+			description: "none",
+		},
+		{
+			description: "all kinds",
+			cfg: latest.NotifyConfig{
+				{Desktop: &latest.DesktopNotifier{}},
+				{Slack: &latest.SlackNotifier{WebhookURL: "https://hooks.slack.com/services/T/B/X"}},
+				{Webhook: &latest.WebhookNotifier{URL: "http://ci/hook", Headers: map[string]string{"Authorization": "token"}}},
+			},
+			expected: []Notifier{
+				&Desktop{},
+				&Slack{WebhookURL: "https://hooks.slack.com/services/T/B/X"},
+				&Webhook{URL: "http://ci/hook", Headers: map[string]string{"Authorization": "token"}},
+			},
+		},
+		{
+			description: "filtered",
+			cfg: latest.NotifyConfig{
+				{Desktop: &latest.DesktopNotifier{}, On: []string{"buildFailed", "deployFailed"}},
+			},
+			expected: []Notifier{
+				filtered{Notifier: &Desktop{}, on: map[string]bool{BuildFailed: true, DeployFailed: true}},
+			},
+		},
+		{
+			description: "unknown notification",
+			cfg: latest.NotifyConfig{
+				{Desktop: &latest.DesktopNotifier{}, On: []string{"testFailed"}},
+			},
+			shouldErr: true,
+		},
+		{
+			description: "missing slack url",
+			cfg:         latest.NotifyConfig{{Slack: &latest.SlackNotifier{}}},
+			shouldErr:   true,
+		},
+		{
+			description: "missing webhook url",
+			cfg:         latest.NotifyConfig{{Webhook: &latest.WebhookNotifier{}}},
+			shouldErr:   true,
+		},
+		{
+			description: "no notifier",
+			cfg:         latest.NotifyConfig{{}},
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			notifiers, err := NewNotifiers(test.cfg)
+
+			testutil.CheckError(t, test.shouldErr, err)
+			if !reflect.DeepEqual(test.expected, notifiers) {
+				t.Errorf("expected %+v, got %+v", test.expected, notifiers)
+			}
+		})
+	}
+}
+
+func TestFiltered(t *testing.T) {
+	out := new(bytes.Buffer)
+	notifier := filtered{Notifier: Bell{}, on: map[string]bool{DeploySucceeded: true}}
+
+	notifier.Notify(context.Background(), out, Notification{Name: BuildSucceeded})
+	testutil.CheckDeepEqual(t, "", out.String())
+
+	notifier.Notify(context.Background(), out, Notification{Name: DeploySucceeded})
+	testutil.CheckDeepEqual(t, terminalBell, out.String())
+}
+
+func TestMessage(t *testing.T) {
+	testutil.CheckDeepEqual(t, "Build succeeded: image:v1, other:v1", Notification{Name: BuildSucceeded, Images: []string{"image:v1", "other:v1"}}.Message())
+	testutil.CheckDeepEqual(t, "Deploy failed: kubectl apply: exit status 1", Notification{Name: DeployFailed, Error: "kubectl apply: exit status 1"}.Message())
+}
+
+func TestWebhooks(t *testing.T) {
+	var (
+		body    []byte
+		headers http.Header
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = ioutil.ReadAll(r.Body)
+		headers = r.Header
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	n := Notification{Name: DeployFailed, Error: "timeout"}
+
+	err := (&Slack{WebhookURL: server.URL + "/slack"}).Notify(context.Background(), ioutil.Discard, n)
+	testutil.CheckErrorAndDeepEqual(t, false, err, `{"text":"Deploy failed: timeout"}`, string(body))
+
+	err = (&Webhook{URL: server.URL + "/hook", Headers: map[string]string{"Authorization": "token"}}).Notify(context.Background(), ioutil.Discard, n)
+	var received Notification
+	json.Unmarshal(body, &received)
+	testutil.CheckErrorAndDeepEqual(t, false, err, n, received)
+	testutil.CheckDeepEqual(t, "token", headers.Get("Authorization"))
+
+	err = (&Webhook{URL: server.URL + "/fail"}).Notify(context.Background(), ioutil.Discard, n)
+	testutil.CheckError(t, true, err)
+}
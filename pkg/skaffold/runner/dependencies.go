@@ -0,0 +1,168 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// definitionFiles are the files, along with the Dockerfiles, that change
+// which files an artifact depends on.
+var definitionFiles = map[string]bool{
+	".dockerignore":       true,
+	"BUILD":               true,
+	"BUILD.bazel":         true,
+	"WORKSPACE":           true,
+	"pom.xml":             true,
+	"build.gradle":        true,
+	"build.gradle.kts":    true,
+	"settings.gradle":     true,
+	"settings.gradle.kts": true,
+}
+
+// dependencies caches the dependencies of the artifacts. Listing them can be slow
+// since it might pull base images or run Maven, Gradle or Bazel.
+var dependencies = newDependencyCache(DependenciesForArtifact)
+
+type dependencyCache struct {
+	list    func(context.Context, *latest.Artifact) ([]string, error)
+	lock    sync.Mutex
+	entries map[string]*cachedDependencies
+}
+
+type cachedDependencies struct {
+	fingerprint string
+	paths       []string
+}
+
+func newDependencyCache(list func(context.Context, *latest.Artifact) ([]string, error)) *dependencyCache {
+	return &dependencyCache{
+		list:    list,
+		entries: map[string]*cachedDependencies{},
+	}
+}
+
+// PrefetchDependencies lists the dependencies of the artifacts ahead of time,
+// so that the next builds and watches find them in the cache.
+func PrefetchDependencies(ctx context.Context, artifacts []*latest.Artifact) {
+	dependencies.prefetch(ctx, artifacts)
+}
+
+// prefetch lists the dependencies of all the artifacts in parallel.
+func (c *dependencyCache) prefetch(ctx context.Context, artifacts []*latest.Artifact) {
+	var wg sync.WaitGroup
+	for _, a := range artifacts {
+		wg.Add(1)
+		go func(a *latest.Artifact) {
+			defer wg.Done()
+			if _, err := c.forArtifact(ctx, a); err != nil {
+				logrus.Debugf("Unable to list the dependencies of %s: %s", a.ImageName, err)
+			}
+		}(a)
+	}
+	wg.Wait()
+}
+
+// forArtifact returns the dependencies of an artifact. They are listed again
+// only if the artifact's configuration, its definition files or the folders
+// holding its dependencies were modified since they were last listed.
+func (c *dependencyCache) forArtifact(ctx context.Context, a *latest.Artifact) ([]string, error) {
+	c.lock.Lock()
+	cached, found := c.entries[a.ImageName]
+	c.lock.Unlock()
+
+	if found && cached.fingerprint == fingerprint(a, cached.paths) {
+		return copyPaths(cached.paths), nil
+	}
+
+	paths, err := c.list(ctx, a)
+	if err != nil || ctx.Err() != nil {
+		return paths, err
+	}
+
+	c.lock.Lock()
+	c.entries[a.ImageName] = &cachedDependencies{
+		fingerprint: fingerprint(a, paths),
+		paths:       paths,
+	}
+	c.lock.Unlock()
+
+	return copyPaths(paths), nil
+}
+
+// fingerprint changes when the artifact's configuration changes or when one of
+// the files that define its dependencies, one of the folders holding them or
+// the workspace folder is modified. Adding or removing a file or a folder
+// modifies its parent folder. The rest of the workspace isn't walked: it's called
+// on every watch tick and workspaces can be huge, with node_modules for example.
+// A file added to a folder that holds no dependency yet is only noticed once
+// something else makes the dependencies be listed again.
+func fingerprint(a *latest.Artifact, paths []string) string {
+	config, err := yaml.Marshal(a)
+	if err != nil {
+		return ""
+	}
+
+	files := map[string]bool{
+		a.Workspace: true,
+	}
+	if a.DockerArtifact != nil {
+		if dockerfile, err := docker.NormalizeDockerfilePath(a.Workspace, a.DockerArtifact.DockerfilePath); err == nil {
+			files[dockerfile] = true
+			files[dockerfile+".dockerignore"] = true
+		}
+		files[filepath.Join(a.Workspace, ".dockerignore")] = true
+	}
+	for _, path := range paths {
+		files[filepath.Dir(path)] = true
+		if definitionFiles[filepath.Base(path)] {
+			files[path] = true
+		}
+	}
+
+	var sorted []string
+	for file := range files {
+		sorted = append(sorted, file)
+	}
+	sort.Strings(sorted)
+
+	var buf strings.Builder
+	buf.Write(config)
+	for _, file := range sorted {
+		var modTime int64
+		if info, err := os.Stat(file); err == nil {
+			modTime = info.ModTime().UnixNano()
+		}
+		fmt.Fprintf(&buf, "%s %d\n", file, modTime)
+	}
+	return buf.String()
+}
+
+func copyPaths(paths []string) []string {
+	return append([]string(nil), paths...)
+}
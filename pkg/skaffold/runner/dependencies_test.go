@@ -0,0 +1,112 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestDependencyCache(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	tmpDir.Write("Dockerfile", "FROM scratch\nCOPY src .").
+		Write("src/main.go", "package main").
+		Mkdir("assets/images")
+
+	before := time.Now().Add(-time.Hour)
+	for _, path := range []string{".", "src", "assets", "assets/images", "Dockerfile", "src/main.go"} {
+		tmpDir.Chtimes(path, before)
+	}
+
+	listed := 0
+	cache := newDependencyCache(func(context.Context, *latest.Artifact) ([]string, error) {
+		listed++
+		return []string{tmpDir.Path("Dockerfile"), tmpDir.Path("src/main.go")}, nil
+	})
+
+	artifact := &latest.Artifact{
+		ImageName: "image",
+		Workspace: tmpDir.Root(),
+		ArtifactType: latest.ArtifactType{
+			DockerArtifact: &latest.DockerArtifact{DockerfilePath: "Dockerfile"},
+		},
+	}
+
+	var tests = []struct {
+		description    string
+		change         func()
+		expectedListed int
+	}{
+		{
+			description:    "first call",
+			change:         func() {},
+			expectedListed: 1,
+		},
+		{
+			description:    "nothing changed",
+			change:         func() {},
+			expectedListed: 1,
+		},
+		{
+			description:    "source modified",
+			change:         func() { tmpDir.Chtimes("src/main.go", time.Now()) },
+			expectedListed: 1,
+		},
+		{
+			description:    "Dockerfile modified",
+			change:         func() { tmpDir.Chtimes("Dockerfile", time.Now()) },
+			expectedListed: 2,
+		},
+		{
+			description:    "file added to a folder",
+			change:         func() { tmpDir.Chtimes("src", time.Now()) },
+			expectedListed: 3,
+		},
+		{
+			description:    "folder without dependencies isn't checked",
+			change:         func() { tmpDir.Chtimes("assets/images", time.Now()) },
+			expectedListed: 3,
+		},
+		{
+			description:    "folder added to the workspace",
+			change:         func() { tmpDir.Chtimes(".", time.Now()) },
+			expectedListed: 4,
+		},
+		{
+			description:    "configuration changed",
+			change:         func() { artifact.DockerArtifact.Target = "stage" },
+			expectedListed: 5,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			test.change()
+
+			deps, err := cache.forArtifact(context.Background(), artifact)
+
+			testutil.CheckErrorAndDeepEqual(t, false, err, []string{tmpDir.Path("Dockerfile"), tmpDir.Path("src/main.go")}, deps)
+			testutil.CheckDeepEqual(t, test.expectedListed, listed)
+		})
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	configutil "github.com/GoogleContainerTools/skaffold/cmd/skaffold/app/cmd/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
+	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// dryRunDigest stands for the digest of images that are not built yet.
+const dryRunDigest = "sha256:unknown"
+
+// DryRun prints what a run, or the first cycle of a dev session if reuse is set,
+// would do: the images that would be built, with their tags and the reason
+// they would be built, then the manifests and the resources that would be applied.
+// Neither docker nor the cluster are called.
+func DryRun(ctx context.Context, out io.Writer, opts *config.SkaffoldOptions, cfg *latest.SkaffoldPipeline, reuse bool) error {
+	kubeContext, err := kubectx.CurrentContext()
+	if err != nil {
+		return errors.Wrap(err, "getting current cluster context")
+	}
+
+	defaultRepo, err := configutil.GetDefaultRepo(opts.DefaultRepo)
+	if err != nil {
+		return errors.Wrap(err, "getting default repo")
+	}
+
+	tagger, err := getTagger(cfg.Build.TagPolicy, opts.CustomTag)
+	if err != nil {
+		return errors.Wrap(err, "parsing tag config")
+	}
+
+	deployer, err := getDeployer(&cfg.Deploy, kubeContext, opts.Namespace, defaultRepo)
+	if err != nil {
+		return errors.Wrap(err, "parsing deploy config")
+	}
+
+	state := loadDevState(opts.ConfigurationFiles, kubeContext, opts.Namespace)
+	keys := artifactKeys(ctx, cfg.Build.Artifacts)
+
+	color.Default.Fprintln(out, "Images:")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	var builds []build.Artifact
+	for _, a := range cfg.Build.Artifacts {
+		previous, built := state.Artifacts[a.ImageName]
+		upToDate := built && keys[a.ImageName] != "" && previous.Key == keys[a.ImageName]
+
+		var imageTag string
+		if reuse && upToDate {
+			imageTag = previous.Tag
+		} else {
+			imageTag, err = tagger.GenerateFullyQualifiedImageName(a.Workspace, &tag.Options{
+				ImageName: a.ImageName,
+				Digest:    dryRunDigest,
+			})
+			if err != nil {
+				return errors.Wrapf(err, "generating tag for %s", a.ImageName)
+			}
+		}
+
+		fmt.Fprintf(w, " - %s\t%s\t%s\n", a.ImageName, imageTag, buildReason(keys[a.ImageName], previous, built, reuse))
+		builds = append(builds, build.Artifact{ImageName: a.ImageName, Tag: imageTag})
+	}
+	w.Flush()
+
+	renderer, ok := deployer.(deploy.Renderer)
+	if !ok {
+		return fmt.Errorf("%T can't be rendered", deployer)
+	}
+
+	var manifests bytes.Buffer
+	resources, err := renderer.Render(ctx, &manifests, builds)
+	if err != nil {
+		return errors.Wrap(err, "rendering manifests")
+	}
+
+	color.Default.Fprintln(out, "\nManifests:")
+	out.Write(manifests.Bytes())
+
+	defaultNamespace, err := kubectx.Namespace(opts.Namespace)
+	if err != nil {
+		return errors.Wrap(err, "getting namespace")
+	}
+
+	color.Default.Fprintln(out, "\nResources:")
+	for _, r := range resources {
+		if r.Obj == nil {
+			continue
+		}
+		accessor, err := meta.Accessor(*r.Obj)
+		if err != nil {
+			return errors.Wrap(err, "reading object metadata")
+		}
+		namespace := accessor.GetNamespace()
+		if namespace == "" {
+			namespace = r.Namespace
+		}
+		if namespace == "" {
+			namespace = defaultNamespace
+		}
+		kind := (*r.Obj).GetObjectKind().GroupVersionKind().Kind
+		if namespace == "" {
+			fmt.Fprintf(out, " - %s %s\n", kind, accessor.GetName())
+		} else {
+			fmt.Fprintf(out, " - %s %s/%s\n", kind, namespace, accessor.GetName())
+		}
+	}
+
+	return nil
+}
+
+// buildReason explains why an artifact would be built, or reused.
+func buildReason(key string, previous artifactState, built bool, reuse bool) string {
+	switch {
+	case key == "":
+		return "built: its sources couldn't be hashed"
+	case !built:
+		return "built: no previous build"
+	case previous.Key != key:
+		return "built: its sources or configuration changed since the last build"
+	case reuse:
+		return "reused from the last build, if the image still exists"
+	default:
+		return "built: unchanged since the last build but run always rebuilds"
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestBuildReason(t *testing.T) {
+	var tests = []struct {
+		description string
+		key         string
+		previous    artifactState
+		built       bool
+		reuse       bool
+		expected    string
+	}{
+		{
+			description: "sources can't be hashed",
+			built:       true,
+			previous:    artifactState{Key: "abc"},
+			expected:    "built: its sources couldn't be hashed",
+		},
+		{
+			description: "never built",
+			key:         "abc",
+			expected:    "built: no previous build",
+		},
+		{
+			description: "changed",
+			key:         "abc",
+			previous:    artifactState{Key: "def"},
+			built:       true,
+			reuse:       true,
+			expected:    "built: its sources or configuration changed since the last build",
+		},
+		{
+			description: "reused in dev",
+			key:         "abc",
+			previous:    artifactState{Key: "abc"},
+			built:       true,
+			reuse:       true,
+			expected:    "reused from the last build, if the image still exists",
+		},
+		{
+			description: "rebuilt by run",
+			key:         "abc",
+			previous:    artifactState{Key: "abc"},
+			built:       true,
+			expected:    "built: unchanged since the last build but run always rebuilds",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			reason := buildReason(test.key, test.previous, test.built, test.reuse)
+
+			testutil.CheckDeepEqual(t, test.expected, reason)
+		})
+	}
+}
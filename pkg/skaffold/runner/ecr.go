@@ -0,0 +1,62 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"io"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/aws"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+)
+
+// WithECRRepositories creates a builder that creates the missing ECR repositories
+// of the artifacts before building them, if the configuration asks for it.
+// Nothing is created if the images are not pushed.
+func WithECRRepositories(b build.Builder, cfg *latest.ECRConfig, defaultRepo string, pushImages bool) build.Builder {
+	if cfg == nil || !cfg.CreateRepositories || !pushImages {
+		return b
+	}
+
+	return builderWithECRRepositories{
+		Builder:      b,
+		repositories: aws.NewECRRepositories(cfg),
+		defaultRepo:  defaultRepo,
+	}
+}
+
+type builderWithECRRepositories struct {
+	build.Builder
+	repositories *aws.ECRRepositories
+	defaultRepo  string
+}
+
+func (b builderWithECRRepositories) Build(ctx context.Context, out io.Writer, tagger tag.Tagger, artifacts []*latest.Artifact) ([]build.Artifact, error) {
+	for _, artifact := range artifacts {
+		// The images are pushed to the default repo, if any.
+		imageName := util.SubstituteDefaultRepoIntoImage(b.defaultRepo, artifact.ImageName)
+		if err := b.repositories.Ensure(ctx, out, imageName); err != nil {
+			return nil, errors.Wrapf(err, "creating ECR repository for %s", imageName)
+		}
+	}
+
+	return b.Builder.Build(ctx, out, tagger, artifacts)
+}
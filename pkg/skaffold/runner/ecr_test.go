@@ -0,0 +1,87 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+// recordingAWS records the aws commands. All the repositories exist.
+type recordingAWS struct {
+	commands []string
+}
+
+func (r *recordingAWS) RunCmdOut(cmd *exec.Cmd) ([]byte, error) {
+	r.commands = append(r.commands, strings.Join(cmd.Args, " "))
+	return nil, nil
+}
+
+func (r *recordingAWS) RunCmd(cmd *exec.Cmd) error {
+	_, err := r.RunCmdOut(cmd)
+	return err
+}
+
+func TestWithECRRepositories(t *testing.T) {
+	var tests = []struct {
+		description      string
+		imageName        string
+		defaultRepo      string
+		pushImages       bool
+		expectedCommands []string
+	}{
+		{
+			description:      "ecr image",
+			imageName:        "123456789.dkr.ecr.us-east-1.amazonaws.com/app",
+			pushImages:       true,
+			expectedCommands: []string{"aws ecr describe-repositories --registry-id 123456789 --region us-east-1 --repository-names app"},
+		},
+		{
+			description:      "default repo",
+			imageName:        "app",
+			defaultRepo:      "123456789.dkr.ecr.eu-west-1.amazonaws.com/team",
+			pushImages:       true,
+			expectedCommands: []string{"aws ecr describe-repositories --registry-id 123456789 --region eu-west-1 --repository-names team/app"},
+		},
+		{
+			description: "images not pushed",
+			imageName:   "123456789.dkr.ecr.us-east-1.amazonaws.com/app",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			recorder := &recordingAWS{}
+			defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+			util.DefaultExecCommand = recorder
+
+			builder := WithECRRepositories(&TestBuilder{}, &latest.ECRConfig{CreateRepositories: true}, test.defaultRepo, test.pushImages)
+			_, err := builder.Build(context.Background(), ioutil.Discard, nil, []*latest.Artifact{
+				{ImageName: test.imageName},
+			})
+
+			testutil.CheckErrorAndDeepEqual(t, false, err, test.expectedCommands, recorder.commands)
+		})
+	}
+}
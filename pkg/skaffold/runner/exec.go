@@ -0,0 +1,109 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	configutil "github.com/GoogleContainerTools/skaffold/cmd/skaffold/app/cmd/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+	"k8s.io/api/core/v1"
+)
+
+// Exec runs a command, a shell by default, in a running container of an artifact.
+// The container is chosen among the pods the syncer copies files to: those running
+// the artifact's image. The image's tag doesn't matter unless this runner built it.
+func (r *SkaffoldRunner) Exec(ctx context.Context, in io.Reader, out io.Writer, imageName string, command []string) error {
+	defer r.pods.Stop()
+
+	matches, err := r.imageMatcher(imageName)
+	if err != nil {
+		return err
+	}
+
+	pods, err := r.pods.Pods()
+	if err != nil {
+		return errors.Wrap(err, "getting pods")
+	}
+
+	target, found := newestRunning(sync.Targets(pods, matches))
+	if !found {
+		return fmt.Errorf("no running container found for %s", imageName)
+	}
+
+	if len(command) == 0 {
+		command = []string{"sh"}
+	}
+
+	color.Default.Fprintf(out, "Running %v in %s/%s, container %s\n", command, target.Pod.Namespace, target.Pod.Name, target.Container.Name)
+
+	args := append(kubectx.KubectlArgs(), "exec", "-i", "-t", target.Pod.Name, "--namespace", target.Pod.Namespace, "-c", target.Container.Name, "--")
+	cmd := kubectx.KubectlCommand(ctx, append(args, command...)...)
+	cmd.Stdin = in
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	return util.RunCmd(cmd)
+}
+
+// imageMatcher matches the images of an artifact: the tag it was last built with
+// or, if this runner didn't build it, any tag of its repository.
+func (r *SkaffoldRunner) imageMatcher(imageName string) (func(string) bool, error) {
+	for _, b := range r.builds {
+		if b.ImageName == imageName {
+			tag := b.Tag
+			return func(image string) bool { return image == tag }, nil
+		}
+	}
+
+	defaultRepo, err := configutil.GetDefaultRepo(r.opts.DefaultRepo)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting default repo")
+	}
+	repo := util.SubstituteDefaultRepoIntoImage(defaultRepo, imageName)
+
+	return func(image string) bool {
+		ref, err := docker.ParseReference(image)
+		return err == nil && ref.BaseName == repo
+	}, nil
+}
+
+// newestRunning returns the running container of the most recent pod.
+func newestRunning(targets []sync.Target) (sync.Target, bool) {
+	var running []sync.Target
+	for _, t := range targets {
+		if t.Pod.Status.Phase == v1.PodRunning && t.Pod.DeletionTimestamp == nil {
+			running = append(running, t)
+		}
+	}
+	if len(running) == 0 {
+		return sync.Target{}, false
+	}
+
+	sort.SliceStable(running, func(i, j int) bool {
+		return running[j].Pod.CreationTimestamp.Before(&running[i].Pod.CreationTimestamp)
+	})
+	return running[0], true
+}
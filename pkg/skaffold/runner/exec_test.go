@@ -0,0 +1,100 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgo "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func pod(name, image string, phase v1.PodPhase, created time.Time) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", CreationTimestamp: metav1.NewTime(created)},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{Name: "sidecar", Image: "envoy:1.0"}, {Name: "app", Image: image}},
+		},
+		Status: v1.PodStatus{Phase: phase},
+	}
+}
+
+func TestExec(t *testing.T) {
+	now := time.Now()
+	pods := []runtime.Object{
+		pod("web-1", "gcr.io/project/web:v1", v1.PodRunning, now.Add(-time.Hour)),
+		pod("web-2", "gcr.io/project/web:v2", v1.PodRunning, now),
+		pod("web-3", "gcr.io/project/web:v3", v1.PodPending, now.Add(time.Minute)),
+		pod("other", "gcr.io/project/other:v1", v1.PodRunning, now),
+	}
+
+	var tests = []struct {
+		description string
+		image       string
+		builds      []build.Artifact
+		command     []string
+		expectedCmd string
+		shouldErr   bool
+	}{
+		{
+			description: "built by this runner",
+			image:       "gcr.io/project/web",
+			builds:      []build.Artifact{{ImageName: "gcr.io/project/web", Tag: "gcr.io/project/web:v1"}},
+			expectedCmd: "kubectl exec -i -t web-1 --namespace ns -c app -- sh",
+		},
+		{
+			description: "most recent running pod",
+			image:       "gcr.io/project/web",
+			command:     []string{"ls", "-l"},
+			expectedCmd: "kubectl exec -i -t web-2 --namespace ns -c app -- ls -l",
+		},
+		{
+			description: "no container",
+			image:       "gcr.io/project/unknown",
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			defer func(c func() (clientgo.Interface, error)) { kubernetes.Client = c }(kubernetes.Client)
+			kubernetes.Client = func() (clientgo.Interface, error) { return fake.NewSimpleClientset(pods...), nil }
+			defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+			util.DefaultExecCommand = testutil.NewFakeCmd(test.expectedCmd, nil)
+
+			r := &SkaffoldRunner{
+				opts:   &config.SkaffoldOptions{DefaultRepo: "gcr.io/project"},
+				builds: test.builds,
+				pods:   kubernetes.NewPodCache("ns", nil),
+			}
+			err := r.Exec(context.Background(), strings.NewReader(""), ioutil.Discard, test.image, test.command)
+
+			testutil.CheckError(t, test.shouldErr, err)
+		})
+	}
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"io"
+	"sync"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/watch"
+)
+
+// intents tracks what the dev loop is allowed to do, either
+// automatically on file changes or because it was requested
+// through the control API.
+type intents struct {
+	sync.Mutex
+
+	autoBuild  bool
+	autoSync   bool
+	autoDeploy bool
+
+	build  bool
+	sync   bool
+	deploy bool
+	reload bool
+
+	watchPaused bool
+
+	requests chan bool
+}
+
+func newIntents(opts *config.SkaffoldOptions) *intents {
+	return &intents{
+		autoBuild:  !opts.DisableAutoBuild,
+		autoSync:   !opts.DisableAutoSync,
+		autoDeploy: !opts.DisableAutoDeploy,
+		requests:   make(chan bool, 1),
+	}
+}
+
+// notify wakes the dev loop up without blocking if it's already been notified.
+func (i *intents) notify() {
+	select {
+	case i.requests <- true:
+	default:
+	}
+}
+
+func (i *intents) RequestBuild() {
+	i.Lock()
+	i.build = true
+	i.Unlock()
+	i.notify()
+}
+
+func (i *intents) RequestSync() {
+	i.Lock()
+	i.sync = true
+	i.Unlock()
+	i.notify()
+}
+
+func (i *intents) RequestDeploy() {
+	i.Lock()
+	i.deploy = true
+	i.Unlock()
+	i.notify()
+}
+
+func (i *intents) RequestReload() {
+	i.Lock()
+	i.reload = true
+	i.Unlock()
+	i.notify()
+}
+
+func (i *intents) SetAutoBuild(enabled bool) {
+	i.Lock()
+	i.autoBuild = enabled
+	i.Unlock()
+	i.notify()
+}
+
+func (i *intents) SetAutoSync(enabled bool) {
+	i.Lock()
+	i.autoSync = enabled
+	i.Unlock()
+	i.notify()
+}
+
+func (i *intents) SetAutoDeploy(enabled bool) {
+	i.Lock()
+	i.autoDeploy = enabled
+	i.Unlock()
+	i.notify()
+}
+
+func (i *intents) PauseWatch() {
+	i.Lock()
+	i.watchPaused = true
+	i.Unlock()
+}
+
+func (i *intents) ResumeWatch() {
+	i.Lock()
+	i.watchPaused = false
+	i.Unlock()
+}
+
+// toggleWatch pauses the watch or resumes it if it's paused.
+func (i *intents) toggleWatch() {
+	i.Lock()
+	i.watchPaused = !i.watchPaused
+	i.Unlock()
+}
+
+// isWatchPaused says if file changes are currently ignored.
+func (i *intents) isWatchPaused() bool {
+	i.Lock()
+	defer i.Unlock()
+
+	return i.watchPaused
+}
+
+// canBuild says if pending changes can be built and consumes
+// the build request, if any.
+func (i *intents) canBuild() bool {
+	i.Lock()
+	defer i.Unlock()
+
+	ok := i.autoBuild || i.build
+	i.build = false
+	return ok
+}
+
+// canSync says if pending changes can be synced and consumes
+// the sync request, if any.
+func (i *intents) canSync() bool {
+	i.Lock()
+	defer i.Unlock()
+
+	ok := i.autoSync || i.sync
+	i.sync = false
+	return ok
+}
+
+// canDeploy says if a deploy can happen and consumes
+// the deploy request, if any.
+func (i *intents) canDeploy() bool {
+	i.Lock()
+	defer i.Unlock()
+
+	ok := i.autoDeploy || i.deploy
+	i.deploy = false
+	return ok
+}
+
+// deployRequested says if a deploy was explicitly requested.
+func (i *intents) deployRequested() bool {
+	i.Lock()
+	defer i.Unlock()
+
+	return i.deploy
+}
+
+// reloadRequested consumes the reload request, if any.
+func (i *intents) reloadRequested() bool {
+	i.Lock()
+	defer i.Unlock()
+
+	reload := i.reload
+	i.reload = false
+	return reload
+}
+
+// intentTrigger wraps a Trigger so that requests made through the
+// control API wake the watcher up.
+type intentTrigger struct {
+	watch.Trigger
+
+	intents *intents
+	out     io.Writer
+}
+
+// Start starts the wrapped trigger and forwards its signals,
+// forcing a callback each time an intent is received.
+// The signals are dropped while the watch is paused, so that the files
+// are only compared again once it's resumed.
+func (t *intentTrigger) Start() (<-chan bool, func()) {
+	trigger := make(chan bool)
+	done := make(chan bool)
+
+	signals, stop := t.Trigger.Start()
+	go func() {
+		paused := false
+
+		for {
+			var force bool
+
+			select {
+			case <-done:
+				return
+			case force = <-signals:
+				if isPaused := t.intents.isWatchPaused(); isPaused != paused {
+					paused = isPaused
+					if paused {
+						color.Yellow.Fprintln(t.out, "Watching is paused. Changes will be handled when it resumes.")
+					} else {
+						color.Yellow.Fprintln(t.out, "Watching resumed")
+					}
+				}
+				if paused {
+					continue
+				}
+			case <-t.intents.requests:
+				force = true
+			}
+
+			select {
+			case <-done:
+				return
+			case trigger <- force:
+			}
+		}
+	}()
+
+	return trigger, func() {
+		close(done)
+		stop()
+	}
+}
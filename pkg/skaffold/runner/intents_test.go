@@ -0,0 +1,66 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+type fakeSignals struct {
+	signals chan bool
+}
+
+func (f *fakeSignals) Start() (<-chan bool, func()) { return f.signals, func() {} }
+func (f *fakeSignals) WatchForChanges(io.Writer)    {}
+func (f *fakeSignals) Debounce() bool               { return true }
+
+func TestPausedWatch(t *testing.T) {
+	intents := newIntents(&config.SkaffoldOptions{})
+	signals := &fakeSignals{signals: make(chan bool)}
+	var out bytes.Buffer
+
+	trigger := &intentTrigger{Trigger: signals, intents: intents, out: &out}
+	forwarded, stop := trigger.Start()
+	defer stop()
+
+	// The second signal can only be sent once the first one was dropped.
+	intents.PauseWatch()
+	signals.signals <- false
+	signals.signals <- false
+	intents.ResumeWatch()
+	go func() { signals.signals <- true }()
+
+	for force := false; !force; {
+		force = <-forwarded
+	}
+	testutil.CheckDeepEqual(t, "Watching is paused. Changes will be handled when it resumes.\nWatching resumed\n", out.String())
+}
+
+func TestToggleWatch(t *testing.T) {
+	intents := newIntents(&config.SkaffoldOptions{})
+
+	intents.toggleWatch()
+	testutil.CheckDeepEqual(t, true, intents.isWatchPaused())
+
+	intents.toggleWatch()
+	testutil.CheckDeepEqual(t, false, intents.isWatchPaused())
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"io"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/intercept"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
+)
+
+// interceptedArtifacts returns the artifacts chosen with --intercept to run as local processes.
+func (r *SkaffoldRunner) interceptedArtifacts(artifacts []*latest.Artifact) ([]*latest.Artifact, error) {
+	var intercepted []*latest.Artifact
+	for _, expression := range r.opts.Intercept {
+		matched := false
+		for _, artifact := range artifacts {
+			match, err := matchesImage(artifact.ImageName, expression)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid intercept expression %s", expression)
+			}
+			if !match {
+				continue
+			}
+			if artifact.Intercept == nil {
+				return nil, errors.Errorf("%s has no intercept configuration", artifact.ImageName)
+			}
+
+			matched = true
+			intercepted = append(intercepted, artifact)
+		}
+		if !matched {
+			return nil, errors.Errorf("no artifact matches intercept expression %s", expression)
+		}
+	}
+
+	return intercepted, nil
+}
+
+// isIntercepted tells if an artifact runs as a local process.
+func (r *SkaffoldRunner) isIntercepted(artifact *latest.Artifact) bool {
+	for _, expression := range r.opts.Intercept {
+		if match, _ := matchesImage(artifact.ImageName, expression); match {
+			return true
+		}
+	}
+	return false
+}
+
+// startIntercepts sends the traffic of the intercepted artifacts' workloads
+// to local processes. The returned function stops them.
+func (r *SkaffoldRunner) startIntercepts(out io.Writer, artifacts []*latest.Artifact) (func(), error) {
+	var sessions []*intercept.Session
+	stop := func() {
+		for _, s := range sessions {
+			s.Stop()
+		}
+	}
+
+	for _, artifact := range artifacts {
+		s, err := intercept.Start(out, r.opts.Namespace, artifact)
+		if err != nil {
+			stop()
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+
+	return stop, nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestInterceptedArtifacts(t *testing.T) {
+	artifacts := []*latest.Artifact{
+		{ImageName: "domain/web", Intercept: &latest.InterceptConfig{Workload: "web", Command: []string{"npm", "start"}}},
+		{ImageName: "domain/api"},
+	}
+
+	var tests = []struct {
+		description string
+		intercept   []string
+		shouldErr   bool
+		expected    []string
+	}{
+		{
+			description: "none",
+		},
+		{
+			description: "by short name",
+			intercept:   []string{"web"},
+			expected:    []string{"domain/web"},
+		},
+		{
+			description: "no intercept configuration",
+			intercept:   []string{"domain/api"},
+			shouldErr:   true,
+		},
+		{
+			description: "no match",
+			intercept:   []string{"other"},
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			runner := &SkaffoldRunner{opts: &config.SkaffoldOptions{Intercept: test.intercept}}
+
+			intercepted, err := runner.interceptedArtifacts(artifacts)
+
+			var imageNames []string
+			for _, artifact := range intercepted {
+				imageNames = append(imageNames, artifact.ImageName)
+			}
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, imageNames)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// pauseKey pauses or resumes the watch when it's typed in the terminal.
+const pauseKey = "p"
+
+// terminalKeys reads what's typed in the terminal during dev mode, line by line.
+// The pause key pauses or resumes the watch. The other lines answer prompts.
+type terminalKeys struct {
+	answers chan string
+}
+
+func listenToKeys(in io.Reader, intents *intents) *terminalKeys {
+	keys := &terminalKeys{answers: make(chan string)}
+
+	go func() {
+		scanner := bufio.NewScanner(in)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if strings.EqualFold(line, pauseKey) {
+				intents.toggleWatch()
+				continue
+			}
+
+			select {
+			case keys.answers <- line:
+			default:
+				// Nothing is being asked.
+			}
+		}
+	}()
+
+	return keys
+}
+
+// Read blocks until a line that's not a key binding is typed.
+func (k *terminalKeys) Read(p []byte) (int, error) {
+	return copy(p, <-k.answers+"\n"), nil
+}
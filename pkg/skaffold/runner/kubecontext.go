@@ -0,0 +1,49 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"io"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
+	"github.com/pkg/errors"
+)
+
+// for testing
+var (
+	checkKubeConfig = kubectx.CheckKubeConfig
+	currentContext  = kubectx.CurrentContext
+)
+
+// checkKubeContext stops dev mode when the cluster it deploys to was replaced
+// in the kubeconfig. When another context becomes current, the session keeps
+// deploying to the context it started with and says so once.
+func (r *SkaffoldRunner) checkKubeContext(out io.Writer) error {
+	other, err := checkKubeConfig()
+	if err != nil {
+		return errors.Wrap(err, "exiting dev mode because the cluster changed")
+	}
+
+	if other != "" && other != r.otherContext {
+		used, _ := currentContext()
+		color.Yellow.Fprintf(out, "The current kube-context is now %s. This session keeps deploying to %s: restart skaffold dev to switch.\n", other, used)
+	}
+	r.otherContext = other
+
+	return nil
+}
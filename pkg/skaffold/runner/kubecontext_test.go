@@ -0,0 +1,49 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"bytes"
+	"testing"
+
+	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestCheckKubeContext(t *testing.T) {
+	defer func(c func() (string, error), f func() (string, error)) { checkKubeConfig, currentContext = c, f }(checkKubeConfig, currentContext)
+	currentContext = func() (string, error) { return "dev", nil }
+
+	var out bytes.Buffer
+	runner := &SkaffoldRunner{}
+
+	for _, other := range []string{"", "prod", "prod", "", "staging"} {
+		checkKubeConfig = func() (string, error) { return other, nil }
+		testutil.CheckError(t, false, runner.checkKubeContext(&out))
+	}
+
+	testutil.CheckDeepEqual(t, `The current kube-context is now prod. This session keeps deploying to dev: restart skaffold dev to switch.
+The current kube-context is now staging. This session keeps deploying to dev: restart skaffold dev to switch.
+`, out.String())
+
+	checkKubeConfig = func() (string, error) {
+		return "", &kubectx.ClusterChangedError{Context: "dev", Previous: "https://127.0.0.1:6443", Server: "https://127.0.0.1:34567"}
+	}
+	err := runner.checkKubeContext(&out)
+
+	testutil.CheckErrorAndDeepEqual(t, true, err, `exiting dev mode because the cluster changed: kubernetes context "dev" now points at https://127.0.0.1:34567 instead of https://127.0.0.1:6443`, err.Error())
+}
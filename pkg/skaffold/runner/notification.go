@@ -18,36 +18,71 @@ package runner
 
 import (
 	"context"
-	"fmt"
 	"io"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/notify"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/sirupsen/logrus"
 )
 
-const (
-	// terminalBell is the sequence that triggers a beep in the terminal
-	terminalBell = "\007"
-)
-
-// WithNotification creates a deployer that bips each time a deploy is done.
-func WithNotification(d deploy.Deployer) deploy.Deployer {
-	return withNotification{
-		Deployer: d,
+// WithNotification creates a builder and a deployer that send
+// notifications each time a build or a deploy ends.
+func WithNotification(b build.Builder, d deploy.Deployer, notifiers []notify.Notifier) (build.Builder, deploy.Deployer) {
+	if len(notifiers) == 0 {
+		return b, d
 	}
+
+	return withBuildNotification{Builder: b, notifiers: notifiers}, withDeployNotification{Deployer: d, notifiers: notifiers}
+}
+
+type withBuildNotification struct {
+	build.Builder
+	notifiers []notify.Notifier
 }
 
-type withNotification struct {
+type withDeployNotification struct {
 	deploy.Deployer
+	notifiers []notify.Notifier
 }
 
-func (w withNotification) Deploy(ctx context.Context, out io.Writer, builds []build.Artifact) ([]deploy.Artifact, error) {
-	res, err := w.Deployer.Deploy(ctx, out, builds)
+func (w withBuildNotification) Build(ctx context.Context, out io.Writer, tagger tag.Tagger, artifacts []*latest.Artifact) ([]build.Artifact, error) {
+	bRes, err := w.Builder.Build(ctx, out, tagger, artifacts)
 	if err != nil {
+		sendNotification(ctx, out, w.notifiers, notify.Notification{Name: notify.BuildFailed, Error: err.Error()})
 		return nil, err
 	}
 
-	fmt.Fprint(out, terminalBell)
+	sendNotification(ctx, out, w.notifiers, notify.Notification{Name: notify.BuildSucceeded, Images: tags(bRes)})
+	return bRes, nil
+}
+
+func (w withDeployNotification) Deploy(ctx context.Context, out io.Writer, builds []build.Artifact) ([]deploy.Artifact, error) {
+	res, err := w.Deployer.Deploy(ctx, out, builds)
+	if err != nil {
+		sendNotification(ctx, out, w.notifiers, notify.Notification{Name: notify.DeployFailed, Error: err.Error()})
+		return nil, err
+	}
 
+	sendNotification(ctx, out, w.notifiers, notify.Notification{Name: notify.DeploySucceeded, Images: tags(builds)})
 	return res, nil
 }
+
+// sendNotification notifies all the notifiers. Failing to notify doesn't fail the pipeline.
+func sendNotification(ctx context.Context, out io.Writer, notifiers []notify.Notifier, n notify.Notification) {
+	for _, notifier := range notifiers {
+		if err := notifier.Notify(ctx, out, n); err != nil {
+			logrus.Warnln("Unable to send notification:", err)
+		}
+	}
+}
+
+func tags(builds []build.Artifact) []string {
+	var tags []string
+	for _, b := range builds {
+		tags = append(tags, b.Tag)
+	}
+	return tags
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
+)
+
+// checkPermissions makes sure, before anything is built or deployed,
+// that the current user is allowed to do what the pipeline needs on the cluster.
+func checkPermissions(cfg *latest.SkaffoldPipeline, namespace string) error {
+	namespace, err := kubectx.Namespace(namespace)
+	if err != nil {
+		return err
+	}
+
+	permissions := requiredPermissions(cfg, namespace)
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	client, err := kubernetes.GetClientset()
+	if err != nil {
+		return errors.Wrap(err, "getting kubernetes client")
+	}
+
+	return kubernetes.CheckPermissions(client, permissions)
+}
+
+// requiredPermissions lists the permissions needed by the builders, deployers,
+// file sync and verification containers used by a pipeline.
+func requiredPermissions(cfg *latest.SkaffoldPipeline, namespace string) []kubernetes.Permission {
+	var permissions []kubernetes.Permission
+	add := func(verb, group, resource, subresource, namespace, reason string) {
+		permissions = append(permissions, kubernetes.Permission{
+			Verb:        verb,
+			Group:       group,
+			Resource:    resource,
+			Subresource: subresource,
+			Namespace:   namespace,
+			Reason:      reason,
+		})
+	}
+
+	if kaniko := cfg.Build.KanikoBuild; kaniko != nil {
+		add("create", "", "pods", "", kaniko.Namespace, "run kaniko builds")
+		add("delete", "", "pods", "", kaniko.Namespace, "clean up kaniko builds")
+		add("get", "", "secrets", "", kaniko.Namespace, "read the kaniko pull secret")
+		if kaniko.BuildContext != nil && kaniko.BuildContext.LocalDir != nil {
+			add("create", "", "pods", "exec", kaniko.Namespace, "copy the build context into kaniko pods")
+		}
+	}
+
+	if cfg.Deploy.KubectlDeploy != nil || cfg.Deploy.KustomizeDeploy != nil {
+		add("create", "apps", "deployments", "", namespace, "apply the manifests")
+		add("patch", "apps", "deployments", "", namespace, "apply the manifests")
+	}
+
+	for _, artifact := range cfg.Build.Artifacts {
+		if len(artifact.Sync) > 0 {
+			add("create", "", "pods", "exec", namespace, "sync files into running containers")
+			break
+		}
+	}
+
+	for _, verify := range cfg.Verify {
+		if verify.Container != nil {
+			add("create", "", "pods", "", namespace, "run verification containers")
+			break
+		}
+	}
+
+	return permissions
+}
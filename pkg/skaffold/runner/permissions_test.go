@@ -0,0 +1,85 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestRequiredPermissions(t *testing.T) {
+	var tests = []struct {
+		description string
+		cfg         *latest.SkaffoldPipeline
+		expected    []string
+	}{
+		{
+			description: "local build and helm",
+			cfg: &latest.SkaffoldPipeline{
+				Build:  latest.BuildConfig{BuildType: latest.BuildType{LocalBuild: &latest.LocalBuild{}}},
+				Deploy: latest.DeployConfig{DeployType: latest.DeployType{HelmDeploy: &latest.HelmDeploy{}}},
+			},
+		},
+		{
+			description: "kaniko with local dir and kubectl",
+			cfg: &latest.SkaffoldPipeline{
+				Build: latest.BuildConfig{BuildType: latest.BuildType{KanikoBuild: &latest.KanikoBuild{
+					Namespace:    "builds",
+					BuildContext: &latest.KanikoBuildContext{LocalDir: &latest.LocalDir{}},
+				}}},
+				Deploy: latest.DeployConfig{DeployType: latest.DeployType{KubectlDeploy: &latest.KubectlDeploy{}}},
+			},
+			expected: []string{
+				"create pods in namespace builds",
+				"delete pods in namespace builds",
+				"get secrets in namespace builds",
+				"create pods/exec in namespace builds",
+				"create deployments.apps in namespace app",
+				"patch deployments.apps in namespace app",
+			},
+		},
+		{
+			description: "sync and verify",
+			cfg: &latest.SkaffoldPipeline{
+				Build: latest.BuildConfig{
+					Artifacts: []*latest.Artifact{
+						{Sync: map[string]string{"*.js": "."}},
+						{Sync: map[string]string{"*.css": "."}},
+					},
+				},
+				Verify: latest.VerifyConfig{{Container: &latest.VerifyContainer{}}},
+			},
+			expected: []string{
+				"create pods/exec in namespace app",
+				"create pods in namespace app",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			var permissions []string
+			for _, p := range requiredPermissions(test.cfg, "app") {
+				permissions = append(permissions, p.String())
+			}
+
+			testutil.CheckDeepEqual(t, test.expected, permissions)
+		})
+	}
+}
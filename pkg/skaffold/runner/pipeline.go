@@ -0,0 +1,95 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"sync"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+)
+
+// deployQueue runs deploys in the background so that the dev loop can build
+// the next changes while the previous deploy and status check are still running.
+// Builds that complete during a deploy are deployed together, in the next batch.
+type deployQueue struct {
+	sync.Mutex
+
+	deploy  func([]build.Artifact)
+	running bool
+	pending bool
+	next    []build.Artifact
+	idle    *sync.Cond
+}
+
+func newDeployQueue(deploy func([]build.Artifact)) *deployQueue {
+	q := &deployQueue{
+		deploy: deploy,
+	}
+	q.idle = sync.NewCond(q)
+	return q
+}
+
+// schedule asks for the given builds to be deployed. If a deploy is already
+// running, they'll be deployed once it's done, replacing any batch not yet started.
+func (q *deployQueue) schedule(builds []build.Artifact) {
+	q.Lock()
+	defer q.Unlock()
+
+	q.next = builds
+	if q.running {
+		q.pending = true
+		return
+	}
+
+	q.running = true
+	go q.run()
+}
+
+func (q *deployQueue) run() {
+	q.Lock()
+	for {
+		builds := q.next
+		q.next = nil
+		q.Unlock()
+
+		q.deploy(builds)
+
+		q.Lock()
+		if !q.pending {
+			break
+		}
+		q.pending = false
+	}
+
+	q.running = false
+	q.idle.Broadcast()
+	q.Unlock()
+}
+
+// wait blocks until all the scheduled deploys are done.
+func (q *deployQueue) wait() {
+	if q == nil {
+		return
+	}
+
+	q.Lock()
+	defer q.Unlock()
+
+	for q.running {
+		q.idle.Wait()
+	}
+}
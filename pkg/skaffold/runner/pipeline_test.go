@@ -0,0 +1,97 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/watch"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestDeployQueue(t *testing.T) {
+	started := make(chan bool)
+	release := make(chan bool)
+
+	var deployed [][]build.Artifact
+	queue := newDeployQueue(func(builds []build.Artifact) {
+		started <- true
+		<-release
+		deployed = append(deployed, builds)
+	})
+
+	queue.schedule([]build.Artifact{{ImageName: "image1", Tag: "v1"}})
+	<-started
+
+	// Builds completed while a deploy is running are batched together.
+	queue.schedule([]build.Artifact{{ImageName: "image1", Tag: "v2"}})
+	queue.schedule([]build.Artifact{{ImageName: "image1", Tag: "v3"}})
+
+	release <- true
+	<-started
+	release <- true
+	queue.wait()
+
+	testutil.CheckDeepEqual(t, [][]build.Artifact{
+		{{ImageName: "image1", Tag: "v1"}},
+		{{ImageName: "image1", Tag: "v3"}},
+	}, deployed)
+}
+
+func TestPipelinedDev(t *testing.T) {
+	kubernetes.Client = fakeGetClient
+	defer resetClient()
+
+	opts := &config.SkaffoldOptions{
+		Trigger:     "polling",
+		PipelineDev: true,
+	}
+	builder := &TestBuilder{}
+	deployer := &TestDeployer{}
+	trigger, _ := watch.NewTrigger(opts)
+	artifacts := []*latest.Artifact{
+		{ImageName: "image1"},
+		{ImageName: "image2"},
+	}
+
+	runner := &SkaffoldRunner{
+		Builder:      builder,
+		Tester:       &TestTester{},
+		Deployer:     deployer,
+		Trigger:      trigger,
+		opts:         opts,
+		Syncer:       NewTestSyncer(),
+		pods:         kubernetes.NewPodCache("", nil),
+		watchFactory: NewWatcherFactory(nil, nil, []int{1}),
+	}
+
+	_, err := runner.Dev(context.Background(), ioutil.Discard, artifacts)
+
+	testutil.CheckError(t, false, err)
+	if len(builder.built) != 1 {
+		t.Errorf("Expected 1 artifact to be rebuilt. Got %d", len(builder.built))
+	}
+	if len(deployer.deployed) != 2 {
+		t.Errorf("Expected 2 artifacts to be deployed. Got %d", len(deployer.deployed))
+	}
+}
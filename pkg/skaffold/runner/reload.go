@@ -0,0 +1,228 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync/kubectl"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh/terminal"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// What dev mode does when the skaffold configuration changes.
+const (
+	// ConfigChangePrompt asks the user, or reloads if nobody can answer.
+	ConfigChangePrompt = "prompt"
+	// ConfigChangeReload reloads the pipeline.
+	ConfigChangeReload = "reload"
+	// ConfigChangeIgnore keeps the current pipeline.
+	ConfigChangeIgnore = "ignore"
+	// ConfigChangeExit stops dev mode.
+	ConfigChangeExit = "exit"
+)
+
+// for testing
+var (
+	newForConfig              = NewForConfig
+	stdin           io.Reader = os.Stdin
+	stdinIsTerminal           = func() bool {
+		return terminal.IsTerminal(int(os.Stdin.Fd()))
+	}
+)
+
+// CheckConfigChange validates the value of --on-config-change.
+func CheckConfigChange(action string) error {
+	switch action {
+	case "", ConfigChangePrompt, ConfigChangeReload, ConfigChangeIgnore, ConfigChangeExit:
+		return nil
+	default:
+		return fmt.Errorf("unknown action %q on config change, expected %s, %s, %s or %s", action, ConfigChangePrompt, ConfigChangeReload, ConfigChangeIgnore, ConfigChangeExit)
+	}
+}
+
+// onConfigChange tells what to do when the skaffold configuration changes.
+// The user is only asked if stdin is a terminal that's not read by the manual trigger.
+func (r *SkaffoldRunner) onConfigChange(out io.Writer) string {
+	action := r.opts.ConfigChange
+	if action != "" && action != ConfigChangePrompt {
+		return action
+	}
+
+	if r.opts.Trigger == "manual" || !stdinIsTerminal() {
+		return ConfigChangeReload
+	}
+	if r.keys != nil {
+		return promptConfigChange(out, r.keys)
+	}
+	return promptConfigChange(out, stdin)
+}
+
+func promptConfigChange(out io.Writer, in io.Reader) string {
+	color.Yellow.Fprint(out, "Configuration changed. [r]eload, [i]gnore or [e]xit? [r] ")
+
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "i", ConfigChangeIgnore:
+		return ConfigChangeIgnore
+	case "e", ConfigChangeExit:
+		return ConfigChangeExit
+	default:
+		return ConfigChangeReload
+	}
+}
+
+// reload reads the skaffold configuration again and replaces the builder, tester,
+// deployer, tagger, syncer, timeouts, pruner, platform checker and push setting in place.
+// The trigger, the control API, the logger, the port-forwards and the previous
+// builds are kept. So is the pod cache: it selects the pods by run ID, whatever
+// the configuration, and the logger and the port-forwards watch it.
+// It returns the new list of artifacts along with those that need to be rebuilt.
+func (r *SkaffoldRunner) reload(previous []*latest.Artifact) ([]*latest.Artifact, []*latest.Artifact, error) {
+	cfg, err := r.LoadConfig()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "loading configuration")
+	}
+
+	reloaded, err := newForConfig(r.opts, cfg)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "creating runner")
+	}
+
+	artifacts := cfg.Build.Artifacts
+	needsRebuild := artifacts
+	if r.config != nil && sameBuildSettings(r.config.Build, cfg.Build) {
+		needsRebuild = changedArtifacts(previous, artifacts)
+	}
+
+	r.Builder = reloaded.Builder
+	r.Tester = reloaded.Tester
+	r.Deployer = reloaded.Deployer
+	r.Tagger = reloaded.Tagger
+	r.Syncer = reloaded.Syncer
+	if syncer, ok := r.Syncer.(*kubectl.Syncer); ok {
+		syncer.Pods = r.pods
+	}
+	reloaded.pods.Stop()
+	r.timeouts = reloaded.timeouts
+	r.pruner = reloaded.pruner
+	r.platforms = reloaded.platforms
+	r.pushImages = reloaded.pushImages
+	r.config = cfg
+	r.builds = keepBuilds(r.builds, artifacts)
+
+	return artifacts, needsRebuild, nil
+}
+
+// redeployAfterReload builds the artifacts that changed with the configuration
+// and redeploys everything. It returns false if any step failed.
+func (r *SkaffoldRunner) redeployAfterReload(ctx context.Context, out io.Writer, imageList *kubernetes.ImageList, needsRebuild []*latest.Artifact) bool {
+	if len(needsRebuild) > 0 {
+		bRes, err := r.buildWithState(ctx, out, needsRebuild, true)
+		if err != nil {
+			build.PrintFailure(out, err)
+			logrus.Warnln("Skipping Deploy due to build error:", err)
+			return false
+		}
+		r.updateBuiltImages(imageList, bRes)
+	}
+
+	if err := r.Test(ctx, out, r.builds); err != nil {
+		logrus.Warnln("Skipping Deploy due to failed tests:", err)
+		return false
+	}
+
+	if err := r.DeployAndCheck(ctx, out, r.builds); err != nil {
+		logrus.Warnln("Skipping Deploy due to error:", err)
+		return false
+	}
+	r.saveState(r.builds)
+
+	if err := r.Verify(ctx, out, r.builds); err != nil {
+		logrus.Warnln("Verification failed:", err)
+	}
+
+	return true
+}
+
+// sameBuildSettings says if two build configurations only differ by their artifacts.
+// Any other change, to the builder or the tag policy for example, invalidates all the builds.
+func sameBuildSettings(previous, current latest.BuildConfig) bool {
+	previous.Artifacts = nil
+	current.Artifacts = nil
+
+	return sameYaml(previous, current)
+}
+
+// changedArtifacts lists the artifacts that are new or whose configuration changed.
+func changedArtifacts(previous, current []*latest.Artifact) []*latest.Artifact {
+	byName := map[string]*latest.Artifact{}
+	for _, a := range previous {
+		byName[a.ImageName] = a
+	}
+
+	var changed []*latest.Artifact
+	for _, a := range current {
+		if old, found := byName[a.ImageName]; !found || !sameYaml(old, a) {
+			changed = append(changed, a)
+		}
+	}
+
+	return changed
+}
+
+// keepBuilds drops the builds of artifacts that were removed from the configuration.
+func keepBuilds(builds []build.Artifact, artifacts []*latest.Artifact) []build.Artifact {
+	valid := map[string]bool{}
+	for _, a := range artifacts {
+		valid[a.ImageName] = true
+	}
+
+	var kept []build.Artifact
+	for _, b := range builds {
+		if valid[b.ImageName] {
+			kept = append(kept, b)
+		}
+	}
+
+	return kept
+}
+
+func sameYaml(a, b interface{}) bool {
+	aBytes, err := yaml.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bBytes, err := yaml.Marshal(b)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(aBytes, bBytes)
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/watch"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestChangedArtifacts(t *testing.T) {
+	previous := []*latest.Artifact{
+		{ImageName: "unchanged", Workspace: "a"},
+		{ImageName: "changed", Workspace: "b"},
+		{ImageName: "removed"},
+	}
+	current := []*latest.Artifact{
+		{ImageName: "unchanged", Workspace: "a"},
+		{ImageName: "changed", Workspace: "c"},
+		{ImageName: "added"},
+	}
+
+	changed := changedArtifacts(previous, current)
+
+	testutil.CheckDeepEqual(t, []*latest.Artifact{current[1], current[2]}, changed)
+}
+
+func TestSameBuildSettings(t *testing.T) {
+	previous := latest.BuildConfig{
+		Artifacts: []*latest.Artifact{{ImageName: "image1"}},
+		TagPolicy: latest.TagPolicy{ShaTagger: &latest.ShaTagger{}},
+	}
+	otherArtifacts := latest.BuildConfig{
+		Artifacts: []*latest.Artifact{{ImageName: "image2"}},
+		TagPolicy: latest.TagPolicy{ShaTagger: &latest.ShaTagger{}},
+	}
+	otherTagger := latest.BuildConfig{
+		Artifacts: []*latest.Artifact{{ImageName: "image1"}},
+		TagPolicy: latest.TagPolicy{GitTagger: &latest.GitTagger{}},
+	}
+
+	testutil.CheckDeepEqual(t, true, sameBuildSettings(previous, otherArtifacts))
+	testutil.CheckDeepEqual(t, false, sameBuildSettings(previous, otherTagger))
+}
+
+func TestDevReloadsConfiguration(t *testing.T) {
+	kubernetes.Client = fakeGetClient
+	defer resetClient()
+
+	opts := &config.SkaffoldOptions{
+		Trigger: "polling",
+	}
+	trigger, _ := watch.NewTrigger(opts)
+	initial := &latest.SkaffoldPipeline{
+		Build: latest.BuildConfig{
+			Artifacts: []*latest.Artifact{
+				{ImageName: "image1", Workspace: "a"},
+				{ImageName: "image2", Workspace: "b"},
+			},
+		},
+	}
+	reloaded := &latest.SkaffoldPipeline{
+		Build: latest.BuildConfig{
+			Artifacts: []*latest.Artifact{
+				{ImageName: "image1", Workspace: "a"},
+				{ImageName: "image2", Workspace: "c"},
+			},
+		},
+	}
+
+	builder := &TestBuilder{}
+	deployer := &TestDeployer{}
+	syncer := NewTestSyncer()
+	original := newForConfig
+	defer func() { newForConfig = original }()
+	newForConfig = func(*config.SkaffoldOptions, *latest.SkaffoldPipeline) (*SkaffoldRunner, error) {
+		return &SkaffoldRunner{
+			Builder:  builder,
+			Tester:   &TestTester{},
+			Deployer: deployer,
+			Syncer:   syncer,
+			pods:     kubernetes.NewPodCache("", nil),
+		}, nil
+	}
+
+	// The first watcher detects a change to skaffold.yaml, the second one stops.
+	watchers := 0
+	runner := &SkaffoldRunner{
+		Builder:  &TestBuilder{},
+		Tester:   &TestTester{},
+		Deployer: &TestDeployer{},
+		Trigger:  trigger,
+		Syncer:   NewTestSyncer(),
+		pods:     kubernetes.NewPodCache("", nil),
+		LoadConfig: func() (*latest.SkaffoldPipeline, error) {
+			return reloaded, nil
+		},
+		config: initial,
+		opts:   opts,
+		watchFactory: func() watch.Watcher {
+			watchers++
+			if watchers == 1 {
+				return &TestWatcher{err: ErrorConfigurationChanged}
+			}
+			return &TestWatcher{}
+		},
+	}
+
+	_, err := runner.Dev(context.Background(), ioutil.Discard, initial.Build.Artifacts)
+
+	testutil.CheckError(t, false, err)
+	testutil.CheckDeepEqual(t, 2, watchers)
+	if len(builder.built) != 1 || builder.built[0].ImageName != "image2" {
+		t.Errorf("Expected only image2 to be rebuilt. Got %+v", builder.built)
+	}
+	if len(deployer.deployed) != 2 {
+		t.Errorf("Expected 2 artifacts to be deployed. Got %d", len(deployer.deployed))
+	}
+	if runner.Syncer != syncer {
+		t.Error("Expected the syncer to be replaced")
+	}
+}
+
+func TestOnConfigChange(t *testing.T) {
+	defer func(s func() bool, r io.Reader) { stdinIsTerminal, stdin = s, r }(stdinIsTerminal, stdin)
+
+	var tests = []struct {
+		description string
+		action      string
+		trigger     string
+		terminal    bool
+		answer      string
+		expected    string
+	}{
+		{description: "flag", action: "exit", terminal: true, expected: ConfigChangeExit},
+		{description: "prompt reload", action: "prompt", terminal: true, answer: "\n", expected: ConfigChangeReload},
+		{description: "prompt ignore", action: "prompt", terminal: true, answer: "i\n", expected: ConfigChangeIgnore},
+		{description: "prompt exit", action: "", terminal: true, answer: "Exit\n", expected: ConfigChangeExit},
+		{description: "no terminal", action: "prompt", terminal: false, answer: "e\n", expected: ConfigChangeReload},
+		{description: "manual trigger", action: "prompt", trigger: "manual", terminal: true, answer: "e\n", expected: ConfigChangeReload},
+		{description: "no answer", action: "prompt", terminal: true, expected: ConfigChangeReload},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			stdinIsTerminal = func() bool { return test.terminal }
+			stdin = strings.NewReader(test.answer)
+			runner := &SkaffoldRunner{
+				opts: &config.SkaffoldOptions{ConfigChange: test.action, Trigger: test.trigger},
+			}
+
+			testutil.CheckDeepEqual(t, test.expected, runner.onConfigChange(ioutil.Discard))
+		})
+	}
+}
+
+func TestCheckConfigChange(t *testing.T) {
+	testutil.CheckError(t, false, CheckConfigChange("ignore"))
+	testutil.CheckError(t, true, CheckConfigChange("restart"))
+}
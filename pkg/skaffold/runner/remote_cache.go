@@ -0,0 +1,230 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	cstorage "cloud.google.com/go/storage"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/gcp"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// remoteCache shares the images built from given sources, identified by
+// their artifact key, between machines such as CI workers and teammates.
+type remoteCache interface {
+	// lookup returns the image built for a key, or an empty string.
+	lookup(ctx context.Context, imageName, key string) (string, error)
+	// store records the image built for a key.
+	store(ctx context.Context, imageName, key, image string) error
+}
+
+// newRemoteCache creates a cache backed either by the registry the images are
+// pushed to, or by a GCS bucket.
+func newRemoteCache(location, defaultRepo string) (remoteCache, error) {
+	switch {
+	case location == "registry":
+		return registryCache{defaultRepo: defaultRepo}, nil
+	case strings.HasPrefix(location, "gs://"):
+		bucket, prefix := splitBucketPath(strings.TrimPrefix(location, "gs://"))
+		if bucket == "" {
+			return nil, fmt.Errorf("invalid build cache %s: missing bucket", location)
+		}
+		return gcsCache{bucket: bucket, prefix: prefix}, nil
+	default:
+		return nil, fmt.Errorf("unsupported build cache %s, expected 'registry' or gs://bucket/path", location)
+	}
+}
+
+func splitBucketPath(location string) (string, string) {
+	parts := strings.SplitN(location, "/", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], strings.Trim(parts[1], "/")
+}
+
+// registryCache tags the images with their key, next to the other tags,
+// in the repository they're pushed to.
+type registryCache struct {
+	defaultRepo string
+}
+
+func cacheTag(repo, key string) string {
+	return fmt.Sprintf("%s:skaffold-cache-%s", repo, key)
+}
+
+// lookupRepo is the repository an image would be pushed to.
+func (c registryCache) lookupRepo(imageName string) string {
+	return util.SubstituteDefaultRepoIntoImage(c.defaultRepo, imageName)
+}
+
+// storeRepo is the repository a built image was pushed to.
+func (c registryCache) storeRepo(imageName, image string) string {
+	ref, err := docker.ParseReference(image)
+	if err != nil {
+		return c.lookupRepo(imageName)
+	}
+	return ref.BaseName
+}
+
+func (c registryCache) lookup(ctx context.Context, imageName, key string) (string, error) {
+	repo := c.lookupRepo(imageName)
+	digest, err := docker.RemoteDigest(cacheTag(repo, key))
+	if err != nil {
+		logrus.Debugf("No cached image for %s: %s", imageName, err)
+		return "", nil
+	}
+	return repo + "@" + digest, nil
+}
+
+func (c registryCache) store(ctx context.Context, imageName, key, image string) error {
+	return docker.AddTag(image, cacheTag(c.storeRepo(imageName, image), key))
+}
+
+// gcsCache writes the name of the images to gs://bucket/prefix/<image name>/<key>.
+type gcsCache struct {
+	bucket string
+	prefix string
+}
+
+func (c gcsCache) object(client *cstorage.Client, imageName, key string) *cstorage.ObjectHandle {
+	return client.Bucket(c.bucket).Object(path.Join(c.prefix, imageName, key))
+}
+
+func (c gcsCache) lookup(ctx context.Context, imageName, key string) (string, error) {
+	client, err := gcp.StorageClient()
+	if err != nil {
+		return "", err
+	}
+
+	r, err := c.object(client, imageName, key).NewReader(ctx)
+	if err == cstorage.ErrObjectNotExist {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "reading build cache")
+	}
+	defer r.Close()
+
+	buf, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", errors.Wrap(err, "reading build cache")
+	}
+
+	// The image might have been garbage collected since.
+	image := strings.TrimSpace(string(buf))
+	if _, err := docker.RemoteDigest(image); err != nil {
+		logrus.Debugf("Ignoring cached image %s: %s", image, err)
+		return "", nil
+	}
+	return image, nil
+}
+
+func (c gcsCache) store(ctx context.Context, imageName, key, image string) error {
+	client, err := gcp.StorageClient()
+	if err != nil {
+		return err
+	}
+
+	w := c.object(client, imageName, key).NewWriter(ctx)
+	if _, err := io.WriteString(w, image); err != nil {
+		w.Close()
+		return errors.Wrap(err, "writing build cache")
+	}
+	return w.Close()
+}
+
+// WithRemoteCache creates a builder that reuses the images found in the remote
+// cache for the same artifact keys, and records the images it builds.
+func WithRemoteCache(b build.Builder, cache remoteCache) build.Builder {
+	return withRemoteCache{
+		Builder: b,
+		cache:   cache,
+	}
+}
+
+type withRemoteCache struct {
+	build.Builder
+	cache remoteCache
+}
+
+func (w withRemoteCache) Build(ctx context.Context, out io.Writer, tagger tag.Tagger, artifacts []*latest.Artifact) ([]build.Artifact, error) {
+	keys := artifactKeys(ctx, artifacts)
+
+	var toBuild []*latest.Artifact
+	cached := map[string]string{}
+	for _, a := range artifacts {
+		key := keys[a.ImageName]
+		if key == "" {
+			toBuild = append(toBuild, a)
+			continue
+		}
+
+		image, err := w.cache.lookup(ctx, a.ImageName, key)
+		if err != nil {
+			logrus.Warnf("Unable to look %s up in the build cache: %s", a.ImageName, err)
+		}
+		if image == "" {
+			toBuild = append(toBuild, a)
+			continue
+		}
+
+		color.Default.Fprintf(out, "Found %s in the build cache\n", image)
+		cached[a.ImageName] = image
+	}
+
+	built := map[string]string{}
+	if len(toBuild) > 0 {
+		bRes, err := w.Builder.Build(ctx, out, tagger, toBuild)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, b := range bRes {
+			built[b.ImageName] = b.Tag
+			if key := keys[b.ImageName]; key != "" {
+				if err := w.cache.store(ctx, b.ImageName, key, b.Tag); err != nil {
+					logrus.Warnf("Unable to store %s in the build cache: %s", b.Tag, err)
+				}
+			}
+		}
+	}
+
+	// Keep the order of the artifacts.
+	var bRes []build.Artifact
+	for _, a := range artifacts {
+		if image, present := cached[a.ImageName]; present {
+			bRes = append(bRes, build.Artifact{ImageName: a.ImageName, Tag: image})
+		} else if tag, present := built[a.ImageName]; present {
+			bRes = append(bRes, build.Artifact{ImageName: a.ImageName, Tag: tag})
+		}
+	}
+	return bRes, nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+type fakeRemoteCache struct {
+	images map[string]string
+	stored map[string]string
+}
+
+func (c *fakeRemoteCache) lookup(ctx context.Context, imageName, key string) (string, error) {
+	return c.images[imageName+"/"+key], nil
+}
+
+func (c *fakeRemoteCache) store(ctx context.Context, imageName, key, image string) error {
+	c.stored[imageName] = image
+	return nil
+}
+
+func TestNewRemoteCache(t *testing.T) {
+	var tests = []struct {
+		location  string
+		shouldErr bool
+		expected  remoteCache
+	}{
+		{location: "registry", expected: registryCache{defaultRepo: "gcr.io/project"}},
+		{location: "gs://bucket", expected: gcsCache{bucket: "bucket"}},
+		{location: "gs://bucket/path/to/cache/", expected: gcsCache{bucket: "bucket", prefix: "path/to/cache"}},
+		{location: "gs://", shouldErr: true},
+		{location: "s3://bucket", shouldErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.location, func(t *testing.T) {
+			cache, err := newRemoteCache(test.location, "gcr.io/project")
+
+			testutil.CheckError(t, test.shouldErr, err)
+			if !reflect.DeepEqual(test.expected, cache) {
+				t.Errorf("Expected %+v, got %+v", test.expected, cache)
+			}
+		})
+	}
+}
+
+func TestRegistryCacheRepos(t *testing.T) {
+	cache := registryCache{defaultRepo: "gcr.io/project"}
+
+	testutil.CheckDeepEqual(t, "gcr.io/project/image", cache.lookupRepo("image"))
+	testutil.CheckDeepEqual(t, "gcr.io/project/image", cache.lookupRepo("gcr.io/project/image"))
+	testutil.CheckDeepEqual(t, "gcr.io/other/image", cache.storeRepo("image", "gcr.io/other/image:v1@sha256:"+strings.Repeat("a", 64)))
+	testutil.CheckDeepEqual(t, "gcr.io/project/image", cache.storeRepo("image", "invalid:"))
+}
+
+func TestWithRemoteCache(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	tmpDir.Write("Dockerfile", "FROM scratch\nCOPY file .\n").Write("file", "content")
+	var artifacts []*latest.Artifact
+	for _, imageName := range []string{"image1", "image2"} {
+		artifacts = append(artifacts, &latest.Artifact{
+			ImageName: imageName,
+			Workspace: tmpDir.Root(),
+			ArtifactType: latest.ArtifactType{
+				DockerArtifact: &latest.DockerArtifact{DockerfilePath: "Dockerfile"},
+			},
+		})
+	}
+
+	key, err := artifactKey(context.Background(), artifacts[0])
+	testutil.CheckError(t, false, err)
+
+	cache := &fakeRemoteCache{
+		images: map[string]string{"image1/" + key: "image1@sha256:cached"},
+		stored: map[string]string{},
+	}
+	builder := &TestBuilder{}
+
+	bRes, err := WithRemoteCache(builder, cache).Build(context.Background(), ioutil.Discard, nil, artifacts)
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, []build.Artifact{
+		{ImageName: "image1", Tag: "image1@sha256:cached"},
+		{ImageName: "image2"},
+	}, bRes)
+	testutil.CheckDeepEqual(t, []build.Artifact{{ImageName: "image2"}}, builder.built)
+	testutil.CheckDeepEqual(t, map[string]string{"image2": ""}, cache.stored)
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/kubectl"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/exitcode"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// deployHistory keeps the last manifest of each object that was
+// successfully rolled out, so that a failed deploy can be rolled back.
+type deployHistory struct {
+	kubectl   kubectl.CLI
+	manifests map[string][]byte
+}
+
+func newDeployHistory(kubeContext string, namespace string) *deployHistory {
+	return &deployHistory{
+		kubectl: kubectl.CLI{
+			KubeContext: kubeContext,
+			Namespace:   namespace,
+		},
+		manifests: map[string][]byte{},
+	}
+}
+
+// record remembers the manifests of successfully deployed objects.
+func (h *deployHistory) record(dRes []deploy.Artifact) {
+	for _, a := range dRes {
+		key, manifest, err := keyAndManifest(a)
+		if err != nil {
+			logrus.Debugln("Not recording deployed object:", err)
+			continue
+		}
+
+		h.manifests[key] = manifest
+	}
+}
+
+// rollback re-applies the last known good manifests
+// of the objects that failed to deploy.
+func (h *deployHistory) rollback(ctx context.Context, out io.Writer, dRes []deploy.Artifact) error {
+	var manifests kubectl.ManifestList
+	for _, a := range dRes {
+		key, _, err := keyAndManifest(a)
+		if err != nil {
+			continue
+		}
+
+		if previous, present := h.manifests[key]; present {
+			manifests.Append(previous)
+		}
+	}
+
+	if len(manifests) == 0 {
+		return errors.New("nothing was previously deployed")
+	}
+
+	return h.kubectl.Run(ctx, manifests.Reader(), out, "apply", nil, "-f", "-")
+}
+
+func keyAndManifest(a deploy.Artifact) (string, []byte, error) {
+	if a.Obj == nil {
+		return "", nil, errors.New("no object")
+	}
+
+	obj := *a.Obj
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return "", nil, err
+	}
+
+	manifest, err := yaml.Marshal(obj)
+	if err != nil {
+		return "", nil, err
+	}
+
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	key := fmt.Sprintf("%s/%s/%s", kind, accessor.GetNamespace(), accessor.GetName())
+
+	return key, manifest, nil
+}
+
+// DeployAndCheck deploys the builds, waits for the configured Jobs to complete and,
+// if asked to, for the deployments to be rolled out. A failed roll out can be
+// rolled back to the previous manifests.
+// In quiet mode, only the deployed resources are printed.
+func (r *SkaffoldRunner) DeployAndCheck(ctx context.Context, out io.Writer, builds []build.Artifact) error {
+	resultsOut := out
+	if r.opts.Quiet {
+		out = ioutil.Discard
+	}
+
+	if r.platforms != nil {
+		r.platforms.CheckPlatforms(ctx, resultsOut, builds)
+	}
+
+	dRes, err := r.Deploy(ctx, out, builds)
+	if err != nil {
+		return exitcode.Wrap(err, exitcode.Deploy)
+	}
+	if r.opts.Quiet {
+		printDeployResults(resultsOut, dRes)
+	}
+
+	if err := r.pods.WatchNamespaces(deployedNamespaces(dRes)...); err != nil {
+		return errors.Wrap(err, "watching pods")
+	}
+	if err := r.pods.Adopt(deploy.Workloads(dRes)); err != nil {
+		return errors.Wrap(err, "watching pods")
+	}
+
+	if r.config != nil {
+		if err := deploy.RunJobs(ctx, out, dRes, r.config.Deploy.Jobs, r.timeouts.jobs); err != nil {
+			return exitcode.Wrap(errors.Wrap(err, "running jobs"), exitcode.Deploy)
+		}
+	}
+
+	if !r.opts.StatusCheck && !r.opts.Rollback {
+		return nil
+	}
+
+	end := event.Start(event.StatusCheck, "")
+	err = deploy.StatusCheck(ctx, out, dRes, r.timeouts.statusCheck)
+	end("", err)
+	if err == nil {
+		r.history.record(dRes)
+		return nil
+	}
+
+	if !r.opts.Rollback {
+		return statusCheckError(err)
+	}
+
+	color.Red.Fprintf(resultsOut, "Deploy failed: %s. Rolling back...\n", err)
+	if err := r.history.rollback(ctx, out, dRes); err != nil {
+		logrus.Warnln("Unable to roll back:", err)
+	}
+
+	return statusCheckError(err)
+}
+
+// printDeployResults prints one line per deployed resource.
+func printDeployResults(out io.Writer, dRes []deploy.Artifact) {
+	for _, r := range dRes {
+		if r.Obj == nil {
+			continue
+		}
+		accessor, err := meta.Accessor(*r.Obj)
+		if err != nil {
+			continue
+		}
+
+		kind := (*r.Obj).GetObjectKind().GroupVersionKind().Kind
+		if r.Namespace != "" {
+			fmt.Fprintf(out, "%s/%s deployed in namespace %s\n", kind, accessor.GetName(), r.Namespace)
+		} else {
+			fmt.Fprintf(out, "%s/%s deployed\n", kind, accessor.GetName())
+		}
+	}
+}
+
+// deployedNamespaces lists the namespaces that the manifests declare.
+// The others are deployed to the namespace skaffold works in.
+func deployedNamespaces(dRes []deploy.Artifact) []string {
+	var namespaces []string
+	seen := map[string]bool{}
+	for _, r := range dRes {
+		if r.Namespace != "" && !seen[r.Namespace] {
+			seen[r.Namespace] = true
+			namespaces = append(namespaces, r.Namespace)
+		}
+	}
+	return namespaces
+}
+
+// statusCheckError tags a failed status check with the exit code of its failure class.
+func statusCheckError(err error) error {
+	code := exitcode.Deploy
+	if _, timeout := errors.Cause(err).(*kubernetes.RolloutTimeoutError); timeout {
+		code = exitcode.StatusCheckTimeout
+	}
+	return exitcode.Wrap(errors.Wrap(err, "status check"), code)
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgo "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func deployed(name string) deploy.Artifact {
+	var obj runtime.Object = &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+	}
+	return deploy.Artifact{Obj: &obj}
+}
+
+func TestRollback(t *testing.T) {
+	defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+	util.DefaultExecCommand = testutil.NewFakeCmd("kubectl --context kubecontext apply -f -", nil)
+
+	history := newDeployHistory("kubecontext", "")
+	history.record([]deploy.Artifact{deployed("web")})
+
+	err := history.rollback(context.Background(), &bytes.Buffer{}, []deploy.Artifact{deployed("web")})
+	testutil.CheckError(t, false, err)
+
+	err = history.rollback(context.Background(), &bytes.Buffer{}, []deploy.Artifact{deployed("other")})
+	testutil.CheckError(t, true, err)
+}
+
+func TestKeyAndManifest(t *testing.T) {
+	key, manifest, err := keyAndManifest(deployed("web"))
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, "Deployment//web", key)
+	if !bytes.Contains(manifest, []byte("kind: Deployment")) || !bytes.Contains(manifest, []byte("apiVersion: apps/v1")) {
+		t.Errorf("Unexpected manifest: %s", manifest)
+	}
+}
+
+func TestDeployAndCheckWatchesDeployedNamespaces(t *testing.T) {
+	web := deployed("web")
+	web.Namespace = "other"
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "other"}}
+
+	defer resetClient()
+	kubernetes.Client = func() (clientgo.Interface, error) { return fake.NewSimpleClientset(pod), nil }
+
+	pods := kubernetes.NewPodCache("default", nil)
+	defer pods.Stop()
+	runner := &SkaffoldRunner{
+		Deployer: &TestDeployer{results: []deploy.Artifact{deployed("api"), web}},
+		opts:     &config.SkaffoldOptions{},
+		pods:     pods,
+	}
+
+	err := runner.DeployAndCheck(context.Background(), &bytes.Buffer{}, nil)
+	testutil.CheckError(t, false, err)
+
+	watched, err := pods.Pods()
+	testutil.CheckErrorAndDeepEqual(t, false, err, []v1.Pod{*pod}, watched)
+}
+
+func TestDeployAndCheckJobsAcrossIterations(t *testing.T) {
+	var obj runtime.Object = &batchv1.Job{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "batch/v1", Kind: "Job"},
+		ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "ns"},
+	}
+	migrate := deploy.Artifact{Obj: &obj}
+	completed := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "ns"},
+		Status: batchv1.JobStatus{
+			Conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}},
+		},
+	}
+
+	defer resetClient()
+	kubernetes.Client = func() (clientgo.Interface, error) { return fake.NewSimpleClientset(completed), nil }
+
+	deployer := &TestDeployer{results: []deploy.Artifact{migrate, deployed("web")}}
+	pods := kubernetes.NewPodCache("default", nil)
+	defer pods.Stop()
+	runner := &SkaffoldRunner{
+		Deployer: deployer,
+		config:   &latest.SkaffoldPipeline{Deploy: latest.DeployConfig{Jobs: []string{"migrate"}}},
+		opts:     &config.SkaffoldOptions{},
+		pods:     pods,
+		timeouts: timeouts{jobs: time.Minute},
+	}
+
+	err := runner.DeployAndCheck(context.Background(), &bytes.Buffer{}, nil)
+	testutil.CheckError(t, false, err)
+
+	// Only the manifests that changed are applied again.
+	deployer.results = []deploy.Artifact{deployed("web")}
+	err = runner.DeployAndCheck(context.Background(), &bytes.Buffer{}, nil)
+	testutil.CheckError(t, false, err)
+}
@@ -20,7 +20,9 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -36,13 +38,19 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/exitcode"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/jib"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/cluster"
 	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/notify"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/server"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync/kubectl"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/test"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/version"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/watch"
 
 	"github.com/pkg/errors"
@@ -52,6 +60,9 @@ import (
 // ErrorConfigurationChanged is a special error that's returned when the skaffold configuration was changed.
 var ErrorConfigurationChanged = errors.New("configuration changed")
 
+// ConfigLoader reads the skaffold configuration, with profiles applied.
+type ConfigLoader func() (*latest.SkaffoldPipeline, error)
+
 // SkaffoldRunner is responsible for running the skaffold build and deploy pipeline.
 type SkaffoldRunner struct {
 	build.Builder
@@ -61,9 +72,24 @@ type SkaffoldRunner struct {
 	watch.Trigger
 	sync.Syncer
 
+	// LoadConfig is used by Dev to reload the configuration in place
+	// when skaffold.yaml changes. If nil, Dev returns ErrorConfigurationChanged instead.
+	LoadConfig ConfigLoader
+
+	config       *latest.SkaffoldPipeline
 	opts         *config.SkaffoldOptions
 	watchFactory watch.Factory
 	builds       []build.Artifact
+	intents      *intents
+	keys         *terminalKeys
+	otherContext string
+	history      *deployHistory
+	timeouts     timeouts
+	state        *devState
+	pruner       build.Pruner
+	platforms    build.PlatformChecker
+	pushImages   bool
+	pods         *kubernetes.PodCache
 }
 
 // NewForConfig returns a new SkaffoldRunner for a SkaffoldPipeline
@@ -72,7 +98,11 @@ func NewForConfig(opts *config.SkaffoldOptions, cfg *latest.SkaffoldPipeline) (*
 	if err != nil {
 		return nil, errors.Wrap(err, "getting current cluster context")
 	}
-	logrus.Infof("Using kubectl context: %s", kubeContext)
+	if kubectx.InCluster() {
+		logrus.Infof("Running inside the cluster, using the pod's service account")
+	} else {
+		logrus.Infof("Using kubectl context: %s", kubeContext)
+	}
 
 	defaultRepo, err := configutil.GetDefaultRepo(opts.DefaultRepo)
 	if err != nil {
@@ -84,12 +114,12 @@ func NewForConfig(opts *config.SkaffoldOptions, cfg *latest.SkaffoldPipeline) (*
 		return nil, errors.Wrap(err, "parsing tag config")
 	}
 
-	builder, err := getBuilder(&cfg.Build, kubeContext)
+	builder, err := getBuilder(&cfg.Build, kubeContext, opts)
 	if err != nil {
 		return nil, errors.Wrap(err, "parsing build config")
 	}
 
-	tester, err := getTester(&cfg.Test)
+	tester, err := getTester(&cfg.Test, &cfg.Verify, cfg.Scan, opts.Namespace)
 	if err != nil {
 		return nil, errors.Wrap(err, "parsing test config")
 	}
@@ -99,34 +129,104 @@ func NewForConfig(opts *config.SkaffoldOptions, cfg *latest.SkaffoldPipeline) (*
 		return nil, errors.Wrap(err, "parsing deploy config")
 	}
 
-	deployer = deploy.WithLabels(deployer, opts, builder, deployer, tagger)
+	if opts.CheckPermissions {
+		if err := checkPermissions(cfg, opts.Namespace); err != nil {
+			return nil, errors.Wrap(err, "checking permissions")
+		}
+	}
+
+	timeouts, err := getTimeouts(opts, cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing timeouts")
+	}
+
+	pruner, _ := builder.(build.Pruner)
+	platforms, _ := builder.(build.PlatformChecker)
+	pusher, isPusher := builder.(build.Pusher)
+	pushImages := !isPusher || pusher.Pushes()
+
+	// The images found in the build cache are signed and get an SBOM like the built ones.
+	if opts.BuildCache != "" {
+		cache, err := newRemoteCache(opts.BuildCache, defaultRepo)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating build cache")
+		}
+		builder = WithRemoteCache(builder, cache)
+	}
+
+	builder = WithECRRepositories(builder, cfg.Build.ECR, defaultRepo, pushImages)
+	builder = WithSBOM(builder, cfg.Build.SBOM)
+	builder, deployer = WithSigning(builder, deployer, cfg.Build.Sign, pushImages)
+
+	deployer = deploy.WithLabels(deployer, deploy.StaticLabels(cfg.Deploy.Labels), opts, builder, deployer, tagger, version.Labeller{})
+	builder, deployer = WithTimeouts(builder, deployer, timeouts.build, timeouts.deploy)
 	builder, tester, deployer = WithTimings(builder, tester, deployer)
+	notifiers, err := notify.NewNotifiers(cfg.Notify)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating notifiers")
+	}
 	if opts.Notification {
-		deployer = WithNotification(deployer)
+		notifiers = append(notifiers, notify.Bell{})
 	}
+	builder, deployer = WithNotification(builder, deployer, notifiers)
 
 	trigger, err := watch.NewTrigger(opts)
 	if err != nil {
 		return nil, errors.Wrap(err, "creating watch trigger")
 	}
 
+	pods := newPodCache(opts)
+
 	return &SkaffoldRunner{
 		Builder:      builder,
 		Tester:       tester,
 		Deployer:     deployer,
 		Tagger:       tagger,
 		Trigger:      trigger,
-		Syncer:       &kubectl.Syncer{},
+		Syncer:       &kubectl.Syncer{Pods: pods},
+		config:       cfg,
 		opts:         opts,
 		watchFactory: watch.NewWatcher,
+		history:      newDeployHistory(kubeContext, opts.Namespace),
+		timeouts:     timeouts,
+		state:        loadDevState(opts.ConfigurationFiles, kubeContext, opts.Namespace),
+		pruner:       pruner,
+		platforms:    platforms,
+		pushImages:   pushImages,
+		pods:         pods,
 	}, nil
 }
 
-func getBuilder(cfg *latest.BuildConfig, kubeContext string) (build.Builder, error) {
+// newPodCache creates the cache of the pods that this skaffold run deploys, which
+// the logger, the port forwarder and the syncer share. They never see the pods of
+// another run. The namespaces that the manifests declare are watched once they're deployed.
+func newPodCache(opts *config.SkaffoldOptions) *kubernetes.PodCache {
+	// The commands give every invocation a run ID, other callers get theirs here.
+	if opts.RunID == "" {
+		opts.RunID = util.RandomID()
+	}
+	return kubernetes.NewPodCache(opts.Namespace, deploy.RunPodLabels(opts.RunID))
+}
+
+// ResetPods gives the runner a new pod cache. A runner that's kept between
+// operations, like the daemon's, needs one per operation because a cache can't
+// be started again once it's stopped, which Dev and Run do when they return.
+func (r *SkaffoldRunner) ResetPods() {
+	r.pods = newPodCache(r.opts)
+	if syncer, ok := r.Syncer.(*kubectl.Syncer); ok {
+		syncer.Pods = r.pods
+	}
+}
+
+func getBuilder(cfg *latest.BuildConfig, kubeContext string, opts *config.SkaffoldOptions) (build.Builder, error) {
 	switch {
 	case cfg.LocalBuild != nil:
 		logrus.Debugf("Using builder: local")
-		return local.NewBuilder(cfg.LocalBuild, kubeContext)
+		localBuild := *cfg.LocalBuild
+		if opts.Push != nil {
+			localBuild.Push = opts.Push
+		}
+		return local.NewBuilder(&localBuild, kubeContext, cluster.Detect(kubeContext, opts.LocalCluster, opts.LocalClusterRules))
 
 	case cfg.GoogleCloudBuild != nil:
 		logrus.Debugf("Using builder: google cloud")
@@ -145,8 +245,8 @@ func getBuilder(cfg *latest.BuildConfig, kubeContext string) (build.Builder, err
 	}
 }
 
-func getTester(cfg *latest.TestConfig) (test.Tester, error) {
-	return test.NewTester(cfg)
+func getTester(cfg *latest.TestConfig, verifyCfg *latest.VerifyConfig, scanCfg *latest.ScanConfig, namespace string) (test.Tester, error) {
+	return test.NewTester(cfg, verifyCfg, scanCfg, namespace)
 }
 
 func getDeployer(cfg *latest.DeployConfig, kubeContext string, namespace string, defaultRepo string) (deploy.Deployer, error) {
@@ -158,7 +258,7 @@ func getDeployer(cfg *latest.DeployConfig, kubeContext string, namespace string,
 	}
 
 	if cfg.KubectlDeploy != nil {
-		// TODO(dgageot): this should be the folder containing skaffold.yaml. Should also be moved elsewhere.
+		// Manifest paths were already resolved against the folder containing skaffold.yaml.
 		cwd, err := os.Getwd()
 		if err != nil {
 			return nil, errors.Wrap(err, "finding current directory")
@@ -206,21 +306,40 @@ func getTagger(t latest.TagPolicy, customTag string) (tag.Tagger, error) {
 }
 
 // Run builds artifacts, runs tests on built artifacts, and then deploys them.
+// In quiet mode, only the results of the build and deploy and the logs of the deployed pods are printed.
 func (r *SkaffoldRunner) Run(ctx context.Context, out io.Writer, artifacts []*latest.Artifact) error {
-	bRes, err := r.Build(ctx, out, r.Tagger, artifacts)
+	stepsOut := out
+	if r.opts.Quiet {
+		stepsOut = ioutil.Discard
+	}
+
+	summary := startTimingSummary()
+	defer summary.stop()
+
+	bRes, err := r.Build(ctx, stepsOut, r.Tagger, artifacts)
 	if err != nil {
-		return errors.Wrap(err, "build step")
+		build.PrintFailure(out, err)
+		return exitcode.Wrap(errors.Wrap(err, "build step"), exitcode.Build)
+	}
+	if r.opts.Quiet {
+		for _, b := range bRes {
+			fmt.Fprintf(out, "%s -> %s\n", b.ImageName, b.Tag)
+		}
 	}
 
-	if err = r.Test(ctx, out, bRes); err != nil {
+	if err = r.Test(ctx, stepsOut, bRes); err != nil {
 		return errors.Wrap(err, "test step")
 	}
 
-	_, err = r.Deploy(ctx, out, bRes)
-	if err != nil {
+	if err = r.DeployAndCheck(ctx, out, bRes); err != nil {
 		return errors.Wrap(err, "deploy step")
 	}
 
+	if err = r.Verify(ctx, stepsOut, bRes); err != nil {
+		return errors.Wrap(err, "verify step")
+	}
+	summary.print(stepsOut)
+
 	return r.TailLogs(ctx, out, artifacts, bRes)
 }
 
@@ -236,22 +355,65 @@ func (r *SkaffoldRunner) TailLogs(ctx context.Context, out io.Writer, artifacts
 	}
 
 	colorPicker := kubernetes.NewColorPicker(artifacts)
-	logger := kubernetes.NewLogAggregator(out, imageList, colorPicker)
+	logger := kubernetes.NewLogAggregator(out, r.pods, imageList, colorPicker)
 	if err := logger.Start(ctx); err != nil {
 		return errors.Wrap(err, "starting logger")
 	}
+	defer r.pods.Stop()
 
 	<-ctx.Done()
 	return nil
 }
 
+// Prune removes the older images of the artifacts, if the builder supports it.
+func (r *SkaffoldRunner) Prune(ctx context.Context, out io.Writer) error {
+	if r.pruner == nil || r.config == nil {
+		return nil
+	}
+
+	return r.pruner.Prune(ctx, out, r.config.Build.Artifacts)
+}
+
 // Dev watches for changes and runs the skaffold build and deploy
 // pipeline until interrrupted by the user.
 func (r *SkaffoldRunner) Dev(ctx context.Context, out io.Writer, artifacts []*latest.Artifact) ([]build.Artifact, error) {
+	r.intents = newIntents(r.opts)
+	kubectx.PinContext()
+	if r.opts.EnableRPC {
+		shutdown, err := server.Initialize(r.opts.RPCPort, r.intents)
+		if err != nil {
+			return nil, errors.Wrap(err, "starting control API")
+		}
+		defer func() {
+			if err := shutdown(); err != nil {
+				logrus.Warnln("stopping control API:", err)
+			}
+		}()
+	}
+
 	imageList := kubernetes.NewImageList()
 	colorPicker := kubernetes.NewColorPicker(artifacts)
-	logger := kubernetes.NewLogAggregator(out, imageList, colorPicker)
-	portForwarder := kubernetes.NewPortForwarder(out, imageList)
+	logger := kubernetes.NewLogAggregator(out, r.pods, imageList, colorPicker)
+	portForwarder := kubernetes.NewPortForwarder(out, r.pods, imageList)
+	healthReporter := kubernetes.NewHealthReporter(out, r.pods, imageList)
+	eventReporter := kubernetes.NewEventReporter(out, r.pods, imageList, artifacts)
+	defer r.pods.Stop()
+
+	// When pipelined, deploys run in the background while the next changes are built.
+	var deploys *deployQueue
+	if r.opts.PipelineDev {
+		deploys = newDeployQueue(func(builds []build.Artifact) {
+			if err := r.DeployAndCheck(ctx, out, builds); err != nil {
+				logrus.Warnln("Deploy failed:", err)
+				return
+			}
+			r.saveState(builds)
+			if err := r.Verify(ctx, out, builds); err != nil {
+				logrus.Warnln("Verification failed:", err)
+			}
+		})
+		defer deploys.wait()
+	}
 
 	// Create watcher and register artifacts to build current state of files.
 	changed := changes{}
@@ -260,15 +422,18 @@ func (r *SkaffoldRunner) Dev(ctx context.Context, out io.Writer, artifacts []*la
 
 		logger.Mute()
 		defer func() {
-			changed.reset()
 			r.Trigger.WatchForChanges(out)
 			if !hasError {
 				logger.Unmute()
 			}
 		}()
+		if err := r.checkKubeContext(out); err != nil {
+			return err
+		}
 		for _, a := range changed.dirtyArtifacts {
 			s, err := sync.NewItem(a.artifact, a.events, r.builds)
 			if err != nil {
+				changed.reset()
 				return errors.Wrap(err, "sync")
 			}
 			if s != nil {
@@ -277,13 +442,29 @@ func (r *SkaffoldRunner) Dev(ctx context.Context, out io.Writer, artifacts []*la
 				changed.AddRebuild(a.artifact)
 			}
 		}
+		changed.dirtyArtifacts = nil
+
+		if r.intents.reloadRequested() {
+			changed.needsReload = true
+		}
+		if r.intents.deployRequested() {
+			changed.needsRedeploy = true
+		}
+
+		// A pending sync doesn't hold back builds and deploys.
+		canSync := len(changed.needsResync) > 0 && r.intents.canSync()
+		if len(changed.needsResync) > 0 && !canSync {
+			color.Yellow.Fprintf(out, "Sync of %d artifacts is pending\n", len(changed.needsResync))
+		}
 
 		switch {
 		case changed.needsReload:
-			logger.Stop()
+			changed.reset()
 			return ErrorConfigurationChanged
-		case len(changed.needsResync) > 0:
-			for _, s := range changed.needsResync {
+		case canSync:
+			needsResync := changed.needsResync
+			changed.needsResync = nil
+			for _, s := range needsResync {
 				color.Default.Fprintf(out, "Syncing %d files for %s\n", len(s.Copy)+len(s.Delete), s.Image)
 
 				if err := r.Syncer.Sync(ctx, s); err != nil {
@@ -292,52 +473,229 @@ func (r *SkaffoldRunner) Dev(ctx context.Context, out io.Writer, artifacts []*la
 				}
 			}
 		case len(changed.needsRebuild) > 0:
-			bRes, err := r.Build(ctx, out, r.Tagger, changed.needsRebuild)
+			if !r.intents.canBuild() {
+				color.Yellow.Fprintf(out, "Build of %d artifacts is pending\n", len(changed.needsRebuild))
+				hasError = false
+				return nil
+			}
+
+			// The summary is stopped on every path out of this change.
+			summary := startTimingSummary()
+
+			needsRebuild := changed.needsRebuild
+			changed.needsRebuild = nil
+			bRes, err := r.buildWithState(ctx, out, needsRebuild, false)
 			if err != nil {
+				summary.stop()
+				build.PrintFailure(out, err)
 				logrus.Warnln("Skipping Deploy due to build error:", err)
 				return nil
 			}
 
 			r.updateBuiltImages(imageList, bRes)
 			if err := r.Test(ctx, out, bRes); err != nil {
+				summary.stop()
 				logrus.Warnln("Skipping Deploy due to failed tests:", err)
 				return nil
 			}
 
-			if _, err = r.Deploy(ctx, out, r.builds); err != nil {
+			if !r.intents.canDeploy() {
+				summary.stop()
+				changed.needsRedeploy = true
+				color.Yellow.Fprintln(out, "Deploy is pending")
+				hasError = false
+				return nil
+			}
+
+			changed.needsRedeploy = false
+			if deploys != nil {
+				summary.stop()
+				deploys.schedule(r.builds)
+				hasError = false
+				return nil
+			}
+			if err = r.DeployAndCheck(ctx, out, r.builds); err != nil {
+				summary.stop()
 				logrus.Warnln("Skipping Deploy due to error:", err)
 				return nil
 			}
+			r.saveState(r.builds)
+			if err := r.Verify(ctx, out, r.builds); err != nil {
+				logrus.Warnln("Verification failed:", err)
+			}
+			summary.print(out)
 		case changed.needsRedeploy:
+			if !r.intents.canDeploy() {
+				color.Yellow.Fprintln(out, "Deploy is pending")
+				hasError = false
+				return nil
+			}
+
+			changed.needsRedeploy = false
 			if err := r.Test(ctx, out, r.builds); err != nil {
 				logrus.Warnln("Skipping Deploy due to failed tests:", err)
 				return nil
 			}
-			if _, err := r.Deploy(ctx, out, r.builds); err != nil {
+			if deploys != nil {
+				deploys.schedule(r.builds)
+				hasError = false
+				return nil
+			}
+			if err := r.DeployAndCheck(ctx, out, r.builds); err != nil {
 				logrus.Warnln("Skipping Deploy due to error:", err)
 				return nil
 			}
+			r.saveState(r.builds)
+			if err := r.Verify(ctx, out, r.builds); err != nil {
+				logrus.Warnln("Verification failed:", err)
+			}
 		}
 
 		hasError = false
 		return nil
 	}
 
+	intercepted, err := r.interceptedArtifacts(artifacts)
+	if err != nil {
+		return nil, err
+	}
+
+	dependencies.prefetch(ctx, artifacts)
 	watcher := r.watchFactory()
+	if err := r.registerWatches(ctx, watcher, artifacts, &changed); err != nil {
+		return nil, err
+	}
 
-	// Watch artifacts
-	for i := range artifacts {
-		artifact := artifacts[i]
+	// First run
+	summary := startTimingSummary()
+
+	bRes, err := r.buildWithState(ctx, out, artifacts, true)
+	if err != nil {
+		summary.stop()
+		build.PrintFailure(out, err)
+		return nil, exitcode.Wrap(errors.Wrap(err, "exiting dev mode because the first build failed"), exitcode.Build)
+	}
+
+	r.updateBuiltImages(imageList, bRes)
+	if err := r.Test(ctx, out, bRes); err != nil {
+		summary.stop()
+		return nil, errors.Wrap(err, "exiting dev mode because the first test run failed")
+	}
+
+	if err := r.DeployAndCheck(ctx, out, r.builds); err != nil {
+		summary.stop()
+		return nil, errors.Wrap(err, "exiting dev mode because the first deploy failed")
+	}
+	r.saveState(r.builds)
+
+	if err := r.Verify(ctx, out, r.builds); err != nil {
+		logrus.Warnln("Verification failed:", err)
+	}
+	summary.print(out)
+
+	// Intercepted artifacts were deployed once, so that their workloads exist.
+	// From now on, their local processes take care of the changes.
+	if len(intercepted) > 0 {
+		stopIntercepts, err := r.startIntercepts(out, intercepted)
+		if err != nil {
+			return nil, errors.Wrap(err, "intercepting workloads")
+		}
+		defer stopIntercepts()
+	}
+
+	// Start logs
+	if r.opts.TailDev {
+		if err := logger.Start(ctx); err != nil {
+			return nil, errors.Wrap(err, "starting logger")
+		}
+		defer logger.Stop()
+	}
+
+	if r.opts.PortForward {
+		if err := portForwarder.Start(ctx); err != nil {
+			return nil, errors.Wrap(err, "starting port-forwarder")
+		}
+		defer portForwarder.Terminate()
+	}
+
+	if err := healthReporter.Start(ctx); err != nil {
+		logrus.Warnln("Unable to report on the health of pods:", err)
+	}
+	defer healthReporter.Stop()
+
+	if err := eventReporter.Start(ctx); err != nil {
+		logrus.Warnln("Unable to report Kubernetes events:", err)
+	}
+	defer eventReporter.Stop()
 
-		if !r.shouldWatch(artifact) {
+	r.Trigger.WatchForChanges(out)
+	if r.opts.Trigger != "manual" && stdinIsTerminal() {
+		r.keys = listenToKeys(stdin, r.intents)
+		color.Yellow.Fprintf(out, "Type %s and press Enter to pause or resume watching\n", pauseKey)
+	}
+	trigger := &intentTrigger{Trigger: r.Trigger, intents: r.intents, out: out}
+	for {
+		err := watcher.Run(ctx, trigger, onChange)
+		if errors.Cause(err) != ErrorConfigurationChanged {
+			return nil, err
+		}
+		if r.LoadConfig == nil {
+			logger.Stop()
+			return nil, err
+		}
+
+		switch r.onConfigChange(out) {
+		case ConfigChangeIgnore:
+			color.Default.Fprintln(out, "Ignoring the configuration change")
+			logger.Unmute()
 			continue
+		case ConfigChangeExit:
+			return nil, nil
+		}
+
+		color.Default.Fprintln(out, "Configuration changed, reloading")
+		deploys.wait()
+		reloaded, needsRebuild, reloadErr := r.reload(artifacts)
+		if reloadErr != nil {
+			logrus.Warnln("Keeping the previous configuration:", reloadErr)
+			logger.Unmute()
+		} else {
+			artifacts = reloaded
+			colorPicker.SetArtifacts(artifacts)
+			eventReporter.SetArtifacts(artifacts)
+		}
+
+		// Start watching the new configuration before rebuilding,
+		// so that changes made during the build aren't missed.
+		watcher = r.watchFactory()
+		if err := r.registerWatches(ctx, watcher, artifacts, &changed); err != nil {
+			return nil, err
 		}
 
+		if reloadErr == nil && r.redeployAfterReload(ctx, out, imageList, needsRebuild) {
+			logger.Unmute()
+		}
+		r.Trigger.WatchForChanges(out)
+	}
+}
+
+// registerWatches registers the artifacts, the test and deploy dependencies and the
+// skaffold configuration with a watcher. Detected changes are recorded in changed.
+func (r *SkaffoldRunner) registerWatches(ctx context.Context, watcher watch.Watcher, artifacts []*latest.Artifact, changed *changes) error {
+	watched, err := r.watchedArtifacts(artifacts)
+	if err != nil {
+		return err
+	}
+
+	// Watch artifacts
+	for i := range watched {
+		artifact := watched[i]
+
 		if err := watcher.Register(
-			func() ([]string, error) { return DependenciesForArtifact(ctx, artifact) },
+			func() ([]string, error) { return dependencies.forArtifact(ctx, artifact) },
 			func(e watch.Events) { changed.AddDirtyArtifact(artifact, e) },
 		); err != nil {
-			return nil, errors.Wrapf(err, "watching files for artifact %s", artifact.ImageName)
+			return errors.Wrapf(err, "watching files for artifact %s", artifact.ImageName)
 		}
 	}
 
@@ -346,7 +704,7 @@ func (r *SkaffoldRunner) Dev(ctx context.Context, out io.Writer, artifacts []*la
 		func() ([]string, error) { return r.TestDependencies() },
 		func(watch.Events) { changed.needsRedeploy = true },
 	); err != nil {
-		return nil, errors.Wrap(err, "watching test files")
+		return errors.Wrap(err, "watching test files")
 	}
 
 	// Watch deployment configuration
@@ -354,62 +712,71 @@ func (r *SkaffoldRunner) Dev(ctx context.Context, out io.Writer, artifacts []*la
 		func() ([]string, error) { return r.Dependencies() },
 		func(watch.Events) { changed.needsRedeploy = true },
 	); err != nil {
-		return nil, errors.Wrap(err, "watching files for deployer")
+		return errors.Wrap(err, "watching files for deployer")
 	}
 
 	// Watch Skaffold configuration
 	if err := watcher.Register(
-		func() ([]string, error) { return []string{r.opts.ConfigurationFile}, nil },
+		func() ([]string, error) { return r.opts.ConfigurationFiles, nil },
 		func(watch.Events) { changed.needsReload = true },
 	); err != nil {
-		return nil, errors.Wrapf(err, "watching skaffold configuration %s", r.opts.ConfigurationFile)
+		return errors.Wrapf(err, "watching skaffold configuration %s", strings.Join(r.opts.ConfigurationFiles, ", "))
 	}
 
-	// First run
-	bRes, err := r.Build(ctx, out, r.Tagger, artifacts)
-	if err != nil {
-		return nil, errors.Wrap(err, "exiting dev mode because the first build failed")
-	}
+	return nil
+}
 
-	r.updateBuiltImages(imageList, bRes)
-	if err := r.Test(ctx, out, bRes); err != nil {
-		return nil, errors.Wrap(err, "exiting dev mode because the first test run failed")
+// watchedArtifacts keeps the artifacts selected by the --watch-image expressions.
+// An expression is either an image name or a glob pattern, matched against the full
+// image name or its last path component. Each expression has to match an artifact.
+// Intercepted artifacts are never watched since they run as local processes.
+func (r *SkaffoldRunner) watchedArtifacts(artifacts []*latest.Artifact) ([]*latest.Artifact, error) {
+	if len(r.opts.Intercept) > 0 {
+		var local []*latest.Artifact
+		for _, artifact := range artifacts {
+			if !r.isIntercepted(artifact) {
+				local = append(local, artifact)
+			}
+		}
+		artifacts = local
 	}
 
-	_, err = r.Deploy(ctx, out, r.builds)
-	if err != nil {
-		return nil, errors.Wrap(err, "exiting dev mode because the first deploy failed")
+	if len(r.opts.Watch) == 0 {
+		return artifacts, nil
 	}
 
-	// Start logs
-	if r.opts.TailDev {
-		if err := logger.Start(ctx); err != nil {
-			return nil, errors.Wrap(err, "starting logger")
+	var watched []*latest.Artifact
+	matched := map[string]bool{}
+	for _, artifact := range artifacts {
+		watch := false
+		for _, expression := range r.opts.Watch {
+			match, err := matchesImage(artifact.ImageName, expression)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid watch expression %s", expression)
+			}
+			if match {
+				matched[expression] = true
+				watch = true
+			}
+		}
+		if watch {
+			watched = append(watched, artifact)
 		}
 	}
 
-	if r.opts.PortForward {
-		if err := portForwarder.Start(ctx); err != nil {
-			return nil, errors.Wrap(err, "starting port-forwarder")
+	for _, expression := range r.opts.Watch {
+		if !matched[expression] {
+			return nil, errors.Errorf("no artifact matches watch expression %s", expression)
 		}
 	}
-
-	r.Trigger.WatchForChanges(out)
-	return nil, watcher.Run(ctx, r.Trigger, onChange)
+	return watched, nil
 }
 
-func (r *SkaffoldRunner) shouldWatch(artifact *latest.Artifact) bool {
-	if len(r.opts.Watch) == 0 {
-		return true
-	}
-
-	for _, watchExpression := range r.opts.Watch {
-		if strings.Contains(artifact.ImageName, watchExpression) {
-			return true
-		}
+func matchesImage(imageName, expression string) (bool, error) {
+	if match, err := path.Match(expression, imageName); err != nil || match {
+		return match, err
 	}
-
-	return false
+	return path.Match(expression, path.Base(imageName))
 }
 
 func (r *SkaffoldRunner) updateBuiltImages(images *kubernetes.ImageList, bRes []build.Artifact) {
@@ -460,6 +827,9 @@ func DependenciesForArtifact(ctx context.Context, a *latest.Artifact) ([]string,
 	case a.JibGradleArtifact != nil:
 		paths, err = jib.GetDependenciesGradle(ctx, a.Workspace, a.JibGradleArtifact)
 
+	case a.TarballArtifact != nil:
+		paths = []string{a.TarballArtifact.Path}
+
 	default:
 		return nil, fmt.Errorf("undefined artifact type: %+v", a.ArtifactType)
 	}
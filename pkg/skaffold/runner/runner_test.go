@@ -17,6 +17,7 @@ limitations under the License.
 package runner
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -28,12 +29,18 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/exitcode"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync/kubectl"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/test"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/watch"
 	"github.com/GoogleContainerTools/skaffold/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	clientgo "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
 )
@@ -79,12 +86,17 @@ func (t *TestTester) Test(ctx context.Context, out io.Writer, builds []build.Art
 	return nil
 }
 
+func (t *TestTester) Verify(ctx context.Context, out io.Writer, builds []build.Artifact) error {
+	return nil
+}
+
 func (t *TestTester) TestDependencies() ([]string, error) {
 	return nil, nil
 }
 
 type TestDeployer struct {
 	deployed []build.Artifact
+	results  []deploy.Artifact
 	errors   []error
 }
 
@@ -104,7 +116,7 @@ func (t *TestDeployer) Deploy(ctx context.Context, out io.Writer, builds []build
 	}
 
 	t.deployed = builds
-	return nil, nil
+	return t.results, nil
 }
 
 func (t *TestDeployer) Cleanup(ctx context.Context, out io.Writer) error {
@@ -279,12 +291,13 @@ func TestNewForConfig(t *testing.T) {
 
 func TestRun(t *testing.T) {
 	var tests = []struct {
-		description string
-		pipeline    *latest.SkaffoldPipeline
-		builder     build.Builder
-		tester      test.Tester
-		deployer    deploy.Deployer
-		shouldErr   bool
+		description      string
+		pipeline         *latest.SkaffoldPipeline
+		builder          build.Builder
+		tester           test.Tester
+		deployer         deploy.Deployer
+		shouldErr        bool
+		expectedExitCode int
 	}{
 		{
 			description: "run no error",
@@ -299,8 +312,9 @@ func TestRun(t *testing.T) {
 			builder: &TestBuilder{
 				errors: []error{fmt.Errorf("")},
 			},
-			tester:    &TestTester{},
-			shouldErr: true,
+			tester:           &TestTester{},
+			shouldErr:        true,
+			expectedExitCode: exitcode.Build,
 		},
 		{
 			description: "run deploy error",
@@ -318,7 +332,8 @@ func TestRun(t *testing.T) {
 			deployer: &TestDeployer{
 				errors: []error{fmt.Errorf("")},
 			},
-			shouldErr: true,
+			shouldErr:        true,
+			expectedExitCode: exitcode.Deploy,
 		},
 		{
 			description: "run test error",
@@ -341,7 +356,8 @@ func TestRun(t *testing.T) {
 			tester: &TestTester{
 				errors: []error{fmt.Errorf("")},
 			},
-			shouldErr: true,
+			shouldErr:        true,
+			expectedExitCode: exitcode.Failure,
 		},
 	}
 
@@ -353,14 +369,39 @@ func TestRun(t *testing.T) {
 				Deployer: test.deployer,
 				Tagger:   &tag.ChecksumTagger{},
 				opts:     &config.SkaffoldOptions{},
+				pods:     kubernetes.NewPodCache("", nil),
 			}
 			err := runner.Run(context.Background(), ioutil.Discard, test.pipeline.Build.Artifacts)
 
 			testutil.CheckError(t, test.shouldErr, err)
+			testutil.CheckDeepEqual(t, test.expectedExitCode, exitcode.Of(err))
 		})
 	}
 }
 
+func TestRunQuiet(t *testing.T) {
+	var deployment runtime.Object = &appsv1.Deployment{
+		TypeMeta:   meta_v1.TypeMeta{Kind: "Deployment"},
+		ObjectMeta: meta_v1.ObjectMeta{Name: "web"},
+	}
+	results := []deploy.Artifact{{Obj: &deployment, Namespace: "ns"}}
+	builder, tester, deployer := WithTimings(&TestBuilder{}, &TestTester{}, &TestDeployer{results: results})
+	runner := &SkaffoldRunner{
+		Builder:  builder,
+		Tester:   tester,
+		Deployer: deployer,
+		Tagger:   &tag.ChecksumTagger{},
+		opts:     &config.SkaffoldOptions{Quiet: true},
+		pods:     kubernetes.NewPodCache("", nil),
+	}
+	artifacts := []*latest.Artifact{{ImageName: "test"}}
+
+	out := new(bytes.Buffer)
+	err := runner.Run(context.Background(), out, artifacts)
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, "test -> \nDeployment/web deployed in namespace ns\n", out.String())
+}
+
 func TestDev(t *testing.T) {
 	kubernetes.Client = fakeGetClient
 	defer resetClient()
@@ -447,6 +488,7 @@ func TestDev(t *testing.T) {
 				watchFactory: test.watcherFactory,
 				opts:         opts,
 				Syncer:       NewTestSyncer(),
+				pods:         kubernetes.NewPodCache("", nil),
 			}
 			_, err := runner.Dev(context.Background(), ioutil.Discard, nil)
 
@@ -478,6 +520,7 @@ func TestBuildAndDeployAllArtifacts(t *testing.T) {
 		Trigger:  trigger,
 		opts:     opts,
 		Syncer:   NewTestSyncer(),
+		pods:     kubernetes.NewPodCache("", nil),
 	}
 
 	ctx := context.Background()
@@ -511,36 +554,93 @@ func TestBuildAndDeployAllArtifacts(t *testing.T) {
 	}
 }
 
-func TestShouldWatch(t *testing.T) {
+func TestPendingBuildWithoutAutoBuild(t *testing.T) {
+	kubernetes.Client = fakeGetClient
+	defer resetClient()
+
+	opts := &config.SkaffoldOptions{
+		Trigger:          "polling",
+		DisableAutoBuild: true,
+	}
+	builder := &TestBuilder{}
+	deployer := &TestDeployer{}
+	trigger, _ := watch.NewTrigger(opts)
+	artifacts := []*latest.Artifact{
+		{ImageName: "image1"},
+		{ImageName: "image2"},
+	}
+
+	runner := &SkaffoldRunner{
+		Builder:      builder,
+		Tester:       &TestTester{},
+		Deployer:     deployer,
+		Trigger:      trigger,
+		opts:         opts,
+		Syncer:       NewTestSyncer(),
+		pods:         kubernetes.NewPodCache("", nil),
+		watchFactory: NewWatcherFactory(nil, nil, []int{1}),
+	}
+
+	_, err := runner.Dev(context.Background(), ioutil.Discard, artifacts)
+
+	testutil.CheckError(t, false, err)
+	if len(builder.built) != 2 {
+		t.Errorf("Expected only the first build to happen. Got %d artifacts built", len(builder.built))
+	}
+}
+
+func TestWatchedArtifacts(t *testing.T) {
 	var tests = []struct {
-		description   string
-		watch         []string
-		expectedMatch bool
+		description string
+		watch       []string
+		intercept   []string
+		shouldErr   bool
+		expected    []string
 	}{
 		{
-			description:   "match all",
-			watch:         nil,
-			expectedMatch: true,
+			description: "match all",
+			watch:       nil,
+			expected:    []string{"domain/image", "domain/image-gateway"},
 		},
 		{
-			description:   "match full name",
-			watch:         []string{"domain/image"},
-			expectedMatch: true,
+			description: "match full name",
+			watch:       []string{"domain/image"},
+			expected:    []string{"domain/image"},
 		},
 		{
-			description:   "match partial name",
-			watch:         []string{"image"},
-			expectedMatch: true,
+			description: "match short name",
+			watch:       []string{"image"},
+			expected:    []string{"domain/image"},
 		},
 		{
-			description:   "match any",
-			watch:         []string{"other", "image"},
-			expectedMatch: true,
+			description: "match glob",
+			watch:       []string{"image*"},
+			expected:    []string{"domain/image", "domain/image-gateway"},
 		},
 		{
-			description:   "no match",
-			watch:         []string{"other"},
-			expectedMatch: false,
+			description: "match glob on full name",
+			watch:       []string{"domain/*-gateway"},
+			expected:    []string{"domain/image-gateway"},
+		},
+		{
+			description: "match any",
+			watch:       []string{"image", "image-gateway"},
+			expected:    []string{"domain/image", "domain/image-gateway"},
+		},
+		{
+			description: "partial name doesn't match",
+			watch:       []string{"image", "gateway"},
+			shouldErr:   true,
+		},
+		{
+			description: "invalid pattern",
+			watch:       []string{"[image"},
+			shouldErr:   true,
+		},
+		{
+			description: "skip intercepted",
+			intercept:   []string{"image-gateway"},
+			expected:    []string{"domain/image"},
 		},
 	}
 
@@ -548,15 +648,69 @@ func TestShouldWatch(t *testing.T) {
 		t.Run(test.description, func(t *testing.T) {
 			runner := &SkaffoldRunner{
 				opts: &config.SkaffoldOptions{
-					Watch: test.watch,
+					Watch:     test.watch,
+					Intercept: test.intercept,
 				},
 			}
 
-			match := runner.shouldWatch(&latest.Artifact{
-				ImageName: "domain/image",
+			watched, err := runner.watchedArtifacts([]*latest.Artifact{
+				{ImageName: "domain/image"},
+				{ImageName: "domain/image-gateway"},
 			})
 
-			testutil.CheckDeepEqual(t, test.expectedMatch, match)
+			var imageNames []string
+			for _, artifact := range watched {
+				imageNames = append(imageNames, artifact.ImageName)
+			}
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, imageNames)
 		})
 	}
 }
+
+func TestPodCacheOfRun(t *testing.T) {
+	pod := func(name, runID string) *v1.Pod {
+		labels := map[string]string{}
+		if runID != "" {
+			labels["skaffold-run-id"] = runID
+		}
+		return &v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: "ns", Labels: labels}}
+	}
+
+	defer func(c func() (clientgo.Interface, error)) { kubernetes.Client = c }(kubernetes.Client)
+	kubernetes.Client = func() (clientgo.Interface, error) {
+		return fake.NewSimpleClientset(pod("this-run", "run1"), pod("other-run", "run2"), pod("not-adopted", "")), nil
+	}
+
+	pods := newPodCache(&config.SkaffoldOptions{Namespace: "ns", RunID: "run1"})
+	defer pods.Stop()
+
+	listed, err := pods.Pods()
+	testutil.CheckErrorAndDeepEqual(t, false, err, []v1.Pod{*pod("this-run", "run1")}, listed)
+
+	opts := &config.SkaffoldOptions{Namespace: "ns"}
+	newPodCache(opts).Stop()
+	if opts.RunID == "" {
+		t.Error("expected a run ID")
+	}
+}
+
+func TestResetPods(t *testing.T) {
+	syncer := &kubectl.Syncer{}
+	runner := &SkaffoldRunner{
+		Syncer: syncer,
+		opts:   &config.SkaffoldOptions{Namespace: "ns"},
+		config: &latest.SkaffoldPipeline{},
+	}
+
+	runner.ResetPods()
+	stopped := runner.pods
+	stopped.Stop()
+	runner.ResetPods()
+
+	if runner.pods == stopped {
+		t.Fatal("expected a new pod cache")
+	}
+	if syncer.Pods != runner.pods {
+		t.Error("expected the syncer to use the new pod cache")
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"io"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sbom"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
+)
+
+// WithSBOM creates a builder that generates the software bill of materials of the built images.
+func WithSBOM(b build.Builder, cfg *latest.SBOMConfig) build.Builder {
+	if cfg == nil {
+		return b
+	}
+
+	return builderWithSBOM{
+		Builder:   b,
+		generator: sbom.NewGenerator(cfg),
+	}
+}
+
+type builderWithSBOM struct {
+	build.Builder
+	generator *sbom.Generator
+}
+
+func (b builderWithSBOM) Build(ctx context.Context, out io.Writer, tagger tag.Tagger, artifacts []*latest.Artifact) ([]build.Artifact, error) {
+	bRes, err := b.Builder.Build(ctx, out, tagger, artifacts)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range bRes {
+		sbom, err := b.generator.Generate(ctx, out, bRes[i])
+		if err != nil {
+			return nil, errors.Wrap(err, "generating SBOMs")
+		}
+		bRes[i].SBOM = sbom
+	}
+
+	return bRes, nil
+}
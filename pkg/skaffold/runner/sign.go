@@ -0,0 +1,89 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"io"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sign"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// WithSigning creates a builder that signs the built images and, if the configuration
+// asks for it, a deployer that verifies their signatures before deploying them.
+// Signatures are stored next to the images in their registry, so nothing is
+// signed or verified if the images are not pushed.
+func WithSigning(b build.Builder, d deploy.Deployer, cfg *latest.SignConfig, pushImages bool) (build.Builder, deploy.Deployer) {
+	if cfg == nil {
+		return b, d
+	}
+	if !pushImages {
+		logrus.Warnln("Skipping image signing: images are not pushed")
+		return b, d
+	}
+
+	signer := sign.NewSigner(cfg)
+	b = builderWithSigning{Builder: b, signer: signer}
+	if cfg.Verify {
+		d = deployerWithVerification{Deployer: d, signer: signer}
+	}
+
+	return b, d
+}
+
+type builderWithSigning struct {
+	build.Builder
+	signer *sign.Signer
+}
+
+func (b builderWithSigning) Build(ctx context.Context, out io.Writer, tagger tag.Tagger, artifacts []*latest.Artifact) ([]build.Artifact, error) {
+	bRes, err := b.Builder.Build(ctx, out, tagger, artifacts)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range bRes {
+		signature, err := b.signer.Sign(ctx, out, bRes[i])
+		if err != nil {
+			return nil, errors.Wrap(err, "signing images")
+		}
+		bRes[i].Signature = signature
+	}
+
+	return bRes, nil
+}
+
+type deployerWithVerification struct {
+	deploy.Deployer
+	signer *sign.Signer
+}
+
+func (d deployerWithVerification) Deploy(ctx context.Context, out io.Writer, builds []build.Artifact) ([]deploy.Artifact, error) {
+	for _, b := range builds {
+		if err := d.signer.Verify(ctx, out, b); err != nil {
+			return nil, errors.Wrap(err, "verifying signatures")
+		}
+	}
+
+	return d.Deployer.Deploy(ctx, out, builds)
+}
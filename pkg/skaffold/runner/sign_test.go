@@ -0,0 +1,81 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+const signedImage = "gcr.io/project/app@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+// pushingBuilder builds images that are already pushed, with their digest.
+type pushingBuilder struct {
+	TestBuilder
+}
+
+func (b *pushingBuilder) Build(ctx context.Context, out io.Writer, tagger tag.Tagger, artifacts []*latest.Artifact) ([]build.Artifact, error) {
+	var builds []build.Artifact
+	for _, artifact := range artifacts {
+		builds = append(builds, build.Artifact{ImageName: artifact.ImageName, Tag: signedImage})
+	}
+	return builds, nil
+}
+
+func TestWithSigning(t *testing.T) {
+	var tests = []struct {
+		description       string
+		pushImages        bool
+		expectedCommands  []string
+		expectedSignature string
+	}{
+		{
+			description:       "images pushed",
+			pushImages:        true,
+			expectedCommands:  []string{"cosign sign --yes --key cosign.key " + signedImage, "cosign verify --key cosign.pub " + signedImage},
+			expectedSignature: "gcr.io/project/app:sha256-0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef.sig",
+		},
+		{
+			description: "images not pushed",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			recorder := &recordingAWS{}
+			defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+			util.DefaultExecCommand = recorder
+
+			cfg := &latest.SignConfig{Key: "cosign.key", PublicKey: "cosign.pub", Verify: true}
+			builder, deployer := WithSigning(&pushingBuilder{}, &TestDeployer{}, cfg, test.pushImages)
+
+			bRes, err := builder.Build(context.Background(), ioutil.Discard, nil, []*latest.Artifact{{ImageName: "gcr.io/project/app"}})
+			testutil.CheckErrorAndDeepEqual(t, false, err, test.expectedSignature, bRes[0].Signature)
+
+			_, err = deployer.Deploy(context.Background(), ioutil.Discard, bRes)
+			testutil.CheckErrorAndDeepEqual(t, false, err, test.expectedCommands, recorder.commands)
+		})
+	}
+}
@@ -0,0 +1,339 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// for testing
+var (
+	stateDir = defaultStateDir
+	// imageExists looks for pushed images in their registry and for the others in the local daemon.
+	imageExists = func(tag string, pushed bool) bool {
+		if pushed {
+			_, err := docker.RemoteDigest(tag)
+			return err == nil
+		}
+		_, err := docker.RetrieveImage(tag)
+		return err == nil
+	}
+)
+
+// fileHashes caches the hashes of the dependency files, by modification time and size,
+// so that computing the keys of the artifacts only reads the modified files again.
+var fileHashes = struct {
+	sync.Mutex
+	entries map[string]fileHash
+}{entries: map[string]fileHash{}}
+
+type fileHash struct {
+	modTime int64
+	size    int64
+	hash    []byte
+}
+
+// devState is what `skaffold dev` remembers from one session to the next,
+// for a given project, kubeContext and namespace.
+type devState struct {
+	KubeContext string                   `json:"kubeContext"`
+	Namespace   string                   `json:"namespace,omitempty"`
+	Artifacts   map[string]artifactState `json:"artifacts"`
+
+	path    string
+	lock    sync.Mutex
+	pending map[string]artifactState
+}
+
+// artifactState is the last known-good build of an artifact.
+type artifactState struct {
+	Key string `json:"key"`
+	Tag string `json:"tag"`
+}
+
+func defaultStateDir() (string, error) {
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", errors.Wrap(err, "retrieving home directory")
+	}
+	return filepath.Join(home, ".skaffold", "state"), nil
+}
+
+// loadDevState reads the state saved by a previous session.
+// An empty state is returned if there's none.
+func loadDevState(configFiles []string, kubeContext, namespace string) *devState {
+	state := &devState{
+		KubeContext: kubeContext,
+		Namespace:   namespace,
+		Artifacts:   map[string]artifactState{},
+		pending:     map[string]artifactState{},
+	}
+
+	dir, err := stateDir()
+	if err != nil {
+		logrus.Debugln("Not persisting dev state:", err)
+		return state
+	}
+
+	var key string
+	for _, configFile := range configFiles {
+		absConfigFile, err := filepath.Abs(configFile)
+		if err != nil {
+			absConfigFile = configFile
+		}
+		key += absConfigFile + "\x00"
+	}
+	id := sha256.Sum256([]byte(key + kubeContext + "\x00" + namespace))
+	state.path = filepath.Join(dir, hex.EncodeToString(id[:])+".json")
+
+	buf, err := ioutil.ReadFile(state.path)
+	if err != nil {
+		return state
+	}
+
+	var previous devState
+	if err := json.Unmarshal(buf, &previous); err != nil {
+		logrus.Debugln("Ignoring invalid dev state:", err)
+		return state
+	}
+	for name, a := range previous.Artifacts {
+		state.Artifacts[name] = a
+	}
+
+	return state
+}
+
+// save writes the state to disk.
+func (s *devState) save() error {
+	if s == nil || s.path == "" {
+		return nil
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	buf, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshalling dev state")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return errors.Wrap(err, "creating state directory")
+	}
+
+	return ioutil.WriteFile(s.path, buf, 0644)
+}
+
+// reusable splits the artifacts between those that need to be built and
+// those for which an image built from the same sources is still available,
+// in the registry if images are pushed, or in the local daemon.
+func (s *devState) reusable(keys map[string]string, artifacts []*latest.Artifact, pushed bool) ([]*latest.Artifact, []build.Artifact) {
+	if s == nil {
+		return artifacts, nil
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var toBuild []*latest.Artifact
+	var reused []build.Artifact
+
+	for _, a := range artifacts {
+		previous, present := s.Artifacts[a.ImageName]
+		if !present || keys[a.ImageName] == "" || previous.Key != keys[a.ImageName] || !imageExists(previous.Tag, pushed) {
+			toBuild = append(toBuild, a)
+			continue
+		}
+
+		reused = append(reused, build.Artifact{
+			ImageName: a.ImageName,
+			Tag:       previous.Tag,
+		})
+	}
+
+	return toBuild, reused
+}
+
+// update records the builds for which a key is known. They're kept
+// pending until they're deployed, see commit.
+func (s *devState) update(keys map[string]string, bRes []build.Artifact) {
+	if s == nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, b := range bRes {
+		if key := keys[b.ImageName]; key != "" {
+			s.pending[b.ImageName] = artifactState{Key: key, Tag: b.Tag}
+		}
+	}
+}
+
+// commit records the pending builds that were successfully deployed.
+func (s *devState) commit(deployed []build.Artifact) {
+	if s == nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, b := range deployed {
+		if pending, found := s.pending[b.ImageName]; found && pending.Tag == b.Tag {
+			s.Artifacts[b.ImageName] = pending
+			delete(s.pending, b.ImageName)
+		}
+	}
+}
+
+// artifactKeys computes a key for each artifact that changes whenever
+// its configuration or the content of its dependencies change.
+func artifactKeys(ctx context.Context, artifacts []*latest.Artifact) map[string]string {
+	keys := map[string]string{}
+
+	dependencies.prefetch(ctx, artifacts)
+	for _, a := range artifacts {
+		key, err := artifactKey(ctx, a)
+		if err != nil {
+			logrus.Debugf("Unable to compute the key of %s: %s", a.ImageName, err)
+			continue
+		}
+
+		keys[a.ImageName] = key
+	}
+
+	return keys
+}
+
+func artifactKey(ctx context.Context, a *latest.Artifact) (string, error) {
+	hasher := sha256.New()
+
+	config, err := yaml.Marshal(a)
+	if err != nil {
+		return "", errors.Wrap(err, "marshalling artifact")
+	}
+	hasher.Write(config)
+
+	deps, err := dependencies.forArtifact(ctx, a)
+	if err != nil {
+		return "", errors.Wrap(err, "listing dependencies")
+	}
+	sort.Strings(deps)
+
+	for _, dep := range deps {
+		hash, err := hashFile(dep)
+		if err != nil {
+			return "", err
+		}
+		hasher.Write([]byte(dep))
+		hasher.Write(hash)
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// hashFile hashes the content of a file, unless it wasn't modified since it was last hashed.
+func hashFile(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fileHashes.Lock()
+	cached, found := fileHashes.entries[path]
+	fileHashes.Unlock()
+	if found && cached.modTime == info.ModTime().UnixNano() && cached.size == info.Size() {
+		return cached.hash, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return nil, err
+	}
+	hash := hasher.Sum(nil)
+
+	fileHashes.Lock()
+	fileHashes.entries[path] = fileHash{modTime: info.ModTime().UnixNano(), size: info.Size(), hash: hash}
+	fileHashes.Unlock()
+
+	return hash, nil
+}
+
+// buildWithState builds the artifacts and records their keys, which are only
+// computed for the given artifacts. Unless asked to rebuild everything, images
+// built by a previous session from the same sources are reused.
+func (r *SkaffoldRunner) buildWithState(ctx context.Context, out io.Writer, artifacts []*latest.Artifact, reuse bool) ([]build.Artifact, error) {
+	if r.state == nil {
+		return r.Build(ctx, out, r.Tagger, artifacts)
+	}
+
+	keys := artifactKeys(ctx, artifacts)
+
+	toBuild := artifacts
+	var bRes []build.Artifact
+	if reuse {
+		toBuild, bRes = r.state.reusable(keys, artifacts, r.pushImages)
+		for _, b := range bRes {
+			color.Default.Fprintf(out, "Reusing %s from a previous session\n", b.Tag)
+		}
+	}
+
+	if len(toBuild) > 0 {
+		built, err := r.Build(ctx, out, r.Tagger, toBuild)
+		if err != nil {
+			return nil, err
+		}
+		bRes = append(bRes, built...)
+	}
+
+	r.state.update(keys, bRes)
+	return bRes, nil
+}
+
+// saveState persists the state once the builds are known to be deployable.
+func (r *SkaffoldRunner) saveState(deployed []build.Artifact) {
+	r.state.commit(deployed)
+	if err := r.state.save(); err != nil {
+		logrus.Warnln("Unable to save dev state:", err)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestDevStateRoundTrip(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	defer func(d func() (string, error)) { stateDir = d }(stateDir)
+	stateDir = func() (string, error) { return tmpDir.Root(), nil }
+
+	defer func(e func(string, bool) bool) { imageExists = e }(imageExists)
+	imageExists = func(tag string, pushed bool) bool { return pushed && tag != "gone:v1" }
+
+	artifacts := []*latest.Artifact{{ImageName: "web"}, {ImageName: "api"}, {ImageName: "gone"}}
+	builds := []build.Artifact{
+		{ImageName: "web", Tag: "web:v1"},
+		{ImageName: "api", Tag: "api:v1"},
+		{ImageName: "gone", Tag: "gone:v1"},
+	}
+
+	state := loadDevState([]string{"skaffold.yaml"}, "kubecontext", "ns")
+	state.update(map[string]string{"web": "key1", "api": "key2", "gone": "key3"}, builds)
+	state.commit(builds)
+	testutil.CheckError(t, false, state.save())
+
+	restored := loadDevState([]string{"skaffold.yaml"}, "kubecontext", "ns")
+	toBuild, reused := restored.reusable(map[string]string{"web": "key1", "api": "changed", "gone": "key3"}, artifacts, true)
+
+	testutil.CheckDeepEqual(t, []*latest.Artifact{{ImageName: "api"}, {ImageName: "gone"}}, toBuild)
+	testutil.CheckDeepEqual(t, []build.Artifact{{ImageName: "web", Tag: "web:v1"}}, reused)
+
+	other := loadDevState([]string{"skaffold.yaml"}, "other-context", "ns")
+	toBuild, reused = other.reusable(map[string]string{"web": "key1"}, artifacts, true)
+
+	testutil.CheckDeepEqual(t, artifacts, toBuild)
+	testutil.CheckDeepEqual(t, 0, len(reused))
+
+	// The image was pushed, so it's not expected in the local daemon.
+	toBuild, reused = restored.reusable(map[string]string{"web": "key1"}, artifacts, false)
+
+	testutil.CheckDeepEqual(t, artifacts, toBuild)
+	testutil.CheckDeepEqual(t, 0, len(reused))
+}
+
+func TestDevStateOnlyKeepsDeployedBuilds(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	defer func(d func() (string, error)) { stateDir = d }(stateDir)
+	stateDir = func() (string, error) { return tmpDir.Root(), nil }
+
+	defer func(e func(string, bool) bool) { imageExists = e }(imageExists)
+	imageExists = func(string, bool) bool { return true }
+
+	artifacts := []*latest.Artifact{{ImageName: "web"}, {ImageName: "api"}}
+	keys := map[string]string{"web": "key1", "api": "key2"}
+
+	state := loadDevState([]string{"skaffold.yaml"}, "kubecontext", "ns")
+	state.update(keys, []build.Artifact{{ImageName: "web", Tag: "web:v1"}, {ImageName: "api", Tag: "api:v1"}})
+	// The deploy of api:v1 failed, a newer api:v2 is being deployed.
+	state.update(keys, []build.Artifact{{ImageName: "api", Tag: "api:v2"}})
+	state.commit([]build.Artifact{{ImageName: "web", Tag: "web:v1"}, {ImageName: "api", Tag: "api:v1"}})
+	testutil.CheckError(t, false, state.save())
+
+	restored := loadDevState([]string{"skaffold.yaml"}, "kubecontext", "ns")
+	toBuild, reused := restored.reusable(keys, artifacts, false)
+
+	testutil.CheckDeepEqual(t, []*latest.Artifact{{ImageName: "api"}}, toBuild)
+	testutil.CheckDeepEqual(t, []build.Artifact{{ImageName: "web", Tag: "web:v1"}}, reused)
+}
+
+func TestArtifactKey(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	tmpDir.Write("Dockerfile", "FROM scratch\nCOPY file .\n").Write("file", "content")
+	artifact := &latest.Artifact{
+		ImageName: "image",
+		Workspace: tmpDir.Root(),
+		ArtifactType: latest.ArtifactType{
+			DockerArtifact: &latest.DockerArtifact{DockerfilePath: "Dockerfile"},
+		},
+	}
+
+	key, err := artifactKey(context.Background(), artifact)
+	testutil.CheckError(t, false, err)
+
+	sameKey, err := artifactKey(context.Background(), artifact)
+	testutil.CheckErrorAndDeepEqual(t, false, err, key, sameKey)
+
+	tmpDir.Write("file", "changed")
+	changedKey, err := artifactKey(context.Background(), artifact)
+	testutil.CheckError(t, false, err)
+	if changedKey == key {
+		t.Error("Expected the key to change with the content of the dependencies")
+	}
+}
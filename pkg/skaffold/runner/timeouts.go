@@ -0,0 +1,118 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
+)
+
+// timeouts holds how long each phase is allowed to take.
+// A zero duration means no timeout.
+type timeouts struct {
+	build       time.Duration
+	deploy      time.Duration
+	statusCheck time.Duration
+	jobs        time.Duration
+}
+
+// getTimeouts reads the timeouts from the configuration.
+// Command line flags take precedence.
+func getTimeouts(opts *config.SkaffoldOptions, cfg *latest.SkaffoldPipeline) (timeouts, error) {
+	var t timeouts
+	var err error
+
+	if t.build, err = timeout(opts.BuildTimeout, cfg.Build.Timeout, 0); err != nil {
+		return t, errors.Wrap(err, "parsing build timeout")
+	}
+	if t.deploy, err = timeout(opts.DeployTimeout, cfg.Deploy.Timeout, 0); err != nil {
+		return t, errors.Wrap(err, "parsing deploy timeout")
+	}
+	if t.statusCheck, err = timeout(opts.StatusCheckTimeout, cfg.Deploy.StatusCheckTimeout, constants.DefaultStatusCheckTimeout); err != nil {
+		return t, errors.Wrap(err, "parsing status check timeout")
+	}
+	if t.jobs, err = timeout(0, cfg.Deploy.JobTimeout, constants.DefaultJobTimeout); err != nil {
+		return t, errors.Wrap(err, "parsing job timeout")
+	}
+
+	return t, nil
+}
+
+func timeout(flag time.Duration, value string, defaultValue time.Duration) (time.Duration, error) {
+	if flag != 0 {
+		return flag, nil
+	}
+	if value == "" {
+		return defaultValue, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// WithTimeouts creates a builder and a deployer that give up after a given duration.
+func WithTimeouts(b build.Builder, d deploy.Deployer, buildTimeout, deployTimeout time.Duration) (build.Builder, deploy.Deployer) {
+	if buildTimeout > 0 {
+		b = builderWithTimeout{Builder: b, timeout: buildTimeout}
+	}
+	if deployTimeout > 0 {
+		d = deployerWithTimeout{Deployer: d, timeout: deployTimeout}
+	}
+
+	return b, d
+}
+
+type builderWithTimeout struct {
+	build.Builder
+	timeout time.Duration
+}
+
+func (b builderWithTimeout) Build(ctx context.Context, out io.Writer, tagger tag.Tagger, artifacts []*latest.Artifact) ([]build.Artifact, error) {
+	ctx, cancel := context.WithTimeout(ctx, b.timeout)
+	defer cancel()
+
+	bRes, err := b.Builder.Build(ctx, out, tagger, artifacts)
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, errors.Errorf("build timed out after %v", b.timeout)
+	}
+
+	return bRes, err
+}
+
+type deployerWithTimeout struct {
+	deploy.Deployer
+	timeout time.Duration
+}
+
+func (d deployerWithTimeout) Deploy(ctx context.Context, out io.Writer, builds []build.Artifact) ([]deploy.Artifact, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	dRes, err := d.Deployer.Deploy(ctx, out, builds)
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, errors.Errorf("deploy timed out after %v", d.timeout)
+	}
+
+	return dRes, err
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestGetTimeouts(t *testing.T) {
+	var tests = []struct {
+		description string
+		opts        *config.SkaffoldOptions
+		cfg         *latest.SkaffoldPipeline
+		expected    timeouts
+		shouldErr   bool
+	}{
+		{
+			description: "defaults",
+			opts:        &config.SkaffoldOptions{},
+			cfg:         &latest.SkaffoldPipeline{},
+			expected:    timeouts{statusCheck: constants.DefaultStatusCheckTimeout, jobs: constants.DefaultJobTimeout},
+		},
+		{
+			description: "from config",
+			opts:        &config.SkaffoldOptions{},
+			cfg: &latest.SkaffoldPipeline{
+				Build:  latest.BuildConfig{Timeout: "10m"},
+				Deploy: latest.DeployConfig{Timeout: "1m", StatusCheckTimeout: "30s", JobTimeout: "20m"},
+			},
+			expected: timeouts{build: 10 * time.Minute, deploy: time.Minute, statusCheck: 30 * time.Second, jobs: 20 * time.Minute},
+		},
+		{
+			description: "flags override config",
+			opts:        &config.SkaffoldOptions{BuildTimeout: 5 * time.Minute},
+			cfg: &latest.SkaffoldPipeline{
+				Build: latest.BuildConfig{Timeout: "10m"},
+			},
+			expected: timeouts{build: 5 * time.Minute, statusCheck: constants.DefaultStatusCheckTimeout, jobs: constants.DefaultJobTimeout},
+		},
+		{
+			description: "invalid duration",
+			opts:        &config.SkaffoldOptions{},
+			cfg: &latest.SkaffoldPipeline{
+				Deploy: latest.DeployConfig{Timeout: "forever"},
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			timeouts, err := getTimeouts(test.opts, test.cfg)
+
+			testutil.CheckError(t, test.shouldErr, err)
+			if !test.shouldErr && timeouts != test.expected {
+				t.Errorf("Expected %+v. Got %+v", test.expected, timeouts)
+			}
+		})
+	}
+}
+
+type hangingBuilder struct {
+	TestBuilder
+}
+
+func (b *hangingBuilder) Build(ctx context.Context, w io.Writer, tagger tag.Tagger, artifacts []*latest.Artifact) ([]build.Artifact, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestBuildTimeout(t *testing.T) {
+	builder, _ := WithTimeouts(&hangingBuilder{}, &TestDeployer{}, 10*time.Millisecond, 0)
+
+	_, err := builder.Build(context.Background(), &bytes.Buffer{}, nil, nil)
+
+	testutil.CheckErrorAndDeepEqual(t, true, err, "build timed out after 10ms", err.Error())
+}
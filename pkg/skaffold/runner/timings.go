@@ -18,7 +18,10 @@ package runner
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"sync"
+	"text/tabwriter"
 	"time"
 
 	"k8s.io/apimachinery/pkg/labels"
@@ -27,11 +30,13 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/test"
 )
 
-// WithTimings creates a deployer that logs the duration of each phase.
+// WithTimings creates a deployer that logs the duration of each phase
+// and reports its start and end as events.
 func WithTimings(b build.Builder, t test.Tester, d deploy.Deployer) (build.Builder, test.Tester, deploy.Deployer) {
 	w := withTimings{
 		Builder:  b,
@@ -56,7 +61,9 @@ func (w withTimings) Build(ctx context.Context, out io.Writer, tagger tag.Tagger
 	start := time.Now()
 	color.Default.Fprintln(out, "Starting build...")
 
+	end := event.Start(event.Build, "")
 	bRes, err := w.Builder.Build(ctx, out, tagger, artifacts)
+	end("", err)
 	if err != nil {
 		return nil, err
 	}
@@ -69,7 +76,9 @@ func (w withTimings) Test(ctx context.Context, out io.Writer, builds []build.Art
 	start := time.Now()
 	color.Default.Fprintln(out, "Starting test...")
 
+	end := event.Start(event.Test, "")
 	err := w.Tester.Test(ctx, out, builds)
+	end("", err)
 	if err != nil {
 		return err
 	}
@@ -78,11 +87,28 @@ func (w withTimings) Test(ctx context.Context, out io.Writer, builds []build.Art
 	return nil
 }
 
+func (w withTimings) Verify(ctx context.Context, out io.Writer, builds []build.Artifact) error {
+	start := time.Now()
+	color.Default.Fprintln(out, "Starting verify...")
+
+	end := event.Start(event.Verify, "")
+	err := w.Tester.Verify(ctx, out, builds)
+	end("", err)
+	if err != nil {
+		return err
+	}
+
+	color.Default.Fprintln(out, "Verify complete in", time.Since(start))
+	return nil
+}
+
 func (w withTimings) Deploy(ctx context.Context, out io.Writer, builds []build.Artifact) ([]deploy.Artifact, error) {
 	start := time.Now()
 	color.Default.Fprintln(out, "Starting deploy...")
 
+	end := event.Start(event.Deploy, "")
 	dRes, err := w.Deployer.Deploy(ctx, out, builds)
+	end("", err)
 	if err != nil {
 		return nil, err
 	}
@@ -103,3 +129,58 @@ func (w withTimings) Cleanup(ctx context.Context, out io.Writer) error {
 	color.Default.Fprintln(out, "Cleanup complete in", time.Since(start))
 	return nil
 }
+
+// for testing
+var now = time.Now
+
+// timingSummary collects the timings reported by the events of a run,
+// to print them at the end.
+type timingSummary struct {
+	start   time.Time
+	stop    func()
+	lock    sync.Mutex
+	timings []event.Timing
+}
+
+func startTimingSummary() *timingSummary {
+	s := &timingSummary{start: now()}
+	s.stop = event.AddListener(s.record)
+	return s
+}
+
+func (s *timingSummary) record(e event.Event) {
+	if e.Status == event.Started || e.Phase == event.Summary {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.timings = append(s.timings, event.Timing{
+		Phase:      e.Phase,
+		Artifact:   e.Artifact,
+		DurationMs: e.DurationMs,
+	})
+}
+
+// print stops collecting the timings, prints them with
+// the total duration of the run and reports them as an event.
+func (s *timingSummary) print(out io.Writer) {
+	s.stop()
+
+	s.lock.Lock()
+	timings := append(s.timings, event.Timing{
+		Phase:      "total",
+		DurationMs: now().Sub(s.start).Nanoseconds() / int64(time.Millisecond),
+	})
+	s.lock.Unlock()
+
+	event.SendSummary(timings)
+
+	color.Default.Fprintln(out, "Timings:")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, t := range timings {
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", t.Phase, t.Artifact, time.Duration(t.DurationMs)*time.Millisecond)
+	}
+	w.Flush()
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestTimingSummary(t *testing.T) {
+	defer func(n func() time.Time) { now = n }(now)
+	start := time.Now()
+	now = func() time.Time { return start }
+
+	summary := startTimingSummary()
+	defer summary.stop()
+
+	summary.record(event.Event{Phase: event.Build, Status: event.Started, Artifact: "image"})
+	summary.record(event.Event{Phase: event.Build, Status: event.Complete, Artifact: "image", DurationMs: 12300})
+	summary.record(event.Event{Phase: event.Push, Status: event.Complete, Artifact: "image", DurationMs: 3100})
+	summary.record(event.Event{Phase: event.Deploy, Status: event.Failed, DurationMs: 2000})
+	summary.record(event.Event{Phase: event.Summary, Status: event.Complete})
+
+	var timings []event.Timing
+	stop := event.AddListener(func(e event.Event) {
+		timings = e.Timings
+	})
+	defer stop()
+
+	now = func() time.Time { return start.Add(20 * time.Second) }
+	out := new(bytes.Buffer)
+	summary.print(out)
+
+	testutil.CheckDeepEqual(t, `Timings:
+  build   image  12.3s
+  push    image  3.1s
+  deploy         2s
+  total          20s
+`, out.String())
+	testutil.CheckDeepEqual(t, []event.Timing{
+		{Phase: "build", Artifact: "image", DurationMs: 12300},
+		{Phase: "push", Artifact: "image", DurationMs: 3100},
+		{Phase: "deploy", DurationMs: 2000},
+		{Phase: "total", DurationMs: 20000},
+	}, timings)
+}
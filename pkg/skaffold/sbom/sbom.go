@@ -0,0 +1,113 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sbom
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sign"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+)
+
+var digestReference = sign.DigestReference // for testing
+
+// Generator produces the software bill of materials of built images with syft.
+type Generator struct {
+	cfg *latest.SBOMConfig
+}
+
+// NewGenerator creates a Generator with the given settings.
+func NewGenerator(cfg *latest.SBOMConfig) *Generator {
+	return &Generator{cfg: cfg}
+}
+
+// Generate writes the SBOM of the image of an artifact to the output folder
+// and, if configured to, attaches it to the image.
+func (g *Generator) Generate(ctx context.Context, out io.Writer, artifact build.Artifact) (*build.SBOM, error) {
+	color.Default.Fprintf(out, "Generating the SBOM of %s...\n", artifact.Tag)
+
+	content, err := util.RunCmdOut(exec.CommandContext(ctx, "syft", artifact.Tag, "--quiet", "--output", g.cfg.Format))
+	if err != nil {
+		return nil, errors.Wrapf(err, "generating the SBOM of %s", artifact.Tag)
+	}
+
+	if err := os.MkdirAll(g.cfg.OutputDir, 0755); err != nil {
+		return nil, errors.Wrap(err, "creating SBOM folder")
+	}
+
+	file := filepath.Join(g.cfg.OutputDir, fileName(artifact.ImageName, g.cfg.Format))
+	if err := ioutil.WriteFile(file, content, 0644); err != nil {
+		return nil, errors.Wrapf(err, "writing SBOM to %s", file)
+	}
+
+	sbom := &build.SBOM{
+		Format: g.cfg.Format,
+		File:   file,
+	}
+
+	if g.cfg.Attach {
+		if sbom.Reference, err = g.attach(ctx, out, artifact.Tag, file); err != nil {
+			return nil, err
+		}
+	}
+
+	return sbom, nil
+}
+
+// attach uploads the SBOM next to the image, where cosign can find it.
+func (g *Generator) attach(ctx context.Context, out io.Writer, tag, file string) (string, error) {
+	digest, err := digestReference(tag)
+	if err != nil {
+		return "", err
+	}
+
+	color.Default.Fprintf(out, "Attaching the SBOM to %s...\n", digest)
+
+	cmd := exec.CommandContext(ctx, "cosign", "attach", "sbom", "--sbom", file, "--type", attachmentType(g.cfg.Format), digest.String())
+	if _, err := util.RunCmdOut(cmd); err != nil {
+		return "", errors.Wrapf(err, "attaching the SBOM to %s", digest)
+	}
+
+	return sign.AttachmentReference(digest, "sbom"), nil
+}
+
+// fileName turns an image name into the name of its SBOM file.
+func fileName(imageName, format string) string {
+	name := strings.NewReplacer("/", "_", ":", "_").Replace(imageName)
+	if format == "cyclonedx-json" {
+		return name + ".cdx.json"
+	}
+	return name + ".spdx.json"
+}
+
+// attachmentType is the cosign name of an SBOM format.
+func attachmentType(format string) string {
+	if format == "cyclonedx-json" {
+		return "cyclonedx"
+	}
+	return "spdx"
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sbom
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+const digest = "gcr.io/project/image@sha256:27ffc7f352665cc50ae3cbcc4b2725e36062f1b38c611b6f95d6df9a7510de23"
+
+func TestGenerate(t *testing.T) {
+	var tests = []struct {
+		description  string
+		format       string
+		expectedCmd  string
+		expectedFile string
+	}{
+		{
+			description:  "spdx",
+			format:       "spdx-json",
+			expectedCmd:  "syft gcr.io/project/image:v1 --quiet --output spdx-json",
+			expectedFile: "gcr.io_project_image.spdx.json",
+		},
+		{
+			description:  "cyclonedx",
+			format:       "cyclonedx-json",
+			expectedCmd:  "syft gcr.io/project/image:v1 --quiet --output cyclonedx-json",
+			expectedFile: "gcr.io_project_image.cdx.json",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			tmpDir, cleanup := testutil.NewTempDir(t)
+			defer cleanup()
+			defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+			util.DefaultExecCommand = testutil.NewFakeCmdOut(test.expectedCmd, "{}", nil)
+
+			cfg := &latest.SBOMConfig{Format: test.format, OutputDir: tmpDir.Root()}
+			sbom, err := NewGenerator(cfg).Generate(context.Background(), ioutil.Discard, build.Artifact{
+				ImageName: "gcr.io/project/image",
+				Tag:       "gcr.io/project/image:v1",
+			})
+
+			file := filepath.Join(tmpDir.Root(), test.expectedFile)
+			testutil.CheckErrorAndDeepEqual(t, false, err, &build.SBOM{Format: test.format, File: file}, sbom)
+			content, err := ioutil.ReadFile(file)
+			testutil.CheckErrorAndDeepEqual(t, false, err, "{}", string(content))
+		})
+	}
+}
+
+func TestAttach(t *testing.T) {
+	defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+	util.DefaultExecCommand = testutil.NewFakeCmdOut("cosign attach sbom --sbom sbom.cdx.json --type cyclonedx "+digest, "", nil)
+	defer func(f func(string) (name.Digest, error)) { digestReference = f }(digestReference)
+	digestReference = func(string) (name.Digest, error) { return name.NewDigest(digest, name.WeakValidation) }
+
+	g := NewGenerator(&latest.SBOMConfig{Format: "cyclonedx-json", Attach: true})
+	reference, err := g.attach(context.Background(), ioutil.Discard, "gcr.io/project/image:v1", "sbom.cdx.json")
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, "gcr.io/project/image:sha256-27ffc7f352665cc50ae3cbcc4b2725e36062f1b38c611b6f95d6df9a7510de23.sbom", reference)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/util"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/yamltags"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	warnedMutex sync.Mutex
+	warned      = map[string]bool{}
+
+	// for testing
+	warnf = logrus.Warnf
+)
+
+// warnOnce logs a warning unless the same warning was already logged.
+// Configurations can be parsed more than once per run, for example by the
+// dev loop, and the user doesn't need to be told the same thing twice.
+func warnOnce(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+
+	warnedMutex.Lock()
+	defer warnedMutex.Unlock()
+
+	if warned[message] {
+		return
+	}
+	warned[message] = true
+	warnf("%s", message)
+}
+
+// warnDeprecations warns about the deprecated fields used by a configuration.
+func warnDeprecations(filename string, cfg util.VersionedConfig) {
+	for _, deprecation := range yamltags.Deprecations(cfg) {
+		warnOnce("%s: %s", filename, deprecation)
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestWarnDeprecations(t *testing.T) {
+	var warnings []string
+	defer func(w func(string, ...interface{})) { warnf = w }(warnf)
+	warnf = func(format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+
+	tmp, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	tmp.Write("skaffold.yaml", `apiVersion: skaffold/v1alpha2
+kind: Config
+build:
+  artifacts:
+  - imageName: example
+deploy:
+  kubectl: {}
+`)
+
+	for i := 0; i < 2; i++ {
+		_, err := ParseAndUpgradeConfig(tmp.Path("skaffold.yaml"), true)
+		testutil.CheckError(t, false, err)
+	}
+
+	testutil.CheckDeepEqual(t, []string{
+		tmp.Path("skaffold.yaml") + ": build.artifacts[0].imageName is deprecated: it was replaced by build.artifacts[].image in skaffold/v1alpha4",
+		"Config version skaffold/v1alpha2 is deprecated and was upgraded in memory to skaffold/v1alpha5: run `skaffold fix --overwrite` to upgrade " + tmp.Path("skaffold.yaml"),
+	}, warnings)
+}
+
+// TestDeprecationTags makes sure that the deprecated fields point at fields
+// that exist in the configuration version that replaced them.
+func TestDeprecationTags(t *testing.T) {
+	for _, version := range schemaVersions {
+		for _, tag := range deprecationTags(reflect.TypeOf(version.factory()), map[reflect.Type]bool{}) {
+			deprecation := map[string]string{}
+			for _, part := range strings.Split(tag, ",") {
+				kv := strings.SplitN(part, "=", 2)
+				if len(kv) == 2 {
+					deprecation[kv[0]] = kv[1]
+				}
+			}
+
+			factory, found := schemaVersions.Find(deprecation["removedIn"])
+			if !found {
+				t.Errorf("%s: unknown version in deprecated:%q", version.apiVersion, tag)
+				continue
+			}
+			if replacement := deprecation["replacement"]; replacement != "" && !hasYamlPath(reflect.TypeOf(factory()), replacement) {
+				t.Errorf("%s: unknown replacement in deprecated:%q", version.apiVersion, tag)
+			}
+		}
+	}
+}
+
+func deprecationTags(t reflect.Type, seen map[reflect.Type]bool) []string {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || seen[t] {
+		return nil
+	}
+	seen[t] = true
+
+	var tags []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if tag, ok := field.Tag.Lookup("deprecated"); ok {
+			tags = append(tags, tag)
+		}
+		tags = append(tags, deprecationTags(field.Type, seen)...)
+	}
+	return tags
+}
+
+// hasYamlPath checks a path such as `build.artifacts[].image` against a type.
+func hasYamlPath(t reflect.Type, path string) bool {
+	for _, name := range strings.Split(strings.Replace(path, "[]", "", -1), ".") {
+		var found bool
+		if t, found = yamlField(t, name); !found {
+			return false
+		}
+	}
+	return true
+}
+
+func yamlField(t reflect.Type, name string) (reflect.Type, bool) {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		parts := strings.Split(field.Tag.Get("yaml"), ",")
+		if parts[0] == name {
+			return field.Type, true
+		}
+		if len(parts) > 1 && parts[1] == "inline" {
+			if inlined, found := yamlField(field.Type, name); found {
+				return inlined, true
+			}
+		}
+	}
+	return nil, false
+}
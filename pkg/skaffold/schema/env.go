@@ -0,0 +1,120 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// envReference matches both `${VAR}` and `{{ env "VAR" }}`.
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}|\{\{\s*env\s+"([A-Za-z_][A-Za-z0-9_]*)"\s*\}\}`)
+
+// ExpandEnv replaces the references to environment variables found in
+// every string of the configuration: image names, manifest paths, helm values...
+// Only the allowed variables are expanded, so that a skaffold.yaml can't
+// read arbitrary values from the environment. Other references are left
+// untouched, like the templates evaluated later by the taggers and deployers.
+func ExpandEnv(config *latest.SkaffoldPipeline, allowed []string) error {
+	env := environment()
+
+	allowList := make(map[string]bool)
+	for _, name := range allowed {
+		allowList[name] = true
+	}
+
+	var err error
+	expand := func(value string) string {
+		return envReference.ReplaceAllStringFunc(value, func(reference string) string {
+			groups := envReference.FindStringSubmatch(reference)
+			name := groups[1] + groups[2]
+
+			if !allowList[name] {
+				logrus.Warnf("Not expanding %s in skaffold config: %s is not an allowed environment variable (see --allow-env)", reference, name)
+				return reference
+			}
+
+			value, present := env[name]
+			if !present && err == nil {
+				err = errors.Errorf("environment variable %s is referenced in skaffold config but isn't set", name)
+			}
+			return value
+		})
+	}
+
+	expandStrings(reflect.ValueOf(config), expand)
+	return err
+}
+
+// expandStrings walks a value and rewrites all the strings it contains.
+func expandStrings(v reflect.Value, expand func(string) string) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			expandStrings(v.Elem(), expand)
+		}
+	case reflect.Interface:
+		// Strings stored in an interface{}, like in helm overrides, can't be
+		// changed in place.
+		if !v.IsNil() {
+			if v.Elem().Kind() == reflect.String && v.CanSet() {
+				v.Set(reflect.ValueOf(expand(v.Elem().String())))
+			} else {
+				expandStrings(v.Elem(), expand)
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				expandStrings(v.Field(i), expand)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			expandStrings(v.Index(i), expand)
+		}
+	case reflect.Map:
+		// Map values aren't addressable: expand a copy and store it back.
+		for _, key := range v.MapKeys() {
+			value := reflect.New(v.Type().Elem()).Elem()
+			value.Set(v.MapIndex(key))
+			expandStrings(value, expand)
+			v.SetMapIndex(key, value)
+		}
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(expand(v.String()))
+		}
+	}
+}
+
+func environment() map[string]string {
+	env := make(map[string]string)
+	for _, kv := range util.OSEnviron() {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) == 2 {
+			env[parts[0]] = parts[1]
+		}
+	}
+	return env
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestExpandEnv(t *testing.T) {
+	var tests = []struct {
+		description string
+		allowed     []string
+		config      *latest.SkaffoldPipeline
+		expected    *latest.SkaffoldPipeline
+		shouldErr   bool
+	}{
+		{
+			description: "image name and kaniko bucket",
+			allowed:     []string{"REGISTRY", "BUCKET"},
+			config: &latest.SkaffoldPipeline{
+				Build: latest.BuildConfig{
+					Artifacts: []*latest.Artifact{{ImageName: "${REGISTRY}/app"}},
+					BuildType: latest.BuildType{
+						KanikoBuild: &latest.KanikoBuild{
+							BuildContext: &latest.KanikoBuildContext{GCSBucket: `{{ env "BUCKET" }}`},
+						},
+					},
+				},
+			},
+			expected: &latest.SkaffoldPipeline{
+				Build: latest.BuildConfig{
+					Artifacts: []*latest.Artifact{{ImageName: "gcr.io/me/app"}},
+					BuildType: latest.BuildType{
+						KanikoBuild: &latest.KanikoBuild{
+							BuildContext: &latest.KanikoBuildContext{GCSBucket: "my-bucket"},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "manifests and helm values",
+			allowed:     []string{"ENV"},
+			config: &latest.SkaffoldPipeline{
+				Deploy: latest.DeployConfig{
+					DeployType: latest.DeployType{
+						KubectlDeploy: &latest.KubectlDeploy{Manifests: []string{"k8s/${ENV}/*.yaml"}},
+						HelmDeploy: &latest.HelmDeploy{
+							Releases: []latest.HelmRelease{{
+								Values:    map[string]string{"image": "app"},
+								SetValues: map[string]string{"env": "${ENV}"},
+								Overrides: map[string]interface{}{"env": "{{env \"ENV\"}}"},
+							}},
+						},
+					},
+				},
+			},
+			expected: &latest.SkaffoldPipeline{
+				Deploy: latest.DeployConfig{
+					DeployType: latest.DeployType{
+						KubectlDeploy: &latest.KubectlDeploy{Manifests: []string{"k8s/dev/*.yaml"}},
+						HelmDeploy: &latest.HelmDeploy{
+							Releases: []latest.HelmRelease{{
+								Values:    map[string]string{"image": "app"},
+								SetValues: map[string]string{"env": "dev"},
+								Overrides: map[string]interface{}{"env": "dev"},
+							}},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "not allowed",
+			config: &latest.SkaffoldPipeline{
+				Build: latest.BuildConfig{Artifacts: []*latest.Artifact{{ImageName: "${REGISTRY}/app"}}},
+			},
+			expected: &latest.SkaffoldPipeline{
+				Build: latest.BuildConfig{Artifacts: []*latest.Artifact{{ImageName: "${REGISTRY}/app"}}},
+			},
+		},
+		{
+			description: "other templates are left untouched",
+			allowed:     []string{"ENV"},
+			config: &latest.SkaffoldPipeline{
+				Build: latest.BuildConfig{
+					TagPolicy: latest.TagPolicy{EnvTemplateTagger: &latest.EnvTemplateTagger{Template: "{{.IMAGE_NAME}}:{{.ENV}}"}},
+				},
+			},
+			expected: &latest.SkaffoldPipeline{
+				Build: latest.BuildConfig{
+					TagPolicy: latest.TagPolicy{EnvTemplateTagger: &latest.EnvTemplateTagger{Template: "{{.IMAGE_NAME}}:{{.ENV}}"}},
+				},
+			},
+		},
+		{
+			description: "unset variable",
+			allowed:     []string{"UNSET"},
+			config: &latest.SkaffoldPipeline{
+				Build: latest.BuildConfig{Artifacts: []*latest.Artifact{{ImageName: "${UNSET}/app"}}},
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			defer func(environ func() []string) { util.OSEnviron = environ }(util.OSEnviron)
+			util.OSEnviron = func() []string {
+				return []string{"REGISTRY=gcr.io/me", "BUCKET=my-bucket", "ENV=dev"}
+			}
+
+			err := ExpandEnv(test.config, test.allowed)
+
+			if test.shouldErr {
+				testutil.CheckError(t, true, err)
+			} else {
+				testutil.CheckErrorAndDeepEqual(t, false, err, test.expected, test.config)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var lineError = regexp.MustCompile(`^line (\d+): (.*)$`)
+
+// withYamlPaths rewrites the errors reported by strict unmarshalling so that
+// they show the path to the faulty field along with its line number.
+func withYamlPaths(contents []byte, err error) error {
+	typeErr, ok := errors.Cause(err).(*yaml.TypeError)
+	if !ok {
+		return err
+	}
+
+	lines := strings.Split(string(contents), "\n")
+
+	var messages []string
+	for _, message := range typeErr.Errors {
+		match := lineError.FindStringSubmatch(message)
+		if match == nil {
+			messages = append(messages, message)
+			continue
+		}
+
+		lineNumber, _ := strconv.Atoi(match[1])
+		path := yamlPath(lines, lineNumber)
+		if path == "" {
+			messages = append(messages, message)
+			continue
+		}
+
+		messages = append(messages, fmt.Sprintf("line %d (%s): %s", lineNumber, path, match[2]))
+	}
+
+	return errors.New("invalid skaffold config:\n  " + strings.Join(messages, "\n  "))
+}
+
+// yamlNode is either a key or a list item on the path to a line.
+type yamlNode struct {
+	indent int
+	key    string
+	item   bool
+	index  int
+}
+
+// yamlPath guesses the path to the field defined on a given line (1-based)
+// of a yaml document, like build.artifacts[0].docker.dockerfile.
+// It only relies on indentation which is good enough for block style yaml.
+func yamlPath(lines []string, lineNumber int) string {
+	if lineNumber < 1 || lineNumber > len(lines) {
+		return ""
+	}
+
+	var stack []yamlNode
+	for _, line := range lines[:lineNumber] {
+		content := strings.TrimLeft(line, " ")
+		if content == "" || strings.HasPrefix(content, "#") || content == "---" {
+			continue
+		}
+		indent := len(line) - len(content)
+
+		for strings.HasPrefix(content, "- ") || content == "-" {
+			index := 0
+			for len(stack) > 0 {
+				top := stack[len(stack)-1]
+				if top.indent < indent || (top.indent == indent && !top.item) {
+					break
+				}
+				if top.item && top.indent == indent {
+					index = top.index + 1
+				}
+				stack = stack[:len(stack)-1]
+			}
+			stack = append(stack, yamlNode{indent: indent, item: true, index: index})
+
+			trimmed := strings.TrimLeft(strings.TrimPrefix(content, "-"), " ")
+			indent += len(content) - len(trimmed)
+			content = trimmed
+		}
+
+		key := yamlKey(content)
+		if key == "" {
+			continue
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		stack = append(stack, yamlNode{indent: indent, key: key})
+	}
+
+	var path string
+	for _, node := range stack {
+		switch {
+		case node.item:
+			path += fmt.Sprintf("[%d]", node.index)
+		case path == "":
+			path = node.key
+		default:
+			path += "." + node.key
+		}
+	}
+
+	return path
+}
+
+// yamlKey returns the key defined by a line of yaml, if any.
+func yamlKey(content string) string {
+	i := strings.Index(content, ":")
+	if i <= 0 {
+		return ""
+	}
+	if i+1 < len(content) && content[i+1] != ' ' {
+		return ""
+	}
+
+	return strings.Trim(content[:i], `"'`)
+}
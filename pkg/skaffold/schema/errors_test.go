@@ -0,0 +1,71 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+const configWithErrors = `apiVersion: skaffold/v1alpha5
+kind: Config
+build:
+  artifacts:
+  - image: first
+  - image: second
+    docker:
+      dockerfilePath: Dockerfile.dev
+      buildArgs: 3
+deploy:
+  kubectl:
+    manifests: k8s/*.yaml
+`
+
+func TestYamlPath(t *testing.T) {
+	lines := strings.Split(configWithErrors, "\n")
+
+	var tests = []struct {
+		line     int
+		expected string
+	}{
+		{1, "apiVersion"},
+		{4, "build.artifacts"},
+		{5, "build.artifacts[0].image"},
+		{6, "build.artifacts[1].image"},
+		{8, "build.artifacts[1].docker.dockerfilePath"},
+		{9, "build.artifacts[1].docker.buildArgs"},
+		{12, "deploy.kubectl.manifests"},
+		{100, ""},
+	}
+	for _, test := range tests {
+		testutil.CheckDeepEqual(t, test.expected, yamlPath(lines, test.line))
+	}
+}
+
+func TestWithYamlPaths(t *testing.T) {
+	err := latest.NewSkaffoldPipeline().Parse([]byte(configWithErrors), false)
+
+	annotated := withYamlPaths([]byte(configWithErrors), err)
+
+	testutil.CheckDeepEqual(t, `invalid skaffold config:
+  line 8 (build.artifacts[1].docker.dockerfilePath): field dockerfilePath not found in type latest.DockerArtifact
+  line 9 (build.artifacts[1].docker.buildArgs): cannot unmarshal !!int `+"`3`"+` into map[string]*string
+  line 12 (deploy.kubectl.manifests): cannot unmarshal !!str `+"`k8s/*.yaml`"+` into []string`, annotated.Error())
+}
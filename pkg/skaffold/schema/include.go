@@ -0,0 +1,153 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+
+	misc "github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// includeKey lists the files a configuration is based on.
+const includeKey = "configsFrom"
+
+// resolveIncludes merges the files listed under `configsFrom` into a
+// configuration, so that common settings can be shared by several configurations.
+// Values set by the configuration take precedence over the included ones.
+// Maps are merged recursively while lists and other values are replaced.
+func resolveIncludes(filename string, buf []byte) ([]byte, error) {
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(buf, &doc); err != nil {
+		// let the schema parsing report the error
+		return buf, nil
+	}
+
+	doc, included, err := readIncludes(filename, buf, map[string]bool{filename: true})
+	if err != nil || !included {
+		return buf, err
+	}
+
+	merged, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling merged config")
+	}
+	return merged, nil
+}
+
+func readIncludes(filename string, buf []byte, seen map[string]bool) (yaml.MapSlice, bool, error) {
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(buf, &doc); err != nil {
+		return nil, false, errors.Wrapf(err, "parsing %s", filename)
+	}
+
+	var includes []string
+	var rest yaml.MapSlice
+	for _, item := range doc {
+		if item.Key != includeKey {
+			rest = append(rest, item)
+			continue
+		}
+
+		list, ok := item.Value.([]interface{})
+		if !ok {
+			return nil, false, errors.Errorf("%s in %s should be a list of files", includeKey, filename)
+		}
+		for _, include := range list {
+			file, ok := include.(string)
+			if !ok {
+				return nil, false, errors.Errorf("%s in %s should be a list of files", includeKey, filename)
+			}
+			includes = append(includes, file)
+		}
+	}
+
+	if len(includes) == 0 {
+		return doc, false, nil
+	}
+
+	var base yaml.MapSlice
+	for _, include := range includes {
+		includePath := relativeTo(filename, include)
+		if seen[includePath] {
+			return nil, false, errors.Errorf("%s includes itself", includePath)
+		}
+
+		includeBuf, err := misc.ReadConfiguration(includePath)
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "reading %s included by %s", includePath, filename)
+		}
+
+		seen[includePath] = true
+		included, _, err := readIncludes(includePath, includeBuf, seen)
+		delete(seen, includePath)
+		if err != nil {
+			return nil, false, err
+		}
+
+		base = mergeMapSlices(base, included)
+	}
+
+	return mergeMapSlices(base, rest), true, nil
+}
+
+// relativeTo resolves an included file relative to the file including it.
+func relativeTo(filename, include string) string {
+	if filepath.IsAbs(include) || strings.HasPrefix(include, "http://") || strings.HasPrefix(include, "https://") {
+		return include
+	}
+
+	if u, err := url.Parse(filename); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		u.Path = path.Join(path.Dir(u.Path), include)
+		return u.String()
+	}
+	return filepath.Join(filepath.Dir(filename), include)
+}
+
+// mergeMapSlices merges two yaml maps, the values of the second taking precedence.
+func mergeMapSlices(base, override yaml.MapSlice) yaml.MapSlice {
+	merged := append(yaml.MapSlice(nil), base...)
+
+	for _, item := range override {
+		found := false
+		for i := range merged {
+			if merged[i].Key != item.Key {
+				continue
+			}
+
+			baseMap, baseIsMap := merged[i].Value.(yaml.MapSlice)
+			overrideMap, overrideIsMap := item.Value.(yaml.MapSlice)
+			if baseIsMap && overrideIsMap {
+				merged[i].Value = mergeMapSlices(baseMap, overrideMap)
+			} else {
+				merged[i].Value = item.Value
+			}
+			found = true
+			break
+		}
+
+		if !found {
+			merged = append(merged, item)
+		}
+	}
+
+	return merged
+}
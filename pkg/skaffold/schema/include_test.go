@@ -0,0 +1,113 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestParseConfigWithIncludes(t *testing.T) {
+	cleanup := testutil.SetupFakeKubernetesContext(t, api.Config{CurrentContext: "cluster1"})
+	defer cleanup()
+
+	var tests = []struct {
+		description string
+		files       map[string]string
+		expected    *latest.SkaffoldPipeline
+		shouldErr   bool
+	}{
+		{
+			description: "shared build settings",
+			files: map[string]string{
+				"common/build.yaml": `build:
+  tagPolicy:
+    sha256: {}
+  timeout: 5m
+deploy:
+  kubectl:
+    manifests:
+    - common/*.yaml
+`,
+				"service/skaffold.yaml": fmt.Sprintf(`apiVersion: %s
+kind: Config
+configsFrom:
+- ../common/build.yaml
+build:
+  artifacts:
+  - image: example
+  timeout: 10m
+deploy:
+  kubectl:
+    manifests:
+    - k8s/*.yaml
+`, latest.Version),
+			},
+			expected: config(
+				withLocalBuild(
+					withShaTagger(),
+					withDockerArtifact("example", ".", "Dockerfile"),
+					withBuildTimeout("10m"),
+				),
+				withKubectlDeploy("k8s/*.yaml"),
+			),
+		},
+		{
+			description: "include cycle",
+			files: map[string]string{
+				"common/build.yaml": "configsFrom:\n- ../service/skaffold.yaml\n",
+				"service/skaffold.yaml": fmt.Sprintf(`apiVersion: %s
+kind: Config
+configsFrom:
+- ../common/build.yaml
+`, latest.Version),
+			},
+			shouldErr: true,
+		},
+		{
+			description: "missing include",
+			files: map[string]string{
+				"service/skaffold.yaml": fmt.Sprintf(`apiVersion: %s
+kind: Config
+configsFrom:
+- ../common/build.yaml
+`, latest.Version),
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			tmp, cleanup := testutil.NewTempDir(t)
+			defer cleanup()
+
+			tmp.Mkdir("common").Mkdir("service")
+			for path, contents := range test.files {
+				tmp.Write(path, contents)
+			}
+
+			cfg, err := ParseAndUpgradeConfig(tmp.Path("service/skaffold.yaml"), true)
+
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, cfg)
+		})
+	}
+}
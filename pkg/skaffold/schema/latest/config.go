@@ -36,6 +36,9 @@ type SkaffoldPipeline struct {
 	Build    BuildConfig  `yaml:"build,omitempty"`
 	Test     TestConfig   `yaml:"test,omitempty"`
 	Deploy   DeployConfig `yaml:"deploy,omitempty"`
+	Verify   VerifyConfig `yaml:"verify,omitempty"`
+	Scan     *ScanConfig  `yaml:"scan,omitempty"`
+	Notify   NotifyConfig `yaml:"notify,omitempty"`
 	Profiles []Profile    `yaml:"profiles,omitempty"`
 }
 
@@ -47,9 +50,62 @@ func (c *SkaffoldPipeline) GetVersion() string {
 type BuildConfig struct {
 	Artifacts []*Artifact `yaml:"artifacts,omitempty"`
 	TagPolicy TagPolicy   `yaml:"tagPolicy,omitempty"`
+	Timeout   string      `yaml:"timeout,omitempty"`
+	ECR       *ECRConfig  `yaml:"ecr,omitempty"`
+	Sign      *SignConfig `yaml:"sign,omitempty"`
+	SBOM      *SBOMConfig `yaml:"sbom,omitempty"`
 	BuildType `yaml:",inline"`
 }
 
+// SBOMConfig configures the generation of a software bill of materials
+// for each built image, with syft.
+type SBOMConfig struct {
+	// Format is either `spdx-json` or `cyclonedx-json`. Defaults to `spdx-json`.
+	Format string `yaml:"format,omitempty"`
+
+	// OutputDir is the folder where the SBOMs are written. Defaults to `.skaffold/sbom`.
+	OutputDir string `yaml:"outputDir,omitempty"`
+
+	// Attach attaches the SBOMs to the pushed images with cosign.
+	Attach bool `yaml:"attach,omitempty"`
+}
+
+// SignConfig configures the signature of the built images with cosign.
+// Images are signed once pushed: signing is skipped, with a warning, if they are not.
+type SignConfig struct {
+	// Key is the cosign private key, either a file or a KMS URI.
+	// Images are signed keyless, with an OIDC identity, if it's not set.
+	Key string `yaml:"key,omitempty"`
+
+	// Verify checks the signatures of the images before they are deployed.
+	Verify bool `yaml:"verify,omitempty"`
+
+	// PublicKey verifies the signatures made with a key.
+	// Defaults to the key, which works with KMS URIs.
+	PublicKey string `yaml:"publicKey,omitempty"`
+
+	// CertificateIdentity and CertificateOIDCIssuer verify the keyless signatures.
+	CertificateIdentity   string `yaml:"certificateIdentity,omitempty"`
+	CertificateOIDCIssuer string `yaml:"certificateOidcIssuer,omitempty"`
+}
+
+// ECRConfig configures how the artifacts are pushed to Amazon ECR registries.
+type ECRConfig struct {
+	// CreateRepositories creates the repositories that don't exist yet,
+	// instead of failing the push.
+	CreateRepositories bool `yaml:"createRepositories,omitempty"`
+
+	// ScanOnPush enables the vulnerability scan of the images pushed
+	// to the created repositories.
+	ScanOnPush bool `yaml:"scanOnPush,omitempty"`
+
+	// ImageTagMutability is either `MUTABLE` or `IMMUTABLE`. Defaults to `MUTABLE`.
+	ImageTagMutability string `yaml:"imageTagMutability,omitempty"`
+
+	// Tags are set on the created repositories.
+	Tags map[string]string `yaml:"tags,omitempty"`
+}
+
 // TagPolicy contains all the configuration for the tagging step
 type TagPolicy struct {
 	GitTagger         *GitTagger         `yaml:"gitCommit,omitempty" yamltags:"oneOf=tag"`
@@ -87,9 +143,23 @@ type BuildType struct {
 // LocalBuild contains the fields needed to do a build on the local docker daemon
 // and optionally push to a repository.
 type LocalBuild struct {
-	Push         *bool `yaml:"push,omitempty"`
-	UseDockerCLI bool  `yaml:"useDockerCLI,omitempty"`
-	UseBuildkit  bool  `yaml:"useBuildkit,omitempty"`
+	Push         *bool        `yaml:"push,omitempty"`
+	UseDockerCLI bool         `yaml:"useDockerCLI,omitempty"`
+	UseBuildkit  bool         `yaml:"useBuildkit,omitempty"`
+	Prune        *PruneConfig `yaml:"prune,omitempty"`
+
+	// Platform is the platform docker images are built for, such as `linux/amd64`.
+	// `cluster` builds them for the platform of the cluster's nodes.
+	// Defaults to the platform of the docker daemon.
+	Platform string `yaml:"platform,omitempty"`
+}
+
+// PruneConfig configures the removal of older images from the
+// local daemon when dev mode exits.
+type PruneConfig struct {
+	// KeepLast is the number of most recent images kept for each artifact.
+	// Defaults to 1.
+	KeepLast int `yaml:"keepLast,omitempty"`
 }
 
 // GoogleCloudBuild contains the fields needed to do a remote build on
@@ -142,9 +212,98 @@ type TestCase struct {
 	StructureTests []string `yaml:"structureTests,omitempty"`
 }
 
+// VerifyConfig is a list of checks run against the deployed application.
+type VerifyConfig []*VerifyCase
+
+// VerifyCase is a single check run after a deploy, either as
+// a command on the host or as a container in the cluster.
+type VerifyCase struct {
+	Name      string           `yaml:"name,omitempty"`
+	Command   []string         `yaml:"command,omitempty"`
+	Container *VerifyContainer `yaml:"container,omitempty"`
+}
+
+// VerifyContainer describes a container run to completion in the cluster.
+// If the image is one of the artifacts, the freshly built image is used.
+type VerifyContainer struct {
+	Image   string   `yaml:"image"`
+	Command []string `yaml:"command,omitempty"`
+	Args    []string `yaml:"args,omitempty"`
+}
+
+// ScanConfig configures the scan of the built images for vulnerabilities,
+// before they are deployed. Only one scanner should be set.
+type ScanConfig struct {
+	Trivy             *TrivyScanner             `yaml:"trivy,omitempty" yamltags:"oneOf=scanner"`
+	Grype             *GrypeScanner             `yaml:"grype,omitempty" yamltags:"oneOf=scanner"`
+	ContainerAnalysis *ContainerAnalysisScanner `yaml:"containerAnalysis,omitempty" yamltags:"oneOf=scanner"`
+
+	// Severity is the lowest severity reported, among `LOW`, `MEDIUM`, `HIGH`
+	// and `CRITICAL`. Defaults to `HIGH`.
+	Severity string `yaml:"severity,omitempty"`
+
+	// WarnOnly reports the vulnerabilities without failing the pipeline.
+	WarnOnly bool `yaml:"warnOnly,omitempty"`
+}
+
+// TrivyScanner scans the images with the trivy CLI.
+type TrivyScanner struct{}
+
+// GrypeScanner scans the images with the grype CLI.
+type GrypeScanner struct{}
+
+// ContainerAnalysisScanner reads the vulnerabilities found by Google Container Analysis
+// in the images pushed to gcr.io or Artifact Registry.
+type ContainerAnalysisScanner struct {
+	// ProjectID is the project holding the analysis results.
+	// Defaults to the project of the image.
+	ProjectID string `yaml:"projectId,omitempty"`
+}
+
+// NotifyConfig is a list of notifiers told about the end of builds and deploys.
+type NotifyConfig []*Notifier
+
+// Notifier sends notifications to the desktop, to a Slack webhook or to an
+// HTTP webhook. Only one of them should be set. `on` restricts the
+// notifications to some of `buildSucceeded`, `buildFailed`, `deploySucceeded`
+// and `deployFailed`. All are sent by default.
+type Notifier struct {
+	Desktop *DesktopNotifier `yaml:"desktop,omitempty" yamltags:"oneOf=notifier"`
+	Slack   *SlackNotifier   `yaml:"slack,omitempty" yamltags:"oneOf=notifier"`
+	Webhook *WebhookNotifier `yaml:"webhook,omitempty" yamltags:"oneOf=notifier"`
+	On      []string         `yaml:"on,omitempty"`
+}
+
+// DesktopNotifier shows notifications on the desktop.
+type DesktopNotifier struct{}
+
+// SlackNotifier posts notifications to a Slack incoming webhook.
+type SlackNotifier struct {
+	WebhookURL string `yaml:"webhookURL"`
+}
+
+// WebhookNotifier posts notifications as JSON to a URL.
+type WebhookNotifier struct {
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+}
+
 // DeployConfig contains all the configuration needed by the deploy steps
 type DeployConfig struct {
-	DeployType `yaml:",inline"`
+	DeployType         `yaml:",inline"`
+	Timeout            string `yaml:"timeout,omitempty"`
+	StatusCheckTimeout string `yaml:"statusCheckTimeout,omitempty"`
+
+	// Labels are set on every deployed resource, along with the skaffold labels
+	// and the ones given with `--label`.
+	Labels map[string]string `yaml:"labels,omitempty"`
+
+	// Jobs are the names of deployed Jobs, such as database migrations, that must
+	// run to completion before the status check. The deploy fails if one of them fails.
+	Jobs []string `yaml:"jobs,omitempty"`
+
+	// JobTimeout is how long to wait for each Job to complete. Defaults to 10m.
+	JobTimeout string `yaml:"jobTimeout,omitempty"`
 }
 
 // DeployType contains the specific implementation and parameters needed
@@ -160,8 +319,46 @@ type KubectlDeploy struct {
 	Manifests       []string     `yaml:"manifests,omitempty"`
 	RemoteManifests []string     `yaml:"remoteManifests,omitempty"`
 	Flags           KubectlFlags `yaml:"flags,omitempty"`
+
+	// Force deletes and recreates the resources that can't be updated in place. Defaults to true.
+	Force *bool `yaml:"force,omitempty"`
+
+	// GracePeriod is how many seconds resources are given to terminate when deleted.
+	GracePeriod *int `yaml:"gracePeriod,omitempty"`
+
+	// Cascade deletes the dependents of deleted resources, such as the pods of a StatefulSet. Defaults to true.
+	Cascade *bool `yaml:"cascade,omitempty"`
+
+	// PruneWhitelist lists the kinds of resources, such as `core/v1/ConfigMap`, that are deleted when
+	// they were deployed earlier in the same skaffold session and are not in the manifests anymore.
+	PruneWhitelist []string `yaml:"pruneWhitelist,omitempty"`
+
+	// Strategy rolls out new versions of Deployments progressively rather than updating them in place.
+	Strategy *RolloutStrategy `yaml:"strategy,omitempty"`
+}
+
+// RolloutStrategy describes how new versions of Deployments are rolled out.
+// Only one field should be populated.
+type RolloutStrategy struct {
+	Canary    *CanaryStrategy    `yaml:"canary,omitempty" yamltags:"oneOf=strategy"`
+	BlueGreen *BlueGreenStrategy `yaml:"blueGreen,omitempty" yamltags:"oneOf=strategy"`
+}
+
+// CanaryStrategy runs the new version of an updated Deployment on a share of
+// its replicas for a while, and then promotes it to all the replicas.
+type CanaryStrategy struct {
+	// Weight is the percentage of replicas that run the canary. Defaults to 20.
+	Weight int `yaml:"weight,omitempty"`
+
+	// Pause is how long the canary runs before it's promoted. Defaults to 30s.
+	Pause string `yaml:"pause,omitempty"`
 }
 
+// BlueGreenStrategy deploys new versions of Deployments next to the live ones,
+// switches the Services that select them once they are available and then
+// deletes the previous versions.
+type BlueGreenStrategy struct{}
+
 // KubectlFlags describes additional options flags that are passed on the command
 // line to kubectl either on every command (Global), on creations (Apply)
 // or deletions (Delete).
@@ -196,6 +393,18 @@ type HelmRelease struct {
 	Overrides         map[string]interface{} `yaml:"overrides,omitempty"`
 	Packaged          *HelmPackaged          `yaml:"packaged,omitempty"`
 	ImageStrategy     HelmImageStrategy      `yaml:"imageStrategy,omitempty"`
+	PostRenderer      *HelmPostRenderer      `yaml:"postRenderer,omitempty"`
+}
+
+// HelmPostRenderer is a command helm pipes the rendered manifests through
+// before installing them, for example to apply kustomizations.
+type HelmPostRenderer struct {
+	// Command is the executable, followed by its arguments.
+	Command []string `yaml:"command,omitempty"`
+
+	// Dependencies are the files, or glob patterns, the post-renderer reads.
+	// They are watched in dev mode like the chart.
+	Dependencies []string `yaml:"dependencies,omitempty"`
 }
 
 // HelmPackaged represents parameters for packaging helm chart.
@@ -231,16 +440,56 @@ type Artifact struct {
 	ImageName    string            `yaml:"image,omitempty"`
 	Workspace    string            `yaml:"context,omitempty"`
 	Sync         map[string]string `yaml:"sync,omitempty"`
+	Hooks        *BuildHooks       `yaml:"hooks,omitempty"`
+	Intercept    *InterceptConfig  `yaml:"intercept,omitempty"`
 	ArtifactType `yaml:",inline"`
 }
 
+// InterceptConfig describes how an artifact runs as a local process during
+// `skaffold dev --intercept`: with telepresence, the traffic of its workload is
+// sent to the process, which reaches the cluster's services as if it ran in a pod.
+type InterceptConfig struct {
+	// Workload is the name of the deployment running the artifact.
+	Workload string `yaml:"workload,omitempty"`
+
+	// Port is the local port the traffic is sent to, optionally followed by the
+	// name or number of the intercepted container port, as in `8080:http`.
+	Port string `yaml:"port,omitempty"`
+
+	// Command starts the local process, from the artifact's context.
+	Command []string `yaml:"command,omitempty"`
+}
+
+// BuildHooks describes commands run on the host before and after an artifact is built.
+type BuildHooks struct {
+	Before []HostHook `yaml:"before,omitempty"`
+	After  []HostHook `yaml:"after,omitempty"`
+}
+
+// HostHook is a command run on the host, from the artifact's workspace.
+type HostHook struct {
+	Command []string `yaml:"command,omitempty"`
+}
+
 // Profile is additional configuration that overrides default
 // configuration when it is activated.
 type Profile struct {
-	Name   string       `yaml:"name,omitempty"`
-	Build  BuildConfig  `yaml:"build,omitempty"`
-	Test   TestConfig   `yaml:"test,omitempty"`
-	Deploy DeployConfig `yaml:"deploy,omitempty"`
+	Name    string       `yaml:"name,omitempty"`
+	Build   BuildConfig  `yaml:"build,omitempty"`
+	Test    TestConfig   `yaml:"test,omitempty"`
+	Deploy  DeployConfig `yaml:"deploy,omitempty"`
+	Verify  VerifyConfig `yaml:"verify,omitempty"`
+	Scan    *ScanConfig  `yaml:"scan,omitempty"`
+	Patches []JSONPatch  `yaml:"patches,omitempty"`
+}
+
+// JSONPatch is a targeted change applied to the configuration by a profile.
+// It follows the JSON Patch syntax (RFC 6902), with `add`, `remove` and
+// `replace` operations and paths such as `/build/artifacts/0/docker/dockerfile`.
+type JSONPatch struct {
+	Op    string      `yaml:"op,omitempty"`
+	Path  string      `yaml:"path,omitempty"`
+	Value interface{} `yaml:"value,omitempty"`
 }
 
 type ArtifactType struct {
@@ -248,6 +497,7 @@ type ArtifactType struct {
 	BazelArtifact     *BazelArtifact     `yaml:"bazel,omitempty" yamltags:"oneOf=artifact"`
 	JibMavenArtifact  *JibMavenArtifact  `yaml:"jibMaven,omitempty" yamltags:"oneOf=artifact"`
 	JibGradleArtifact *JibGradleArtifact `yaml:"jibGradle,omitempty" yamltags:"oneOf=artifact"`
+	TarballArtifact   *TarballArtifact   `yaml:"tarball,omitempty" yamltags:"oneOf=artifact"`
 }
 
 // DockerArtifact describes an artifact built from a Dockerfile,
@@ -264,6 +514,13 @@ type BazelArtifact struct {
 	BuildTarget string `yaml:"target,omitempty"`
 }
 
+// TarballArtifact describes an artifact imported from an image tarball
+// produced by another tool, in the format written by `docker save`.
+type TarballArtifact struct {
+	// Path is the tarball's location, relative to the workspace.
+	Path string `yaml:"path"`
+}
+
 type JibMavenArtifact struct {
 	// Only multi-module
 	Module  string `yaml:"module"`
@@ -35,6 +35,10 @@ func (c *SkaffoldPipeline) SetDefaultValues() error {
 	c.setDefaultTagger()
 	c.setDefaultKustomizePath()
 	c.setDefaultKubectlManifests()
+	c.setDefaultCanaryStrategy()
+	c.setDefaultPruneKeepLast()
+	c.setDefaultScanSeverity()
+	c.setDefaultSBOM()
 
 	if err := c.withKanikoConfig(
 		setDefaultKanikoTimeout,
@@ -105,6 +109,45 @@ func (c *SkaffoldPipeline) setDefaultKubectlManifests() {
 	}
 }
 
+func (c *SkaffoldPipeline) setDefaultCanaryStrategy() {
+	kubectl := c.Deploy.KubectlDeploy
+	if kubectl == nil || kubectl.Strategy == nil || kubectl.Strategy.Canary == nil {
+		return
+	}
+
+	canary := kubectl.Strategy.Canary
+	if canary.Weight == 0 {
+		canary.Weight = constants.DefaultCanaryWeight
+	}
+	canary.Pause = valueOrDefault(canary.Pause, constants.DefaultCanaryPause)
+}
+
+func (c *SkaffoldPipeline) setDefaultPruneKeepLast() {
+	local := c.Build.LocalBuild
+	if local != nil && local.Prune != nil && local.Prune.KeepLast == 0 {
+		local.Prune.KeepLast = constants.DefaultPruneKeepLast
+	}
+}
+
+func (c *SkaffoldPipeline) setDefaultScanSeverity() {
+	if c.Scan != nil && c.Scan.Severity == "" {
+		c.Scan.Severity = constants.DefaultScanSeverity
+	}
+}
+
+func (c *SkaffoldPipeline) setDefaultSBOM() {
+	sbom := c.Build.SBOM
+	if sbom == nil {
+		return
+	}
+	if sbom.Format == "" {
+		sbom.Format = constants.DefaultSBOMFormat
+	}
+	if sbom.OutputDir == "" {
+		sbom.OutputDir = constants.DefaultSBOMDir
+	}
+}
+
 func (c *SkaffoldPipeline) defaultToDockerArtifact(a *Artifact) {
 	if a.ArtifactType == (ArtifactType{}) {
 		a.ArtifactType = ArtifactType{
@@ -191,6 +234,11 @@ func valueOrDefault(v, def string) string {
 }
 
 func currentNamespace() (string, error) {
+	if kubectx.InCluster() {
+		namespace, _, err := kubectx.ClientConfig().Namespace()
+		return namespace, err
+	}
+
 	cfg, err := kubectx.CurrentConfig()
 	if err != nil {
 		return "", err
@@ -0,0 +1,188 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"reflect"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
+)
+
+// MergeConfigs merges pipelines read from several files into one. Artifacts,
+// tests, deployed manifests, helm releases, jobs and verifications are concatenated.
+// The other settings, like the builder or the tag policy, must be the same in
+// every file, or only set in one of them.
+func MergeConfigs(configs []*latest.SkaffoldPipeline, files []string) (*latest.SkaffoldPipeline, error) {
+	if len(configs) == 0 {
+		return nil, errors.New("no configuration to merge")
+	}
+
+	merged := *configs[0]
+	merged.Profiles = nil
+	merged.Build.Artifacts = append([]*latest.Artifact(nil), merged.Build.Artifacts...)
+	merged.Deploy.DeployType = copyDeployType(merged.Deploy.DeployType)
+
+	imageFiles := map[string]string{}
+	for _, artifact := range merged.Build.Artifacts {
+		imageFiles[artifact.ImageName] = files[0]
+	}
+
+	for i := 1; i < len(configs); i++ {
+		config, file := configs[i], files[i]
+
+		for _, artifact := range config.Build.Artifacts {
+			if previous, found := imageFiles[artifact.ImageName]; found {
+				return nil, errors.Errorf("image %s is built by both %s and %s", artifact.ImageName, previous, file)
+			}
+			imageFiles[artifact.ImageName] = file
+			merged.Build.Artifacts = append(merged.Build.Artifacts, artifact)
+		}
+
+		if !reflect.DeepEqual(merged.Build.TagPolicy, config.Build.TagPolicy) ||
+			!reflect.DeepEqual(merged.Build.ECR, config.Build.ECR) ||
+			!reflect.DeepEqual(merged.Build.Sign, config.Build.Sign) ||
+			!reflect.DeepEqual(merged.Build.SBOM, config.Build.SBOM) ||
+			!reflect.DeepEqual(merged.Build.BuildType, config.Build.BuildType) {
+			return nil, errors.Errorf("build settings in %s differ from %s, only artifacts can be different", file, files[0])
+		}
+
+		var err error
+		if merged.Build.Timeout, err = mergeSetting("build.timeout", merged.Build.Timeout, config.Build.Timeout, file); err != nil {
+			return nil, err
+		}
+		if merged.Deploy.Timeout, err = mergeSetting("deploy.timeout", merged.Deploy.Timeout, config.Deploy.Timeout, file); err != nil {
+			return nil, err
+		}
+		if merged.Deploy.StatusCheckTimeout, err = mergeSetting("deploy.statusCheckTimeout", merged.Deploy.StatusCheckTimeout, config.Deploy.StatusCheckTimeout, file); err != nil {
+			return nil, err
+		}
+		if merged.Deploy.JobTimeout, err = mergeSetting("deploy.jobTimeout", merged.Deploy.JobTimeout, config.Deploy.JobTimeout, file); err != nil {
+			return nil, err
+		}
+		merged.Deploy.Jobs = append(append([]string(nil), merged.Deploy.Jobs...), config.Deploy.Jobs...)
+		if err := mergeDeployType(&merged.Deploy.DeployType, config.Deploy.DeployType, file); err != nil {
+			return nil, err
+		}
+		if merged.Deploy.Labels, err = mergeLabels(merged.Deploy.Labels, config.Deploy.Labels, file); err != nil {
+			return nil, err
+		}
+
+		merged.Test = append(merged.Test, config.Test...)
+		merged.Verify = append(merged.Verify, config.Verify...)
+		if config.Scan != nil {
+			if merged.Scan != nil && !reflect.DeepEqual(merged.Scan, config.Scan) {
+				return nil, errors.Errorf("scan settings in %s differ from %s", file, files[0])
+			}
+			merged.Scan = config.Scan
+		}
+		merged.Notify = append(merged.Notify, config.Notify...)
+	}
+
+	return &merged, nil
+}
+
+// copyDeployType copies the deployers that are modified by a merge.
+func copyDeployType(deployType latest.DeployType) latest.DeployType {
+	if deployType.KubectlDeploy != nil {
+		kubectl := *deployType.KubectlDeploy
+		deployType.KubectlDeploy = &kubectl
+	}
+	if deployType.HelmDeploy != nil {
+		helm := *deployType.HelmDeploy
+		deployType.HelmDeploy = &helm
+	}
+	return deployType
+}
+
+func mergeDeployType(merged *latest.DeployType, other latest.DeployType, file string) error {
+	switch {
+	case other.KubectlDeploy == nil:
+	case merged.KubectlDeploy == nil:
+		kubectl := *other.KubectlDeploy
+		merged.KubectlDeploy = &kubectl
+	default:
+		if !reflect.DeepEqual(merged.KubectlDeploy.Flags, other.KubectlDeploy.Flags) {
+			return errors.Errorf("kubectl flags in %s differ from the other files", file)
+		}
+		if !reflect.DeepEqual(merged.KubectlDeploy.Strategy, other.KubectlDeploy.Strategy) {
+			return errors.Errorf("kubectl rollout strategy in %s differs from the other files", file)
+		}
+		merged.KubectlDeploy.Manifests = append(append([]string(nil), merged.KubectlDeploy.Manifests...), other.KubectlDeploy.Manifests...)
+		merged.KubectlDeploy.RemoteManifests = append(append([]string(nil), merged.KubectlDeploy.RemoteManifests...), other.KubectlDeploy.RemoteManifests...)
+	}
+
+	switch {
+	case other.HelmDeploy == nil:
+	case merged.HelmDeploy == nil:
+		helm := *other.HelmDeploy
+		merged.HelmDeploy = &helm
+	default:
+		releases := append([]latest.HelmRelease(nil), merged.HelmDeploy.Releases...)
+		for _, release := range other.HelmDeploy.Releases {
+			for _, existing := range releases {
+				if existing.Name == release.Name {
+					return errors.Errorf("helm release %s in %s is already defined", release.Name, file)
+				}
+			}
+			releases = append(releases, release)
+		}
+		merged.HelmDeploy.Releases = releases
+	}
+
+	switch {
+	case other.KustomizeDeploy == nil:
+	case merged.KustomizeDeploy == nil:
+		merged.KustomizeDeploy = other.KustomizeDeploy
+	case !reflect.DeepEqual(merged.KustomizeDeploy, other.KustomizeDeploy):
+		return errors.Errorf("kustomize settings in %s differ from the other files", file)
+	}
+
+	return nil
+}
+
+// mergeSetting returns the value of a setting that can be set by any of the
+// merged files, as long as they agree.
+func mergeSetting(name, merged, other, file string) (string, error) {
+	switch {
+	case other == "":
+		return merged, nil
+	case merged == "" || merged == other:
+		return other, nil
+	default:
+		return "", errors.Errorf("%s in %s differs from the other files", name, file)
+	}
+}
+
+// mergeLabels merges the labels of two files. A label set in both files must have the same value.
+func mergeLabels(merged, other map[string]string, file string) (map[string]string, error) {
+	if len(other) == 0 {
+		return merged, nil
+	}
+
+	labels := map[string]string{}
+	for k, v := range merged {
+		labels[k] = v
+	}
+	for k, v := range other {
+		if previous, found := labels[k]; found && previous != v {
+			return nil, errors.Errorf("deploy.labels.%s in %s differs from the other files", k, file)
+		}
+		labels[k] = v
+	}
+	return labels, nil
+}
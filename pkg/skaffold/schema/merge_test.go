@@ -0,0 +1,117 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestMergeConfigs(t *testing.T) {
+	var tests = []struct {
+		description string
+		configs     []*latest.SkaffoldPipeline
+		expected    *latest.SkaffoldPipeline
+		shouldErr   bool
+	}{
+		{
+			description: "single config",
+			configs: []*latest.SkaffoldPipeline{
+				config(withLocalBuild(withGitTagger(), withDockerArtifact("image1", "one", "Dockerfile")), withKubectlDeploy("one/k8s/*.yaml")),
+			},
+			expected: config(withLocalBuild(withGitTagger(), withDockerArtifact("image1", "one", "Dockerfile")), withKubectlDeploy("one/k8s/*.yaml")),
+		},
+		{
+			description: "artifacts and manifests",
+			configs: []*latest.SkaffoldPipeline{
+				config(withLocalBuild(withGitTagger(), withDockerArtifact("image1", "one", "Dockerfile")), withKubectlDeploy("one/k8s/*.yaml")),
+				config(withLocalBuild(withGitTagger(), withDockerArtifact("image2", "two", "Dockerfile")), withKubectlDeploy("two/k8s/*.yaml")),
+			},
+			expected: config(
+				withLocalBuild(withGitTagger(), withDockerArtifact("image1", "one", "Dockerfile"), withDockerArtifact("image2", "two", "Dockerfile")),
+				withKubectlDeploy("one/k8s/*.yaml", "two/k8s/*.yaml"),
+			),
+		},
+		{
+			description: "timeout set in one file",
+			configs: []*latest.SkaffoldPipeline{
+				config(withLocalBuild(withGitTagger()), withKubectlDeploy("one/k8s/*.yaml")),
+				config(withLocalBuild(withGitTagger(), withBuildTimeout("5m")), withKubectlDeploy("two/k8s/*.yaml")),
+			},
+			expected: config(
+				withLocalBuild(withGitTagger(), withBuildTimeout("5m")),
+				withKubectlDeploy("one/k8s/*.yaml", "two/k8s/*.yaml"),
+			),
+		},
+		{
+			description: "same image in two files",
+			configs: []*latest.SkaffoldPipeline{
+				config(withLocalBuild(withGitTagger(), withDockerArtifact("image", "one", "Dockerfile")), withKubectlDeploy("one/k8s/*.yaml")),
+				config(withLocalBuild(withGitTagger(), withDockerArtifact("image", "two", "Dockerfile")), withKubectlDeploy("two/k8s/*.yaml")),
+			},
+			shouldErr: true,
+		},
+		{
+			description: "different builders",
+			configs: []*latest.SkaffoldPipeline{
+				config(withLocalBuild(withGitTagger()), withKubectlDeploy("one/k8s/*.yaml")),
+				config(withGoogleCloudBuild("project", withGitTagger()), withKubectlDeploy("two/k8s/*.yaml")),
+			},
+			shouldErr: true,
+		},
+		{
+			description: "labels",
+			configs: []*latest.SkaffoldPipeline{
+				config(withLocalBuild(withGitTagger()), withKubectlDeploy("one/k8s/*.yaml"), withDeployLabels(map[string]string{"team": "payments", "owner": "alice"})),
+				config(withLocalBuild(withGitTagger()), withKubectlDeploy("two/k8s/*.yaml"), withDeployLabels(map[string]string{"team": "payments", "ticket": "PAY-123"})),
+			},
+			expected: config(
+				withLocalBuild(withGitTagger()),
+				withKubectlDeploy("one/k8s/*.yaml", "two/k8s/*.yaml"),
+				withDeployLabels(map[string]string{"team": "payments", "owner": "alice", "ticket": "PAY-123"}),
+			),
+		},
+		{
+			description: "different label values",
+			configs: []*latest.SkaffoldPipeline{
+				config(withLocalBuild(withGitTagger()), withKubectlDeploy("one/k8s/*.yaml"), withDeployLabels(map[string]string{"team": "payments"})),
+				config(withLocalBuild(withGitTagger()), withKubectlDeploy("two/k8s/*.yaml"), withDeployLabels(map[string]string{"team": "checkout"})),
+			},
+			shouldErr: true,
+		},
+		{
+			description: "different timeouts",
+			configs: []*latest.SkaffoldPipeline{
+				config(withLocalBuild(withGitTagger(), withBuildTimeout("1m")), withKubectlDeploy("one/k8s/*.yaml")),
+				config(withLocalBuild(withGitTagger(), withBuildTimeout("5m")), withKubectlDeploy("two/k8s/*.yaml")),
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			files := []string{"one/skaffold.yaml", "two/skaffold.yaml"}
+
+			merged, err := MergeConfigs(test.configs, files[:len(test.configs)])
+
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, merged)
+		})
+	}
+}
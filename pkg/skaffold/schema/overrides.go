@@ -0,0 +1,191 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ApplyOverrides sets configuration fields given on the command line as
+// `path=value`, with paths such as `build.artifacts[0].docker.dockerfile`.
+// Values are parsed as yaml. Like helm's --set, the missing sections along the
+// path are created and an index right after the last item of a list appends to it.
+func ApplyOverrides(config *latest.SkaffoldPipeline, overrides []string) error {
+	if len(overrides) == 0 {
+		return nil
+	}
+
+	var parsed []override
+	for _, o := range overrides {
+		p, err := parseOverride(o)
+		if err != nil {
+			return errors.Wrapf(err, "parsing override %s", o)
+		}
+		parsed = append(parsed, p)
+	}
+
+	if err := patchConfig(config, func(doc interface{}) (interface{}, error) {
+		for _, o := range parsed {
+			var err error
+			if doc, err = setValue(doc, o.tokens, o.value); err != nil {
+				return nil, errors.Wrapf(err, "setting %s", o.path)
+			}
+		}
+		return doc, nil
+	}); err != nil {
+		return err
+	}
+	return config.SetDefaultValues()
+}
+
+// override is a field to set.
+type override struct {
+	path   string
+	tokens []string
+	value  interface{}
+}
+
+// parseOverride parses a `path=value` override.
+func parseOverride(o string) (override, error) {
+	kv := strings.SplitN(o, "=", 2)
+	if len(kv) != 2 || kv[0] == "" {
+		return override{}, errors.New("expected path=value")
+	}
+
+	tokens, err := parseOverridePath(kv[0])
+	if err != nil {
+		return override{}, err
+	}
+
+	var value interface{}
+	if err := yaml.Unmarshal([]byte(kv[1]), &value); err != nil {
+		return override{}, errors.Wrap(err, "parsing value")
+	}
+
+	return override{path: kv[0], tokens: tokens, value: value}, nil
+}
+
+// setValue sets a value in the node found by following the tokens, creating
+// the missing maps and lists. It returns the updated node since setting an
+// item of a list can change its size.
+func setValue(node interface{}, tokens []string, value interface{}) (interface{}, error) {
+	token := tokens[0]
+	last := len(tokens) == 1
+
+	if node == nil {
+		if _, err := strconv.Atoi(token); err == nil {
+			node = []interface{}{}
+		} else {
+			node = map[interface{}]interface{}{}
+		}
+	}
+
+	switch n := node.(type) {
+	case map[interface{}]interface{}:
+		if last {
+			n[token] = value
+			return n, nil
+		}
+
+		updated, err := setValue(n[token], tokens[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[token] = updated
+		return n, nil
+
+	case []interface{}:
+		index, err := strconv.Atoi(token)
+		if err != nil || index < 0 {
+			return nil, errors.Errorf("invalid index %s", token)
+		}
+		if index > len(n) {
+			return nil, errors.Errorf("index %d is out of range: the list has %d items", index, len(n))
+		}
+		if index == len(n) {
+			n = append(n, nil)
+		}
+
+		if last {
+			n[index] = value
+			return n, nil
+		}
+
+		updated, err := setValue(n[index], tokens[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[index] = updated
+		return n, nil
+
+	default:
+		return nil, errors.Errorf("%s can't be set in a value", token)
+	}
+}
+
+// parseOverridePath splits a path such as `build.artifacts[0].image`.
+// Dots in keys can be escaped with a backslash.
+func parseOverridePath(path string) ([]string, error) {
+	var tokens []string
+	var token strings.Builder
+	inIndex := false
+
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case c == '\\' && i+1 < len(path):
+			i++
+			token.WriteByte(path[i])
+		case c == '.' && !inIndex:
+			if token.Len() > 0 {
+				tokens = append(tokens, token.String())
+				token.Reset()
+			}
+		case c == '[' && !inIndex:
+			if token.Len() > 0 {
+				tokens = append(tokens, token.String())
+				token.Reset()
+			}
+			inIndex = true
+		case c == ']' && inIndex:
+			if _, err := strconv.Atoi(token.String()); err != nil {
+				return nil, errors.Errorf("invalid index [%s]", token.String())
+			}
+			tokens = append(tokens, token.String())
+			token.Reset()
+			inIndex = false
+		default:
+			token.WriteByte(c)
+		}
+	}
+
+	if inIndex {
+		return nil, errors.New("unterminated index")
+	}
+	if token.Len() > 0 {
+		tokens = append(tokens, token.String())
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("empty path")
+	}
+	return tokens, nil
+}
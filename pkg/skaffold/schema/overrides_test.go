@@ -0,0 +1,134 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestApplyOverrides(t *testing.T) {
+	var tests = []struct {
+		description string
+		overrides   []string
+		expected    *latest.SkaffoldPipeline
+		shouldErr   bool
+	}{
+		{
+			description: "no overrides",
+			expected: config(
+				withLocalBuild(
+					withGitTagger(),
+					withDockerArtifact("image", ".", "Dockerfile"),
+				),
+				withKubectlDeploy("k8s/*.yaml"),
+			),
+		},
+		{
+			description: "override fields",
+			overrides: []string{
+				"build.artifacts[0].docker.dockerfile=Dockerfile.dev",
+				"build.artifacts.0.image=other",
+				"deploy.kubectl.manifests[0]=k8s-dev/*.yaml",
+				"build.timeout=5m",
+			},
+			expected: config(
+				withLocalBuild(
+					withGitTagger(),
+					withDockerArtifact("other", ".", "Dockerfile.dev"),
+					withBuildTimeout("5m"),
+				),
+				withKubectlDeploy("k8s-dev/*.yaml"),
+			),
+		},
+		{
+			description: "missing parent keys",
+			overrides:   []string{"deploy.kubectl.flags.global=[--v=2]"},
+			expected: config(
+				withLocalBuild(
+					withGitTagger(),
+					withDockerArtifact("image", ".", "Dockerfile"),
+				),
+				withKubectlDeploy("k8s/*.yaml"),
+				func(cfg *latest.SkaffoldPipeline) { cfg.Deploy.KubectlDeploy.Flags.Global = []string{"--v=2"} },
+			),
+		},
+		{
+			description: "append to a list",
+			overrides: []string{
+				"deploy.kubectl.manifests[1]=extra/*.yaml",
+				"build.artifacts[1].image=second",
+			},
+			expected: config(
+				withLocalBuild(
+					withGitTagger(),
+					withDockerArtifact("image", ".", "Dockerfile"),
+					withDockerArtifact("second", ".", "Dockerfile"),
+				),
+				withKubectlDeploy("k8s/*.yaml", "extra/*.yaml"),
+			),
+		},
+		{
+			description: "index out of range",
+			overrides:   []string{"deploy.kubectl.manifests[2]=extra/*.yaml"},
+			shouldErr:   true,
+		},
+		{
+			description: "invalid syntax",
+			overrides:   []string{"build.timeout"},
+			shouldErr:   true,
+		},
+		{
+			description: "unterminated index",
+			overrides:   []string{"build.artifacts[0=value"},
+			shouldErr:   true,
+		},
+		{
+			description: "unknown field",
+			overrides:   []string{"build.unknown=value"},
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			cfg := config(
+				withLocalBuild(
+					withGitTagger(),
+					withDockerArtifact("image", ".", "Dockerfile"),
+				),
+				withKubectlDeploy("k8s/*.yaml"),
+			)
+
+			err := ApplyOverrides(cfg, test.overrides)
+
+			if test.shouldErr {
+				testutil.CheckError(t, true, err)
+			} else {
+				testutil.CheckErrorAndDeepEqual(t, false, err, test.expected, cfg)
+			}
+		})
+	}
+}
+
+func TestParseOverridePath(t *testing.T) {
+	tokens, err := parseOverridePath(`deploy.helm.releases[1].setValues.image\.tag`)
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, []string{"deploy", "helm", "releases", "1", "setValues", "image.tag"}, tokens)
+}
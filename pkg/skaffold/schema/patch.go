@@ -0,0 +1,162 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/yamltags"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// applyPatches applies json patches to a configuration.
+func applyPatches(config *latest.SkaffoldPipeline, patches []latest.JSONPatch) error {
+	return patchConfig(config, func(doc interface{}) (interface{}, error) {
+		for _, patch := range patches {
+			tokens, err := parsePointer(patch.Path)
+			if err != nil {
+				return nil, err
+			}
+
+			if doc, err = patchNode(doc, tokens, patch); err != nil {
+				return nil, errors.Wrapf(err, "applying patch %s %s", patch.Op, patch.Path)
+			}
+		}
+		return doc, nil
+	})
+}
+
+// patchConfig converts a configuration to a generic yaml tree, patches it
+// and converts it back.
+func patchConfig(config *latest.SkaffoldPipeline, patch func(interface{}) (interface{}, error)) error {
+	buf, err := yaml.Marshal(config)
+	if err != nil {
+		return errors.Wrap(err, "marshalling config")
+	}
+
+	var doc interface{}
+	if err := yaml.Unmarshal(buf, &doc); err != nil {
+		return errors.Wrap(err, "unmarshalling config")
+	}
+
+	if doc, err = patch(doc); err != nil {
+		return err
+	}
+
+	if buf, err = yaml.Marshal(doc); err != nil {
+		return errors.Wrap(err, "marshalling patched config")
+	}
+
+	patched := latest.SkaffoldPipeline{}
+	if err := yaml.UnmarshalStrict(buf, &patched); err != nil {
+		return errors.Wrap(err, "patched config is invalid")
+	}
+	if err := yamltags.ProcessStruct(&patched); err != nil {
+		return errors.Wrap(err, "patched config is invalid")
+	}
+
+	*config = patched
+	return nil
+}
+
+// parsePointer splits a JSON pointer (RFC 6901) into unescaped tokens.
+func parsePointer(path string) ([]string, error) {
+	if !strings.HasPrefix(path, "/") {
+		return nil, errors.Errorf("invalid patch path %q: it should start with /", path)
+	}
+
+	var tokens []string
+	for _, token := range strings.Split(path[1:], "/") {
+		token = strings.Replace(token, "~1", "/", -1)
+		token = strings.Replace(token, "~0", "~", -1)
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+// patchNode applies a patch to the node found by following the tokens.
+// It returns the updated node since patching a list can change its size.
+func patchNode(node interface{}, tokens []string, patch latest.JSONPatch) (interface{}, error) {
+	token := tokens[0]
+	last := len(tokens) == 1
+
+	switch n := node.(type) {
+	case map[interface{}]interface{}:
+		child, present := n[token]
+		if !present && (!last || patch.Op != "add") {
+			return nil, errors.Errorf("%s not found", token)
+		}
+
+		if !last {
+			updated, err := patchNode(child, tokens[1:], patch)
+			if err != nil {
+				return nil, err
+			}
+			n[token] = updated
+			return n, nil
+		}
+
+		switch patch.Op {
+		case "add", "replace":
+			n[token] = patch.Value
+		case "remove":
+			delete(n, token)
+		default:
+			return nil, errors.Errorf("unknown operation %s", patch.Op)
+		}
+		return n, nil
+
+	case []interface{}:
+		if last && token == "-" && patch.Op == "add" {
+			return append(n, patch.Value), nil
+		}
+
+		index, err := strconv.Atoi(token)
+		if err != nil || index < 0 || index > len(n) || (index == len(n) && (!last || patch.Op != "add")) {
+			return nil, errors.Errorf("invalid index %s", token)
+		}
+
+		if !last {
+			updated, err := patchNode(n[index], tokens[1:], patch)
+			if err != nil {
+				return nil, err
+			}
+			n[index] = updated
+			return n, nil
+		}
+
+		switch patch.Op {
+		case "add":
+			n = append(n, nil)
+			copy(n[index+1:], n[index:])
+			n[index] = patch.Value
+		case "replace":
+			n[index] = patch.Value
+		case "remove":
+			n = append(n[:index], n[index+1:]...)
+		default:
+			return nil, errors.Errorf("unknown operation %s", patch.Op)
+		}
+		return n, nil
+
+	default:
+		return nil, errors.Errorf("%s can't be found in a value", token)
+	}
+}
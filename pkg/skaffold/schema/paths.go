@@ -0,0 +1,101 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+)
+
+// ResolvePaths makes the relative paths of a configuration relative to the
+// current directory, given the configuration file they were read from.
+// This way, skaffold can be run from any folder with `-f path/to/skaffold.yaml`.
+// Absolute paths and urls are left untouched. Paths that are relative to
+// something else than the config file, like the Dockerfile which is relative to
+// the artifact's context, are left untouched too. So are the paths of a
+// configuration downloaded from a url: they are relative to the current directory.
+func ResolvePaths(config *latest.SkaffoldPipeline, configFile string) {
+	if isURL(configFile) {
+		return
+	}
+	dir := filepath.Dir(configFile)
+	if dir == "." || dir == "" {
+		return
+	}
+
+	for _, a := range config.Build.Artifacts {
+		a.Workspace = resolvePath(dir, a.Workspace)
+	}
+	if kaniko := config.Build.KanikoBuild; kaniko != nil {
+		kaniko.PullSecret = resolvePath(dir, kaniko.PullSecret)
+	}
+	if sbom := config.Build.SBOM; sbom != nil {
+		sbom.OutputDir = resolvePath(dir, sbom.OutputDir)
+	}
+	if sign := config.Build.Sign; sign != nil {
+		// Keys can also be KMS URIs.
+		if !strings.Contains(sign.Key, "://") {
+			sign.Key = resolvePath(dir, sign.Key)
+		}
+		if !strings.Contains(sign.PublicKey, "://") {
+			sign.PublicKey = resolvePath(dir, sign.PublicKey)
+		}
+	}
+
+	for i := range config.Test {
+		resolvePaths(dir, config.Test[i].StructureTests)
+	}
+
+	if kubectl := config.Deploy.KubectlDeploy; kubectl != nil {
+		resolvePaths(dir, kubectl.Manifests)
+	}
+	if kustomize := config.Deploy.KustomizeDeploy; kustomize != nil {
+		kustomize.KustomizePath = resolvePath(dir, kustomize.KustomizePath)
+	}
+	if helm := config.Deploy.HelmDeploy; helm != nil {
+		for i := range helm.Releases {
+			release := &helm.Releases[i]
+			release.ChartPath = resolvePath(dir, release.ChartPath)
+			resolvePaths(dir, release.ValuesFiles)
+			if postRenderer := release.PostRenderer; postRenderer != nil {
+				if len(postRenderer.Command) > 0 && strings.ContainsAny(postRenderer.Command[0], `/\`) {
+					postRenderer.Command[0] = resolvePath(dir, postRenderer.Command[0])
+				}
+				resolvePaths(dir, postRenderer.Dependencies)
+			}
+		}
+	}
+}
+
+func resolvePaths(dir string, paths []string) {
+	for i := range paths {
+		paths[i] = resolvePath(dir, paths[i])
+	}
+}
+
+func resolvePath(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) || isURL(path) || strings.HasPrefix(path, "oci://") {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+func isURL(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
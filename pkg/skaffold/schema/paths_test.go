@@ -0,0 +1,147 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestResolvePaths(t *testing.T) {
+	var tests = []struct {
+		description string
+		configFile  string
+		config      *latest.SkaffoldPipeline
+		expected    *latest.SkaffoldPipeline
+	}{
+		{
+			description: "current directory",
+			configFile:  "skaffold.yaml",
+			config: config(
+				withLocalBuild(withDockerArtifact("image", "app", "Dockerfile")),
+				withKubectlDeploy("k8s/*.yaml"),
+			),
+			expected: config(
+				withLocalBuild(withDockerArtifact("image", "app", "Dockerfile")),
+				withKubectlDeploy("k8s/*.yaml"),
+			),
+		},
+		{
+			description: "remote config",
+			configFile:  "https://example.com/path/to/skaffold.yaml",
+			config: config(
+				withLocalBuild(withDockerArtifact("image", "app", "Dockerfile")),
+				withKubectlDeploy("k8s/*.yaml"),
+			),
+			expected: config(
+				withLocalBuild(withDockerArtifact("image", "app", "Dockerfile")),
+				withKubectlDeploy("k8s/*.yaml"),
+			),
+		},
+		{
+			description: "kubectl",
+			configFile:  "path/to/skaffold.yaml",
+			config: config(
+				withLocalBuild(withDockerArtifact("image", ".", "Dockerfile")),
+				withKubectlDeploy("k8s/*.yaml", "/abs/k8s.yaml", "https://example.com/k8s.yaml"),
+			),
+			expected: config(
+				withLocalBuild(withDockerArtifact("image", "path/to", "Dockerfile")),
+				withKubectlDeploy("path/to/k8s/*.yaml", "/abs/k8s.yaml", "https://example.com/k8s.yaml"),
+			),
+		},
+		{
+			description: "helm and kaniko",
+			configFile:  "path/to/skaffold.yaml",
+			config: config(
+				withKanikoBuild("bucket", "secret-name", "default", "secret.json", "20m"),
+				withHelmDeploy(),
+			),
+			expected: config(
+				withKanikoBuild("bucket", "secret-name", "default", "path/to/secret.json", "20m"),
+				withHelmDeploy(),
+			),
+		},
+		{
+			description: "cosign keys",
+			configFile:  "path/to/skaffold.yaml",
+			config: config(
+				withLocalBuild(withSign(&latest.SignConfig{Key: "gcpkms://projects/p/keys/k", PublicKey: "cosign.pub"})),
+				withKubectlDeploy(),
+			),
+			expected: config(
+				withLocalBuild(withSign(&latest.SignConfig{Key: "gcpkms://projects/p/keys/k", PublicKey: "path/to/cosign.pub"})),
+				withKubectlDeploy(),
+			),
+		},
+		{
+			description: "sbom folder",
+			configFile:  "path/to/skaffold.yaml",
+			config: config(
+				withLocalBuild(withSBOM(&latest.SBOMConfig{OutputDir: ".skaffold/sbom"})),
+				withKubectlDeploy(),
+			),
+			expected: config(
+				withLocalBuild(withSBOM(&latest.SBOMConfig{OutputDir: "path/to/.skaffold/sbom"})),
+				withKubectlDeploy(),
+			),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			ResolvePaths(test.config, test.configFile)
+
+			testutil.CheckDeepEqual(t, test.expected, test.config)
+		})
+	}
+}
+
+func TestResolveHelmPaths(t *testing.T) {
+	cfg := &latest.SkaffoldPipeline{}
+	cfg.Deploy.HelmDeploy = &latest.HelmDeploy{
+		Releases: []latest.HelmRelease{{
+			Name:        "release",
+			ChartPath:   "charts/app",
+			ValuesFiles: []string{"values.yaml", "/etc/values.yaml"},
+			PostRenderer: &latest.HelmPostRenderer{
+				Command:      []string{"./hack/render.sh", "overlays/dev"},
+				Dependencies: []string{"overlays/*"},
+			},
+		}, {
+			Name:         "other",
+			ChartPath:    "charts/other",
+			PostRenderer: &latest.HelmPostRenderer{Command: []string{"kustomize-render"}},
+		}, {
+			Name:      "registry",
+			ChartPath: "oci://gcr.io/project/charts/app",
+		}},
+	}
+	cfg.Test = latest.TestConfig{{StructureTests: []string{"tests/*.yaml"}}}
+
+	ResolvePaths(cfg, "../project/skaffold.yaml")
+
+	testutil.CheckDeepEqual(t, "../project/charts/app", cfg.Deploy.HelmDeploy.Releases[0].ChartPath)
+	testutil.CheckDeepEqual(t, []string{"../project/values.yaml", "/etc/values.yaml"}, cfg.Deploy.HelmDeploy.Releases[0].ValuesFiles)
+	testutil.CheckDeepEqual(t, []string{"../project/hack/render.sh", "overlays/dev"}, cfg.Deploy.HelmDeploy.Releases[0].PostRenderer.Command)
+	testutil.CheckDeepEqual(t, []string{"../project/overlays/*"}, cfg.Deploy.HelmDeploy.Releases[0].PostRenderer.Dependencies)
+	testutil.CheckDeepEqual(t, []string{"kustomize-render"}, cfg.Deploy.HelmDeploy.Releases[1].PostRenderer.Command)
+	testutil.CheckDeepEqual(t, "oci://gcr.io/project/charts/app", cfg.Deploy.HelmDeploy.Releases[2].ChartPath)
+	testutil.CheckDeepEqual(t, []string{"../project/tests/*.yaml"}, cfg.Test[0].StructureTests)
+}
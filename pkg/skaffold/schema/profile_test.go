@@ -142,6 +142,149 @@ func TestApplyProfiles(t *testing.T) {
 				withHelmDeploy(),
 			),
 		},
+		{
+			description: "timeout",
+			profile:     "slow",
+			config: config(
+				withLocalBuild(
+					withGitTagger(),
+				),
+				withKubectlDeploy("k8s/*.yaml"),
+				withProfiles(latest.Profile{
+					Name: "slow",
+					Build: latest.BuildConfig{
+						Timeout: "30m",
+					},
+				}),
+			),
+			expected: config(
+				withLocalBuild(
+					withGitTagger(),
+					withBuildTimeout("30m"),
+				),
+				withKubectlDeploy("k8s/*.yaml"),
+			),
+		},
+		{
+			description: "ecr",
+			profile:     "aws",
+			config: config(
+				withLocalBuild(
+					withGitTagger(),
+				),
+				withKubectlDeploy("k8s/*.yaml"),
+				withProfiles(latest.Profile{
+					Name: "aws",
+					Build: latest.BuildConfig{
+						ECR: &latest.ECRConfig{CreateRepositories: true},
+					},
+				}),
+			),
+			expected: config(
+				withLocalBuild(
+					withGitTagger(),
+					withECR(&latest.ECRConfig{CreateRepositories: true}),
+				),
+				withKubectlDeploy("k8s/*.yaml"),
+			),
+		},
+		{
+			description: "labels",
+			profile:     "staging",
+			config: config(
+				withLocalBuild(
+					withGitTagger(),
+				),
+				withKubectlDeploy("k8s/*.yaml"),
+				withDeployLabels(map[string]string{"team": "payments"}),
+				withProfiles(latest.Profile{
+					Name: "staging",
+					Deploy: latest.DeployConfig{
+						Labels: map[string]string{"env": "staging"},
+					},
+				}),
+			),
+			expected: config(
+				withLocalBuild(
+					withGitTagger(),
+				),
+				withKubectlDeploy("k8s/*.yaml"),
+				withDeployLabels(map[string]string{"env": "staging"}),
+			),
+		},
+		{
+			description: "patches",
+			profile:     "patches",
+			config: config(
+				withLocalBuild(
+					withGitTagger(),
+					withDockerArtifact("image", ".", "Dockerfile"),
+					withDockerArtifact("other", "other", "Dockerfile"),
+				),
+				withKubectlDeploy("k8s/*.yaml"),
+				withProfiles(latest.Profile{
+					Name: "patches",
+					Patches: []latest.JSONPatch{
+						{Op: "replace", Path: "/build/artifacts/0/docker/dockerfile", Value: "Dockerfile.dev"},
+						{Op: "remove", Path: "/build/artifacts/1"},
+						{Op: "add", Path: "/deploy/kubectl/manifests/-", Value: "k8s-dev/*.yaml"},
+					},
+				}),
+			),
+			expected: config(
+				withLocalBuild(
+					withGitTagger(),
+					withDockerArtifact("image", ".", "Dockerfile.dev"),
+				),
+				withKubectlDeploy("k8s/*.yaml", "k8s-dev/*.yaml"),
+			),
+		},
+		{
+			description: "patch unknown path",
+			profile:     "patches",
+			config: config(
+				withLocalBuild(
+					withGitTagger(),
+				),
+				withKubectlDeploy("k8s/*.yaml"),
+				withProfiles(latest.Profile{
+					Name: "patches",
+					Patches: []latest.JSONPatch{
+						{Op: "replace", Path: "/build/artifacts/0/docker/dockerfile", Value: "Dockerfile.dev"},
+					},
+				}),
+			),
+			expected: config(
+				withLocalBuild(
+					withGitTagger(),
+				),
+				withKubectlDeploy("k8s/*.yaml"),
+			),
+			shouldErr: true,
+		},
+		{
+			description: "patch makes config invalid",
+			profile:     "patches",
+			config: config(
+				withLocalBuild(
+					withGitTagger(),
+				),
+				withKubectlDeploy("k8s/*.yaml"),
+				withProfiles(latest.Profile{
+					Name: "patches",
+					Patches: []latest.JSONPatch{
+						{Op: "add", Path: "/build/unknown", Value: "value"},
+					},
+				}),
+			),
+			expected: config(
+				withLocalBuild(
+					withGitTagger(),
+				),
+				withKubectlDeploy("k8s/*.yaml"),
+			),
+			shouldErr: true,
+		},
 	}
 
 	for _, test := range tests {
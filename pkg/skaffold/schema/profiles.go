@@ -37,7 +37,9 @@ func ApplyProfiles(c *latest.SkaffoldPipeline, profiles []string) error {
 			return fmt.Errorf("couldn't find profile %s", name)
 		}
 
-		applyProfile(c, profile)
+		if err := applyProfile(c, profile); err != nil {
+			return errors.Wrapf(err, "applying profile %s", name)
+		}
 	}
 	if err := c.SetDefaultValues(); err != nil {
 		return errors.Wrap(err, "applying default values")
@@ -46,7 +48,7 @@ func ApplyProfiles(c *latest.SkaffoldPipeline, profiles []string) error {
 	return nil
 }
 
-func applyProfile(config *latest.SkaffoldPipeline, profile latest.Profile) {
+func applyProfile(config *latest.SkaffoldPipeline, profile latest.Profile) error {
 	logrus.Infof("applying profile: %s", profile.Name)
 
 	// this intentionally removes the Profiles field from the returned config
@@ -56,7 +58,15 @@ func applyProfile(config *latest.SkaffoldPipeline, profile latest.Profile) {
 		Build:      overlayProfileField(config.Build, profile.Build).(latest.BuildConfig),
 		Deploy:     overlayProfileField(config.Deploy, profile.Deploy).(latest.DeployConfig),
 		Test:       overlayProfileField(config.Test, profile.Test).(latest.TestConfig),
+		Verify:     overlayProfileField(config.Verify, profile.Verify).(latest.VerifyConfig),
+		Scan:       overlayProfileField(config.Scan, profile.Scan).(*latest.ScanConfig),
+		Notify:     config.Notify,
+	}
+
+	if len(profile.Patches) == 0 {
+		return nil
 	}
+	return applyPatches(config, profile.Patches)
 }
 
 func profilesByName(profiles []latest.Profile) map[string]latest.Profile {
@@ -120,6 +130,24 @@ func overlayProfileField(config interface{}, profile interface{}) interface{} {
 			return config
 		}
 		return v.Interface()
+	case reflect.Map:
+		// either return the entries provided in the profile, or the original entries if none were provided.
+		if v.Len() == 0 {
+			return config
+		}
+		return v.Interface()
+	case reflect.String:
+		// either return the value provided in the profile, or the original value if none was provided.
+		if v.Len() == 0 {
+			return config
+		}
+		return v.Interface()
+	case reflect.Ptr:
+		// either return the value provided in the profile, or the original value if none was provided.
+		if v.IsNil() {
+			return config
+		}
+		return v.Interface()
 	default:
 		logrus.Warnf("unknown field type in profile overlay: %s. falling back to original config values", v.Kind())
 		return config
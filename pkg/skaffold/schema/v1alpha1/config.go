@@ -87,7 +87,7 @@ type KubectlDeploy struct {
 }
 
 type Manifest struct {
-	Paths      []string          `yaml:"paths"`
+	Paths      []string          `yaml:"paths" deprecated:"replacement=deploy.kubectl.manifests,removedIn=skaffold/v1alpha2"`
 	Parameters map[string]string `yaml:"parameters,omitempty"`
 }
 
@@ -98,7 +98,7 @@ type HelmDeploy struct {
 type HelmRelease struct {
 	Name           string            `yaml:"name"`
 	ChartPath      string            `yaml:"chartPath"`
-	ValuesFilePath string            `yaml:"valuesFilePath"`
+	ValuesFilePath string            `yaml:"valuesFilePath" deprecated:"replacement=deploy.helm.releases[].valuesFiles,removedIn=skaffold/v1alpha3"`
 	Values         map[string]string `yaml:"values"`
 	Namespace      string            `yaml:"namespace"`
 	Version        string            `yaml:"version"`
@@ -107,7 +107,7 @@ type HelmRelease struct {
 // Artifact represents items that need should be built, along with the context in which
 // they should be built.
 type Artifact struct {
-	ImageName      string             `yaml:"imageName"`
+	ImageName      string             `yaml:"imageName" deprecated:"replacement=build.artifacts[].image,removedIn=skaffold/v1alpha4"`
 	DockerfilePath string             `yaml:"dockerfilePath,omitempty"`
 	Workspace      string             `yaml:"workspace"`
 	BuildArgs      map[string]*string `yaml:"buildArgs,omitempty"`
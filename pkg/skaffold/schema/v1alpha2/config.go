@@ -103,7 +103,7 @@ type GoogleCloudBuild struct {
 // KanikoBuild contains the fields needed to do a on-cluster build using
 // the kaniko image
 type KanikoBuild struct {
-	GCSBucket      string `yaml:"gcsBucket,omitempty"`
+	GCSBucket      string `yaml:"gcsBucket,omitempty" deprecated:"replacement=build.kaniko.buildContext.gcsBucket,removedIn=skaffold/v1alpha3"`
 	PullSecret     string `yaml:"pullSecret,omitempty"`
 	PullSecretName string `yaml:"pullSecretName,omitempty"`
 	Namespace      string `yaml:"namespace,omitempty"`
@@ -152,7 +152,7 @@ type KustomizeDeploy struct {
 type HelmRelease struct {
 	Name              string                 `yaml:"name"`
 	ChartPath         string                 `yaml:"chartPath"`
-	ValuesFilePath    string                 `yaml:"valuesFilePath"`
+	ValuesFilePath    string                 `yaml:"valuesFilePath" deprecated:"replacement=deploy.helm.releases[].valuesFiles,removedIn=skaffold/v1alpha3"`
 	Values            map[string]string      `yaml:"values,omitempty"`
 	Namespace         string                 `yaml:"namespace"`
 	Version           string                 `yaml:"version"`
@@ -195,7 +195,7 @@ type HelmConventionConfig struct {
 // Artifact represents items that need to be built, along with the context in which
 // they should be built.
 type Artifact struct {
-	ImageName    string `yaml:"imageName"`
+	ImageName    string `yaml:"imageName" deprecated:"replacement=build.artifacts[].image,removedIn=skaffold/v1alpha4"`
 	Workspace    string `yaml:"workspace,omitempty"`
 	ArtifactType `yaml:",inline"`
 }
@@ -0,0 +1,322 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// bucketName matches valid Google Cloud Storage bucket names.
+var bucketName = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{1,220}[a-z0-9]$`)
+
+// Validate checks that the files referenced by a configuration exist and
+// that its values are well-formed, so that the problems are found before the
+// pipeline starts. All the problems are reported at once.
+func Validate(config *latest.SkaffoldPipeline) error {
+	var problems []string
+	problems = append(problems, validateArtifacts(config.Build.Artifacts)...)
+	problems = append(problems, validateLocalBuild(config.Build.LocalBuild)...)
+	problems = append(problems, validateKaniko(config.Build.KanikoBuild)...)
+	problems = append(problems, validateECR(config.Build.ECR)...)
+	problems = append(problems, validateSign(config.Build.Sign)...)
+	problems = append(problems, validateSBOM(config.Build.SBOM)...)
+	problems = append(problems, validateTests(config.Test)...)
+	problems = append(problems, validateDeploy(config.Deploy)...)
+	problems = append(problems, validateScan(config.Scan)...)
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return errors.New("invalid skaffold config:\n  " + strings.Join(problems, "\n  "))
+}
+
+func validateArtifacts(artifacts []*latest.Artifact) []string {
+	var problems []string
+
+	for i, artifact := range artifacts {
+		field := fmt.Sprintf("build.artifacts[%d]", i)
+
+		if problem := checkDir(field+".context", artifact.Workspace); problem != "" {
+			problems = append(problems, problem)
+			continue
+		}
+
+		if intercept := artifact.Intercept; intercept != nil && (intercept.Workload == "" || len(intercept.Command) == 0) {
+			problems = append(problems, field+".intercept: workload and command are required")
+		}
+
+		if artifact.DockerArtifact != nil {
+			dockerfile := artifact.DockerArtifact.DockerfilePath
+			if !filepath.IsAbs(dockerfile) {
+				dockerfile = filepath.Join(artifact.Workspace, dockerfile)
+			}
+			if problem := checkFile(field+".docker.dockerfile", dockerfile); problem != "" {
+				problems = append(problems, problem)
+			}
+		}
+	}
+
+	return problems
+}
+
+func validateECR(ecr *latest.ECRConfig) []string {
+	if ecr == nil {
+		return nil
+	}
+
+	switch ecr.ImageTagMutability {
+	case "", "MUTABLE", "IMMUTABLE":
+		return nil
+	default:
+		return []string{fmt.Sprintf("build.ecr.imageTagMutability: %s should be MUTABLE or IMMUTABLE", ecr.ImageTagMutability)}
+	}
+}
+
+func validateScan(scan *latest.ScanConfig) []string {
+	if scan == nil {
+		return nil
+	}
+
+	switch scan.Severity {
+	case "", "LOW", "MEDIUM", "HIGH", "CRITICAL":
+		return nil
+	default:
+		return []string{fmt.Sprintf("scan.severity: %s should be LOW, MEDIUM, HIGH or CRITICAL", scan.Severity)}
+	}
+}
+
+func validateSign(sign *latest.SignConfig) []string {
+	if sign == nil || !sign.Verify || sign.Key != "" || sign.PublicKey != "" {
+		return nil
+	}
+
+	if sign.CertificateIdentity == "" || sign.CertificateOIDCIssuer == "" {
+		return []string{"build.sign: certificateIdentity and certificateOidcIssuer are required to verify keyless signatures"}
+	}
+	return nil
+}
+
+func validateSBOM(sbom *latest.SBOMConfig) []string {
+	if sbom == nil {
+		return nil
+	}
+
+	switch sbom.Format {
+	case "", "spdx-json", "cyclonedx-json":
+		return nil
+	default:
+		return []string{fmt.Sprintf("build.sbom.format: %s should be spdx-json or cyclonedx-json", sbom.Format)}
+	}
+}
+
+func validateLocalBuild(local *latest.LocalBuild) []string {
+	if local == nil || local.Platform == "" || local.Platform == "cluster" {
+		return nil
+	}
+
+	parts := strings.Split(local.Platform, "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return []string{fmt.Sprintf("build.local.platform: %s should be cluster or os/arch[/variant]", local.Platform)}
+	}
+	return nil
+}
+
+func validateKaniko(kaniko *latest.KanikoBuild) []string {
+	if kaniko == nil {
+		return nil
+	}
+
+	var problems []string
+
+	if kaniko.BuildContext != nil && kaniko.BuildContext.GCSBucket != "" {
+		bucket := kaniko.BuildContext.GCSBucket
+		switch {
+		case strings.HasPrefix(bucket, "gs://"):
+			problems = append(problems, fmt.Sprintf("build.kaniko.buildContext.gcsBucket: %s should be a bucket name, without gs://", bucket))
+		case !bucketName.MatchString(bucket):
+			problems = append(problems, fmt.Sprintf("build.kaniko.buildContext.gcsBucket: %s is not a valid bucket name", bucket))
+		}
+	}
+
+	if kaniko.PullSecret != "" {
+		if problem := checkFile("build.kaniko.pullSecret", kaniko.PullSecret); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+	if kaniko.PullSecretName != "" {
+		for _, message := range validation.IsDNS1123Subdomain(kaniko.PullSecretName) {
+			problems = append(problems, fmt.Sprintf("build.kaniko.pullSecretName: %s is not a valid secret name: %s", kaniko.PullSecretName, message))
+		}
+	}
+	if kaniko.Namespace != "" {
+		for _, message := range validation.IsDNS1123Label(kaniko.Namespace) {
+			problems = append(problems, fmt.Sprintf("build.kaniko.namespace: %s is not a valid namespace: %s", kaniko.Namespace, message))
+		}
+	}
+
+	return problems
+}
+
+func validateTests(tests latest.TestConfig) []string {
+	var problems []string
+
+	for i, test := range tests {
+		for j, structureTest := range test.StructureTests {
+			if problem := checkGlob(fmt.Sprintf("test[%d].structureTests[%d]", i, j), structureTest); problem != "" {
+				problems = append(problems, problem)
+			}
+		}
+	}
+
+	return problems
+}
+
+func validateDeploy(deploy latest.DeployConfig) []string {
+	var problems []string
+
+	var keys []string
+	for key := range deploy.Labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, message := range validation.IsQualifiedName(key) {
+			problems = append(problems, fmt.Sprintf("deploy.labels: %s is not a valid label key: %s", key, message))
+		}
+		for _, message := range validation.IsValidLabelValue(deploy.Labels[key]) {
+			problems = append(problems, fmt.Sprintf("deploy.labels.%s: %s is not a valid label value: %s", key, deploy.Labels[key], message))
+		}
+	}
+
+	if deploy.KubectlDeploy != nil {
+		for i, manifest := range deploy.KubectlDeploy.Manifests {
+			if strings.HasPrefix(manifest, "http://") || strings.HasPrefix(manifest, "https://") {
+				continue
+			}
+			if problem := checkGlob(fmt.Sprintf("deploy.kubectl.manifests[%d]", i), manifest); problem != "" {
+				problems = append(problems, problem)
+			}
+		}
+		problems = append(problems, validateRolloutStrategy(deploy.KubectlDeploy)...)
+	}
+
+	if deploy.KustomizeDeploy != nil {
+		if problem := checkDir("deploy.kustomize.path", deploy.KustomizeDeploy.KustomizePath); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+
+	if deploy.HelmDeploy != nil {
+		for i, release := range deploy.HelmDeploy.Releases {
+			for j, valuesFile := range release.ValuesFiles {
+				if problem := checkFile(fmt.Sprintf("deploy.helm.releases[%d].valuesFiles[%d]", i, j), valuesFile); problem != "" {
+					problems = append(problems, problem)
+				}
+			}
+			if release.Packaged != nil && strings.HasPrefix(release.ChartPath, "oci://") {
+				problems = append(problems, fmt.Sprintf("deploy.helm.releases[%d].packaged: charts from an OCI registry can't be packaged", i))
+			}
+			if postRenderer := release.PostRenderer; postRenderer != nil {
+				if len(postRenderer.Command) == 0 {
+					problems = append(problems, fmt.Sprintf("deploy.helm.releases[%d].postRenderer: command is required", i))
+				}
+				for j, dependency := range postRenderer.Dependencies {
+					if problem := checkGlob(fmt.Sprintf("deploy.helm.releases[%d].postRenderer.dependencies[%d]", i, j), dependency); problem != "" {
+						problems = append(problems, problem)
+					}
+				}
+			}
+		}
+	}
+
+	return problems
+}
+
+func validateRolloutStrategy(kubectl *latest.KubectlDeploy) []string {
+	strategy := kubectl.Strategy
+	if strategy == nil {
+		return nil
+	}
+
+	var problems []string
+	if len(kubectl.PruneWhitelist) > 0 {
+		problems = append(problems, "deploy.kubectl.strategy: can't be used with pruneWhitelist")
+	}
+	if canary := strategy.Canary; canary != nil {
+		if canary.Weight < 1 || canary.Weight > 100 {
+			problems = append(problems, fmt.Sprintf("deploy.kubectl.strategy.canary.weight: %d is not a percentage between 1 and 100", canary.Weight))
+		}
+		if _, err := time.ParseDuration(canary.Pause); err != nil {
+			problems = append(problems, fmt.Sprintf("deploy.kubectl.strategy.canary.pause: %s", err))
+		}
+	}
+	return problems
+}
+
+func checkDir(field, path string) string {
+	info, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		return fmt.Sprintf("%s: directory %s doesn't exist", field, path)
+	case err != nil:
+		return fmt.Sprintf("%s: %s", field, err)
+	case !info.IsDir():
+		return fmt.Sprintf("%s: %s is not a directory", field, path)
+	default:
+		return ""
+	}
+}
+
+func checkFile(field, path string) string {
+	info, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		return fmt.Sprintf("%s: file %s doesn't exist", field, path)
+	case err != nil:
+		return fmt.Sprintf("%s: %s", field, err)
+	case info.IsDir():
+		return fmt.Sprintf("%s: %s is a directory", field, path)
+	default:
+		return ""
+	}
+}
+
+func checkGlob(field, pattern string) string {
+	if _, err := os.Stat(pattern); err == nil {
+		return ""
+	}
+
+	matches, err := filepath.Glob(pattern)
+	switch {
+	case err != nil:
+		return fmt.Sprintf("%s: invalid pattern %s: %s", field, pattern, err)
+	case len(matches) == 0:
+		return fmt.Sprintf("%s: %s doesn't match any file", field, pattern)
+	default:
+		return ""
+	}
+}
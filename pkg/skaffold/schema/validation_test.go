@@ -0,0 +1,161 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"os"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestValidate(t *testing.T) {
+	var tests = []struct {
+		description string
+		config      *latest.SkaffoldPipeline
+		expected    string
+	}{
+		{
+			description: "valid",
+			config: config(
+				withLocalBuild(withDockerArtifact("image", "app", "Dockerfile")),
+				withKubectlDeploy("k8s/*.yaml"),
+			),
+		},
+		{
+			description: "missing files",
+			config: config(
+				withLocalBuild(
+					withDockerArtifact("image", "missing", "Dockerfile"),
+					withDockerArtifact("image", "app", "Dockerfile.missing"),
+				),
+				withKubectlDeploy("k8s/*.yaml", "other/*.yaml", "https://example.com/manifest.yaml"),
+			),
+			expected: `invalid skaffold config:
+  build.artifacts[0].context: directory missing doesn't exist
+  build.artifacts[1].docker.dockerfile: file app/Dockerfile.missing doesn't exist
+  deploy.kubectl.manifests[1]: other/*.yaml doesn't match any file`,
+		},
+		{
+			description: "kaniko",
+			config: config(
+				withKanikoBuild("gs://bucket", "kaniko-secret", "default", "", ""),
+				withKubectlDeploy("k8s/*.yaml"),
+			),
+			expected: `invalid skaffold config:
+  build.kaniko.buildContext.gcsBucket: gs://bucket should be a bucket name, without gs://`,
+		},
+		{
+			description: "ecr",
+			config: config(
+				withLocalBuild(withECR(&latest.ECRConfig{CreateRepositories: true, ImageTagMutability: "immutable"})),
+				withKubectlDeploy("k8s/*.yaml"),
+			),
+			expected: `invalid skaffold config:
+  build.ecr.imageTagMutability: immutable should be MUTABLE or IMMUTABLE`,
+		},
+		{
+			description: "scan",
+			config: config(
+				withLocalBuild(),
+				withKubectlDeploy("k8s/*.yaml"),
+				func(cfg *latest.SkaffoldPipeline) {
+					cfg.Scan = &latest.ScanConfig{Trivy: &latest.TrivyScanner{}, Severity: "SEVERE"}
+				},
+			),
+			expected: `invalid skaffold config:
+  scan.severity: SEVERE should be LOW, MEDIUM, HIGH or CRITICAL`,
+		},
+		{
+			description: "keyless verification",
+			config: config(
+				withLocalBuild(withSign(&latest.SignConfig{Verify: true, CertificateIdentity: "ci@example.com"})),
+				withKubectlDeploy("k8s/*.yaml"),
+			),
+			expected: `invalid skaffold config:
+  build.sign: certificateIdentity and certificateOidcIssuer are required to verify keyless signatures`,
+		},
+		{
+			description: "local platform",
+			config: config(
+				withLocalBuild(func(cfg *latest.BuildConfig) { cfg.LocalBuild.Platform = "arm64" }),
+				withKubectlDeploy("k8s/*.yaml"),
+			),
+			expected: `invalid skaffold config:
+  build.local.platform: arm64 should be cluster or os/arch[/variant]`,
+		},
+		{
+			description: "sbom format",
+			config: config(
+				withLocalBuild(withSBOM(&latest.SBOMConfig{Format: "spdx"})),
+				withKubectlDeploy("k8s/*.yaml"),
+			),
+			expected: `invalid skaffold config:
+  build.sbom.format: spdx should be spdx-json or cyclonedx-json`,
+		},
+		{
+			description: "canary strategy",
+			config: config(
+				withLocalBuild(),
+				withKubectlDeploy("k8s/*.yaml"),
+				func(cfg *latest.SkaffoldPipeline) {
+					cfg.Deploy.KubectlDeploy.PruneWhitelist = []string{"core/v1/ConfigMap"}
+					cfg.Deploy.KubectlDeploy.Strategy = &latest.RolloutStrategy{Canary: &latest.CanaryStrategy{Weight: 150, Pause: "1 minute"}}
+				},
+			),
+			expected: `invalid skaffold config:
+  deploy.kubectl.strategy: can't be used with pruneWhitelist
+  deploy.kubectl.strategy.canary.weight: 150 is not a percentage between 1 and 100
+  deploy.kubectl.strategy.canary.pause: time: unknown unit " minute" in duration "1 minute"`,
+		},
+		{
+			description: "labels",
+			config: config(
+				withLocalBuild(withDockerArtifact("image", "app", "Dockerfile")),
+				withKubectlDeploy("k8s/*.yaml"),
+				withDeployLabels(map[string]string{"team": "payments", "bad key": "value", "ticket": "PAY 123"}),
+			),
+			expected: `invalid skaffold config:
+  deploy.labels: bad key is not a valid label key: name part must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]')
+  deploy.labels.ticket: PAY 123 is not a valid label value: a valid label must be an empty string or consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyValue',  or 'my_value',  or '12345', regex used for validation is '(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])?')`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			tmp, cleanup := testutil.NewTempDir(t)
+			defer cleanup()
+
+			tmp.Mkdir("app").Mkdir("k8s").
+				Write("app/Dockerfile", "FROM scratch").
+				Write("k8s/deployment.yaml", "")
+
+			wd, _ := os.Getwd()
+			os.Chdir(tmp.Root())
+			defer os.Chdir(wd)
+
+			err := Validate(test.config)
+
+			if test.expected == "" {
+				testutil.CheckError(t, false, err)
+			} else {
+				testutil.CheckErrorAndDeepEqual(t, true, err, test.expected, err.Error())
+			}
+		})
+	}
+}
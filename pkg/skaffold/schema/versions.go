@@ -68,6 +68,11 @@ func ParseConfig(filename string, applyDefaults bool) (util.VersionedConfig, err
 		return nil, errors.Wrap(err, "read skaffold config")
 	}
 
+	buf, err = resolveIncludes(filename, buf)
+	if err != nil {
+		return nil, errors.Wrap(err, "including configs")
+	}
+
 	apiVersion := &APIVersion{}
 	if err := yaml.Unmarshal(buf, apiVersion); err != nil {
 		return nil, errors.Wrap(err, "parsing api version")
@@ -80,16 +85,55 @@ func ParseConfig(filename string, applyDefaults bool) (util.VersionedConfig, err
 
 	cfg := factory()
 	if err := cfg.Parse(buf, applyDefaults); err != nil {
-		return nil, errors.Wrap(err, "unable to parse config")
+		return nil, errors.Wrap(withYamlPaths(buf, err), "unable to parse config")
 	}
 
 	if err := yamltags.ProcessStruct(cfg); err != nil {
 		return nil, errors.Wrap(err, "invalid config")
 	}
+	warnDeprecations(filename, cfg)
 
 	return cfg, nil
 }
 
+// ParseAndUpgradeConfig reads a configuration file and, if it's using an older
+// version of the schema, upgrades it in memory to the latest version.
+// Default values are applied to the upgraded configuration.
+func ParseAndUpgradeConfig(filename string, applyDefaults bool) (*latest.SkaffoldPipeline, error) {
+	cfg, err := ParseConfig(filename, false)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedVersion, err := apiversion.Parse(cfg.GetVersion())
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing api version")
+	}
+
+	latestVersion := apiversion.MustParse(latest.Version)
+	if parsedVersion.GT(latestVersion) {
+		return nil, errors.New("config version is too new for this version of skaffold: upgrade skaffold")
+	}
+
+	if parsedVersion.LT(latestVersion) {
+		warnOnce("Config version %s is deprecated and was upgraded in memory to %s: run `skaffold fix --overwrite` to upgrade %s", cfg.GetVersion(), latest.Version, filename)
+
+		cfg, err = UpgradeToLatest(cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pipeline := cfg.(*latest.SkaffoldPipeline)
+	if applyDefaults {
+		if err := pipeline.SetDefaultValues(); err != nil {
+			return nil, errors.Wrap(err, "applying default values")
+		}
+	}
+
+	return pipeline, nil
+}
+
 // CheckVersionIsLatest checks that a given version is the most recent.
 func CheckVersionIsLatest(apiVersion string) error {
 	parsedVersion, err := apiversion.Parse(apiVersion)
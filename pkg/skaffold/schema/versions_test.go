@@ -269,6 +269,36 @@ func withHelmDeploy() func(*latest.SkaffoldPipeline) {
 	}
 }
 
+func withDeployLabels(labels map[string]string) func(*latest.SkaffoldPipeline) {
+	return func(cfg *latest.SkaffoldPipeline) {
+		cfg.Deploy.Labels = labels
+	}
+}
+
+func withBuildTimeout(timeout string) func(*latest.BuildConfig) {
+	return func(cfg *latest.BuildConfig) {
+		cfg.Timeout = timeout
+	}
+}
+
+func withECR(ecr *latest.ECRConfig) func(*latest.BuildConfig) {
+	return func(cfg *latest.BuildConfig) {
+		cfg.ECR = ecr
+	}
+}
+
+func withSign(sign *latest.SignConfig) func(*latest.BuildConfig) {
+	return func(cfg *latest.BuildConfig) {
+		cfg.Sign = sign
+	}
+}
+
+func withSBOM(sbom *latest.SBOMConfig) func(*latest.BuildConfig) {
+	return func(cfg *latest.BuildConfig) {
+		cfg.SBOM = sbom
+	}
+}
+
 func withDockerArtifact(image, workspace, dockerfile string) func(*latest.BuildConfig) {
 	return func(cfg *latest.BuildConfig) {
 		cfg.Artifacts = append(cfg.Artifacts, &latest.Artifact{
@@ -315,6 +345,69 @@ func withProfiles(profiles ...latest.Profile) func(*latest.SkaffoldPipeline) {
 	}
 }
 
+func TestParseAndUpgradeConfig(t *testing.T) {
+	cleanup := testutil.SetupFakeKubernetesContext(t, api.Config{CurrentContext: "cluster1"})
+	defer cleanup()
+
+	var tests = []struct {
+		description string
+		config      string
+		expected    *latest.SkaffoldPipeline
+		shouldErr   bool
+	}{
+		{
+			description: "latest version",
+			config:      fmt.Sprintf("apiVersion: %s\nkind: Config\n%s", latest.Version, simpleConfig),
+			expected: config(
+				withLocalBuild(
+					withGitTagger(),
+					withDockerArtifact("example", ".", "Dockerfile"),
+				),
+				withKubectlDeploy("k8s/*.yaml"),
+			),
+		},
+		{
+			description: "old version is upgraded",
+			config: `apiVersion: skaffold/v1alpha1
+kind: Config
+build:
+  artifacts:
+  - imageName: example
+deploy:
+  kubectl:
+    manifests:
+    - paths:
+      - k8s/*.yaml
+`,
+			expected: config(
+				withLocalBuild(
+					withGitTagger(),
+					withDockerArtifact("example", ".", "Dockerfile"),
+				),
+				withKubectlDeploy("k8s/*.yaml"),
+			),
+		},
+		{
+			description: "too new",
+			config:      "apiVersion: skaffold/v9\nkind: Config\n",
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			tmp, cleanup := testutil.NewTempDir(t)
+			defer cleanup()
+
+			tmp.Write("skaffold.yaml", test.config)
+
+			cfg, err := ParseAndUpgradeConfig(tmp.Path("skaffold.yaml"), true)
+
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, cfg)
+		})
+	}
+}
+
 func TestCheckVersionIsLatest(t *testing.T) {
 	tests := []struct {
 		name      string
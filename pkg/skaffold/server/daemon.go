@@ -0,0 +1,205 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/sirupsen/logrus"
+)
+
+// Daemon is what the daemon API can ask a warm runner to do.
+// The operations are stopped when their context is cancelled,
+// which happens when the client goes away.
+type Daemon interface {
+	// Build builds the artifacts.
+	Build(ctx context.Context, out io.Writer) ([]build.Artifact, error)
+
+	// Deploy deploys the given images, or the last ones built if none is given.
+	Deploy(ctx context.Context, out io.Writer, builds []build.Artifact) error
+
+	// Run builds, tests and deploys the artifacts once.
+	Run(ctx context.Context, out io.Writer) error
+
+	// Dev runs a dev loop until the context is cancelled.
+	Dev(ctx context.Context, out io.Writer) error
+}
+
+// DaemonMessage is a line of the daemon API responses, that are newline
+// delimited JSON. The output of an operation is streamed, then the last
+// message, marked as done, tells whether it succeeded.
+type DaemonMessage struct {
+	Output string           `json:"output,omitempty"`
+	Done   bool             `json:"done,omitempty"`
+	Error  string           `json:"error,omitempty"`
+	Builds []build.Artifact `json:"builds,omitempty"`
+}
+
+// DeployRequest is the payload of the deploy endpoint.
+type DeployRequest struct {
+	Builds []build.Artifact `json:"builds"`
+}
+
+// daemonOperation runs an operation, streaming its output, and returns its result.
+type daemonOperation func(ctx context.Context, out io.Writer) (DaemonMessage, error)
+
+// InitializeDaemon starts the daemon API on the given port.
+// It returns a function that shuts the server down.
+func InitializeDaemon(port int, daemon Daemon) (func() error, error) {
+	return serve("Daemon API", port, NewDaemonHandler(daemon))
+}
+
+// NewDaemonHandler returns the http.Handler serving the daemon API.
+// Operations are run one at a time: others are refused while one is running.
+func NewDaemonHandler(daemon Daemon) http.Handler {
+	busy := make(chan struct{}, 1)
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/v1/build", operation(busy, func(r *http.Request) (daemonOperation, error) {
+		return func(ctx context.Context, out io.Writer) (DaemonMessage, error) {
+			builds, err := daemon.Build(ctx, out)
+			return DaemonMessage{Builds: builds}, err
+		}, nil
+	}))
+	mux.HandleFunc("/v1/deploy", operation(busy, func(r *http.Request) (daemonOperation, error) {
+		var payload DeployRequest
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				return nil, err
+			}
+		}
+
+		return func(ctx context.Context, out io.Writer) (DaemonMessage, error) {
+			return DaemonMessage{}, daemon.Deploy(ctx, out, payload.Builds)
+		}, nil
+	}))
+	mux.HandleFunc("/v1/run", operation(busy, func(r *http.Request) (daemonOperation, error) {
+		return func(ctx context.Context, out io.Writer) (DaemonMessage, error) {
+			return DaemonMessage{}, daemon.Run(ctx, out)
+		}, nil
+	}))
+	mux.HandleFunc("/v1/dev", operation(busy, func(r *http.Request) (daemonOperation, error) {
+		return func(ctx context.Context, out io.Writer) (DaemonMessage, error) {
+			return DaemonMessage{}, daemon.Dev(ctx, out)
+		}, nil
+	}))
+
+	return mux
+}
+
+// operation serves a daemon operation. parse reads the request and returns
+// the function that runs the operation, or an error if the request is invalid.
+func operation(busy chan struct{}, parse func(*http.Request) (daemonOperation, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, fmt.Sprintf("method %s not allowed", r.Method), http.StatusMethodNotAllowed)
+			return
+		}
+		if !fromLocalClient(r) {
+			http.Error(w, "only local clients are allowed", http.StatusForbidden)
+			return
+		}
+
+		run, err := parse(r)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("decoding payload: %s", err), http.StatusBadRequest)
+			return
+		}
+
+		select {
+		case busy <- struct{}{}:
+			defer func() { <-busy }()
+		default:
+			http.Error(w, "another operation is running", http.StatusConflict)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		out := newMessageWriter(w)
+
+		result, err := run(r.Context(), out)
+		result.Done = true
+		if err != nil {
+			result.Error = err.Error()
+		}
+		out.send(result)
+	}
+}
+
+// fromLocalClient tells requests sent by local tools, like IDEs, from the ones
+// that web pages make a browser send: to localhost directly (CSRF), in which case
+// the browser adds an Origin, or through a domain that resolves to 127.0.0.1
+// (DNS rebinding), in which case the Host isn't a loopback one.
+func fromLocalClient(r *http.Request) bool {
+	if r.Header.Get("Origin") != "" {
+		return false
+	}
+
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		host = r.Host
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// messageWriter streams what's written to it as DaemonMessages.
+type messageWriter struct {
+	lock    sync.Mutex
+	encoder *json.Encoder
+	flusher http.Flusher
+}
+
+func newMessageWriter(w http.ResponseWriter) *messageWriter {
+	flusher, _ := w.(http.Flusher)
+
+	return &messageWriter{
+		encoder: json.NewEncoder(w),
+		flusher: flusher,
+	}
+}
+
+func (w *messageWriter) Write(p []byte) (int, error) {
+	if err := w.send(DaemonMessage{Output: string(p)}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w *messageWriter) send(message DaemonMessage) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if err := w.encoder.Encode(message); err != nil {
+		logrus.Debugln("Unable to write daemon message:", err)
+		return err
+	}
+	if w.flusher != nil {
+		w.flusher.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+type fakeDaemon struct {
+	deployed []build.Artifact
+	devErr   error
+}
+
+func (f *fakeDaemon) Build(ctx context.Context, out io.Writer) ([]build.Artifact, error) {
+	fmt.Fprintln(out, "Building...")
+	return []build.Artifact{{ImageName: "image", Tag: "image:v1"}}, nil
+}
+
+func (f *fakeDaemon) Deploy(ctx context.Context, out io.Writer, builds []build.Artifact) error {
+	f.deployed = builds
+	return nil
+}
+
+func (f *fakeDaemon) Run(ctx context.Context, out io.Writer) error {
+	return nil
+}
+
+func (f *fakeDaemon) Dev(ctx context.Context, out io.Writer) error {
+	return f.devErr
+}
+
+// daemonURL is where the daemon API listens by default.
+const daemonURL = "http://localhost:50052"
+
+func TestDaemonHandler(t *testing.T) {
+	var tests = []struct {
+		description      string
+		method           string
+		url              string
+		origin           string
+		body             string
+		expectedStatus   int
+		expectedBody     string
+		expectedDeployed []build.Artifact
+	}{
+		{
+			description:    "build",
+			method:         http.MethodPost,
+			url:            daemonURL + "/v1/build",
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"output":"Building...\n"}
+{"done":true,"builds":[{"imageName":"image","tag":"image:v1"}]}
+`,
+		},
+		{
+			description:      "deploy",
+			method:           http.MethodPost,
+			url:              daemonURL + "/v1/deploy",
+			body:             `{"builds":[{"imageName":"image","tag":"image:v2"}]}`,
+			expectedStatus:   http.StatusOK,
+			expectedBody:     `{"done":true}` + "\n",
+			expectedDeployed: []build.Artifact{{ImageName: "image", Tag: "image:v2"}},
+		},
+		{
+			description:    "invalid deploy payload",
+			method:         http.MethodPost,
+			url:            daemonURL + "/v1/deploy",
+			body:           `not json`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "decoding payload: invalid character 'o' in literal null (expecting 'u')\n",
+		},
+		{
+			description:    "failed dev loop",
+			method:         http.MethodPost,
+			url:            daemonURL + "/v1/dev",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"done":true,"error":"dev failed"}` + "\n",
+		},
+		{
+			description:    "wrong method",
+			method:         http.MethodGet,
+			url:            daemonURL + "/v1/run",
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedBody:   "method GET not allowed\n",
+		},
+		{
+			description:    "web page",
+			method:         http.MethodPost,
+			url:            daemonURL + "/v1/run",
+			origin:         "https://example.com",
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   "only local clients are allowed\n",
+		},
+		{
+			description:    "dns rebinding",
+			method:         http.MethodPost,
+			url:            "http://attacker.example.com:50052/v1/run",
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   "only local clients are allowed\n",
+		},
+		{
+			description:    "loopback address",
+			method:         http.MethodPost,
+			url:            "http://127.0.0.1:50052/v1/run",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"done":true}` + "\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			daemon := &fakeDaemon{devErr: errors.New("dev failed")}
+			handler := NewDaemonHandler(daemon)
+
+			req := httptest.NewRequest(test.method, test.url, strings.NewReader(test.body))
+			if test.origin != "" {
+				req.Header.Set("Origin", test.origin)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			testutil.CheckDeepEqual(t, test.expectedStatus, rec.Code)
+			testutil.CheckDeepEqual(t, test.expectedBody, rec.Body.String())
+			testutil.CheckDeepEqual(t, test.expectedDeployed, daemon.deployed)
+		})
+	}
+}
+
+type blockingDaemon struct {
+	fakeDaemon
+	started chan bool
+}
+
+func (b *blockingDaemon) Dev(ctx context.Context, out io.Writer) error {
+	b.started <- true
+	<-ctx.Done()
+	return nil
+}
+
+func TestDaemonOneOperationAtATime(t *testing.T) {
+	daemon := &blockingDaemon{started: make(chan bool)}
+	handler := NewDaemonHandler(daemon)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	devDone := make(chan bool)
+	go func() {
+		req := httptest.NewRequest(http.MethodPost, daemonURL+"/v1/dev", nil).WithContext(ctx)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		devDone <- true
+	}()
+	<-daemon.started
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, daemonURL+"/v1/build", nil))
+	testutil.CheckDeepEqual(t, http.StatusConflict, rec.Code)
+
+	cancel()
+	<-devDone
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, daemonURL+"/v1/build", nil))
+	testutil.CheckDeepEqual(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Control is what the control API can ask the dev loop to do.
+type Control interface {
+	// RequestBuild asks for the pending changes to be built.
+	RequestBuild()
+
+	// RequestSync asks for the pending file changes to be synced.
+	RequestSync()
+
+	// RequestDeploy asks for a redeploy.
+	RequestDeploy()
+
+	// RequestReload asks for the skaffold configuration to be reloaded.
+	RequestReload()
+
+	// SetAutoBuild toggles automatic builds on file changes.
+	SetAutoBuild(bool)
+
+	// SetAutoSync toggles automatic syncs on file changes.
+	SetAutoSync(bool)
+
+	// SetAutoDeploy toggles automatic deploys after a build.
+	SetAutoDeploy(bool)
+
+	// PauseWatch stops watching for file changes until ResumeWatch is called.
+	// The changes made in the meantime are then handled at once.
+	PauseWatch()
+
+	// ResumeWatch resumes watching for file changes.
+	ResumeWatch()
+}
+
+// AutoExecute is the payload of the auto_execute endpoints.
+type AutoExecute struct {
+	Enabled bool `json:"enabled"`
+}
+
+const shutdownTimeout = 5 * time.Second
+
+// Initialize starts the control API on the given port.
+// It returns a function that shuts the server down.
+func Initialize(port int, control Control) (func() error, error) {
+	return serve("Control API", port, NewHandler(control))
+}
+
+func serve(name string, port int, handler http.Handler) (func() error, error) {
+	l, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", port))
+	if err != nil {
+		return nil, errors.Wrapf(err, "listening on port %d", port)
+	}
+
+	srv := &http.Server{
+		Handler: handler,
+	}
+
+	go func() {
+		if err := srv.Serve(l); err != nil && err != http.ErrServerClosed {
+			logrus.Errorf("%s: %s", name, err)
+		}
+	}()
+	logrus.Infof("%s listening on %s", name, l.Addr())
+
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		return srv.Shutdown(ctx)
+	}, nil
+}
+
+// NewHandler returns the http.Handler serving the control API.
+func NewHandler(control Control) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/v1/build/execute", execute(control.RequestBuild))
+	mux.HandleFunc("/v1/sync/execute", execute(control.RequestSync))
+	mux.HandleFunc("/v1/deploy/execute", execute(control.RequestDeploy))
+	mux.HandleFunc("/v1/config/reload", execute(control.RequestReload))
+	mux.HandleFunc("/v1/watch/pause", execute(control.PauseWatch))
+	mux.HandleFunc("/v1/watch/resume", execute(control.ResumeWatch))
+
+	mux.HandleFunc("/v1/build/auto_execute", autoExecute(control.SetAutoBuild))
+	mux.HandleFunc("/v1/sync/auto_execute", autoExecute(control.SetAutoSync))
+	mux.HandleFunc("/v1/deploy/auto_execute", autoExecute(control.SetAutoDeploy))
+
+	return mux
+}
+
+func execute(request func()) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, fmt.Sprintf("method %s not allowed", r.Method), http.StatusMethodNotAllowed)
+			return
+		}
+
+		request()
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+func autoExecute(set func(bool)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			http.Error(w, fmt.Sprintf("method %s not allowed", r.Method), http.StatusMethodNotAllowed)
+			return
+		}
+
+		var payload AutoExecute
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, fmt.Sprintf("decoding payload: %s", err), http.StatusBadRequest)
+			return
+		}
+
+		set(payload.Enabled)
+		w.WriteHeader(http.StatusOK)
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+type fakeControl struct {
+	calls []string
+}
+
+func (f *fakeControl) RequestBuild()  { f.calls = append(f.calls, "build") }
+func (f *fakeControl) RequestSync()   { f.calls = append(f.calls, "sync") }
+func (f *fakeControl) RequestDeploy() { f.calls = append(f.calls, "deploy") }
+func (f *fakeControl) RequestReload() { f.calls = append(f.calls, "reload") }
+func (f *fakeControl) PauseWatch()    { f.calls = append(f.calls, "pause") }
+func (f *fakeControl) ResumeWatch()   { f.calls = append(f.calls, "resume") }
+
+func (f *fakeControl) SetAutoBuild(enabled bool)  { f.record("autoBuild", enabled) }
+func (f *fakeControl) SetAutoSync(enabled bool)   { f.record("autoSync", enabled) }
+func (f *fakeControl) SetAutoDeploy(enabled bool) { f.record("autoDeploy", enabled) }
+
+func (f *fakeControl) record(name string, enabled bool) {
+	if enabled {
+		f.calls = append(f.calls, name+"=on")
+	} else {
+		f.calls = append(f.calls, name+"=off")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	var tests = []struct {
+		description    string
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+		expectedCalls  []string
+	}{
+		{
+			description:    "build",
+			method:         http.MethodPost,
+			path:           "/v1/build/execute",
+			expectedStatus: http.StatusAccepted,
+			expectedCalls:  []string{"build"},
+		},
+		{
+			description:    "sync",
+			method:         http.MethodPost,
+			path:           "/v1/sync/execute",
+			expectedStatus: http.StatusAccepted,
+			expectedCalls:  []string{"sync"},
+		},
+		{
+			description:    "deploy",
+			method:         http.MethodPost,
+			path:           "/v1/deploy/execute",
+			expectedStatus: http.StatusAccepted,
+			expectedCalls:  []string{"deploy"},
+		},
+		{
+			description:    "reload",
+			method:         http.MethodPost,
+			path:           "/v1/config/reload",
+			expectedStatus: http.StatusAccepted,
+			expectedCalls:  []string{"reload"},
+		},
+		{
+			description:    "pause watch",
+			method:         http.MethodPost,
+			path:           "/v1/watch/pause",
+			expectedStatus: http.StatusAccepted,
+			expectedCalls:  []string{"pause"},
+		},
+		{
+			description:    "resume watch",
+			method:         http.MethodPost,
+			path:           "/v1/watch/resume",
+			expectedStatus: http.StatusAccepted,
+			expectedCalls:  []string{"resume"},
+		},
+		{
+			description:    "wrong method",
+			method:         http.MethodGet,
+			path:           "/v1/build/execute",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			description:    "disable auto build",
+			method:         http.MethodPut,
+			path:           "/v1/build/auto_execute",
+			body:           `{"enabled":false}`,
+			expectedStatus: http.StatusOK,
+			expectedCalls:  []string{"autoBuild=off"},
+		},
+		{
+			description:    "enable auto sync",
+			method:         http.MethodPut,
+			path:           "/v1/sync/auto_execute",
+			body:           `{"enabled":true}`,
+			expectedStatus: http.StatusOK,
+			expectedCalls:  []string{"autoSync=on"},
+		},
+		{
+			description:    "invalid payload",
+			method:         http.MethodPut,
+			path:           "/v1/deploy/auto_execute",
+			body:           `not json`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "unknown path",
+			method:         http.MethodPost,
+			path:           "/v1/unknown",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			control := &fakeControl{}
+			handler := NewHandler(control)
+
+			req := httptest.NewRequest(test.method, test.path, strings.NewReader(test.body))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			testutil.CheckDeepEqual(t, test.expectedStatus, rec.Code)
+			testutil.CheckDeepEqual(t, test.expectedCalls, control.calls)
+		})
+	}
+}
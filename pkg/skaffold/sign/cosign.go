@@ -0,0 +1,128 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sign
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+)
+
+var remoteDigest = docker.RemoteDigest // for testing
+
+// Signer signs the built images with cosign and verifies their signatures.
+type Signer struct {
+	cfg *latest.SignConfig
+}
+
+// NewSigner creates a Signer with the given settings.
+func NewSigner(cfg *latest.SignConfig) *Signer {
+	return &Signer{cfg: cfg}
+}
+
+// Sign signs the image of an artifact and returns the reference of the signature.
+func (s *Signer) Sign(ctx context.Context, out io.Writer, artifact build.Artifact) (string, error) {
+	digest, err := DigestReference(artifact.Tag)
+	if err != nil {
+		return "", err
+	}
+
+	color.Default.Fprintf(out, "Signing %s...\n", digest)
+
+	args := []string{"sign", "--yes"}
+	if s.cfg.Key != "" {
+		args = append(args, "--key", s.cfg.Key)
+	}
+	args = append(args, digest.String())
+
+	if err := runCosign(ctx, args); err != nil {
+		return "", errors.Wrapf(err, "signing %s", digest)
+	}
+
+	return AttachmentReference(digest, "sig"), nil
+}
+
+// Verify checks the signature of the image of an artifact.
+func (s *Signer) Verify(ctx context.Context, out io.Writer, artifact build.Artifact) error {
+	digest, err := DigestReference(artifact.Tag)
+	if err != nil {
+		return err
+	}
+
+	color.Default.Fprintf(out, "Verifying the signature of %s...\n", digest)
+
+	args := []string{"verify"}
+	switch {
+	case s.cfg.PublicKey != "":
+		args = append(args, "--key", s.cfg.PublicKey)
+	case s.cfg.Key != "":
+		args = append(args, "--key", s.cfg.Key)
+	default:
+		args = append(args, "--certificate-identity", s.cfg.CertificateIdentity, "--certificate-oidc-issuer", s.cfg.CertificateOIDCIssuer)
+	}
+	args = append(args, digest.String())
+
+	if err := runCosign(ctx, args); err != nil {
+		return errors.Wrapf(err, "verifying the signature of %s", digest)
+	}
+
+	return nil
+}
+
+// runCosign runs a cosign command. Its output is only shown when it fails.
+func runCosign(ctx context.Context, args []string) error {
+	_, err := util.RunCmdOut(exec.CommandContext(ctx, "cosign", args...))
+	return err
+}
+
+// DigestReference resolves the digest of a pushed image, since cosign signs digests.
+func DigestReference(tag string) (name.Digest, error) {
+	image, hash := tag, ""
+	if i := strings.Index(tag, "@"); i != -1 {
+		image, hash = tag[:i], tag[i+1:]
+	}
+
+	ref, err := name.ParseReference(image, name.WeakValidation)
+	if err != nil {
+		return name.Digest{}, errors.Wrapf(err, "parsing %s", tag)
+	}
+
+	if hash == "" {
+		if hash, err = remoteDigest(tag); err != nil {
+			return name.Digest{}, errors.Wrapf(err, "getting the digest of %s, images have to be pushed to be signed", tag)
+		}
+	}
+
+	digest, err := name.NewDigest(fmt.Sprintf("%s@%s", ref.Context(), hash), name.WeakValidation)
+	return digest, errors.Wrapf(err, "parsing digest of %s", tag)
+}
+
+// AttachmentReference is where cosign stores what it attaches to an image, such as
+// its signature or its SBOM: next to it, in a tag named after its digest.
+func AttachmentReference(digest name.Digest, kind string) string {
+	return fmt.Sprintf("%s:%s.%s", digest.Context(), strings.Replace(digest.DigestStr(), ":", "-", 1), kind)
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sign
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+const digest = "sha256:27ffc7f352665cc50ae3cbcc4b2725e36062f1b38c611b6f95d6df9a7510de23"
+
+func TestSign(t *testing.T) {
+	var tests = []struct {
+		description string
+		cfg         *latest.SignConfig
+		tag         string
+		digestErr   error
+		expectedCmd string
+		shouldErr   bool
+	}{
+		{
+			description: "keyless",
+			cfg:         &latest.SignConfig{},
+			tag:         "gcr.io/project/image:v1",
+			expectedCmd: "cosign sign --yes gcr.io/project/image@" + digest,
+		},
+		{
+			description: "with key",
+			cfg:         &latest.SignConfig{Key: "gcpkms://projects/p/locations/l/keyRings/r/cryptoKeys/k"},
+			tag:         "gcr.io/project/image:v1@" + digest,
+			expectedCmd: "cosign sign --yes --key gcpkms://projects/p/locations/l/keyRings/r/cryptoKeys/k gcr.io/project/image@" + digest,
+		},
+		{
+			description: "image not pushed",
+			cfg:         &latest.SignConfig{},
+			tag:         "image:v1",
+			digestErr:   fmt.Errorf("not found"),
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+			util.DefaultExecCommand = testutil.NewFakeCmdOut(test.expectedCmd, "", nil)
+			defer func(f func(string) (string, error)) { remoteDigest = f }(remoteDigest)
+			remoteDigest = func(string) (string, error) { return digest, test.digestErr }
+
+			signature, err := NewSigner(test.cfg).Sign(context.Background(), ioutil.Discard, build.Artifact{ImageName: "image", Tag: test.tag})
+
+			expected := ""
+			if !test.shouldErr {
+				expected = "gcr.io/project/image:sha256-27ffc7f352665cc50ae3cbcc4b2725e36062f1b38c611b6f95d6df9a7510de23.sig"
+			}
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, expected, signature)
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	tag := "gcr.io/project/image:v1@" + digest
+
+	var tests = []struct {
+		description string
+		cfg         *latest.SignConfig
+		expectedCmd string
+	}{
+		{
+			description: "public key",
+			cfg:         &latest.SignConfig{Key: "cosign.key", PublicKey: "cosign.pub"},
+			expectedCmd: "cosign verify --key cosign.pub gcr.io/project/image@" + digest,
+		},
+		{
+			description: "kms key",
+			cfg:         &latest.SignConfig{Key: "awskms:///alias/skaffold"},
+			expectedCmd: "cosign verify --key awskms:///alias/skaffold gcr.io/project/image@" + digest,
+		},
+		{
+			description: "keyless",
+			cfg:         &latest.SignConfig{CertificateIdentity: "ci@example.com", CertificateOIDCIssuer: "https://accounts.google.com"},
+			expectedCmd: "cosign verify --certificate-identity ci@example.com --certificate-oidc-issuer https://accounts.google.com gcr.io/project/image@" + digest,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+			util.DefaultExecCommand = testutil.NewFakeCmdOut(test.expectedCmd, "", nil)
+
+			err := NewSigner(test.cfg).Verify(context.Background(), ioutil.Discard, build.Artifact{ImageName: "image", Tag: tag})
+
+			testutil.CheckError(t, false, err)
+		})
+	}
+}
@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"os/exec"
 
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync"
 
 	"github.com/pkg/errors"
@@ -28,18 +30,21 @@ import (
 	"k8s.io/api/core/v1"
 )
 
-type Syncer struct{}
+// Syncer copies files into running containers with kubectl.
+type Syncer struct {
+	Pods *kubernetes.PodCache
+}
 
 func (k *Syncer) Sync(ctx context.Context, s *sync.Item) error {
 	logrus.Infoln("Copying files:", s.Copy, "to", s.Image)
 
-	if err := sync.Perform(ctx, s.Image, s.Copy, copyFileFn); err != nil {
+	if err := sync.Perform(ctx, k.Pods, s.Image, s.Copy, copyFileFn); err != nil {
 		return errors.Wrap(err, "copying files")
 	}
 
 	logrus.Infoln("Deleting files:", s.Delete, "from", s.Image)
 
-	if err := sync.Perform(ctx, s.Image, s.Delete, deleteFileFn); err != nil {
+	if err := sync.Perform(ctx, k.Pods, s.Image, s.Delete, deleteFileFn); err != nil {
 		return errors.Wrap(err, "deleting files")
 	}
 
@@ -47,9 +52,9 @@ func (k *Syncer) Sync(ctx context.Context, s *sync.Item) error {
 }
 
 func deleteFileFn(ctx context.Context, pod v1.Pod, container v1.Container, src, dst string) *exec.Cmd {
-	return exec.CommandContext(ctx, "kubectl", "exec", pod.Name, "--namespace", pod.Namespace, "-c", container.Name, "--", "rm", "-rf", dst)
+	return kubectx.KubectlCommand(ctx, append(kubectx.KubectlArgs(), "exec", pod.Name, "--namespace", pod.Namespace, "-c", container.Name, "--", "rm", "-rf", dst)...)
 }
 
 func copyFileFn(ctx context.Context, pod v1.Pod, container v1.Container, src, dst string) *exec.Cmd {
-	return exec.CommandContext(ctx, "kubectl", "cp", src, fmt.Sprintf("%s/%s:%s", pod.Namespace, pod.Name, dst), "-c", container.Name)
+	return kubectx.KubectlCommand(ctx, append(kubectx.KubectlArgs(), "cp", src, fmt.Sprintf("%s/%s:%s", pod.Namespace, pod.Name, dst), "-c", container.Name)...)
 }
@@ -32,7 +32,6 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/watch"
 	"github.com/pkg/errors"
 	"k8s.io/api/core/v1"
-	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type Syncer interface {
@@ -123,37 +122,47 @@ func intersect(context string, syncMap map[string]string, files []string) (map[s
 	return ret, nil
 }
 
-func Perform(ctx context.Context, image string, files map[string]string, cmdFn func(context.Context, v1.Pod, v1.Container, string, string) *exec.Cmd) error {
-	if len(files) == 0 {
-		return nil
+// Target is a container files are synced to.
+type Target struct {
+	Pod       v1.Pod
+	Container v1.Container
+}
+
+// Targets returns the containers of the pods that run a matching image.
+func Targets(pods []v1.Pod, matches func(image string) bool) []Target {
+	var targets []Target
+
+	for _, p := range pods {
+		for _, c := range p.Spec.Containers {
+			if matches(c.Image) {
+				targets = append(targets, Target{Pod: p, Container: c})
+			}
+		}
 	}
 
-	client, err := kubernetes.Client()
-	if err != nil {
-		return errors.Wrap(err, "getting k8s client")
+	return targets
+}
+
+func Perform(ctx context.Context, podCache *kubernetes.PodCache, image string, files map[string]string, cmdFn func(context.Context, v1.Pod, v1.Container, string, string) *exec.Cmd) error {
+	if len(files) == 0 {
+		return nil
 	}
 
-	pods, err := client.CoreV1().Pods("").List(meta_v1.ListOptions{})
+	pods, err := podCache.Pods()
 	if err != nil {
 		return errors.Wrap(err, "getting pods")
 	}
 
 	synced := map[string]bool{}
 
-	for _, p := range pods.Items {
-		for _, c := range p.Spec.Containers {
-			if c.Image != image {
-				continue
+	for _, t := range Targets(pods, func(i string) bool { return i == image }) {
+		for src, dst := range files {
+			cmd := cmdFn(ctx, t.Pod, t.Container, src, dst)
+			if err := util.RunCmd(cmd); err != nil {
+				return err
 			}
 
-			for src, dst := range files {
-				cmd := cmdFn(ctx, p, c, src, dst)
-				if err := util.RunCmd(cmd); err != nil {
-					return err
-				}
-
-				synced[src] = true
-			}
+			synced[src] = true
 		}
 	}
 
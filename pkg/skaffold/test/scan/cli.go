@@ -0,0 +1,115 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scan
+
+import (
+	"context"
+	"encoding/json"
+	"os/exec"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+)
+
+// trivy scans the images with https://github.com/aquasecurity/trivy.
+type trivy struct{}
+
+type trivyResult struct {
+	Vulnerabilities []struct {
+		VulnerabilityID string
+		PkgName         string
+		Severity        string
+	}
+}
+
+func (trivy) scan(ctx context.Context, image string) ([]Vulnerability, error) {
+	cmd := exec.CommandContext(ctx, "trivy", "image", "--quiet", "--format", "json", image)
+	out, err := util.RunCmdOut(cmd)
+	if err != nil {
+		return nil, errors.Wrap(err, "running trivy")
+	}
+
+	return parseTrivy(out)
+}
+
+func parseTrivy(out []byte) ([]Vulnerability, error) {
+	// Older versions of trivy print the results, newer ones wrap them in a report.
+	var results []trivyResult
+	if err := json.Unmarshal(out, &results); err != nil {
+		var report struct {
+			Results []trivyResult
+		}
+		if err := json.Unmarshal(out, &report); err != nil {
+			return nil, errors.Wrap(err, "parsing trivy report")
+		}
+		results = report.Results
+	}
+
+	var vulnerabilities []Vulnerability
+	for _, result := range results {
+		for _, v := range result.Vulnerabilities {
+			vulnerabilities = append(vulnerabilities, Vulnerability{
+				ID:       v.VulnerabilityID,
+				Package:  v.PkgName,
+				Severity: v.Severity,
+			})
+		}
+	}
+
+	return vulnerabilities, nil
+}
+
+// grype scans the images with https://github.com/anchore/grype.
+type grype struct{}
+
+func (grype) scan(ctx context.Context, image string) ([]Vulnerability, error) {
+	cmd := exec.CommandContext(ctx, "grype", image, "--output", "json")
+	out, err := util.RunCmdOut(cmd)
+	if err != nil {
+		return nil, errors.Wrap(err, "running grype")
+	}
+
+	return parseGrype(out)
+}
+
+func parseGrype(out []byte) ([]Vulnerability, error) {
+	var report struct {
+		Matches []struct {
+			Vulnerability struct {
+				ID       string `json:"id"`
+				Severity string `json:"severity"`
+			} `json:"vulnerability"`
+			Artifact struct {
+				Name string `json:"name"`
+			} `json:"artifact"`
+		} `json:"matches"`
+	}
+	if err := json.Unmarshal(out, &report); err != nil {
+		return nil, errors.Wrap(err, "parsing grype report")
+	}
+
+	var vulnerabilities []Vulnerability
+	for _, match := range report.Matches {
+		vulnerabilities = append(vulnerabilities, Vulnerability{
+			ID:       match.Vulnerability.ID,
+			Package:  match.Artifact.Name,
+			Severity: match.Vulnerability.Severity,
+		})
+	}
+
+	return vulnerabilities, nil
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/gcp"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+)
+
+var (
+	// for testing
+	containerAnalysisURL = "https://containeranalysis.googleapis.com"
+	analysisPollInterval = 5 * time.Second
+	analysisTimeout      = 5 * time.Minute
+	httpClient           = gcp.HTTPClient
+	remoteDigest         = docker.RemoteDigest
+)
+
+// containerAnalysis reads the vulnerabilities found by Google Container Analysis.
+// Since images are analyzed once they're pushed, it waits for the analysis to finish.
+type containerAnalysis struct {
+	projectID string
+}
+
+type occurrence struct {
+	NoteName      string `json:"noteName"`
+	Vulnerability struct {
+		Severity          string `json:"severity"`
+		EffectiveSeverity string `json:"effectiveSeverity"`
+		PackageIssue      []struct {
+			AffectedPackage string `json:"affectedPackage"`
+		} `json:"packageIssue"`
+	} `json:"vulnerability"`
+	Discovery struct {
+		AnalysisStatus string `json:"analysisStatus"`
+	} `json:"discovery"`
+}
+
+func (c *containerAnalysis) scan(ctx context.Context, image string) ([]Vulnerability, error) {
+	resourceURL, project, err := c.resource(image)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := httpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := waitForAnalysis(ctx, client, project, resourceURL); err != nil {
+		return nil, err
+	}
+
+	occurrences, err := listOccurrences(ctx, client, project, fmt.Sprintf(`kind="VULNERABILITY" AND resourceUrl="%s"`, resourceURL))
+	if err != nil {
+		return nil, errors.Wrap(err, "listing vulnerabilities")
+	}
+
+	var vulnerabilities []Vulnerability
+	for _, o := range occurrences {
+		severity := o.Vulnerability.EffectiveSeverity
+		if severity == "" || severity == "SEVERITY_UNSPECIFIED" {
+			severity = o.Vulnerability.Severity
+		}
+
+		var packages []string
+		for _, issue := range o.Vulnerability.PackageIssue {
+			packages = append(packages, issue.AffectedPackage)
+		}
+
+		vulnerabilities = append(vulnerabilities, Vulnerability{
+			ID:       path.Base(o.NoteName),
+			Package:  strings.Join(packages, ", "),
+			Severity: severity,
+		})
+	}
+
+	return vulnerabilities, nil
+}
+
+// resource gives the url under which Container Analysis stores the results of an image,
+// and the project that holds them.
+func (c *containerAnalysis) resource(image string) (string, string, error) {
+	ref, err := name.ParseReference(image, name.WeakValidation)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "parsing image %s", image)
+	}
+
+	registry := ref.Context().RegistryStr()
+	if !gcp.IsGCR(registry) {
+		return "", "", fmt.Errorf("%s isn't pushed to gcr.io or Artifact Registry", image)
+	}
+
+	digest, err := remoteDigest(image)
+	if err != nil {
+		return "", "", errors.Wrap(err, "getting digest")
+	}
+
+	project := c.projectID
+	if project == "" {
+		project = strings.Split(ref.Context().RepositoryStr(), "/")[0]
+	}
+
+	return fmt.Sprintf("https://%s/%s@%s", registry, ref.Context().RepositoryStr(), digest), project, nil
+}
+
+func waitForAnalysis(ctx context.Context, client *http.Client, project, resourceURL string) error {
+	filter := fmt.Sprintf(`kind="DISCOVERY" AND resourceUrl="%s"`, resourceURL)
+	deadline := time.Now().Add(analysisTimeout)
+
+	for {
+		occurrences, err := listOccurrences(ctx, client, project, filter)
+		if err != nil {
+			return errors.Wrap(err, "getting analysis status")
+		}
+
+		status := ""
+		if len(occurrences) > 0 {
+			status = occurrences[0].Discovery.AnalysisStatus
+		}
+
+		switch status {
+		case "FINISHED_SUCCESS":
+			return nil
+		case "FINISHED_FAILED", "FINISHED_UNSUPPORTED":
+			return fmt.Errorf("analysis of %s ended with %s", resourceURL, status)
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("analysis of %s didn't finish after %s", resourceURL, analysisTimeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(analysisPollInterval):
+		}
+	}
+}
+
+func listOccurrences(ctx context.Context, client *http.Client, project, filter string) ([]occurrence, error) {
+	var occurrences []occurrence
+
+	pageToken := ""
+	for {
+		query := url.Values{"filter": {filter}}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/projects/%s/occurrences?%s", containerAnalysisURL, project, query.Encode()), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := client.Do(req.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+
+		var page struct {
+			Occurrences   []occurrence `json:"occurrences"`
+			NextPageToken string       `json:"nextPageToken"`
+		}
+		err = decodeResponse(resp, &page)
+		if err != nil {
+			return nil, err
+		}
+
+		occurrences = append(occurrences, page.Occurrences...)
+		if page.NextPageToken == "" {
+			return occurrences, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+func decodeResponse(resp *http.Response, v interface{}) error {
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("container analysis returned %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scan
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
+)
+
+// maxListed is how many vulnerabilities are listed for each image.
+const maxListed = 20
+
+// Vulnerability is a vulnerability found in a package of an image.
+type Vulnerability struct {
+	ID       string
+	Package  string
+	Severity string
+}
+
+// scanner lists the vulnerabilities of an image.
+type scanner interface {
+	scan(ctx context.Context, image string) ([]Vulnerability, error)
+}
+
+// severities ranks the severities reported by the scanners.
+var severities = map[string]int{
+	"NEGLIGIBLE": 0,
+	"UNKNOWN":    0,
+	"LOW":        1,
+	"MEDIUM":     2,
+	"HIGH":       3,
+	"CRITICAL":   4,
+}
+
+// Runner scans the built images and fails if they have vulnerabilities
+// of the configured severity or above.
+type Runner struct {
+	scanner
+	severity string
+	warnOnly bool
+}
+
+// NewRunner creates a Runner with the scanner chosen in the configuration.
+func NewRunner(cfg *latest.ScanConfig) (*Runner, error) {
+	var s scanner
+	switch {
+	case cfg.Trivy != nil:
+		s = trivy{}
+	case cfg.Grype != nil:
+		s = grype{}
+	case cfg.ContainerAnalysis != nil:
+		s = &containerAnalysis{projectID: cfg.ContainerAnalysis.ProjectID}
+	default:
+		return nil, errors.New("no scanner configured")
+	}
+
+	return &Runner{
+		scanner:  s,
+		severity: strings.ToUpper(cfg.Severity),
+		warnOnly: cfg.WarnOnly,
+	}, nil
+}
+
+// Test scans an image and reports its vulnerabilities.
+func (r *Runner) Test(ctx context.Context, out io.Writer, image string) error {
+	color.Default.Fprintf(out, "Scanning %s for vulnerabilities...\n", image)
+
+	all, err := r.scan(ctx, image)
+	if err != nil {
+		return errors.Wrapf(err, "scanning %s", image)
+	}
+
+	found := filter(all, r.severity)
+	if len(found) == 0 {
+		return nil
+	}
+
+	c := color.Red
+	if r.warnOnly {
+		c = color.Yellow
+	}
+	c.Fprintf(out, "Found %d vulnerabilities of severity %s or above in %s:\n", len(found), r.severity, image)
+	for i, v := range found {
+		if i == maxListed {
+			fmt.Fprintf(out, " - ... and %d more\n", len(found)-maxListed)
+			break
+		}
+		fmt.Fprintf(out, " - %s %s (%s)\n", v.Severity, v.ID, v.Package)
+	}
+
+	if r.warnOnly {
+		return nil
+	}
+	return fmt.Errorf("%d vulnerabilities of severity %s or above in %s", len(found), r.severity, image)
+}
+
+// filter keeps the vulnerabilities of a given severity or above, the most severe first.
+// Each vulnerability is only listed once per package.
+func filter(vulnerabilities []Vulnerability, severity string) []Vulnerability {
+	threshold := severities[severity]
+
+	var found []Vulnerability
+	seen := map[Vulnerability]bool{}
+	for _, v := range vulnerabilities {
+		v.Severity = strings.ToUpper(v.Severity)
+		if severities[v.Severity] < threshold || seen[v] {
+			continue
+		}
+		seen[v] = true
+		found = append(found, v)
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		if severities[found[i].Severity] != severities[found[j].Severity] {
+			return severities[found[i].Severity] > severities[found[j].Severity]
+		}
+		return found[i].ID < found[j].ID
+	})
+
+	return found
+}
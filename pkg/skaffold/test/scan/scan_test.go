@@ -0,0 +1,182 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scan
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+type fakeScanner []Vulnerability
+
+func (f fakeScanner) scan(context.Context, string) ([]Vulnerability, error) {
+	return f, nil
+}
+
+func TestRunner(t *testing.T) {
+	vulnerabilities := fakeScanner{
+		{ID: "CVE-1", Package: "openssl", Severity: "medium"},
+		{ID: "CVE-2", Package: "glibc", Severity: "CRITICAL"},
+		{ID: "CVE-3", Package: "zlib", Severity: "HIGH"},
+		{ID: "CVE-3", Package: "zlib", Severity: "HIGH"},
+	}
+
+	var tests = []struct {
+		description    string
+		severity       string
+		warnOnly       bool
+		clean          bool
+		shouldErr      bool
+		expectedOutput string
+	}{
+		{
+			description: "fail on high",
+			severity:    "HIGH",
+			shouldErr:   true,
+			expectedOutput: `Scanning image for vulnerabilities...
+Found 2 vulnerabilities of severity HIGH or above in image:
+ - CRITICAL CVE-2 (glibc)
+ - HIGH CVE-3 (zlib)
+`,
+		},
+		{
+			description: "warn only",
+			severity:    "CRITICAL",
+			warnOnly:    true,
+			expectedOutput: `Scanning image for vulnerabilities...
+Found 1 vulnerabilities of severity CRITICAL or above in image:
+ - CRITICAL CVE-2 (glibc)
+`,
+		},
+		{
+			description:    "nothing found",
+			severity:       "CRITICAL",
+			clean:          true,
+			expectedOutput: "Scanning image for vulnerabilities...\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			runner := &Runner{scanner: vulnerabilities, severity: test.severity, warnOnly: test.warnOnly}
+			if test.clean {
+				runner.scanner = fakeScanner{}
+			}
+
+			var out bytes.Buffer
+			err := runner.Test(context.Background(), &out, "image")
+
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expectedOutput, out.String())
+		})
+	}
+}
+
+func TestNewRunner(t *testing.T) {
+	_, err := NewRunner(&latest.ScanConfig{})
+	testutil.CheckError(t, true, err)
+
+	runner, err := NewRunner(&latest.ScanConfig{Grype: &latest.GrypeScanner{}, Severity: "low"})
+	testutil.CheckError(t, false, err)
+	if !reflect.DeepEqual(&Runner{scanner: grype{}, severity: "LOW"}, runner) {
+		t.Errorf("unexpected runner: %+v", runner)
+	}
+}
+
+func TestParseTrivy(t *testing.T) {
+	expected := []Vulnerability{{ID: "CVE-1", Package: "openssl", Severity: "HIGH"}}
+
+	report := `{"Results":[{"Target":"image","Vulnerabilities":[{"VulnerabilityID":"CVE-1","PkgName":"openssl","Severity":"HIGH"}]}]}`
+	vulnerabilities, err := parseTrivy([]byte(report))
+	testutil.CheckErrorAndDeepEqual(t, false, err, expected, vulnerabilities)
+
+	legacy := `[{"Target":"image","Vulnerabilities":[{"VulnerabilityID":"CVE-1","PkgName":"openssl","Severity":"HIGH"}]}]`
+	vulnerabilities, err = parseTrivy([]byte(legacy))
+	testutil.CheckErrorAndDeepEqual(t, false, err, expected, vulnerabilities)
+
+	_, err = parseTrivy([]byte("not json"))
+	testutil.CheckError(t, true, err)
+}
+
+func TestGrype(t *testing.T) {
+	defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+	util.DefaultExecCommand = testutil.NewFakeCmdOut(
+		"grype gcr.io/project/image:tag --output json",
+		`{"matches":[{"vulnerability":{"id":"CVE-1","severity":"Critical"},"artifact":{"name":"glibc"}}]}`,
+		nil,
+	)
+
+	vulnerabilities, err := grype{}.scan(context.Background(), "gcr.io/project/image:tag")
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, []Vulnerability{{ID: "CVE-1", Package: "glibc", Severity: "Critical"}}, vulnerabilities)
+}
+
+func TestContainerAnalysis(t *testing.T) {
+	digest := "sha256:27ffc7f352665cc50ae3cbcc4b2725e36062f1b38c611b6f95d6df9a7510de23"
+	resourceURL := "https://gcr.io/project/image@" + digest
+
+	discoveries := []string{"PENDING", "FINISHED_SUCCESS"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/projects/project/occurrences" {
+			http.NotFound(w, r)
+			return
+		}
+
+		filter := r.URL.Query().Get("filter")
+		switch {
+		case filter == fmt.Sprintf(`kind="DISCOVERY" AND resourceUrl="%s"`, resourceURL):
+			status := discoveries[0]
+			discoveries = discoveries[1:]
+			fmt.Fprintf(w, `{"occurrences":[{"discovery":{"analysisStatus":"%s"}}]}`, status)
+		case filter == fmt.Sprintf(`kind="VULNERABILITY" AND resourceUrl="%s"`, resourceURL) && r.URL.Query().Get("pageToken") == "":
+			fmt.Fprint(w, `{"occurrences":[{"noteName":"projects/goog-vulnz/notes/CVE-1","vulnerability":{"severity":"HIGH","effectiveSeverity":"CRITICAL","packageIssue":[{"affectedPackage":"openssl"}]}}],"nextPageToken":"next"}`)
+		case strings.HasPrefix(filter, `kind="VULNERABILITY"`):
+			fmt.Fprint(w, `{"occurrences":[{"noteName":"projects/goog-vulnz/notes/CVE-2","vulnerability":{"severity":"LOW"}}]}`)
+		default:
+			http.Error(w, "unexpected filter", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	defer func(u string) { containerAnalysisURL = u }(containerAnalysisURL)
+	containerAnalysisURL = server.URL
+	defer func(f func() (*http.Client, error)) { httpClient = f }(httpClient)
+	httpClient = func() (*http.Client, error) { return server.Client(), nil }
+	defer func(f func(string) (string, error)) { remoteDigest = f }(remoteDigest)
+	remoteDigest = func(string) (string, error) { return digest, nil }
+	defer func(d time.Duration) { analysisPollInterval = d }(analysisPollInterval)
+	analysisPollInterval = 0
+
+	vulnerabilities, err := (&containerAnalysis{}).scan(context.Background(), "gcr.io/project/image:tag")
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, []Vulnerability{
+		{ID: "CVE-1", Package: "openssl", Severity: "CRITICAL"},
+		{ID: "CVE-2", Severity: "LOW"},
+	}, vulnerabilities)
+
+	_, err = (&containerAnalysis{}).scan(context.Background(), "docker.io/library/image")
+	testutil.CheckError(t, true, err)
+}
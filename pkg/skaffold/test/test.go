@@ -23,6 +23,7 @@ import (
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/test/scan"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/test/structure"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 
@@ -32,16 +33,26 @@ import (
 // NewTester parses the provided test cases from the Skaffold config,
 // and returns a Tester instance with all the necessary test runners
 // to run all specified tests.
-func NewTester(testCases *latest.TestConfig) (Tester, error) {
-	// TODO(nkubala): copied this from runner.getDeployer(), this should be moved somewhere else
+func NewTester(testCases *latest.TestConfig, verifyCases *latest.VerifyConfig, scanCfg *latest.ScanConfig, namespace string) (Tester, error) {
+	// Test paths were already resolved against the folder containing skaffold.yaml.
 	cwd, err := os.Getwd()
 	if err != nil {
 		return nil, errors.Wrap(err, "finding current directory")
 	}
 
+	var scanner Runner
+	if scanCfg != nil {
+		if scanner, err = scan.NewRunner(scanCfg); err != nil {
+			return nil, errors.Wrap(err, "creating image scanner")
+		}
+	}
+
 	return FullTester{
-		testCases:  testCases,
-		workingDir: cwd,
+		testCases:   testCases,
+		verifyCases: verifyCases,
+		scanner:     scanner,
+		namespace:   namespace,
+		workingDir:  cwd,
 	}, nil
 }
 
@@ -74,6 +85,14 @@ func (t FullTester) Test(ctx context.Context, out io.Writer, bRes []build.Artifa
 		}
 	}
 
+	if t.scanner != nil {
+		for _, res := range bRes {
+			if err := t.scanner.Test(ctx, out, res.Tag); err != nil {
+				return errors.Wrap(err, "scanning images")
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -31,6 +31,9 @@ import (
 type Tester interface {
 	Test(context.Context, io.Writer, []build.Artifact) error
 
+	// Verify runs the checks against the deployed application.
+	Verify(context.Context, io.Writer, []build.Artifact) error
+
 	TestDependencies() ([]string, error)
 }
 
@@ -42,8 +45,11 @@ type Tester interface {
 // FullTester should always be the ONLY implementation of the Tester interface;
 // newly added testing implementations should implement the Runner interface.
 type FullTester struct {
-	testCases  *latest.TestConfig
-	workingDir string
+	testCases   *latest.TestConfig
+	verifyCases *latest.VerifyConfig
+	scanner     Runner
+	namespace   string
+	workingDir  string
 }
 
 // Runner is the lowest-level test executor in Skaffold, responsible for
@@ -0,0 +1,137 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const verifyTimeout = 5 * time.Minute
+
+// Verify runs each verification case, in order, against the deployed application.
+func (t FullTester) Verify(ctx context.Context, out io.Writer, bRes []build.Artifact) error {
+	if t.verifyCases == nil {
+		return nil
+	}
+
+	for i, verifyCase := range *t.verifyCases {
+		name := verifyCase.Name
+		if name == "" {
+			name = fmt.Sprintf("verify-%d", i)
+		}
+
+		color.Default.Fprintf(out, "Running verification [%s]...\n", name)
+
+		var err error
+		switch {
+		case len(verifyCase.Command) > 0:
+			err = t.runVerifyCommand(ctx, out, verifyCase.Command)
+		case verifyCase.Container != nil:
+			err = t.runVerifyContainer(ctx, out, verifyCase.Container, bRes)
+		default:
+			err = errors.New("either a command or a container is required")
+		}
+
+		if err != nil {
+			return errors.Wrapf(err, "verification [%s]", name)
+		}
+	}
+
+	return nil
+}
+
+func (t FullTester) runVerifyCommand(ctx context.Context, out io.Writer, command []string) error {
+	logrus.Debugf("Running verification command: %s", strings.Join(command, " "))
+
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Dir = t.workingDir
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	return util.RunCmd(cmd)
+}
+
+func (t FullTester) runVerifyContainer(ctx context.Context, out io.Writer, container *latest.VerifyContainer, bRes []build.Artifact) error {
+	client, err := kubernetes.GetClientset()
+	if err != nil {
+		return errors.Wrap(err, "getting kubernetes client")
+	}
+
+	namespace, err := kubectx.Namespace(t.namespace)
+	if err != nil {
+		return err
+	}
+
+	pods := client.CoreV1().Pods(namespace)
+	p, err := pods.Create(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "skaffold-verify-",
+		},
+		Spec: v1.PodSpec{
+			RestartPolicy: v1.RestartPolicyNever,
+			Containers: []v1.Container{{
+				Name:    "verify",
+				Image:   resolveArtifactImageTag(container.Image, bRes),
+				Command: container.Command,
+				Args:    container.Args,
+			}},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "creating verification pod")
+	}
+	defer func() {
+		if err := pods.Delete(p.Name, &metav1.DeleteOptions{
+			GracePeriodSeconds: new(int64),
+		}); err != nil {
+			logrus.Warnf("deleting verification pod %s: %s", p.Name, err)
+		}
+	}()
+
+	err = kubernetes.WaitForPodComplete(ctx, out, pods, client.CoreV1().Events(namespace), p.Name, verifyTimeout)
+	printLogs(out, pods, p.Name)
+
+	return err
+}
+
+func printLogs(out io.Writer, pods corev1.PodInterface, name string) {
+	r, err := pods.GetLogs(name, &v1.PodLogOptions{}).Stream()
+	if err != nil {
+		logrus.Debugf("unable to get logs for %s: %s", name, err)
+		return
+	}
+	defer r.Close()
+
+	io.Copy(out, r)
+}
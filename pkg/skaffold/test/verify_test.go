@@ -0,0 +1,80 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestVerify(t *testing.T) {
+	var tests = []struct {
+		description string
+		verifyCases *latest.VerifyConfig
+		command     util.Command
+		shouldErr   bool
+	}{
+		{
+			description: "no verification",
+		},
+		{
+			description: "successful command",
+			verifyCases: &latest.VerifyConfig{{
+				Name:    "smoke",
+				Command: []string{"curl", "-f", "http://localhost:8080"},
+			}},
+			command: testutil.NewFakeCmd("curl -f http://localhost:8080", nil),
+		},
+		{
+			description: "failing command",
+			verifyCases: &latest.VerifyConfig{{
+				Command: []string{"curl", "-f", "http://localhost:8080"},
+			}},
+			command:   testutil.NewFakeCmd("curl -f http://localhost:8080", errors.New("BUG")),
+			shouldErr: true,
+		},
+		{
+			description: "missing command or container",
+			verifyCases: &latest.VerifyConfig{{
+				Name: "empty",
+			}},
+			shouldErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			if test.command != nil {
+				defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+				util.DefaultExecCommand = test.command
+			}
+
+			tester := FullTester{
+				verifyCases: test.verifyCases,
+			}
+			err := tester.Verify(context.Background(), &bytes.Buffer{}, nil)
+
+			testutil.CheckError(t, test.shouldErr, err)
+		})
+	}
+}
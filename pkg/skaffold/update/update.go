@@ -18,18 +18,50 @@ package update
 
 import (
 	"context"
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/version"
 	"github.com/blang/semver"
+	homedir "github.com/mitchellh/go-homedir"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
-const latestVersionURL = "https://storage.googleapis.com/skaffold/releases/latest/VERSION"
+const (
+	// checkInterval is how long the latest version is cached for.
+	checkInterval = 24 * time.Hour
+
+	// fetchTimeout bounds the time spent fetching the latest version.
+	fetchTimeout = 3 * time.Second
+)
+
+// for testing
+var (
+	latestVersionURL = "https://storage.googleapis.com/skaffold/releases/latest/VERSION"
+	cacheFile        = defaultCacheFile
+	now              = time.Now
+)
+
+// cachedVersion is the latest version last fetched from GCS.
+type cachedVersion struct {
+	Version   string    `json:"version"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+func defaultCacheFile() (string, error) {
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", errors.Wrap(err, "retrieving home directory")
+	}
+	return filepath.Join(home, ".skaffold", "latest-version.json"), nil
+}
 
 // IsUpdateCheckEnabled returns whether or not the update check is enabled
 // It is true by default, but setting it to any other value than true will disable the check
@@ -43,15 +75,72 @@ func IsUpdateCheckEnabled() bool {
 	return v == "" || strings.ToLower(v) == "true"
 }
 
-// GetLatestVersion uses a VERSION file stored on GCS to determine the latest released version of skaffold
+// GetLatestVersion uses a VERSION file stored on GCS to determine the latest released version of skaffold.
+// The result is cached in ~/.skaffold for a day so that GCS is checked at most once a day.
 func GetLatestVersion(ctx context.Context) (semver.Version, error) {
-	resp, err := http.Get(latestVersionURL)
+	path, err := cacheFile()
+	if err != nil {
+		logrus.Debugln("Not caching the latest version:", err)
+		return fetchLatestVersion(ctx)
+	}
+
+	if cached, err := readCachedVersion(path); err == nil && now().Sub(cached.CheckedAt) < checkInterval {
+		if v, err := version.ParseVersion(cached.Version); err == nil {
+			return v, nil
+		}
+	}
+
+	v, err := fetchLatestVersion(ctx)
+	if err != nil {
+		return semver.Version{}, err
+	}
+	if err := writeCachedVersion(path, v); err != nil {
+		logrus.Debugln("Unable to cache the latest version:", err)
+	}
+	return v, nil
+}
+
+func readCachedVersion(path string) (*cachedVersion, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cached cachedVersion
+	if err := json.Unmarshal(buf, &cached); err != nil {
+		return nil, err
+	}
+	return &cached, nil
+}
+
+func writeCachedVersion(path string, v semver.Version) error {
+	buf, err := json.Marshal(cachedVersion{
+		Version:   v.String(),
+		CheckedAt: now(),
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buf, 0644)
+}
+
+func fetchLatestVersion(ctx context.Context) (semver.Version, error) {
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodGet, latestVersionURL, nil)
+	if err != nil {
+		return semver.Version{}, errors.Wrap(err, "creating request")
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
 	if err != nil {
 		return semver.Version{}, errors.Wrap(err, "getting latest version info from GCS")
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return semver.Version{}, errors.Wrapf(err, "http %d, error: %s", resp.StatusCode, resp.Status)
+		return semver.Version{}, errors.Errorf("http %d, error: %s", resp.StatusCode, resp.Status)
 	}
 	versionBytes, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
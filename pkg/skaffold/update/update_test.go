@@ -0,0 +1,110 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package update
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/version"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestGetLatestVersion(t *testing.T) {
+	var tests = []struct {
+		description string
+		cached      string
+		elapsed     time.Duration
+		status      int
+		expected    string
+		expectedHit int
+		shouldErr   bool
+	}{
+		{
+			description: "nothing cached",
+			status:      http.StatusOK,
+			expected:    "0.20.0",
+			expectedHit: 1,
+		},
+		{
+			description: "fresh cache",
+			cached:      "v0.19.0",
+			elapsed:     time.Hour,
+			status:      http.StatusOK,
+			expected:    "0.19.0",
+		},
+		{
+			description: "stale cache",
+			cached:      "v0.19.0",
+			elapsed:     25 * time.Hour,
+			status:      http.StatusOK,
+			expected:    "0.20.0",
+			expectedHit: 1,
+		},
+		{
+			description: "http error",
+			status:      http.StatusNotFound,
+			expectedHit: 1,
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			hits := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hits++
+				w.WriteHeader(test.status)
+				fmt.Fprintln(w, "v0.20.0")
+			}))
+			defer server.Close()
+
+			tmpDir, cleanup := testutil.NewTempDir(t)
+			defer cleanup()
+			path := filepath.Join(tmpDir.Root(), "latest-version.json")
+
+			start := time.Date(2018, 11, 1, 10, 0, 0, 0, time.UTC)
+			defer func(u string, c func() (string, error), n func() time.Time) {
+				latestVersionURL, cacheFile, now = u, c, n
+			}(latestVersionURL, cacheFile, now)
+			latestVersionURL = server.URL
+			cacheFile = func() (string, error) { return path, nil }
+			now = func() time.Time { return start }
+
+			if test.cached != "" {
+				v, _ := version.ParseVersion(test.cached)
+				if err := writeCachedVersion(path, v); err != nil {
+					t.Fatal(err)
+				}
+				now = func() time.Time { return start.Add(test.elapsed) }
+			}
+
+			v, err := GetLatestVersion(context.Background())
+
+			testutil.CheckError(t, test.shouldErr, err)
+			if !test.shouldErr {
+				testutil.CheckDeepEqual(t, test.expected, v.String())
+			}
+			testutil.CheckDeepEqual(t, test.expectedHit, hits)
+		})
+	}
+}
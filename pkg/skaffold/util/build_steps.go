@@ -0,0 +1,139 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"regexp"
+	"strings"
+)
+
+// BuildStepLines is how many lines of output of the current build step are kept.
+const BuildStepLines = 20
+
+// BuildLine tells what a line of build output is.
+type BuildLine int
+
+const (
+	// StepStart starts a step of the build.
+	StepStart BuildLine = iota
+	// StepOutput is printed by the current step.
+	StepOutput
+	// BuildEnd is printed once all the steps are done.
+	BuildEnd
+	// BuildOutput is printed outside of any step.
+	BuildOutput
+	// Noise is a blank line or docker's intermediate images and containers.
+	Noise
+)
+
+// steps match the lines that start a step of a build: docker's
+// `Step 3/7 : RUN make`, even when prefixed by Cloud Build, BuildKit's
+// `#8 [3/5] RUN make` and Kaniko's `INFO[0003] RUN make`.
+var steps = []*regexp.Regexp{
+	regexp.MustCompile(`Step \d+/\d+ : .*`),
+	regexp.MustCompile(`^#\d+ \[[^\]]*\d+/\d+\] .*`),
+	regexp.MustCompile(`^INFO\[\d+\] ((?:RUN|COPY|ADD) .*)`),
+}
+
+// buildEnds match the lines printed once all the steps of a build are done:
+// docker's `Successfully built 1234`, BuildKit's `#9 exporting to image` and
+// Kaniko's `INFO[0010] Pushing image`. What fails afterwards isn't a step.
+var buildEnds = []*regexp.Regexp{
+	regexp.MustCompile(`Successfully built `),
+	regexp.MustCompile(`^#\d+ exporting to image`),
+	regexp.MustCompile(`^INFO\[\d+\] Pushing image`),
+}
+
+// noise matches docker's intermediate images and containers.
+var noise = regexp.MustCompile(`^ ---> |^Removing intermediate container`)
+
+// BuildSteps follows the output of a build, line by line, to know
+// which step runs and to keep the last lines that it printed.
+type BuildSteps struct {
+	pending string
+	step    string
+	lines   []string
+}
+
+// Write processes the complete lines of a chunk of build output. The last line
+// is kept until it's complete. handle, if not nil, is given each line and what it is.
+func (s *BuildSteps) Write(chunk string, handle func(line string, kind BuildLine)) {
+	lines := strings.Split(s.pending+chunk, "\n")
+	s.pending = lines[len(lines)-1]
+
+	for _, line := range lines[:len(lines)-1] {
+		s.add(line, handle)
+	}
+}
+
+// Flush processes the last line if it's not terminated.
+func (s *BuildSteps) Flush(handle func(line string, kind BuildLine)) {
+	if s.pending != "" {
+		line := s.pending
+		s.pending = ""
+		s.add(line, handle)
+	}
+}
+
+// Step returns the step that runs. It's empty before the first step and once they're all done.
+func (s *BuildSteps) Step() string {
+	return s.step
+}
+
+// Lines returns the last lines printed by the current step, or since the build started.
+func (s *BuildSteps) Lines() []string {
+	return s.lines
+}
+
+func (s *BuildSteps) add(line string, handle func(line string, kind BuildLine)) {
+	line = strings.TrimRight(line, "\r")
+
+	kind := s.parse(line)
+	if handle != nil {
+		handle(line, kind)
+	}
+}
+
+func (s *BuildSteps) parse(line string) BuildLine {
+	for _, step := range steps {
+		if match := step.FindStringSubmatch(line); match != nil {
+			s.step = match[len(match)-1]
+			s.lines = nil
+			return StepStart
+		}
+	}
+	for _, end := range buildEnds {
+		if end.MatchString(line) {
+			s.step = ""
+			s.lines = nil
+			return BuildEnd
+		}
+	}
+
+	if strings.TrimSpace(line) == "" || noise.MatchString(line) {
+		return Noise
+	}
+	s.lines = append(s.lines, line)
+	if len(s.lines) > BuildStepLines {
+		s.lines = s.lines[1:]
+	}
+
+	if s.step == "" {
+		return BuildOutput
+	}
+	return StepOutput
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestBuildSteps(t *testing.T) {
+	var manyLines []string
+	var manyKinds []BuildLine
+	for i := 0; i < 30; i++ {
+		manyLines = append(manyLines, fmt.Sprintf("line %d", i))
+		manyKinds = append(manyKinds, BuildOutput)
+	}
+
+	tests := []struct {
+		description   string
+		chunks        []string
+		expectedKinds []BuildLine
+		expectedStep  string
+		expectedLines []string
+	}{
+		{
+			description:   "docker",
+			chunks:        []string{"Step 1/2 : FROM busybox\n ---> 1234\n", "Step 2/2 : RUN make\r\ncompil", "ing\n\nerror"},
+			expectedKinds: []BuildLine{StepStart, Noise, StepStart, StepOutput, Noise, StepOutput},
+			expectedStep:  "Step 2/2 : RUN make",
+			expectedLines: []string{"compiling", "error"},
+		},
+		{
+			description:   "buildkit",
+			chunks:        []string{"#1 [internal] load build definition\n#5 [2/3] RUN make\n#5 0.4 error\n"},
+			expectedKinds: []BuildLine{BuildOutput, StepStart, StepOutput},
+			expectedStep:  "#5 [2/3] RUN make",
+			expectedLines: []string{"#5 0.4 error"},
+		},
+		{
+			description:   "kaniko",
+			chunks:        []string{"INFO[0001] Retrieving image manifest\nINFO[0003] RUN make\nerror\n"},
+			expectedKinds: []BuildLine{BuildOutput, StepStart, StepOutput},
+			expectedStep:  "RUN make",
+			expectedLines: []string{"error"},
+		},
+		{
+			description:   "after the build",
+			chunks:        []string{"Step 1/1 : FROM busybox\nSuccessfully built 1234\nThe push refers to repository\n"},
+			expectedKinds: []BuildLine{StepStart, BuildEnd, BuildOutput},
+			expectedLines: []string{"The push refers to repository"},
+		},
+		{
+			description:   "last lines",
+			chunks:        []string{strings.Join(manyLines, "\n")},
+			expectedKinds: manyKinds,
+			expectedLines: manyLines[10:],
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			var steps BuildSteps
+			var kinds []BuildLine
+			handle := func(line string, kind BuildLine) {
+				kinds = append(kinds, kind)
+			}
+
+			for _, chunk := range test.chunks {
+				steps.Write(chunk, handle)
+			}
+			steps.Flush(handle)
+
+			testutil.CheckDeepEqual(t, test.expectedKinds, kinds)
+			testutil.CheckDeepEqual(t, test.expectedStep, steps.Step())
+			testutil.CheckDeepEqual(t, test.expectedLines, steps.Lines())
+		})
+	}
+}
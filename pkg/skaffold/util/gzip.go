@@ -0,0 +1,138 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// gzipBlockSize is the size of the blocks compressed in parallel.
+const gzipBlockSize = 1 << 20
+
+// parallelGzipWriter compresses blocks of data concurrently, each one into
+// its own gzip member. The members are written in order and their concatenation
+// is a valid gzip stream that every gzip reader can decompress.
+type parallelGzipWriter struct {
+	level   int
+	buf     []byte
+	written bool
+	pending chan chan compressedBlock
+	done    chan struct{}
+
+	mu  sync.Mutex
+	err error
+}
+
+type compressedBlock struct {
+	data []byte
+	err  error
+}
+
+// NewParallelGzipWriter returns a writer that gzips data using all the CPUs.
+func NewParallelGzipWriter(w io.Writer) io.WriteCloser {
+	return NewParallelGzipWriterLevel(w, gzip.DefaultCompression)
+}
+
+// NewParallelGzipWriterLevel is like NewParallelGzipWriter but with a given
+// compression level, that must be valid for gzip.NewWriterLevel.
+func NewParallelGzipWriterLevel(w io.Writer, level int) io.WriteCloser {
+	gw := &parallelGzipWriter{
+		level:   level,
+		pending: make(chan chan compressedBlock, runtime.NumCPU()),
+		done:    make(chan struct{}),
+	}
+
+	go func() {
+		defer close(gw.done)
+
+		for result := range gw.pending {
+			block := <-result
+			if gw.error() != nil {
+				continue
+			}
+
+			err := block.err
+			if err == nil {
+				_, err = w.Write(block.data)
+			}
+			if err != nil {
+				gw.mu.Lock()
+				gw.err = err
+				gw.mu.Unlock()
+			}
+		}
+	}()
+
+	return gw
+}
+
+func (gw *parallelGzipWriter) Write(p []byte) (int, error) {
+	if err := gw.error(); err != nil {
+		return 0, err
+	}
+
+	gw.buf = append(gw.buf, p...)
+	for len(gw.buf) >= gzipBlockSize {
+		gw.compress(gw.buf[:gzipBlockSize])
+		gw.buf = gw.buf[gzipBlockSize:]
+	}
+
+	return len(p), nil
+}
+
+// Close compresses the remaining data and waits for all the blocks to be written.
+func (gw *parallelGzipWriter) Close() error {
+	if len(gw.buf) > 0 || !gw.written {
+		gw.compress(gw.buf)
+		gw.buf = nil
+	}
+
+	close(gw.pending)
+	<-gw.done
+
+	return gw.error()
+}
+
+// compress starts compressing a block. It blocks when all the CPUs are busy.
+func (gw *parallelGzipWriter) compress(block []byte) {
+	gw.written = true
+
+	result := make(chan compressedBlock, 1)
+	gw.pending <- result
+
+	go func() {
+		var b bytes.Buffer
+		zw, err := gzip.NewWriterLevel(&b, gw.level)
+		if err == nil {
+			_, err = zw.Write(block)
+		}
+		if err == nil {
+			err = zw.Close()
+		}
+		result <- compressedBlock{data: b.Bytes(), err: err}
+	}()
+}
+
+func (gw *parallelGzipWriter) error() error {
+	gw.mu.Lock()
+	defer gw.mu.Unlock()
+	return gw.err
+}
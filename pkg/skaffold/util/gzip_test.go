@@ -0,0 +1,77 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io/ioutil"
+	"math/rand"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestParallelGzipWriter(t *testing.T) {
+	var tests = []struct {
+		description string
+		size        int
+	}{
+		{"empty", 0},
+		{"single block", 1000},
+		{"multiple blocks", 3*gzipBlockSize + 42},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			data := make([]byte, test.size)
+			rand.New(rand.NewSource(0)).Read(data)
+
+			var compressed bytes.Buffer
+			gw := NewParallelGzipWriter(&compressed)
+			for i := 0; i < len(data); i += 4096 {
+				end := i + 4096
+				if end > len(data) {
+					end = len(data)
+				}
+				_, err := gw.Write(data[i:end])
+				testutil.CheckError(t, false, err)
+			}
+			testutil.CheckError(t, false, gw.Close())
+
+			gr, err := gzip.NewReader(&compressed)
+			testutil.CheckError(t, false, err)
+			uncompressed, err := ioutil.ReadAll(gr)
+
+			testutil.CheckErrorAndDeepEqual(t, false, err, data, uncompressed)
+		})
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestParallelGzipWriterError(t *testing.T) {
+	gw := NewParallelGzipWriter(failingWriter{})
+	gw.Write([]byte("data"))
+
+	testutil.CheckError(t, true, gw.Close())
+}
@@ -23,31 +23,52 @@ import (
 
 const maxLength = 255
 
-const gcr = "gcr.io"
 const escapeChars = "[/._:@]"
-const prefixRegexStr = "gcr.io/[a-zA-Z]+/"
 
 var escapeRegex = regexp.MustCompile(escapeChars)
-var prefixRegex = regexp.MustCompile(prefixRegexStr)
 
+// gcrPrefixRegex matches the registry and project of a gcr.io image, including regional registries.
+var gcrPrefixRegex = regexp.MustCompile(`^([a-z]+\.)?gcr\.io/[a-z0-9-]+/`)
+
+// flatPathsRegex matches the registries known not to support nested paths in image
+// names: Docker Hub and Quay only have one level of namespaces, and every path is a
+// separate ECR repository that has to be created beforehand.
+var flatPathsRegex = regexp.MustCompile(`^((index\.)?docker\.io|quay\.io|[^/]+\.dkr\.ecr\.[^/]+\.amazonaws\.com(\.cn)?)$`)
+
+// SubstituteDefaultRepoIntoImage rewrites an image name so that it's pushed to
+// the default repo. The original path is kept under the default repo, except on
+// the registries known not to support nested paths, like Docker Hub or ECR, where
+// the original image name is flattened into a single path component.
 func SubstituteDefaultRepoIntoImage(defaultRepo string, originalImage string) string {
+	defaultRepo = strings.TrimSuffix(defaultRepo, "/")
 	if defaultRepo == "" {
 		return originalImage
 	}
-	if strings.HasPrefix(defaultRepo, gcr) {
-		originalPrefix := prefixRegex.FindString(originalImage)
-		defaultRepoPrefix := prefixRegex.FindString(defaultRepo)
-
-		if originalPrefix == defaultRepoPrefix {
-			// prefixes match
-			return defaultRepo + "/" + originalImage[len(originalPrefix):]
-		} else if strings.HasPrefix(originalImage, defaultRepo) {
-			return originalImage
-		}
-		// prefixes don't match, concatenate and truncate
-		return truncate(defaultRepo + "/" + originalImage)
+	if strings.HasPrefix(originalImage, defaultRepo+"/") {
+		return originalImage
+	}
+
+	if flatPaths(defaultRepo) {
+		return truncate(defaultRepo + "/" + escapeRegex.ReplaceAllString(originalImage, "_"))
+	}
+
+	originalPrefix := gcrPrefixRegex.FindString(originalImage)
+	defaultRepoPrefix := gcrPrefixRegex.FindString(defaultRepo + "/")
+	if originalPrefix != "" && originalPrefix == defaultRepoPrefix {
+		// same project: don't repeat it
+		return truncate(defaultRepo + "/" + originalImage[len(originalPrefix):])
+	}
+	return truncate(defaultRepo + "/" + originalImage)
+}
+
+// flatPaths tells whether the registry of a repo is known not to support nested paths.
+// A repo without a registry host is on Docker Hub.
+func flatPaths(repo string) bool {
+	host := strings.SplitN(repo, "/", 2)[0]
+	if !strings.ContainsAny(host, ".:") && host != "localhost" {
+		return true
 	}
-	return truncate(defaultRepo + "/" + escapeRegex.ReplaceAllString(originalImage, "_"))
+	return flatPathsRegex.MatchString(host)
 }
 
 func truncate(image string) string {
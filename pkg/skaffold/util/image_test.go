@@ -52,6 +52,66 @@ func TestImageReplaceDefaultRepo(t *testing.T) {
 			defaultRepo:   "gcr.io/default/repository",
 			expectedImage: "gcr.io/default/repository/example/registry",
 		},
+		{
+			name:          "project with dashes",
+			image:         "gcr.io/my-project-1/app",
+			defaultRepo:   "gcr.io/my-project-1",
+			expectedImage: "gcr.io/my-project-1/app",
+		},
+		{
+			name:          "regional GCR",
+			image:         "gcr.io/some/registry",
+			defaultRepo:   "eu.gcr.io/default",
+			expectedImage: "eu.gcr.io/default/gcr.io/some/registry",
+		},
+		{
+			name:          "trailing slash",
+			image:         "gcr.io/some/registry",
+			defaultRepo:   "gcr.io/default/",
+			expectedImage: "gcr.io/default/gcr.io/some/registry",
+		},
+		{
+			name:          "artifact registry",
+			image:         "gcr.io/some/registry",
+			defaultRepo:   "us-docker.pkg.dev/project/repo",
+			expectedImage: "us-docker.pkg.dev/project/repo/gcr.io/some/registry",
+		},
+		{
+			name:          "docker hub",
+			image:         "gcr.io/some/registry",
+			defaultRepo:   "docker.io/user",
+			expectedImage: "docker.io/user/gcr_io_some_registry",
+		},
+		{
+			name:          "docker hub without registry",
+			image:         "gcr.io/some/registry",
+			defaultRepo:   "user",
+			expectedImage: "user/gcr_io_some_registry",
+		},
+		{
+			name:          "quay",
+			image:         "gcr.io/some/registry",
+			defaultRepo:   "quay.io/org",
+			expectedImage: "quay.io/org/gcr_io_some_registry",
+		},
+		{
+			name:          "ecr",
+			image:         "app/web",
+			defaultRepo:   "123456789012.dkr.ecr.us-east-1.amazonaws.com/team",
+			expectedImage: "123456789012.dkr.ecr.us-east-1.amazonaws.com/team/app_web",
+		},
+		{
+			name:          "other registry",
+			image:         "gcr.io/some/registry",
+			defaultRepo:   "registry.example.com/team",
+			expectedImage: "registry.example.com/team/gcr.io/some/registry",
+		},
+		{
+			name:          "local registry",
+			image:         "app/web",
+			defaultRepo:   "localhost:5000",
+			expectedImage: "localhost:5000/app/web",
+		},
 		{
 			name:          "aws",
 			image:         "gcr.io/some/registry",
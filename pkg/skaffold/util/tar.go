@@ -18,7 +18,6 @@ package util
 
 import (
 	"archive/tar"
-	"compress/gzip"
 	"io"
 	"os"
 	"path/filepath"
@@ -31,6 +30,47 @@ func CreateTar(w io.Writer, root string, paths []string) error {
 	tw := tar.NewWriter(w)
 	defer tw.Close()
 
+	return addFilesToTar(tw, root, paths)
+}
+
+// CreateTarGz creates a tar, compressed in parallel.
+func CreateTarGz(w io.Writer, root string, paths []string) error {
+	gw := NewParallelGzipWriter(w)
+	if err := CreateTar(gw, root, paths); err != nil {
+		gw.Close()
+		return err
+	}
+	return gw.Close()
+}
+
+// CreateTarGzChunk is like CreateTarGz but leaves out the end-of-archive marker and
+// compresses with the given gzip level. Chunks can be concatenated, followed by
+// TarGzEndOfArchive, into a valid tar.gz.
+func CreateTarGzChunk(w io.Writer, root string, paths []string, level int) error {
+	gw := NewParallelGzipWriterLevel(w, level)
+	tw := tar.NewWriter(gw)
+	if err := addFilesToTar(tw, root, paths); err != nil {
+		gw.Close()
+		return err
+	}
+	if err := tw.Flush(); err != nil {
+		gw.Close()
+		return err
+	}
+	return gw.Close()
+}
+
+// TarGzEndOfArchive writes the compressed end-of-archive marker of a tar.
+func TarGzEndOfArchive(w io.Writer) error {
+	gw := NewParallelGzipWriter(w)
+	if err := tar.NewWriter(gw).Close(); err != nil {
+		gw.Close()
+		return err
+	}
+	return gw.Close()
+}
+
+func addFilesToTar(tw *tar.Writer, root string, paths []string) error {
 	for _, p := range paths {
 		tarPath := filepath.ToSlash(p)
 
@@ -46,12 +86,6 @@ func CreateTar(w io.Writer, root string, paths []string) error {
 	return nil
 }
 
-func CreateTarGz(w io.Writer, root string, paths []string) error {
-	gw := gzip.NewWriter(w)
-	defer gw.Close()
-	return CreateTar(gw, root, paths)
-}
-
 func addFileToTar(p string, tarPath string, tw *tar.Writer) error {
 	fi, err := os.Lstat(p)
 	if err != nil {
@@ -19,6 +19,7 @@ package util
 import (
 	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"io"
 	"io/ioutil"
 	"testing"
@@ -73,3 +74,47 @@ func Test_addFileToTar(t *testing.T) {
 		}
 	}
 }
+
+func TestCreateTarGzChunks(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	tmpDir.Write("foo", "baz1").
+		Write("bar/bat", "baz2").
+		Write("bar/baz", "baz3")
+
+	// Concatenate two chunks and the end-of-archive marker.
+	var b bytes.Buffer
+	if err := CreateTarGzChunk(&b, tmpDir.Root(), []string{"foo"}, gzip.BestSpeed); err != nil {
+		t.Fatalf("CreateTarGzChunk() error = %v", err)
+	}
+	if err := CreateTarGzChunk(&b, tmpDir.Root(), []string{"bar/bat", "bar/baz"}, gzip.BestCompression); err != nil {
+		t.Fatalf("CreateTarGzChunk() error = %v", err)
+	}
+	if err := TarGzEndOfArchive(&b); err != nil {
+		t.Fatalf("TarGzEndOfArchive() error = %v", err)
+	}
+
+	gr, err := gzip.NewReader(&b)
+	if err != nil {
+		t.Fatalf("Error reading gzip: %s", err)
+	}
+	tr := tar.NewReader(gr)
+	contents := map[string]string{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Error reading tar: %s", err)
+		}
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Errorf("Error %s reading file %s from tar", err, hdr.Name)
+		}
+		contents[hdr.Name] = string(content)
+	}
+
+	testutil.CheckDeepEqual(t, map[string]string{"foo": "baz1", "bar/bat": "baz2", "bar/baz": "baz3"}, contents)
+}
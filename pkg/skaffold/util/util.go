@@ -25,7 +25,6 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
-	"regexp"
 	"runtime"
 	"sort"
 	"strings"
@@ -72,7 +71,10 @@ func StrSliceContains(sl []string, s string) bool {
 func ExpandPathsGlob(workingDir string, paths []string) ([]string, error) {
 	expandedPaths := make(map[string]bool)
 	for _, p := range paths {
-		path := filepath.Join(workingDir, p)
+		path := p
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(workingDir, p)
+		}
 
 		if _, err := os.Stat(path); err == nil {
 			// This is a file reference, so just add it
@@ -194,29 +196,6 @@ func RemoveFromSlice(s []string, target string) []string {
 	return s
 }
 
-// Expand replaces placeholders for a given key with a given value.
-// It supports the ${key} and the $key syntax.
-func Expand(text, key, value string) string {
-	text = strings.Replace(text, "${"+key+"}", value, -1)
-
-	indices := regexp.MustCompile(`\$`+key).FindAllStringIndex(text, -1)
-
-	for i := len(indices) - 1; i >= 0; i-- {
-		from := indices[i][0]
-		to := indices[i][1]
-
-		if to >= len(text) || !isAlphaNum(text[to]) {
-			text = text[0:from] + value + text[to:]
-		}
-	}
-
-	return text
-}
-
-func isAlphaNum(c uint8) bool {
-	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
-}
-
 // AbsFile resolves the absolute path of the file named filename in directory workspace, erroring if it is not a file
 func AbsFile(workspace string, filename string) (string, error) {
 	file := filepath.Join(workspace, filename)
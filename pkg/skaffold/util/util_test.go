@@ -89,6 +89,11 @@ func TestExpandPathsGlob(t *testing.T) {
 			in:          []string{"dir*"},
 			out:         []string{tmpDir.Path("dir/sub_dir/file"), tmpDir.Path("dir_b/sub_dir_b/file")},
 		},
+		{
+			description: "match absolute path",
+			in:          []string{tmpDir.Path("dir_b/sub_dir_b/*")},
+			out:         []string{tmpDir.Path("dir_b/sub_dir_b/file")},
+		},
 		{
 			description: "error unmatched glob",
 			in:          []string{"dir/sub_dir_c/*"},
@@ -116,67 +121,6 @@ func TestDefaultConfigFilenameAlternate(t *testing.T) {
 	testutil.CheckErrorAndDeepEqual(t, false, err, []byte("foo"), content)
 }
 
-func TestExpand(t *testing.T) {
-	var tests = []struct {
-		description string
-		text        string
-		key         string
-		value       string
-		expected    string
-	}{
-		{
-			description: "${key} syntax",
-			text:        "BEFORE[${key}]AFTER",
-			key:         "key",
-			value:       "VALUE",
-			expected:    "BEFORE[VALUE]AFTER",
-		},
-		{
-			description: "$key syntax",
-			text:        "BEFORE[$key]AFTER",
-			key:         "key",
-			value:       "VALUE",
-			expected:    "BEFORE[VALUE]AFTER",
-		},
-		{
-			description: "replace all",
-			text:        "BEFORE[$key][${key}][$key][${key}]AFTER",
-			key:         "key",
-			value:       "VALUE",
-			expected:    "BEFORE[VALUE][VALUE][VALUE][VALUE]AFTER",
-		},
-		{
-			description: "ignore common prefix",
-			text:        "BEFORE[$key1][${key1}]AFTER",
-			key:         "key",
-			value:       "VALUE",
-			expected:    "BEFORE[$key1][${key1}]AFTER",
-		},
-		{
-			description: "just the ${key} placeholder",
-			text:        "${key}",
-			key:         "key",
-			value:       "VALUE",
-			expected:    "VALUE",
-		},
-		{
-			description: "just the $key placeholder",
-			text:        "$key",
-			key:         "key",
-			value:       "VALUE",
-			expected:    "VALUE",
-		},
-	}
-
-	for _, test := range tests {
-		t.Run(test.description, func(t *testing.T) {
-			actual := Expand(test.text, test.key, test.value)
-
-			testutil.CheckDeepEqual(t, test.expected, actual)
-		})
-	}
-}
-
 func TestAbsFile(t *testing.T) {
 	tmpDir, cleanup := testutil.NewTempDir(t)
 	defer cleanup()
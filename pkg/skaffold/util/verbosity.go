@@ -0,0 +1,44 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import "github.com/sirupsen/logrus"
+
+// Verbosity is how much the tools run by the builders and deployers print.
+type Verbosity int
+
+const (
+	// QuietOutput only keeps the errors.
+	QuietOutput Verbosity = iota
+	// NormalOutput is the default output of each tool.
+	NormalOutput
+	// VerboseOutput includes progress and debug information.
+	VerboseOutput
+)
+
+// ToolVerbosity maps the log level given with -v to the verbosity of the tools:
+// error and below make them quiet, debug and above make them verbose.
+func ToolVerbosity() Verbosity {
+	switch level := logrus.GetLevel(); {
+	case level <= logrus.ErrorLevel:
+		return QuietOutput
+	case level >= logrus.DebugLevel:
+		return VerboseOutput
+	default:
+		return NormalOutput
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"github.com/sirupsen/logrus"
+)
+
+func TestToolVerbosity(t *testing.T) {
+	var tests = []struct {
+		level    logrus.Level
+		expected Verbosity
+	}{
+		{level: logrus.PanicLevel, expected: QuietOutput},
+		{level: logrus.FatalLevel, expected: QuietOutput},
+		{level: logrus.ErrorLevel, expected: QuietOutput},
+		{level: logrus.WarnLevel, expected: NormalOutput},
+		{level: logrus.InfoLevel, expected: NormalOutput},
+		{level: logrus.DebugLevel, expected: VerboseOutput},
+	}
+
+	level := logrus.GetLevel()
+	defer logrus.SetLevel(level)
+
+	for _, test := range tests {
+		t.Run(test.level.String(), func(t *testing.T) {
+			logrus.SetLevel(test.level)
+
+			testutil.CheckDeepEqual(t, test.expected, ToolVerbosity())
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/blang/semver"
 	"github.com/pkg/errors"
 
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 )
 
@@ -31,15 +32,15 @@ var version, gitCommit, gitTreeState, buildDate string
 var platform = fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH)
 
 type Info struct {
-	Version       string
-	ConfigVersion string
-	GitVersion    string
-	GitCommit     string
-	GitTreeState  string
-	BuildDate     string
-	GoVersion     string
-	Compiler      string
-	Platform      string
+	Version       string `json:"version"`
+	ConfigVersion string `json:"configVersion"`
+	GitVersion    string `json:"gitVersion,omitempty"`
+	GitCommit     string `json:"gitCommit"`
+	GitTreeState  string `json:"gitTreeState"`
+	BuildDate     string `json:"buildDate"`
+	GoVersion     string `json:"goVersion"`
+	Compiler      string `json:"compiler"`
+	Platform      string `json:"platform"`
 }
 
 // Get returns the version and buildtime information about the binary
@@ -57,8 +58,42 @@ func Get() *Info {
 	}
 }
 
+// UserAgent identifies skaffold in the requests made to cloud APIs.
 func UserAgent() string {
-	return fmt.Sprintf("skaffold/%s/%s", platform, version)
+	info := Get()
+	return fmt.Sprintf("skaffold/%s/%s (%s; %s)", info.Platform, info.Version, info.GitCommit, info.GoVersion)
+}
+
+// Labeller sets the version of skaffold on the deployed resources.
+type Labeller struct{}
+
+// Labels returns the skaffold version label, if the version is known.
+func (Labeller) Labels() map[string]string {
+	labels := map[string]string{}
+
+	if v := labelValue(Get().Version); v != "" {
+		labels[constants.Labels.SkaffoldVersion] = v
+	}
+
+	return labels
+}
+
+// labelValue makes a version usable as a label value: at most 63 alphanumeric
+// characters, '-', '_' or '.', starting and ending with an alphanumeric character.
+func labelValue(version string) string {
+	value := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, version)
+
+	if len(value) > 63 {
+		value = value[:63]
+	}
+	return strings.Trim(value, "-_.")
 }
 
 func ParseVersion(version string) (semver.Version, error) {
@@ -17,6 +17,7 @@ limitations under the License.
 package version
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/GoogleContainerTools/skaffold/testutil"
@@ -54,3 +55,43 @@ func TestParseVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestLabelValue(t *testing.T) {
+	var tests = []struct {
+		description string
+		version     string
+		expected    string
+	}{
+		{
+			description: "release",
+			version:     "v0.18.0",
+			expected:    "v0.18.0",
+		},
+		{
+			description: "dirty build",
+			version:     "v0.18.0-12-g1a2b3c4-dirty",
+			expected:    "v0.18.0-12-g1a2b3c4-dirty",
+		},
+		{
+			description: "invalid characters",
+			version:     "v0.18.0+build/1",
+			expected:    "v0.18.0_build_1",
+		},
+		{
+			description: "unknown",
+			version:     "",
+			expected:    "",
+		},
+		{
+			description: "too long",
+			version:     "v0.18.0-" + strings.Repeat("a", 60),
+			expected:    "v0.18.0-" + strings.Repeat("a", 55),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			testutil.CheckDeepEqual(t, test.expected, labelValue(test.version))
+		})
+	}
+}
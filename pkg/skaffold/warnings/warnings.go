@@ -0,0 +1,50 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package warnings
+
+import (
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	strict bool
+
+	// for testing
+	warn = logrus.Warnf
+)
+
+// SetStrict turns the warnings that usually signal a drift in the
+// configuration into errors. This is useful on CI where nobody reads the logs.
+func SetStrict(enabled bool) {
+	strict = enabled
+}
+
+// IsStrict says if warnings should be treated as errors.
+func IsStrict() bool {
+	return strict
+}
+
+// Warnf logs a warning or, in strict mode, returns it as an error.
+func Warnf(format string, args ...interface{}) error {
+	if strict {
+		return errors.Errorf(format, args...)
+	}
+
+	warn(format, args...)
+	return nil
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package warnings
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestWarnf(t *testing.T) {
+	var warnings []string
+	defer func(w func(string, ...interface{})) { warn = w }(warn)
+	warn = func(format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+	defer SetStrict(false)
+
+	err := Warnf("image [%s] is not used", "image1")
+	testutil.CheckError(t, false, err)
+
+	SetStrict(true)
+	err = Warnf("image [%s] is not used", "image2")
+	testutil.CheckError(t, true, err)
+	testutil.CheckDeepEqual(t, "image [image2] is not used", err.Error())
+
+	testutil.CheckDeepEqual(t, []string{"image [image1] is not used"}, warnings)
+}
@@ -78,44 +78,96 @@ func (e *Events) String() string {
 	return sb.String()
 }
 
-func events(prev, curr FileMap) Events {
-	e := Events{}
-	for f, t := range prev {
-		modtime, ok := curr[f]
-		if !ok {
-			// file in prev but not in curr -> file deleted
-			e.Deleted = append(e.Deleted, f)
-			continue
+// fileState is the modification time of a watched file.
+type fileState struct {
+	path    string
+	modTime int64
+}
+
+// snapshot is the state of a component's files, sorted by path.
+// Comparing sorted slices is much cheaper than rebuilding and
+// comparing maps on each tick when tens of thousands of files are watched.
+type snapshot []fileState
+
+func (s snapshot) Len() int           { return len(s) }
+func (s snapshot) Less(i, j int) bool { return s[i].path < s[j].path }
+func (s snapshot) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// stat lists and stats the component's files. It reuses the storage of the
+// previous unchanged snapshot, and interns the paths so that the snapshots
+// kept between ticks all share the same strings.
+func (c *component) stat() (snapshot, error) {
+	paths, err := c.deps()
+	if err != nil {
+		return nil, errors.Wrap(err, "listing files")
+	}
+
+	s := c.next[:0]
+	for _, path := range paths {
+		stat, err := os.Stat(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				logrus.Debugf("could not stat dependency: %s", err)
+				continue // Ignore files that don't exist
+			}
+			return nil, errors.Wrapf(err, "unable to stat file %s", path)
 		}
-		if !modtime.Equal(t) {
-			// file in both prev and curr
-			// time not equal -> file modified
-			e.Modified = append(e.Modified, f)
+
+		if interned, found := c.paths[path]; found {
+			path = interned
+		} else {
+			c.paths[path] = path
+		}
+		s = append(s, fileState{path: path, modTime: stat.ModTime().UnixNano()})
+	}
+
+	if !sort.IsSorted(s) {
+		sort.Sort(s)
+	}
+
+	// Remove duplicates
+	unique := 0
+	for i := range s {
+		if i > 0 && s[i].path == s[unique-1].path {
 			continue
 		}
+		s[unique] = s[i]
+		unique++
 	}
 
-	for f := range curr {
-		// don't need to check case where file is in both curr and prev
-		// covered above
-		_, ok := prev[f]
-		if !ok {
+	return s[:unique], nil
+}
+
+// events compares two snapshots. Since both are sorted, the events are sorted too.
+func events(prev, curr snapshot) Events {
+	e := Events{}
+
+	i, j := 0, 0
+	for i < len(prev) || j < len(curr) {
+		switch {
+		case j == len(curr) || (i < len(prev) && prev[i].path < curr[j].path):
+			// file in prev but not in curr -> file deleted
+			e.Deleted = append(e.Deleted, prev[i].path)
+			i++
+		case i == len(prev) || curr[j].path < prev[i].path:
 			// file in curr but not in prev -> file added
-			e.Added = append(e.Added, f)
+			e.Added = append(e.Added, curr[j].path)
+			j++
+		default:
+			// file in both prev and curr
+			// time not equal -> file modified
+			if prev[i].modTime != curr[j].modTime {
+				e.Modified = append(e.Modified, curr[j].path)
+			}
+			i++
+			j++
 		}
 	}
 
-	sortEvents(e)
 	logEvents(e)
 	return e
 }
 
-func sortEvents(e Events) {
-	sort.Strings(e.Added)
-	sort.Strings(e.Modified)
-	sort.Strings(e.Deleted)
-}
-
 func logEvents(e Events) {
 	if e.Added != nil && len(e.Added) > 0 {
 		logrus.Infof("files added: %v", e.Added)
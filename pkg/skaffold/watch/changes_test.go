@@ -17,6 +17,8 @@ package watch
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
 	"testing"
 	"time"
 
@@ -90,7 +92,7 @@ func TestEvents(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.description, func(t *testing.T) {
-			testutil.CheckDeepEqual(t, test.expected, events(test.prev, test.current))
+			testutil.CheckDeepEqual(t, test.expected, events(toSnapshot(test.prev), toSnapshot(test.current)))
 		})
 	}
 }
@@ -165,6 +167,42 @@ func TestStatNotExist(t *testing.T) {
 	}
 }
 
+func TestComponentStat(t *testing.T) {
+	folder, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	folder.Write("b", "").Write("a", "").Write("c", "")
+	folder.Chtimes("a", yesterday).Chtimes("b", yesterday).Chtimes("c", today)
+
+	c := &component{
+		deps: func() ([]string, error) {
+			return []string{folder.Path("c"), folder.Path("a"), folder.Path("missing"), folder.Path("c"), folder.Path("b")}, nil
+		},
+		paths: map[string]string{},
+	}
+
+	state, err := c.stat()
+	testutil.CheckError(t, false, err)
+
+	expected := snapshot{
+		{path: folder.Path("a"), modTime: yesterday.UnixNano()},
+		{path: folder.Path("b"), modTime: yesterday.UnixNano()},
+		{path: folder.Path("c"), modTime: today.UnixNano()},
+	}
+	if !reflect.DeepEqual(expected, state) {
+		t.Errorf("expected %v, got %v", expected, state)
+	}
+}
+
+func toSnapshot(files FileMap) snapshot {
+	var s snapshot
+	for path, modTime := range files {
+		s = append(s, fileState{path: path, modTime: modTime.UnixNano()})
+	}
+	sort.Sort(s)
+	return s
+}
+
 func checkListInMap(t *testing.T, list []string, m FileMap) {
 	for _, f := range list {
 		if _, ok := m[f]; !ok {
@@ -30,6 +30,8 @@ import (
 )
 
 // Trigger describes a mechanism that triggers the watch.
+// Values sent on the channel returned by Start tell the watcher
+// whether it should call back even if no file has changed.
 type Trigger interface {
 	Start() (<-chan bool, func())
 	WatchForChanges(io.Writer)
@@ -72,7 +74,7 @@ func (t *pollTrigger) Start() (<-chan bool, func()) {
 	go func() {
 		for {
 			<-ticker.C
-			trigger <- true
+			trigger <- false
 		}
 	}()
 
@@ -103,7 +105,7 @@ func (t *manualTrigger) Start() (<-chan bool, func()) {
 			if err != nil {
 				logrus.Debugf("manual trigger error: %s", err)
 			}
-			trigger <- true
+			trigger <- false
 		}
 	}()
 
@@ -41,22 +41,27 @@ func NewWatcher() Watcher {
 type component struct {
 	deps     func() ([]string, error)
 	onChange func(Events)
-	state    FileMap
+	state    snapshot
+	next     snapshot
+	paths    map[string]string
 	events   Events
 }
 
 // Register adds a new component to the watch list.
 func (w *watchList) Register(deps func() ([]string, error), onChange func(Events)) error {
-	state, err := Stat(deps)
+	component := &component{
+		deps:     deps,
+		onChange: onChange,
+		paths:    map[string]string{},
+	}
+
+	state, err := component.stat()
 	if err != nil {
 		return errors.Wrap(err, "listing files")
 	}
+	component.state = state
 
-	*w = append(*w, &component{
-		deps:     deps,
-		onChange: onChange,
-		state:    state,
-	})
+	*w = append(*w, component)
 	return nil
 }
 
@@ -71,21 +76,28 @@ func (w *watchList) Run(ctx context.Context, trigger Trigger, onChange func() er
 		select {
 		case <-ctx.Done():
 			return nil
-		case <-t:
+		case force := <-t:
 			changed := 0
 			for i, component := range *w {
-				state, err := Stat(component.deps)
+				state, err := component.stat()
 				if err != nil {
 					return errors.Wrap(err, "listing files")
 				}
 				e := events(component.state, state)
 
-				if e.HasChanged() {
-					changedComponents[i] = true
-					component.state = state
-					component.events = e
-					changed++
+				if !e.HasChanged() {
+					// Reuse the storage on next tick
+					component.next = state
+					continue
+				}
+
+				changedComponents[i] = true
+				component.state, component.next = state, component.state
+				component.events = e
+				for _, deleted := range e.Deleted {
+					delete(component.paths, deleted)
 				}
+				changed++
 			}
 
 			// Rapid file changes that are more frequent than the poll interval would trigger
@@ -94,7 +106,7 @@ func (w *watchList) Run(ctx context.Context, trigger Trigger, onChange func() er
 			// by waiting for a full turn where nothing happens and trigger a rebuild for
 			// the accumulated changes.
 			debounce := trigger.Debounce()
-			if (!debounce && changed > 0) || (debounce && changed == 0 && len(changedComponents) > 0) {
+			if force || (!debounce && changed > 0) || (debounce && changed == 0 && len(changedComponents) > 0) {
 				for i, component := range *w {
 					if changedComponents[i] {
 						component.onChange(component.events)
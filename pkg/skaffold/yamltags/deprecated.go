@@ -0,0 +1,130 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package yamltags
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Deprecation is a deprecated field that is set in a configuration.
+type Deprecation struct {
+	// Field is the yaml path to the field.
+	Field string
+	// Replacement is the yaml path to the field to use instead, if any.
+	Replacement string
+	// RemovedIn is the configuration version that doesn't have the field anymore, if any.
+	RemovedIn string
+}
+
+func (d Deprecation) String() string {
+	message := fmt.Sprintf("%s is deprecated", d.Field)
+	switch {
+	case d.Replacement != "" && d.RemovedIn != "":
+		message += fmt.Sprintf(": it was replaced by %s in %s", d.Replacement, d.RemovedIn)
+	case d.Replacement != "":
+		message += fmt.Sprintf(", use %s instead", d.Replacement)
+	case d.RemovedIn != "":
+		message += fmt.Sprintf(": it was removed in %s", d.RemovedIn)
+	}
+	return message
+}
+
+// Deprecations lists the deprecated fields that are set in the provided pointer to a struct.
+// Fields are deprecated with a tag such as `deprecated:"replacement=build.artifacts[].image,removedIn=skaffold/v1alpha4"`.
+func Deprecations(s interface{}) []Deprecation {
+	return deprecations(reflect.ValueOf(s), "")
+}
+
+func deprecations(v reflect.Value, path string) []Deprecation {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return deprecations(v.Elem(), path)
+
+	case reflect.Slice:
+		var found []Deprecation
+		for i := 0; i < v.Len(); i++ {
+			found = append(found, deprecations(v.Index(i), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return found
+
+	case reflect.Struct:
+		var found []Deprecation
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			value := v.Field(i)
+			if !value.CanInterface() {
+				continue
+			}
+
+			fieldPath := path
+			if name := yamlName(field); name != "" {
+				fieldPath = strings.TrimPrefix(path+"."+name, ".")
+			}
+
+			if tag, ok := field.Tag.Lookup("deprecated"); ok && !isZero(value) {
+				found = append(found, parseDeprecation(fieldPath, tag))
+			}
+			found = append(found, deprecations(value, fieldPath)...)
+		}
+		return found
+
+	default:
+		return nil
+	}
+}
+
+// yamlName returns the name of a field in yaml, or an empty string for inlined fields.
+func yamlName(field reflect.StructField) string {
+	parts := strings.Split(field.Tag.Get("yaml"), ",")
+	for _, option := range parts[1:] {
+		if option == "inline" {
+			return ""
+		}
+	}
+	name := parts[0]
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+	return name
+}
+
+func parseDeprecation(field, tag string) Deprecation {
+	deprecation := Deprecation{Field: field}
+	for _, part := range strings.Split(tag, ",") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "replacement":
+			deprecation.Replacement = kv[1]
+		case "removedIn":
+			deprecation.RemovedIn = kv[1]
+		}
+	}
+	return deprecation
+}
+
+func isZero(v reflect.Value) bool {
+	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package yamltags
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+type deprecatedImage struct {
+	Image     string `yaml:"image"`
+	ImageName string `yaml:"imageName" deprecated:"replacement=image,removedIn=skaffold/v1alpha4"`
+}
+
+type inlinedImage struct {
+	Image deprecatedImage `yaml:",inline"`
+}
+
+type deprecatedConfig struct {
+	Artifacts []*deprecatedImage `yaml:"artifacts"`
+	Inlined   inlinedImage       `yaml:"inlined"`
+	Old       string             `yaml:"old" deprecated:""`
+}
+
+func TestDeprecations(t *testing.T) {
+	var tests = []struct {
+		description string
+		config      *deprecatedConfig
+		expected    []Deprecation
+	}{
+		{
+			description: "nothing deprecated",
+			config: &deprecatedConfig{
+				Artifacts: []*deprecatedImage{{Image: "image"}},
+			},
+		},
+		{
+			description: "deprecated field in slice",
+			config: &deprecatedConfig{
+				Artifacts: []*deprecatedImage{{Image: "image1"}, {ImageName: "image2"}},
+			},
+			expected: []Deprecation{{Field: "artifacts[1].imageName", Replacement: "image", RemovedIn: "skaffold/v1alpha4"}},
+		},
+		{
+			description: "inlined and untagged",
+			config: &deprecatedConfig{
+				Inlined: inlinedImage{deprecatedImage{ImageName: "image"}},
+				Old:     "value",
+			},
+			expected: []Deprecation{
+				{Field: "inlined.imageName", Replacement: "image", RemovedIn: "skaffold/v1alpha4"},
+				{Field: "old"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			testutil.CheckDeepEqual(t, test.expected, Deprecations(test.config))
+		})
+	}
+}
+
+func TestDeprecationString(t *testing.T) {
+	testutil.CheckDeepEqual(t, "a.b is deprecated", Deprecation{Field: "a.b"}.String())
+	testutil.CheckDeepEqual(t, "a.b is deprecated, use a.c instead", Deprecation{Field: "a.b", Replacement: "a.c"}.String())
+	testutil.CheckDeepEqual(t, "a.b is deprecated: it was removed in skaffold/v1alpha4", Deprecation{Field: "a.b", RemovedIn: "skaffold/v1alpha4"}.String())
+	testutil.CheckDeepEqual(t, "a.b is deprecated: it was replaced by a.c in skaffold/v1alpha4", Deprecation{Field: "a.b", Replacement: "a.c", RemovedIn: "skaffold/v1alpha4"}.String())
+}
@@ -42,6 +42,9 @@ type FakeImageAPIOptions struct {
 
 	BuildImageID string
 
+	// ImagePlatform is the os/arch platform of the inspected images.
+	ImagePlatform string
+
 	ReturnBody io.ReadCloser
 }
 
@@ -87,7 +90,12 @@ func (f *FakeImageAPIClient) ImageInspectWithRaw(ctx context.Context, ref string
 		return types.ImageInspect{}, nil, nil
 	}
 
-	return types.ImageInspect{ID: imageID}, nil, nil
+	inspect := types.ImageInspect{ID: imageID}
+	if f.opts.ImagePlatform != "" {
+		parts := strings.SplitN(f.opts.ImagePlatform, "/", 2)
+		inspect.Os, inspect.Architecture = parts[0], parts[1]
+	}
+	return inspect, nil, nil
 }
 
 func (f *FakeImageAPIClient) ImageTag(ctx context.Context, image, ref string) error {